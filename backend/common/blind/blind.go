@@ -0,0 +1,212 @@
+// Package blind 盲筛：开启盲筛的职位在初筛阶段对权限不足的用户隐藏候选人身份信息
+// （姓名、性别、年龄、照片、电话、邮箱、学校等），申请流转出筛选阶段后恢复显示
+package blind
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RevealRoleEnv 可查看盲筛候选人身份信息的最低角色
+const RevealRoleEnv = "BLIND_SCREENING_REVEAL_ROLE"
+
+// 替换身份信息的占位符
+const (
+	MaskName     = "[姓名]"
+	MaskGender   = "[性别]"
+	MaskAge      = "[年龄]"
+	MaskPhone    = "[电话]"
+	MaskEmail    = "[邮箱]"
+	MaskSchool   = "[学校]"
+	MaskPhoto    = "[照片]"
+	MaskIDNumber = "[证件号]"
+	MaskOther    = "[已隐藏]"
+)
+
+// roleRank 角色权限由低到高，未登录、候选人或未知角色为 0；
+// hr 为 user-service 自助注册的 HR 账号，与 hr_manager 同级
+var roleRank = map[string]int{
+	"viewer":      1,
+	"interviewer": 2,
+	"recruiter":   3,
+	"hr":          4,
+	"hr_manager":  4,
+	"admin":       5,
+}
+
+// Policy 盲筛权限：角色不低于 RevealRole 的用户始终可以查看身份信息
+type Policy struct {
+	RevealRole string
+}
+
+// PolicyFromEnv 按 BLIND_SCREENING_REVEAL_ROLE 创建权限配置，默认 hr_manager
+func PolicyFromEnv() Policy {
+	role := strings.TrimSpace(os.Getenv(RevealRoleEnv))
+	if _, ok := roleRank[role]; !ok {
+		role = "hr_manager"
+	}
+	return Policy{RevealRole: role}
+}
+
+// CanReveal 该角色能否查看盲筛候选人的身份信息
+func (p Policy) CanReveal(role string) bool {
+	return roleRank[role] > 0 && roleRank[role] >= roleRank[p.RevealRole]
+}
+
+// Exempt 当前请求的用户不受盲筛限制（角色由 JWT 中间件写入）
+func (p Policy) Exempt(c *gin.Context) bool {
+	return p.CanReveal(c.GetString("role"))
+}
+
+// Alias 隐藏姓名后显示的候选人代号
+func Alias(id uint) string {
+	return fmt.Sprintf("候选人 #%d", id)
+}
+
+// Identity 需要从文本中抹去的已知身份信息，未识别的电话、邮箱、证件号、照片、学校按格式识别
+type Identity struct {
+	Name    string
+	Email   string
+	Phone   string
+	Schools []string
+}
+
+var (
+	markdownImage = regexp.MustCompile(`!\[[^\]]*\]\([^)]*\)`)
+	htmlImage     = regexp.MustCompile(`(?i)<img\b[^>]*>`)
+	dataImage     = regexp.MustCompile(`data:image/[a-zA-Z+]+;base64,[A-Za-z0-9+/=]+`)
+	emailPattern  = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	idNumber      = regexp.MustCompile(`\b\d{17}[\dXx]\b`)
+	mobilePattern = regexp.MustCompile(`(?:\+86[\s-]?|\b86[\s-]?|\b)1[3-9]\d[\s-]?\d{4}[\s-]?\d{4}\b`)
+	landline      = regexp.MustCompile(`\b0\d{2,3}-\d{7,8}\b`)
+
+	// labelled 带标签的个人信息，如“性别：男”“**出生年月**: 1995.06”
+	labelled = regexp.MustCompile(`(姓\s*名|性\s*别|年\s*龄|出生日期|出生年月|生\s*日|民\s*族|籍\s*贯|婚姻状况|婚\s*否|政治面貌|身份证号码?|照\s*片)([*\s]*[:：][*\s]*)([^\s|｜,，;；*]+)`)
+	// labelledEN 英文简历行首的个人信息，如“Name: John Smith”
+	labelledEN = regexp.MustCompile(`(?im)^([\s\-*|#>]*)(full name|name|gender|sex|age|date of birth|dob)([*\s]*:[*\s]*)([^|\n]+)`)
+	ageYears   = regexp.MustCompile(`\d{2}\s*(岁|周岁)`)
+	// gender 以分隔符隔开的单独性别，如“男 | 28岁 | 本科”
+	gender = regexp.MustCompile(`(^|[\s|｜,，/、(（])(男|女)([\s|｜,，/、)）]|$)`)
+
+	schoolCN = regexp.MustCompile(`\p{Han}{2,12}(大学|学院|高等专科学校|专科学校|中学|高级中学)`)
+	schoolEN = regexp.MustCompile(`\b(?:[A-Z][A-Za-z&.'\-]*\s+){0,5}(?:University|College|Institute of Technology|Institute)\b(?:\s+of(?:\s+[A-Z][A-Za-z&.'\-]*)+)?`)
+)
+
+// labelMasks 标签对应的占位符
+var labelMasks = map[string]string{
+	"姓名": MaskName, "name": MaskName, "full name": MaskName,
+	"性别": MaskGender, "gender": MaskGender, "sex": MaskGender,
+	"年龄": MaskAge, "出生日期": MaskAge, "出生年月": MaskAge, "生日": MaskAge, "age": MaskAge, "date of birth": MaskAge, "dob": MaskAge,
+	"身份证号": MaskIDNumber, "身份证号码": MaskIDNumber,
+	"照片": MaskPhoto,
+}
+
+// schoolPrefixes 中文学校名前常见的动词，匹配到时保留，如“毕业于清华大学”只隐藏“清华大学”
+var schoolPrefixes = []string{"毕业于", "就读于", "肄业于", "就读", "毕业", "于", "在", "自"}
+
+// RedactText 隐藏文本中的身份信息：先替换已知的姓名、邮箱、电话、学校，再按格式识别其余信息
+func (id Identity) RedactText(s string) string {
+	if s == "" {
+		return s
+	}
+
+	known := []struct{ value, mask string }{{id.Name, MaskName}, {id.Email, MaskEmail}, {id.Phone, MaskPhone}}
+	for _, school := range id.Schools {
+		known = append(known, struct{ value, mask string }{school, MaskSchool})
+	}
+	// 长的先替换，避免学校名中包含的短词先被替换
+	sort.SliceStable(known, func(i, j int) bool { return len(known[i].value) > len(known[j].value) })
+	for _, k := range known {
+		if v := strings.TrimSpace(k.value); utf8.RuneCountInString(v) >= 2 {
+			s = strings.ReplaceAll(s, v, k.mask)
+		}
+	}
+
+	s = markdownImage.ReplaceAllString(s, MaskPhoto)
+	s = htmlImage.ReplaceAllString(s, MaskPhoto)
+	s = dataImage.ReplaceAllString(s, MaskPhoto)
+	s = emailPattern.ReplaceAllString(s, MaskEmail)
+	s = idNumber.ReplaceAllString(s, MaskIDNumber)
+	s = mobilePattern.ReplaceAllString(s, MaskPhone)
+	s = landline.ReplaceAllString(s, MaskPhone)
+
+	s = labelled.ReplaceAllStringFunc(s, func(m string) string {
+		parts := labelled.FindStringSubmatch(m)
+		return parts[1] + parts[2] + labelMask(strings.Join(strings.Fields(parts[1]), ""), parts[3])
+	})
+	s = labelledEN.ReplaceAllStringFunc(s, func(m string) string {
+		parts := labelledEN.FindStringSubmatch(m)
+		return parts[1] + parts[2] + parts[3] + labelMask(strings.ToLower(parts[2]), parts[4])
+	})
+	s = ageYears.ReplaceAllString(s, MaskAge)
+	s = gender.ReplaceAllString(s, "${1}"+MaskGender+"${3}")
+
+	s = schoolCN.ReplaceAllStringFunc(s, func(m string) string {
+		for _, p := range schoolPrefixes {
+			if i := strings.LastIndex(m, p); i >= 0 && utf8.RuneCountInString(m[:i+len(p)]) >= 2 && utf8.RuneCountInString(m[i+len(p):]) >= 4 {
+				return m[:i+len(p)] + MaskSchool
+			}
+		}
+		return MaskSchool
+	})
+	return schoolEN.ReplaceAllString(s, MaskSchool)
+}
+
+// labelMask 标签对应的占位符，值已是占位符时保持不变
+func labelMask(label, value string) string {
+	if strings.HasPrefix(value, "[") {
+		return value
+	}
+	if mask, ok := labelMasks[label]; ok {
+		return mask
+	}
+	return MaskOther
+}
+
+// sensitiveKeys 结构化数据中直接隐藏的字段
+var sensitiveKeys = map[string]string{
+	"name": MaskName, "candidate_name": MaskName, "candidatename": MaskName, "real_name": MaskName, "姓名": MaskName,
+	"email": MaskEmail, "mail": MaskEmail, "邮箱": MaskEmail,
+	"phone": MaskPhone, "mobile": MaskPhone, "tel": MaskPhone, "telephone": MaskPhone, "电话": MaskPhone, "手机": MaskPhone,
+	"gender": MaskGender, "sex": MaskGender, "性别": MaskGender,
+	"age": MaskAge, "birthday": MaskAge, "birth_date": MaskAge, "birth": MaskAge, "年龄": MaskAge, "出生日期": MaskAge,
+	"photo": MaskPhoto, "avatar": MaskPhoto, "photo_url": MaskPhoto, "照片": MaskPhoto,
+	"school": MaskSchool, "university": MaskSchool, "college": MaskSchool, "学校": MaskSchool, "毕业院校": MaskSchool,
+	"id_card": MaskIDNumber, "id_number": MaskIDNumber, "身份证号": MaskIDNumber,
+	"address": MaskOther, "地址": MaskOther,
+}
+
+// RedactValue 隐藏 JSON 解码后的数据（map、切片、字符串）中的身份信息：
+// 敏感字段整体替换为占位符，其余字符串按 RedactText 处理，返回新的值，不修改原数据
+func (id Identity) RedactValue(v any) any {
+	switch x := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(x))
+		for k, val := range x {
+			if mask, ok := sensitiveKeys[strings.ToLower(k)]; ok && val != nil {
+				if _, nested := val.(map[string]any); !nested {
+					out[k] = mask
+					continue
+				}
+			}
+			out[k] = id.RedactValue(val)
+		}
+		return out
+	case []any:
+		out := make([]any, len(x))
+		for i, val := range x {
+			out[i] = id.RedactValue(val)
+		}
+		return out
+	case string:
+		return id.RedactText(x)
+	default:
+		return v
+	}
+}
@@ -0,0 +1,98 @@
+package blind
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy(t *testing.T) {
+	t.Setenv(RevealRoleEnv, "recruiter")
+	p := PolicyFromEnv()
+	assert.True(t, p.CanReveal("admin"))
+	assert.True(t, p.CanReveal("recruiter"))
+	assert.False(t, p.CanReveal("interviewer"))
+	assert.False(t, p.CanReveal(""), "未登录")
+
+	assert.True(t, p.CanReveal("hr"), "自助注册的 HR 账号")
+	assert.False(t, p.CanReveal("candidate"))
+
+	t.Setenv(RevealRoleEnv, "")
+	p = PolicyFromEnv()
+	assert.True(t, p.CanReveal("hr"), "默认配置下 HR 可以查看身份信息")
+	assert.True(t, p.CanReveal("hr_manager"))
+	assert.False(t, p.CanReveal("recruiter"))
+
+	t.Setenv(RevealRoleEnv, "owner")
+	assert.Equal(t, "hr_manager", PolicyFromEnv().RevealRole, "未知角色使用默认值")
+}
+
+func TestRedactText(t *testing.T) {
+	id := Identity{Name: "张三", Email: "zhangsan@example.com", Phone: "13812345678", Schools: []string{"浙江大学"}}
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "已知信息", text: "张三，邮箱 zhangsan@example.com，电话 13812345678", want: "[姓名]，邮箱 [邮箱]，电话 [电话]"},
+		{name: "未知联系方式", text: "联系：lisi@test.cn / +86 139-1234-5678 / 010-12345678", want: "联系：[邮箱] / [电话] / [电话]"},
+		{name: "标签字段", text: "**性别**：男 | 出生年月：1995.06 | 民族：汉", want: "**性别**：[性别] | 出生年月：[年龄] | 民族：[已隐藏]"},
+		{name: "性别与年龄", text: "男 | 28岁 | 本科", want: "[性别] | [年龄] | 本科"},
+		{name: "学校", text: "2014-2018 毕业于浙江大学计算机学院，高中就读于杭州第二中学", want: "2014-2018 毕业于[学校][学校]，高中就读于[学校]"},
+		{name: "英文简历", text: "Name: John Smith\nEducation: Stanford University, B.S.", want: "Name: [姓名]\nEducation: [学校], B.S."},
+		{name: "照片和证件号", text: "![头像](http://x/a.png) 身份证 110101199003071234", want: "[照片] 身份证 [证件号]"},
+		{name: "不含身份信息", text: "5 年 Go 开发经验，熟悉 Kubernetes", want: "5 年 Go 开发经验，熟悉 Kubernetes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, id.RedactText(tt.text))
+		})
+	}
+}
+
+func TestRedactValue(t *testing.T) {
+	id := Identity{Name: "张三"}
+	data := map[string]any{
+		"name":        "张三",
+		"age":         float64(28),
+		"total_score": float64(86),
+		"educations":  []any{map[string]any{"school": "浙江大学", "degree": "本科"}},
+		"summary":     "张三在杭州工作",
+	}
+
+	got := id.RedactValue(data).(map[string]any)
+	assert.Equal(t, MaskName, got["name"])
+	assert.Equal(t, MaskAge, got["age"])
+	assert.Equal(t, float64(86), got["total_score"])
+	assert.Equal(t, []any{map[string]any{"school": MaskSchool, "degree": "本科"}}, got["educations"])
+	assert.Equal(t, "[姓名]在杭州工作", got["summary"])
+	assert.Equal(t, "张三", data["name"], "不修改原数据")
+}
+
+func TestDecide(t *testing.T) {
+	apps := []application{
+		{ID: 1, TalentID: 10, JobID: 1, Status: "pending", Blind: true},  // 筛选中
+		{ID: 2, TalentID: 20, JobID: 1, Status: "rejected", Blind: true}, // 筛选阶段淘汰
+		{ID: 3, TalentID: 30, JobID: 1, Status: "pending", Blind: true},  // 淘汰后重新激活，曾进入面试
+		{ID: 4, TalentID: 40, JobID: 1, Status: "reviewed", Blind: true}, // 已通过筛选
+		{ID: 5, TalentID: 50, JobID: 1, Status: "pending", Blind: true},  // 同时申请了未开启盲筛的职位
+		{ID: 6, TalentID: 50, JobID: 2, Status: "reviewed"},
+		{ID: 7, TalentID: 60, JobID: 3, Status: "new", Blind: true}, // 自定义流程
+		{ID: 8, TalentID: 70, JobID: 2, Status: "pending"},          // 未开启盲筛
+	}
+	stages := map[uint][]string{
+		2: {"pending", "rejected"},
+		3: {"pending", "interviewing", "rejected", "pending"},
+	}
+	entries := map[uint]string{1: "pending", 3: "new"}
+
+	assert.Equal(t, map[uint]bool{10: true, 50: true, 60: true}, decide(apps, stages, entries))
+}
+
+func TestScreened(t *testing.T) {
+	assert.False(t, Screened("pending", "pending", "rejected"))
+	assert.True(t, Screened("pending", "pending", "offered"), "旧状态名")
+	assert.True(t, Screened("new", "pending"), "自定义流程中 pending 不是初始阶段")
+}
@@ -0,0 +1,110 @@
+package blind
+
+import (
+	"common/pipeline"
+
+	"gorm.io/gorm"
+)
+
+// application 人才的申请及其职位的盲筛设置
+type application struct {
+	ID       uint
+	TalentID uint
+	JobID    uint
+	Status   string
+	Blind    bool
+}
+
+// Jobs 返回开启盲筛的职位（jobs 表由 job-service 维护）
+func Jobs(db *gorm.DB, jobIDs []uint) (map[uint]bool, error) {
+	result := map[uint]bool{}
+	if len(jobIDs) == 0 {
+		return result, nil
+	}
+	var ids []uint
+	if err := db.Table("jobs").Where("id IN ? AND blind_screening AND deleted_at IS NULL", jobIDs).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		result[id] = true
+	}
+	return result, nil
+}
+
+// Talents 返回仍处于盲筛中的人才：有投递盲筛职位、尚在筛选阶段的申请。
+// 申请流转出筛选阶段后恢复显示；在筛选阶段被淘汰或撤回的申请已结束筛选，不再隐藏
+func Talents(db *gorm.DB, talentIDs []uint) (map[uint]bool, error) {
+	result := map[uint]bool{}
+	if len(talentIDs) == 0 {
+		return result, nil
+	}
+
+	var apps []application
+	if err := db.Table("applications AS a").
+		Select("a.id, a.talent_id, a.job_id, a.status, j.blind_screening AS blind").
+		Joins("JOIN jobs j ON j.id = a.job_id").
+		Where("a.deleted_at IS NULL AND j.blind_screening AND a.talent_id IN ?", talentIDs).
+		Scan(&apps).Error; err != nil {
+		return nil, err
+	}
+
+	if len(apps) == 0 {
+		return result, nil
+	}
+
+	appIDs := make([]uint, len(apps))
+	for i, a := range apps {
+		appIDs[i] = a.ID
+	}
+	var transitions []struct {
+		ApplicationID uint
+		ToStage       string
+	}
+	if err := db.Table("application_stage_transitions").Select("application_id, to_stage").
+		Where("application_id IN ?", appIDs).Scan(&transitions).Error; err != nil {
+		return nil, err
+	}
+	stages := map[uint][]string{}
+	for _, t := range transitions {
+		stages[t.ApplicationID] = append(stages[t.ApplicationID], t.ToStage)
+	}
+
+	// 各职位的初始阶段即筛选阶段
+	entries := map[uint]string{}
+	for _, a := range apps {
+		if _, ok := entries[a.JobID]; ok {
+			continue
+		}
+		cfg, _, err := pipeline.Load(db, a.JobID)
+		if err != nil {
+			return nil, err
+		}
+		entries[a.JobID] = cfg.Entry()
+	}
+
+	return decide(apps, stages, entries), nil
+}
+
+// decide 按申请、阶段流转历史和各职位的初始（筛选）阶段判断仍处于盲筛中的人才
+func decide(apps []application, stages map[uint][]string, entries map[uint]string) map[uint]bool {
+	result := map[uint]bool{}
+	for _, a := range apps {
+		if a.Blind && !pipeline.IsClosed(a.Status) && !Screened(entries[a.JobID], append([]string{a.Status}, stages[a.ID]...)...) {
+			result[a.TalentID] = true
+		}
+	}
+	return result
+}
+
+// Screened 申请是否曾流转出筛选阶段：到达过初始阶段、淘汰、撤回以外的任一阶段
+func Screened(entry string, stages ...string) bool {
+	for _, s := range stages {
+		switch s = pipeline.Normalize(s); s {
+		case "", entry, pipeline.StageRejected, pipeline.StageWithdrawn:
+		default:
+			return true
+		}
+	}
+	return false
+}
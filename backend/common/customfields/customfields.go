@@ -0,0 +1,449 @@
+package customfields
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// 实体类型
+const (
+	EntityTalent      = "talent"
+	EntityJob         = "job"
+	EntityApplication = "application"
+)
+
+// 字段类型
+const (
+	TypeText        = "text"
+	TypeNumber      = "number"
+	TypeDate        = "date"
+	TypeEnum        = "enum"
+	TypeMultiSelect = "multi_select"
+)
+
+// DateLayout 日期字段统一格式
+const DateLayout = "2006-01-02"
+
+// maxTextLength 文本字段最大长度
+const maxTextLength = 2000
+
+var keyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// Definition 自定义字段定义（由管理员按实体类型配置）
+type Definition struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	EntityType  string         `gorm:"size:20;not null;uniqueIndex:idx_custom_field_entity_key" json:"entity_type"` // talent, job, application
+	Key         string         `gorm:"size:50;not null;uniqueIndex:idx_custom_field_entity_key" json:"key"`         // 存储键，如 notice_period
+	Label       string         `gorm:"size:100;not null" json:"label"`                                              // 显示名称，如 到岗周期
+	Type        string         `gorm:"size:20;not null" json:"type"`                                                // text, number, date, enum, multi_select
+	Options     pq.StringArray `gorm:"type:text[]" json:"options"`                                                  // enum / multi_select 可选项
+	Required    bool           `gorm:"default:false" json:"required"`
+	Description string         `gorm:"size:500" json:"description"`
+	SortOrder   int            `gorm:"default:0" json:"sort_order"`
+	Active      bool           `gorm:"default:true" json:"active"`
+}
+
+// TableName 设置表名
+func (Definition) TableName() string {
+	return "custom_field_definitions"
+}
+
+// Values 自定义字段值，以 JSONB 存储在各实体表的 custom_fields 列
+type Values map[string]interface{}
+
+// Value 实现 driver.Valuer
+func (v Values) Value() (driver.Value, error) {
+	if v == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan 实现 sql.Scanner
+func (v *Values) Scan(src interface{}) error {
+	var data []byte
+	switch s := src.(type) {
+	case nil:
+		*v = Values{}
+		return nil
+	case []byte:
+		data = s
+	case string:
+		data = []byte(s)
+	default:
+		return fmt.Errorf("customfields: unsupported scan type %T", src)
+	}
+	if len(data) == 0 {
+		*v = Values{}
+		return nil
+	}
+	out := Values{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return err
+	}
+	*v = out
+	return nil
+}
+
+// GormDataType 指定 GORM 列类型
+func (Values) GormDataType() string {
+	return "jsonb"
+}
+
+// ValidEntityType 判断实体类型是否支持自定义字段
+func ValidEntityType(entity string) bool {
+	switch entity {
+	case EntityTalent, EntityJob, EntityApplication:
+		return true
+	}
+	return false
+}
+
+// ValidateDefinition 校验字段定义本身是否合法
+func ValidateDefinition(def *Definition) error {
+	if !ValidEntityType(def.EntityType) {
+		return fmt.Errorf("不支持的实体类型: %s", def.EntityType)
+	}
+	if !keyPattern.MatchString(def.Key) {
+		return errors.New("字段键只能包含小写字母、数字和下划线，且以字母开头")
+	}
+	if strings.TrimSpace(def.Label) == "" {
+		return errors.New("字段名称不能为空")
+	}
+	switch def.Type {
+	case TypeText, TypeNumber, TypeDate:
+		def.Options = nil
+	case TypeEnum, TypeMultiSelect:
+		options := make([]string, 0, len(def.Options))
+		seen := make(map[string]bool)
+		for _, o := range def.Options {
+			o = strings.TrimSpace(o)
+			if o != "" && !seen[o] {
+				seen[o] = true
+				options = append(options, o)
+			}
+		}
+		if len(options) == 0 {
+			return errors.New("枚举和多选字段必须提供可选项")
+		}
+		def.Options = options
+	default:
+		return fmt.Errorf("不支持的字段类型: %s", def.Type)
+	}
+	return nil
+}
+
+// LoadDefinitions 加载某实体类型的启用字段定义
+func LoadDefinitions(db *gorm.DB, entity string) ([]Definition, error) {
+	var defs []Definition
+	err := db.Where("entity_type = ? AND active = ?", entity, true).
+		Order("sort_order ASC, id ASC").
+		Find(&defs).Error
+	return defs, err
+}
+
+// Validate 按字段定义校验并规范化字段值。
+// requireAll 为 true 时检查必填字段（创建时使用）；值为 null 的字段会被移除。
+func Validate(defs []Definition, values Values, requireAll bool) (Values, error) {
+	byKey := make(map[string]Definition, len(defs))
+	for _, d := range defs {
+		byKey[d.Key] = d
+	}
+
+	result := Values{}
+	for key, raw := range values {
+		def, ok := byKey[key]
+		if !ok {
+			return nil, fmt.Errorf("未定义的自定义字段: %s", key)
+		}
+		if raw == nil {
+			continue
+		}
+		value, err := coerce(def, raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", def.Label, err)
+		}
+		if value != nil {
+			result[key] = value
+		}
+	}
+
+	if requireAll {
+		for _, d := range defs {
+			if _, ok := result[d.Key]; d.Required && !ok {
+				return nil, fmt.Errorf("%s 为必填项", d.Label)
+			}
+		}
+	}
+	return result, nil
+}
+
+// Patch 校验补丁并合并到已有字段值，补丁中值为 null 或空的字段表示删除；
+// 已停用字段的历史值原样保留
+func Patch(defs []Definition, existing, patch Values) (Values, error) {
+	validated, err := Validate(defs, patch, false)
+	if err != nil {
+		return nil, err
+	}
+	merged := Values{}
+	for k, v := range existing {
+		merged[k] = v
+	}
+	for k := range patch {
+		if v, ok := validated[k]; ok {
+			merged[k] = v
+		} else {
+			delete(merged, k)
+		}
+	}
+	return merged, nil
+}
+
+// FromRaw 将 JSON 解码得到的任意值转换为 Values
+func FromRaw(raw interface{}) (Values, error) {
+	switch v := raw.(type) {
+	case nil:
+		return Values{}, nil
+	case map[string]interface{}:
+		return Values(v), nil
+	case Values:
+		return v, nil
+	}
+	return nil, errors.New("custom_fields 必须是对象")
+}
+
+func coerce(def Definition, raw interface{}) (interface{}, error) {
+	switch def.Type {
+	case TypeText:
+		s, ok := raw.(string)
+		if !ok {
+			return nil, errors.New("应为文本")
+		}
+		s = strings.TrimSpace(s)
+		if len([]rune(s)) > maxTextLength {
+			return nil, fmt.Errorf("长度不能超过 %d 个字符", maxTextLength)
+		}
+		if s == "" {
+			return nil, nil
+		}
+		return s, nil
+
+	case TypeNumber:
+		switch n := raw.(type) {
+		case float64:
+			return n, nil
+		case int:
+			return float64(n), nil
+		case json.Number:
+			return n.Float64()
+		case string:
+			if strings.TrimSpace(n) == "" {
+				return nil, nil
+			}
+			f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+			if err != nil {
+				return nil, errors.New("应为数字")
+			}
+			return f, nil
+		}
+		return nil, errors.New("应为数字")
+
+	case TypeDate:
+		s, ok := raw.(string)
+		if !ok {
+			return nil, errors.New("应为日期")
+		}
+		s = strings.TrimSpace(s)
+		if s == "" {
+			return nil, nil
+		}
+		if _, err := time.Parse(DateLayout, s); err != nil {
+			return nil, errors.New("日期格式应为 YYYY-MM-DD")
+		}
+		return s, nil
+
+	case TypeEnum:
+		s, ok := raw.(string)
+		if !ok {
+			return nil, errors.New("应为单个选项")
+		}
+		if s == "" {
+			return nil, nil
+		}
+		if !contains(def.Options, s) {
+			return nil, fmt.Errorf("选项 %s 不在可选范围内", s)
+		}
+		return s, nil
+
+	case TypeMultiSelect:
+		items, ok := raw.([]interface{})
+		if !ok {
+			if ss, isStrings := raw.([]string); isStrings {
+				for _, s := range ss {
+					items = append(items, s)
+				}
+			} else {
+				return nil, errors.New("应为选项数组")
+			}
+		}
+		selected := make([]string, 0, len(items))
+		seen := make(map[string]bool)
+		for _, item := range items {
+			s, ok := item.(string)
+			if !ok {
+				return nil, errors.New("选项必须是文本")
+			}
+			if !contains(def.Options, s) {
+				return nil, fmt.Errorf("选项 %s 不在可选范围内", s)
+			}
+			if !seen[s] {
+				seen[s] = true
+				selected = append(selected, s)
+			}
+		}
+		if len(selected) == 0 {
+			return nil, nil
+		}
+		return selected, nil
+	}
+	return nil, fmt.Errorf("不支持的字段类型: %s", def.Type)
+}
+
+// ApplyFilters 根据查询参数为列表/搜索追加自定义字段过滤条件。
+// 参数格式：cf.<key>=值（枚举/多选可用逗号分隔多个值，文本为模糊匹配），
+// 数字与日期字段额外支持 cf.<key>.min / cf.<key>.max 范围过滤。
+// column 为 JSONB 列名，联表查询时应带表名前缀，如 applications.custom_fields。
+func ApplyFilters(query *gorm.DB, defs []Definition, params url.Values, column string) (*gorm.DB, error) {
+	for _, def := range defs {
+		param := "cf." + def.Key
+		field := column + "->>'" + def.Key + "'"
+		// 数字/日期比较只作用于类型匹配的历史值，避免脏数据触发类型转换错误
+		number := "(CASE WHEN jsonb_typeof(" + column + "->'" + def.Key + "') = 'number' THEN (" + field + ")::numeric END)"
+		date := "(CASE WHEN " + field + " ~ '^[0-9]{4}-[0-9]{2}-[0-9]{2}$' THEN " + field + " END)"
+
+		if v := strings.TrimSpace(params.Get(param)); v != "" {
+			switch def.Type {
+			case TypeText:
+				query = query.Where(field+" ILIKE ?", "%"+v+"%")
+			case TypeNumber:
+				f, err := strconv.ParseFloat(v, 64)
+				if err != nil {
+					return nil, fmt.Errorf("%s 过滤值应为数字", def.Label)
+				}
+				query = query.Where(number+" = ?", f)
+			case TypeDate:
+				query = query.Where(date+" = ?", v)
+			case TypeEnum:
+				query = query.Where(field+" IN ?", splitValues(v))
+			case TypeMultiSelect:
+				conds := make([]string, 0)
+				args := make([]interface{}, 0)
+				for _, item := range splitValues(v) {
+					b, _ := json.Marshal([]string{item})
+					conds = append(conds, column+"->'"+def.Key+"' @> ?::jsonb")
+					args = append(args, string(b))
+				}
+				query = query.Where("("+strings.Join(conds, " OR ")+")", args...)
+			}
+		}
+
+		if def.Type != TypeNumber && def.Type != TypeDate {
+			continue
+		}
+		for _, bound := range []struct{ suffix, op string }{{".min", ">="}, {".max", "<="}} {
+			op := bound.op
+			v := strings.TrimSpace(params.Get(param + bound.suffix))
+			if v == "" {
+				continue
+			}
+			if def.Type == TypeNumber {
+				f, err := strconv.ParseFloat(v, 64)
+				if err != nil {
+					return nil, fmt.Errorf("%s 过滤值应为数字", def.Label)
+				}
+				query = query.Where(number+" "+op+" ?", f)
+			} else {
+				if _, err := time.Parse(DateLayout, v); err != nil {
+					return nil, fmt.Errorf("%s 日期格式应为 YYYY-MM-DD", def.Label)
+				}
+				query = query.Where(date+" "+op+" ?", v)
+			}
+		}
+	}
+	return query, nil
+}
+
+// Header 返回导出用的自定义字段表头
+func Header(defs []Definition) []string {
+	header := make([]string, len(defs))
+	for i, d := range defs {
+		header[i] = d.Label
+	}
+	return header
+}
+
+// Cells 返回导出用的自定义字段单元格
+func Cells(defs []Definition, values Values) []string {
+	cells := make([]string, len(defs))
+	for i, d := range defs {
+		cells[i] = Format(values[d.Key])
+	}
+	return cells
+}
+
+// Format 将字段值格式化为文本
+func Format(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case []interface{}:
+		parts := make([]string, 0, len(val))
+		for _, item := range val {
+			parts = append(parts, Format(item))
+		}
+		return strings.Join(parts, ", ")
+	case []string:
+		return strings.Join(val, ", ")
+	}
+	return fmt.Sprint(v)
+}
+
+func splitValues(v string) []string {
+	parts := strings.Split(v, ",")
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package customfields
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func testDefinitions() []Definition {
+	return []Definition{
+		{Key: "notice_period", Label: "到岗周期", Type: TypeNumber, Required: true},
+		{Key: "visa_status", Label: "签证状态", Type: TypeEnum, Options: []string{"citizen", "work_visa", "none"}},
+		{Key: "available_from", Label: "可入职日期", Type: TypeDate},
+		{Key: "languages", Label: "语言", Type: TypeMultiSelect, Options: []string{"中文", "English", "日本語"}},
+		{Key: "hiring_manager", Label: "招聘负责人", Type: TypeText},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	defs := testDefinitions()
+
+	tests := []struct {
+		name    string
+		values  Values
+		wantErr bool
+		check   func(t *testing.T, v Values)
+	}{
+		{
+			name: "合法值并规范化",
+			values: Values{
+				"notice_period":  "30",
+				"visa_status":    "work_visa",
+				"available_from": "2025-03-01",
+				"languages":      []interface{}{"中文", "English", "中文"},
+				"hiring_manager": "  王经理 ",
+			},
+			check: func(t *testing.T, v Values) {
+				assert.Equal(t, 30.0, v["notice_period"])
+				assert.Equal(t, []string{"中文", "English"}, v["languages"])
+				assert.Equal(t, "王经理", v["hiring_manager"])
+			},
+		},
+		{name: "缺少必填字段", values: Values{"visa_status": "none"}, wantErr: true},
+		{name: "未定义字段", values: Values{"notice_period": 1.0, "unknown": "x"}, wantErr: true},
+		{name: "枚举值不在范围内", values: Values{"notice_period": 1.0, "visa_status": "tourist"}, wantErr: true},
+		{name: "日期格式错误", values: Values{"notice_period": 1.0, "available_from": "2025/03/01"}, wantErr: true},
+		{name: "数字格式错误", values: Values{"notice_period": "thirty"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := Validate(defs, tt.values, true)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			if tt.check != nil {
+				tt.check(t, v)
+			}
+		})
+	}
+}
+
+func TestPatch(t *testing.T) {
+	defs := testDefinitions()
+	existing := Values{"notice_period": 30.0, "visa_status": "none", "retired_field": "kept"}
+
+	merged, err := Patch(defs, existing, Values{"visa_status": nil, "hiring_manager": "李四"})
+	assert.NoError(t, err)
+	assert.Equal(t, Values{"notice_period": 30.0, "hiring_manager": "李四", "retired_field": "kept"}, merged)
+
+	_, err = Patch(defs, existing, Values{"visa_status": "tourist"})
+	assert.Error(t, err)
+}
+
+func TestValuesScan(t *testing.T) {
+	var v Values
+	assert.NoError(t, v.Scan([]byte(`{"languages":["中文"],"notice_period":15}`)))
+	assert.Equal(t, 15.0, v["notice_period"])
+
+	assert.NoError(t, v.Scan(nil))
+	assert.Empty(t, v)
+}
+
+func TestCells(t *testing.T) {
+	defs := testDefinitions()
+	cells := Cells(defs, Values{"notice_period": 30.0, "languages": []interface{}{"中文", "English"}})
+	assert.Equal(t, []string{"30", "", "", "中文, English", ""}, cells)
+}
+
+func TestApplyFiltersGuardsTypes(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{DryRun: true})
+	assert.NoError(t, err)
+	defs := []Definition{
+		{Key: "years", Label: "年限", Type: TypeNumber},
+		{Key: "start", Label: "到岗日期", Type: TypeDate},
+	}
+
+	tests := []struct {
+		name   string
+		params url.Values
+		want   string
+	}{
+		{"数字等值仅比较数字值", url.Values{"cf.years": {"3"}}, "jsonb_typeof(custom_fields->'years') = 'number'"},
+		{"数字范围仅比较数字值", url.Values{"cf.years.min": {"1"}}, "jsonb_typeof(custom_fields->'years') = 'number'"},
+		{"日期范围仅比较日期格式值", url.Values{"cf.start.max": {"2024-01-01"}}, "custom_fields->>'start' ~ '^[0-9]{4}-[0-9]{2}-[0-9]{2}$'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := ApplyFilters(db.Table("talents"), defs, tt.params, "custom_fields")
+			assert.NoError(t, err)
+			stmt := query.Find(&[]map[string]interface{}{}).Statement
+			assert.Contains(t, stmt.SQL.String(), tt.want)
+		})
+	}
+}
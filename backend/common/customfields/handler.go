@@ -0,0 +1,199 @@
+package customfields
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Handler 自定义字段定义管理接口
+type Handler struct {
+	db *gorm.DB
+}
+
+// NewHandler 创建自定义字段定义处理器
+func NewHandler(db *gorm.DB) *Handler {
+	return &Handler{db: db}
+}
+
+// DefinitionRequest 创建/更新字段定义请求
+type DefinitionRequest struct {
+	EntityType  string   `json:"entity_type"`
+	Key         string   `json:"key"`
+	Label       string   `json:"label" binding:"required"`
+	Type        string   `json:"type"`
+	Options     []string `json:"options"`
+	Required    bool     `json:"required"`
+	Description string   `json:"description"`
+	SortOrder   int      `json:"sort_order"`
+	Active      *bool    `json:"active"`
+}
+
+// RegisterRoutes 注册字段定义路由，adminMiddleware 作用于修改类接口
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup, adminMiddleware ...gin.HandlerFunc) {
+	rg.GET("", h.ListDefinitions)
+	rg.GET("/:id", h.GetDefinition)
+
+	admin := rg.Group("", adminMiddleware...)
+	admin.POST("", h.CreateDefinition)
+	admin.PUT("/:id", h.UpdateDefinition)
+	admin.DELETE("/:id", h.DeleteDefinition)
+}
+
+// ListDefinitions 获取字段定义列表
+func (h *Handler) ListDefinitions(c *gin.Context) {
+	entity := c.Query("entity_type")
+	includeInactive := c.Query("include_inactive") == "true"
+
+	query := h.db.Model(&Definition{})
+	if entity != "" {
+		query = query.Where("entity_type = ?", entity)
+	}
+	if !includeInactive {
+		query = query.Where("active = ?", true)
+	}
+
+	var defs []Definition
+	if err := query.Order("entity_type ASC, sort_order ASC, id ASC").Find(&defs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "Failed to fetch custom fields"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"fields": defs,
+			"total":  len(defs),
+		},
+	})
+}
+
+// GetDefinition 获取单个字段定义
+func (h *Handler) GetDefinition(c *gin.Context) {
+	var def Definition
+	if err := h.db.First(&def, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "Custom field not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data":    def,
+	})
+}
+
+// CreateDefinition 新增字段定义
+func (h *Handler) CreateDefinition(c *gin.Context) {
+	var req DefinitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": err.Error()})
+		return
+	}
+
+	def := Definition{
+		EntityType:  strings.TrimSpace(req.EntityType),
+		Key:         strings.TrimSpace(req.Key),
+		Label:       strings.TrimSpace(req.Label),
+		Type:        strings.TrimSpace(req.Type),
+		Options:     req.Options,
+		Required:    req.Required,
+		Description: req.Description,
+		SortOrder:   req.SortOrder,
+		Active:      true,
+	}
+	if req.Active != nil {
+		def.Active = *req.Active
+	}
+	if err := ValidateDefinition(&def); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": err.Error()})
+		return
+	}
+
+	// 已停用的定义同样占用字段键：历史值仍按原类型保存，换类型复用会让过滤与导出读错数据
+	var count int64
+	h.db.Model(&Definition{}).Where("entity_type = ? AND key = ?", def.EntityType, def.Key).Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "字段键已存在: " + def.Key})
+		return
+	}
+
+	if err := h.db.Create(&def).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "Failed to create custom field: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"code":    0,
+		"message": "Custom field created successfully",
+		"data":    def,
+	})
+}
+
+// UpdateDefinition 更新字段定义（实体类型、字段键和字段类型创建后不可修改）
+func (h *Handler) UpdateDefinition(c *gin.Context) {
+	var def Definition
+	if err := h.db.First(&def, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "Custom field not found"})
+		return
+	}
+
+	var req DefinitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": err.Error()})
+		return
+	}
+
+	if (req.EntityType != "" && req.EntityType != def.EntityType) ||
+		(req.Key != "" && req.Key != def.Key) ||
+		(req.Type != "" && req.Type != def.Type) {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "实体类型、字段键和字段类型创建后不可修改"})
+		return
+	}
+
+	def.Label = strings.TrimSpace(req.Label)
+	def.Options = req.Options
+	def.Required = req.Required
+	def.Description = req.Description
+	def.SortOrder = req.SortOrder
+	if req.Active != nil {
+		def.Active = *req.Active
+	}
+	if err := ValidateDefinition(&def); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": err.Error()})
+		return
+	}
+
+	if err := h.db.Save(&def).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "Failed to update custom field"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "Custom field updated successfully",
+		"data":    def,
+	})
+}
+
+// DeleteDefinition 停用字段定义（已保存的字段值保留，重新启用后可继续使用）
+func (h *Handler) DeleteDefinition(c *gin.Context) {
+	var def Definition
+	if err := h.db.First(&def, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "Custom field not found"})
+		return
+	}
+
+	if err := h.db.Model(&def).Update("active", false).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "Failed to delete custom field"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "Custom field deactivated successfully",
+	})
+}
@@ -0,0 +1,22 @@
+package export
+
+import (
+	"encoding/csv"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WriteCSV 以附件形式输出 CSV（带 UTF-8 BOM，便于 Excel 正确识别中文）
+func WriteCSV(c *gin.Context, name string, header []string, rows [][]string) {
+	filename := name + "_" + time.Now().Format("20060102_150405") + ".csv"
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Status(http.StatusOK)
+
+	_, _ = c.Writer.Write([]byte("\uFEFF"))
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write(header)
+	_ = w.WriteAll(rows)
+}
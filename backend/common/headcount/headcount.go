@@ -0,0 +1,83 @@
+package headcount
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// HiredStatuses 视为已录用的申请状态
+var HiredStatuses = []string{"accepted", "hired"}
+
+// ClosedStatuses 已结束招聘流程的申请状态，其余状态（含职位自定义的阶段）均视为流程中
+var ClosedStatuses = []string{"accepted", "hired", "rejected", "withdrawn"}
+
+// ErrNoOpenings 职位名额已满
+var ErrNoOpenings = errors.New("职位已招满，没有剩余名额")
+
+// IsHired 判断申请状态是否为已录用
+func IsHired(status string) bool {
+	for _, s := range HiredStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// jobOpenings 职位名额快照
+type jobOpenings struct {
+	Headcount int
+	Hired     int
+	Status    string
+}
+
+// CheckOpening 录用前检查职位是否还有剩余名额（历史数据 headcount 为空或 0 时不限名额）
+func CheckOpening(db *gorm.DB, jobID uint) error {
+	var job jobOpenings
+	if err := db.Table("jobs").Select("COALESCE(headcount, 0) AS headcount, COALESCE(hired, 0) AS hired, status").Where("id = ?", jobID).Take(&job).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	hired, err := countHired(db, jobID)
+	if err != nil {
+		return err
+	}
+	if job.Headcount > 0 && int(hired) >= job.Headcount {
+		return ErrNoOpenings
+	}
+	return nil
+}
+
+// SyncJob 按已录用的申请数刷新职位的录用人数，名额招满时职位自动转为 filled
+func SyncJob(db *gorm.DB, jobID uint) error {
+	var job jobOpenings
+	if err := db.Table("jobs").Select("COALESCE(headcount, 0) AS headcount, COALESCE(hired, 0) AS hired, status").Where("id = ?", jobID).Take(&job).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	hired, err := countHired(db, jobID)
+	if err != nil {
+		return err
+	}
+
+	updates := map[string]interface{}{"hired": hired}
+	if job.Headcount > 0 && int(hired) >= job.Headcount && (job.Status == "open" || job.Status == "on_hold") {
+		updates["status"] = "filled"
+	}
+	return db.Table("jobs").Where("id = ?", jobID).Updates(updates).Error
+}
+
+func countHired(db *gorm.DB, jobID uint) (int64, error) {
+	var count int64
+	err := db.Table("applications").
+		Where("job_id = ? AND status IN ? AND deleted_at IS NULL", jobID, HiredStatuses).
+		Count(&count).Error
+	return count, err
+}
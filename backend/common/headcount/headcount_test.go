@@ -0,0 +1,49 @@
+package headcount
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	db.Exec(`CREATE TABLE jobs (id INTEGER PRIMARY KEY, headcount INTEGER, hired INTEGER DEFAULT 0, status TEXT)`)
+	db.Exec(`CREATE TABLE applications (id INTEGER PRIMARY KEY, job_id INTEGER, status TEXT, deleted_at DATETIME)`)
+	return db
+}
+
+func TestSyncJob(t *testing.T) {
+	db := setupTestDB(t)
+	db.Exec(`INSERT INTO jobs (id, headcount, status) VALUES (1, 2, 'open')`)
+	db.Exec(`INSERT INTO applications (job_id, status) VALUES (1, 'accepted'), (1, 'pending')`)
+
+	assert.NoError(t, SyncJob(db, 1))
+	var job jobOpenings
+	db.Table("jobs").Where("id = 1").Take(&job)
+	assert.Equal(t, 1, job.Hired)
+	assert.Equal(t, "open", job.Status)
+	assert.NoError(t, CheckOpening(db, 1))
+
+	db.Exec(`UPDATE applications SET status = 'hired' WHERE status = 'pending'`)
+	assert.NoError(t, SyncJob(db, 1))
+	db.Table("jobs").Where("id = 1").Take(&job)
+	assert.Equal(t, 2, job.Hired)
+	assert.Equal(t, "filled", job.Status, "名额招满后自动转为 filled")
+	assert.ErrorIs(t, CheckOpening(db, 1), ErrNoOpenings)
+}
+
+func TestUnlimitedHeadcount(t *testing.T) {
+	db := setupTestDB(t)
+	db.Exec(`INSERT INTO jobs (id, headcount, status) VALUES (1, 0, 'open')`)
+	db.Exec(`INSERT INTO applications (job_id, status) VALUES (1, 'accepted')`)
+
+	assert.NoError(t, CheckOpening(db, 1))
+	assert.NoError(t, SyncJob(db, 1))
+	var job jobOpenings
+	db.Table("jobs").Where("id = 1").Take(&job)
+	assert.Equal(t, "open", job.Status)
+}
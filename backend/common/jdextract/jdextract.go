@@ -0,0 +1,236 @@
+package jdextract
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"common/skills"
+)
+
+// 职级，与前端职位表单的选项一致
+const (
+	LevelJunior     = "junior"
+	LevelMid        = "mid"
+	LevelSenior     = "senior"
+	LevelExpert     = "expert"
+	LevelManagement = "management"
+)
+
+// Suggestions 从职位描述中提取的建议值
+type Suggestions struct {
+	Skills        []string `json:"skills"`
+	Requirements  []string `json:"requirements"`
+	Level         string   `json:"level"`
+	MinExperience int      `json:"min_experience"` // 最低工作年限，0 表示未提及
+	Education     string   `json:"education"`      // 最低学历要求，空表示未提及或不限
+}
+
+// IsEmpty 是否没有提取到任何内容
+func (s Suggestions) IsEmpty() bool {
+	return len(s.Skills) == 0 && len(s.Requirements) == 0 && s.Level == "" && s.MinExperience == 0 && s.Education == ""
+}
+
+// Extractor 职位描述解析器，沿用简历解析的关键词 + 正则方式，技能基于共享技能字典
+type Extractor struct {
+	skills *skills.Dictionary
+}
+
+// New 创建解析器
+func New(dict *skills.Dictionary) *Extractor {
+	return &Extractor{skills: dict}
+}
+
+// Extract 从职位标题和描述中提取技能、任职要求、职级、工作年限和学历
+func (e *Extractor) Extract(title, description string) Suggestions {
+	text := title + "\n" + description
+
+	s := Suggestions{
+		Skills:        e.skills.Extract(text),
+		Requirements:  ExtractRequirements(description),
+		MinExperience: ExtractYears(text),
+		Education:     ExtractEducation(text),
+	}
+	s.Level = ExtractLevel(title, s.MinExperience)
+	return s
+}
+
+// educationRanks 学历等级，别名归一为规范名称
+var educationRanks = []struct {
+	name    string
+	aliases []string
+	rank    int
+}{
+	{"博士", []string{"博士"}, 5},
+	{"硕士", []string{"硕士", "研究生"}, 4},
+	{"本科", []string{"本科", "学士", "统招本科"}, 3},
+	{"大专", []string{"大专", "专科"}, 2},
+	{"高中", []string{"高中", "中专"}, 1},
+}
+
+// educationPreferred 学历后紧跟“优先”时只是加分项，不作为最低要求
+var educationPreferred = regexp.MustCompile(`^\s*(?:及以上)?(?:学历)?(?:者)?优先`)
+
+// ExtractEducation 提取最低学历要求，如“本科及以上，硕士优先”返回“本科”
+func ExtractEducation(text string) string {
+	if strings.Contains(text, "学历不限") {
+		return ""
+	}
+
+	best, bestRank := "", 0
+	for _, level := range educationRanks {
+		for _, alias := range level.aliases {
+			for idx := 0; ; {
+				pos := strings.Index(text[idx:], alias)
+				if pos < 0 {
+					break
+				}
+				end := idx + pos + len(alias)
+				idx = end
+				if educationPreferred.MatchString(text[end:]) {
+					continue
+				}
+				if bestRank == 0 || level.rank < bestRank {
+					best, bestRank = level.name, level.rank
+				}
+			}
+		}
+	}
+	return best
+}
+
+var (
+	yearsPattern   = regexp.MustCompile(`(\d+|[一二两三四五六七八九十]+)\s*(?:[-~至到]\s*(?:\d+|[一二两三四五六七八九十]+)\s*)?年(?:及?以上)?[^，。；;,\n]{0,12}?经验`)
+	yearsPattern2  = regexp.MustCompile(`经验[：:]?\s*(\d+|[一二两三四五六七八九十]+)\s*年`)
+	yearsPatternEn = regexp.MustCompile(`(?i)(\d+)\+?\s*(?:-\s*\d+\s*)?years?\s+(?:of\s+)?(?:[\w-]+\s+){0,3}?experience`)
+)
+
+var chineseDigits = map[rune]int{
+	'一': 1, '二': 2, '两': 2, '三': 3, '四': 4, '五': 5, '六': 6, '七': 7, '八': 8, '九': 9, '十': 10,
+}
+
+// ExtractYears 提取最低工作年限，如“3-5年Go开发经验”“五年以上工作经验”“5+ years”
+func ExtractYears(text string) int {
+	for _, pattern := range []*regexp.Regexp{yearsPattern, yearsPattern2, yearsPatternEn} {
+		if m := pattern.FindStringSubmatch(text); len(m) > 1 {
+			if years := parseNumber(m[1]); years > 0 && years <= 30 {
+				return years
+			}
+		}
+	}
+	return 0
+}
+
+func parseNumber(s string) int {
+	if n, err := strconv.Atoi(s); err == nil {
+		return n
+	}
+	// 十以内及“十X”“X十”形式的中文数字
+	runes := []rune(s)
+	switch {
+	case len(runes) == 1:
+		return chineseDigits[runes[0]]
+	case len(runes) == 2 && runes[0] == '十':
+		return 10 + chineseDigits[runes[1]]
+	case len(runes) == 2 && runes[1] == '十':
+		return chineseDigits[runes[0]] * 10
+	}
+	return 0
+}
+
+// contributorTitles 名称带“经理/manager”但通常不带团队的岗位，不视为管理岗
+var contributorTitles = []string{
+	"产品经理", "项目经理", "客户经理",
+	"product manager", "project manager", "program manager", "account manager",
+}
+
+// levelKeywords 职级关键词，按优先级排列
+var levelKeywords = []struct {
+	level    string
+	keywords []string
+}{
+	{LevelManagement, []string{"总监", "经理", "主管", "负责人", "manager", "director", "head of"}},
+	{LevelExpert, []string{"专家", "架构师", "首席", "principal", "staff", "architect", "expert"}},
+	{LevelSenior, []string{"资深", "高级", "senior", "sr."}},
+	{LevelJunior, []string{"初级", "助理", "实习", "应届", "校招", "junior", "intern"}},
+	{LevelMid, []string{"中级"}},
+}
+
+// ExtractLevel 推断职级：优先看标题中的职级关键词（描述里常出现“向产品经理汇报”等干扰词，不参与），
+// 没有时按工作年限估算
+func ExtractLevel(title string, years int) string {
+	lower := strings.ToLower(title)
+	// 产品经理、项目经理等是岗位名称而非管理岗，先去掉再匹配，“高级产品经理”仍按“高级”判断
+	for _, t := range contributorTitles {
+		lower = strings.ReplaceAll(lower, t, " ")
+	}
+	for _, l := range levelKeywords {
+		for _, kw := range l.keywords {
+			if strings.Contains(lower, kw) {
+				return l.level
+			}
+		}
+	}
+
+	switch {
+	case years == 0:
+		return ""
+	case years < 2:
+		return LevelJunior
+	case years < 5:
+		return LevelMid
+	case years < 8:
+		return LevelSenior
+	default:
+		return LevelExpert
+	}
+}
+
+var (
+	requirementHeader = regexp.MustCompile(`(?i)^[【\[#\s]*(任职要求|任职资格|岗位要求|职位要求|岗位资格|招聘要求|requirements|qualifications)[】\]\s]*[：:]?\s*$`)
+	sectionHeader     = regexp.MustCompile(`(?i)^[【\[#\s]*(岗位职责|工作职责|职位描述|岗位描述|工作内容|职位福利|福利待遇|加分项|我们提供|responsibilities|benefits|nice to have)[】\]\s]*[：:]?\s*$`)
+	bulletPrefix      = regexp.MustCompile(`^\s*(?:[-*•·]|\d+\s*[.、)）]|[（(]\d+[)）]|[一二三四五六七八九十]+、)\s*`)
+)
+
+// ExtractRequirements 提取“任职要求”段落下的条目
+func ExtractRequirements(description string) []string {
+	lines := strings.Split(strings.ReplaceAll(description, "\r\n", "\n"), "\n")
+
+	var result []string
+	inSection := false
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if requirementHeader.MatchString(line) {
+			inSection = true
+			continue
+		}
+		if sectionHeader.MatchString(line) {
+			inSection = false
+			continue
+		}
+		if !inSection {
+			continue
+		}
+		item := strings.TrimSpace(bulletPrefix.ReplaceAllString(line, ""))
+		item = strings.TrimRight(item, "；;。")
+		if item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// EducationRank 学历等级，未知学历返回 0
+func EducationRank(education string) int {
+	for _, level := range educationRanks {
+		for _, alias := range level.aliases {
+			if education == alias {
+				return level.rank
+			}
+		}
+	}
+	return 0
+}
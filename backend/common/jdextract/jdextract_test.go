@@ -0,0 +1,112 @@
+package jdextract
+
+import (
+	"testing"
+
+	"common/skills"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const sampleJD = `岗位职责：
+1. 负责交易系统后端服务的设计与开发
+2. 向产品经理同步技术方案
+
+任职要求：
+1、本科及以上学历，硕士优先；
+2、3-5年Golang后端开发经验；
+3、熟悉 MySQL、Redis，了解 Kubernetes。
+
+福利待遇：
+- 六险一金`
+
+func TestExtract(t *testing.T) {
+	s := New(skills.Default()).Extract("高级后端开发工程师", sampleJD)
+
+	assert.Contains(t, s.Skills, "Go")
+	assert.Contains(t, s.Skills, "MySQL")
+	assert.Contains(t, s.Skills, "Redis")
+	assert.Equal(t, []string{"本科及以上学历，硕士优先", "3-5年Golang后端开发经验", "熟悉 MySQL、Redis，了解 Kubernetes"}, s.Requirements)
+	assert.Equal(t, LevelSenior, s.Level)
+	assert.Equal(t, 3, s.MinExperience)
+	assert.Equal(t, "本科", s.Education)
+	assert.False(t, s.IsEmpty())
+}
+
+func TestExtractEmpty(t *testing.T) {
+	s := New(skills.Default()).Extract("", "")
+	assert.True(t, s.IsEmpty())
+}
+
+func TestExtractYears(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"3-5年Go开发经验", 3},
+		{"五年以上工作经验", 5},
+		{"两年及以上相关经验", 2},
+		{"工作经验：4年", 4},
+		{"5+ years of backend experience", 5},
+		{"成立于2010年", 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			assert.Equal(t, tt.want, ExtractYears(tt.text))
+		})
+	}
+}
+
+func TestExtractEducation(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"本科及以上学历，硕士优先", "本科"},
+		{"统招本科", "本科"},
+		{"研究生学历", "硕士"},
+		{"大专及以上", "大专"},
+		{"博士优先", ""},
+		{"学历不限，本科优先", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			assert.Equal(t, tt.want, ExtractEducation(tt.text))
+		})
+	}
+}
+
+func TestExtractLevel(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		years int
+		want  string
+	}{
+		{"标题关键词", "资深前端工程师", 0, LevelSenior},
+		{"架构师", "Java 架构师", 3, LevelExpert},
+		{"管理岗", "技术经理", 0, LevelManagement},
+		{"部门经理", "研发部门经理", 0, LevelManagement},
+		{"总监", "产品总监", 0, LevelManagement},
+		{"产品经理不是管理岗", "产品经理", 3, LevelMid},
+		{"项目经理不是管理岗", "项目经理", 0, ""},
+		{"高级产品经理", "高级产品经理", 0, LevelSenior},
+		{"英文产品经理", "Senior Product Manager", 0, LevelSenior},
+		{"英文管理岗", "Engineering Manager", 0, LevelManagement},
+		{"实习", "后端开发实习生", 0, LevelJunior},
+		{"按年限估算", "后端开发工程师", 3, LevelMid},
+		{"无法判断", "后端开发工程师", 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ExtractLevel(tt.title, tt.years))
+		})
+	}
+}
+
+func TestEducationRank(t *testing.T) {
+	assert.Equal(t, 4, EducationRank("研究生"))
+	assert.Equal(t, 3, EducationRank("本科"))
+	assert.Equal(t, 0, EducationRank("未知"))
+}
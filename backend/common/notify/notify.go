@@ -0,0 +1,129 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// 消息类型（与 message-service 保持一致）
+const (
+	TypeSystem   = "system"
+	TypeApproval = "approval"
+	TypeReminder = "reminder"
+)
+
+// Message 站内消息
+type Message struct {
+	SenderID   *uint  `json:"sender_id,omitempty"`
+	ReceiverID uint   `json:"receiver_id"`
+	Title      string `json:"title"`
+	Content    string `json:"content"`
+	Type       string `json:"type"`
+}
+
+// Client message-service 客户端
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient 创建消息服务客户端
+func NewClient(baseURL string) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// NewClientFromEnv 从环境变量 MESSAGE_SERVICE_URL 创建客户端
+func NewClientFromEnv() *Client {
+	baseURL := os.Getenv("MESSAGE_SERVICE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8085"
+	}
+	return NewClient(baseURL)
+}
+
+// Send 发送站内消息
+func (c *Client) Send(msg Message) error {
+	if msg.ReceiverID == 0 {
+		return fmt.Errorf("receiver_id is required")
+	}
+	if msg.Type == "" {
+		msg.Type = TypeSystem
+	}
+
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Post(c.baseURL+"/api/v1/messages", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("message-service returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Event 实时推送事件，由 message-service 通过 WebSocket 发给在线用户，不保存为站内消息
+type Event struct {
+	UserID uint        `json:"user_id"` // 为 0 时广播
+	Type   string      `json:"type"`
+	Data   interface{} `json:"data"`
+}
+
+// Push 推送实时事件
+func (c *Client) Push(event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Post(c.baseURL+"/api/v1/ws/push", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("message-service returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// PushAsync 异步推送实时事件，失败只记录日志
+func (c *Client) PushAsync(event Event) {
+	if c == nil {
+		return
+	}
+	go func() {
+		if err := c.Push(event); err != nil {
+			log.Printf("Warning: Failed to push %s event to user %d: %v", event.Type, event.UserID, err)
+		}
+	}()
+}
+
+// SendAsync 异步发送消息，失败只记录日志，不影响主流程
+func (c *Client) SendAsync(msgs ...Message) {
+	if c == nil || len(msgs) == 0 {
+		return
+	}
+	go func() {
+		for _, msg := range msgs {
+			if err := c.Send(msg); err != nil {
+				log.Printf("Warning: Failed to send message to user %d: %v", msg.ReceiverID, err)
+			}
+		}
+	}()
+}
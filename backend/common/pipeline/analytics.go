@@ -0,0 +1,136 @@
+package pipeline
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Transition 一次阶段流转，统计用
+type Transition struct {
+	ApplicationID uint
+	FromStage     string
+	ToStage       string
+	CreatedAt     time.Time
+}
+
+// FunnelStage 漏斗中的一个阶段
+type FunnelStage struct {
+	Key        string  `json:"key"`
+	Name       string  `json:"name"`
+	Count      int     `json:"count"`      // 曾到达该阶段或之后阶段的申请数
+	Rate       float64 `json:"rate"`       // 相对初始阶段的百分比
+	Conversion float64 `json:"conversion"` // 相对上一阶段的百分比
+}
+
+// Funnel 按流转历史统计漏斗：申请到达过的最远阶段计入此前的每个阶段，
+// 跳过的阶段（如直接从待筛选进入面试）也视为已通过
+func Funnel(c Config, history []Transition) []FunnelStage {
+	stages := c.FunnelStages()
+	index := make(map[string]int, len(stages))
+	for i, s := range stages {
+		index[s.Key] = i
+	}
+
+	furthest := map[uint]int{}
+	for _, t := range history {
+		i, ok := index[Normalize(t.ToStage)]
+		if !ok {
+			i = 0
+		}
+		if prev, seen := furthest[t.ApplicationID]; !seen || i > prev {
+			furthest[t.ApplicationID] = i
+		}
+	}
+
+	counts := make([]int, len(stages))
+	for _, reached := range furthest {
+		for i := 0; i <= reached; i++ {
+			counts[i]++
+		}
+	}
+
+	result := make([]FunnelStage, len(stages))
+	for i, s := range stages {
+		result[i] = FunnelStage{Key: s.Key, Name: s.Name, Count: counts[i]}
+		if counts[0] > 0 {
+			result[i].Rate = percent(counts[i], counts[0])
+		}
+		if i == 0 {
+			result[i].Conversion = result[i].Rate
+		} else if counts[i-1] > 0 {
+			result[i].Conversion = percent(counts[i], counts[i-1])
+		}
+	}
+	return result
+}
+
+// StageDuration 阶段停留时长统计
+type StageDuration struct {
+	Key         string  `json:"key"`
+	Name        string  `json:"name"`
+	Completed   int     `json:"completed"`    // 已离开该阶段的次数
+	Current     int     `json:"current"`      // 当前停留在该阶段的申请数
+	AvgHours    float64 `json:"avg_hours"`    // 已离开的平均停留小时数
+	MedianHours float64 `json:"median_hours"` // 已离开的停留小时数中位数
+	MaxHours    float64 `json:"max_hours"`    // 当前停留最久的小时数，用于发现积压
+}
+
+// TimeInStage 按流转历史统计各流程阶段的停留时长；录用、淘汰、撤回为结束阶段不统计
+func TimeInStage(c Config, history []Transition, now time.Time) []StageDuration {
+	byApp := map[uint][]Transition{}
+	for _, t := range history {
+		byApp[t.ApplicationID] = append(byApp[t.ApplicationID], t)
+	}
+
+	stays := map[string][]float64{}
+	current := map[string]int{}
+	waiting := map[string]float64{}
+	for _, list := range byApp {
+		sort.SliceStable(list, func(i, j int) bool { return list[i].CreatedAt.Before(list[j].CreatedAt) })
+		for i, t := range list {
+			stage := Normalize(t.ToStage)
+			if i+1 < len(list) {
+				stays[stage] = append(stays[stage], list[i+1].CreatedAt.Sub(t.CreatedAt).Hours())
+				continue
+			}
+			current[stage]++
+			waiting[stage] = math.Max(waiting[stage], now.Sub(t.CreatedAt).Hours())
+		}
+	}
+
+	result := make([]StageDuration, 0, len(c.Stages))
+	for _, s := range c.Stages {
+		if IsClosed(s.Key) {
+			continue
+		}
+		d := StageDuration{Key: s.Key, Name: s.Name, Completed: len(stays[s.Key]), Current: current[s.Key], MaxHours: round1(waiting[s.Key])}
+		if hours := stays[s.Key]; len(hours) > 0 {
+			sort.Float64s(hours)
+			var sum float64
+			for _, h := range hours {
+				sum += h
+			}
+			d.AvgHours = round1(sum / float64(len(hours)))
+			d.MedianHours = round1(median(hours))
+		}
+		result = append(result, d)
+	}
+	return result
+}
+
+func median(sorted []float64) float64 {
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+func percent(n, total int) float64 {
+	return round1(float64(n) * 100 / float64(total))
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
@@ -0,0 +1,52 @@
+package pipeline
+
+// 候选人可见的申请状态：自定义流程的内部阶段、淘汰原因等不对候选人展示
+const (
+	CandidateSubmitted = "submitted"
+	CandidateInReview  = "in_review"
+	CandidateInterview = "interview"
+	CandidateOffer     = "offer"
+	CandidateHired     = "hired"
+	CandidateClosed    = "closed"
+	CandidateWithdrawn = "withdrawn"
+)
+
+// candidateLabels 候选人可见状态的显示名称
+var candidateLabels = map[string]string{
+	CandidateSubmitted: "已投递",
+	CandidateInReview:  "处理中",
+	CandidateInterview: "面试中",
+	CandidateOffer:     "已发Offer",
+	CandidateHired:     "已录用",
+	CandidateClosed:    "流程已结束",
+	CandidateWithdrawn: "已撤回",
+}
+
+// CandidateStatus 将申请阶段转换为候选人可见的状态：初始阶段为已投递，
+// 淘汰统一显示为流程已结束，默认流程的面试、Offer 阶段原样展示，其余阶段均为处理中
+func (c Config) CandidateStatus(stage string) string {
+	switch stage = Normalize(stage); stage {
+	case StageHired:
+		return CandidateHired
+	case StageRejected:
+		return CandidateClosed
+	case StageWithdrawn:
+		return CandidateWithdrawn
+	case "interview":
+		return CandidateInterview
+	case "offer":
+		return CandidateOffer
+	}
+	if len(c.Stages) > 0 && stage == c.Entry() {
+		return CandidateSubmitted
+	}
+	return CandidateInReview
+}
+
+// CandidateLabel 候选人可见状态的显示名称
+func CandidateLabel(status string) string {
+	if label, ok := candidateLabels[status]; ok {
+		return label
+	}
+	return status
+}
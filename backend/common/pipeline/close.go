@@ -0,0 +1,69 @@
+package pipeline
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CloseMove 职位关闭时对待处理申请的批量流转
+type CloseMove struct {
+	To            string // 目标阶段，通常为 rejected
+	RejectionCode string // 目标为 rejected 时记录的淘汰原因
+	Reason        string
+	ActorName     string
+}
+
+// CloseEntryApplications 将职位上仍处于初始阶段（待处理）的申请流转到 move.To，并逐条记录流转历史，
+// 返回实际流转的申请 ID。按条件逐条更新，并发的人工流转不会被覆盖
+func CloseEntryApplications(tx *gorm.DB, jobID uint, move CloseMove) ([]uint, error) {
+	config, _, err := Load(tx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	entry := config.Entry()
+	if move.To == "" || move.To == entry {
+		return nil, nil
+	}
+	if !config.Has(move.To) && !IsClosed(move.To) {
+		return nil, fmt.Errorf("职位 %d 的流程中没有阶段 %s", jobID, move.To)
+	}
+	if move.To != StageRejected {
+		move.RejectionCode = ""
+	}
+
+	var ids []uint
+	if err := tx.Table("applications").
+		Where("job_id = ? AND status = ? AND deleted_at IS NULL", jobID, entry).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	moved := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		res := tx.Table("applications").Where("id = ? AND status = ?", id, entry).
+			Updates(map[string]interface{}{"status": move.To, "updated_at": now})
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		if res.RowsAffected == 0 {
+			continue
+		}
+		if err := tx.Table("application_stage_transitions").Create(map[string]interface{}{
+			"created_at":     now,
+			"application_id": id,
+			"job_id":         jobID,
+			"from_stage":     entry,
+			"to_stage":       move.To,
+			"actor_name":     move.ActorName,
+			"reason":         move.Reason,
+			"rejection_code": move.RejectionCode,
+		}).Error; err != nil {
+			return nil, err
+		}
+		moved = append(moved, id)
+	}
+	return moved, nil
+}
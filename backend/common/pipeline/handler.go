@@ -0,0 +1,135 @@
+package pipeline
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Handler 职位招聘流程配置接口，路由参数 :id 为职位 ID
+type Handler struct {
+	db *gorm.DB
+}
+
+// NewHandler 创建招聘流程配置处理器
+func NewHandler(db *gorm.DB) *Handler {
+	return &Handler{db: db}
+}
+
+// jobID 读取并校验路由中的职位 ID，失败时已写入响应
+func (h *Handler) jobID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	var count int64
+	if err == nil {
+		h.db.Table("jobs").Where("id = ? AND deleted_at IS NULL", id).Count(&count)
+	}
+	if count == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "职位不存在"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// GetPipeline 获取职位的招聘流程，custom 为 false 表示使用默认流程
+func (h *Handler) GetPipeline(c *gin.Context) {
+	id, ok := h.jobID(c)
+	if !ok {
+		return
+	}
+	config, custom, err := Load(h.db, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "读取招聘流程失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"job_id":            id,
+			"custom":            custom,
+			"config":            config,
+			"rejection_reasons": RejectionReasons,
+		},
+	})
+}
+
+// SavePipeline 保存职位的招聘流程。仍有申请处于某阶段时不能删除该阶段
+func (h *Handler) SavePipeline(c *gin.Context) {
+	id, ok := h.jobID(c)
+	if !ok {
+		return
+	}
+	var config Config
+	if err := c.ShouldBindJSON(&config); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": err.Error()})
+		return
+	}
+	if err := config.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": err.Error()})
+		return
+	}
+	if missing := h.stagesInUse(id, config); len(missing) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "以下阶段仍有申请，不能删除：" + strings.Join(missing, "、")})
+		return
+	}
+
+	p := JobPipeline{JobID: id}
+	h.db.Where("job_id = ?", id).Take(&p)
+	p.Config = config
+	if userID, exists := c.Get("user_id"); exists {
+		p.UpdatedBy, _ = userID.(uint)
+	}
+	if err := h.db.Save(&p).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "保存招聘流程失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data":    p,
+	})
+}
+
+// ResetPipeline 删除职位的自定义流程，恢复默认流程
+func (h *Handler) ResetPipeline(c *gin.Context) {
+	id, ok := h.jobID(c)
+	if !ok {
+		return
+	}
+	if missing := h.stagesInUse(id, Default()); len(missing) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "以下阶段仍有申请，不能恢复默认流程：" + strings.Join(missing, "、")})
+		return
+	}
+	if err := h.db.Where("job_id = ?", id).Delete(&JobPipeline{}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "恢复默认流程失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data":    gin.H{"job_id": id, "custom": false, "config": Default()},
+	})
+}
+
+// stagesInUse 职位申请当前所处、但新流程中没有的阶段
+func (h *Handler) stagesInUse(jobID uint, config Config) []string {
+	var statuses []string
+	h.db.Table("applications").Where("job_id = ? AND deleted_at IS NULL", jobID).
+		Distinct().Pluck("status", &statuses)
+
+	var missing []string
+	for _, status := range statuses {
+		if status != "" && !config.Has(status) {
+			missing = append(missing, status)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
@@ -0,0 +1,286 @@
+// Package pipeline 申请招聘流程：每个职位可配置阶段及允许的流转，未配置时使用默认流程。
+// 阶段流转由 resume-service 记录到 application_stage_transitions，漏斗与阶段停留时长据此统计。
+package pipeline
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 系统阶段：录用计入职位名额，淘汰、撤回结束流程，自定义流程必须沿用这些键
+const (
+	StageHired     = "hired"
+	StageRejected  = "rejected"
+	StageWithdrawn = "withdrawn"
+)
+
+// aliases 历史数据中的旧状态名
+var aliases = map[string]string{
+	"accepted":     StageHired,
+	"reviewing":    "reviewed",
+	"interviewing": "interview",
+	"interviewed":  "interview",
+	"offered":      "offer",
+}
+
+// Normalize 将旧状态名转换为当前阶段键
+func Normalize(status string) string {
+	if key, ok := aliases[status]; ok {
+		return key
+	}
+	return status
+}
+
+// IsClosed 阶段是否已结束招聘流程（录用、淘汰、撤回）
+func IsClosed(stage string) bool {
+	switch Normalize(stage) {
+	case StageHired, StageRejected, StageWithdrawn:
+		return true
+	}
+	return false
+}
+
+var keyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,19}$`)
+
+// Stage 流程阶段
+type Stage struct {
+	Key  string `json:"key"`  // 存储在 applications.status 中
+	Name string `json:"name"` // 显示名称
+}
+
+// Config 职位的招聘流程：Stages 按顺序排列，第一个为新申请的初始阶段；
+// Transitions 为每个阶段允许流转到的阶段
+type Config struct {
+	Stages      []Stage             `json:"stages"`
+	Transitions map[string][]string `json:"transitions"`
+}
+
+// Default 默认流程：待筛选 → 已筛选 → 面试中 → 已发 Offer → 已录用，流程中随时可淘汰或撤回，
+// 淘汰、撤回的申请可重新激活，已录用的候选人可撤回（如未到岗）
+func Default() Config {
+	return Config{
+		Stages: []Stage{
+			{Key: "pending", Name: "待筛选"},
+			{Key: "reviewed", Name: "已筛选"},
+			{Key: "interview", Name: "面试中"},
+			{Key: "offer", Name: "已发Offer"},
+			{Key: StageHired, Name: "已录用"},
+			{Key: StageRejected, Name: "已淘汰"},
+			{Key: StageWithdrawn, Name: "已撤回"},
+		},
+		Transitions: map[string][]string{
+			"pending":      {"reviewed", "interview", StageRejected, StageWithdrawn},
+			"reviewed":     {"interview", StageRejected, StageWithdrawn},
+			"interview":    {"offer", StageRejected, StageWithdrawn},
+			"offer":        {StageHired, StageRejected, StageWithdrawn},
+			StageHired:     {StageWithdrawn},
+			StageRejected:  {"pending"},
+			StageWithdrawn: {"pending"},
+		},
+	}
+}
+
+// Value 实现 driver.Valuer
+func (c Config) Value() (driver.Value, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan 实现 sql.Scanner
+func (c *Config) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	case nil:
+		*c = Config{}
+		return nil
+	default:
+		return fmt.Errorf("unsupported pipeline config type %T", src)
+	}
+	return json.Unmarshal(data, c)
+}
+
+// Validate 校验流程配置
+func (c Config) Validate() error {
+	if len(c.Stages) == 0 {
+		return errors.New("流程至少需要一个阶段")
+	}
+	seen := make(map[string]bool, len(c.Stages))
+	for _, s := range c.Stages {
+		if !keyPattern.MatchString(s.Key) {
+			return fmt.Errorf("阶段键 %q 格式错误：需以小写字母开头，只含小写字母、数字和下划线，最长 20 个字符", s.Key)
+		}
+		if _, legacy := aliases[s.Key]; legacy {
+			return fmt.Errorf("阶段键 %q 为保留的旧状态名，请换一个", s.Key)
+		}
+		if strings.TrimSpace(s.Name) == "" {
+			return fmt.Errorf("阶段 %s 缺少名称", s.Key)
+		}
+		if seen[s.Key] {
+			return fmt.Errorf("阶段 %s 重复", s.Key)
+		}
+		seen[s.Key] = true
+	}
+	if IsClosed(c.Stages[0].Key) {
+		return errors.New("初始阶段不能是录用、淘汰或撤回")
+	}
+	for _, key := range []string{StageHired, StageRejected} {
+		if !seen[key] {
+			return fmt.Errorf("流程必须包含 %s 阶段", key)
+		}
+	}
+	for from, targets := range c.Transitions {
+		if !seen[from] {
+			return fmt.Errorf("流转规则引用了不存在的阶段 %s", from)
+		}
+		for _, to := range targets {
+			if !seen[to] {
+				return fmt.Errorf("流转规则引用了不存在的阶段 %s", to)
+			}
+			if to == from {
+				return fmt.Errorf("阶段 %s 不能流转到自身", from)
+			}
+		}
+	}
+	return nil
+}
+
+// Entry 新申请的初始阶段
+func (c Config) Entry() string {
+	return c.Stages[0].Key
+}
+
+// Has 流程是否包含该阶段
+func (c Config) Has(stage string) bool {
+	return c.Index(stage) >= 0
+}
+
+// Index 阶段在流程中的位置，不存在时返回 -1
+func (c Config) Index(stage string) int {
+	stage = Normalize(stage)
+	for i, s := range c.Stages {
+		if s.Key == stage {
+			return i
+		}
+	}
+	return -1
+}
+
+// Name 阶段显示名称，不在流程中时返回键本身
+func (c Config) Name(stage string) string {
+	if i := c.Index(stage); i >= 0 {
+		return c.Stages[i].Name
+	}
+	return stage
+}
+
+// CanMove 检查申请能否从 from 流转到 to。to 必须是流程中的阶段；
+// from 不在流程中（流程调整前的历史状态）时允许流转到任意阶段，以便纠正
+func (c Config) CanMove(from, to string) error {
+	if !c.Has(to) || Normalize(to) != to {
+		return fmt.Errorf("阶段 %s 不在该职位的招聘流程中", to)
+	}
+	from = Normalize(from)
+	if from == to {
+		return fmt.Errorf("申请已处于%s阶段", c.Name(to))
+	}
+	if !c.Has(from) {
+		return nil
+	}
+	for _, allowed := range c.Transitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("不允许从%s流转到%s", c.Name(from), c.Name(to))
+}
+
+// FunnelStages 漏斗统计的阶段：按流程顺序排除淘汰、撤回
+func (c Config) FunnelStages() []Stage {
+	stages := make([]Stage, 0, len(c.Stages))
+	for _, s := range c.Stages {
+		if s.Key != StageRejected && s.Key != StageWithdrawn {
+			stages = append(stages, s)
+		}
+	}
+	return stages
+}
+
+// RejectionReason 淘汰原因
+type RejectionReason struct {
+	Code  string `json:"code"`
+	Label string `json:"label"`
+}
+
+// 系统自动淘汰时使用的原因代码
+const (
+	RejectionScreening      = "screening_knockout" // 申请时筛选问题不满足淘汰条件
+	RejectionPositionClosed = "position_closed"    // 职位关闭时仍未处理
+)
+
+// RejectionReasons 淘汰时必须选择的原因代码
+var RejectionReasons = []RejectionReason{
+	{Code: "skills_mismatch", Label: "技能不匹配"},
+	{Code: "experience_insufficient", Label: "经验不足"},
+	{Code: "education_mismatch", Label: "学历不符"},
+	{Code: "salary_mismatch", Label: "薪资期望不符"},
+	{Code: "interview_failed", Label: "面试未通过"},
+	{Code: "culture_fit", Label: "文化匹配度低"},
+	{Code: "no_show", Label: "未参加面试"},
+	{Code: "position_filled", Label: "职位已招满"},
+	{Code: RejectionPositionClosed, Label: "职位已关闭"},
+	{Code: "duplicate", Label: "重复申请"},
+	{Code: RejectionScreening, Label: "筛选问题未通过"},
+	{Code: "other", Label: "其他"},
+}
+
+// ValidRejectionCode 原因代码是否有效
+func ValidRejectionCode(code string) bool {
+	for _, r := range RejectionReasons {
+		if r.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// JobPipeline 职位自定义的招聘流程（由 job-service 维护，未配置的职位使用默认流程）
+type JobPipeline struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	JobID     uint      `gorm:"uniqueIndex;not null" json:"job_id"`
+	Config    Config    `gorm:"type:jsonb;not null" json:"config"`
+	UpdatedBy uint      `json:"updated_by"`
+}
+
+// TableName 设置表名
+func (JobPipeline) TableName() string {
+	return "job_pipelines"
+}
+
+// Load 读取职位的招聘流程，未配置时返回默认流程
+func Load(db *gorm.DB, jobID uint) (Config, bool, error) {
+	var p JobPipeline
+	err := db.Where("job_id = ?", jobID).Take(&p).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Default(), false, nil
+	}
+	if err != nil {
+		return Config{}, false, err
+	}
+	return p.Config, true, nil
+}
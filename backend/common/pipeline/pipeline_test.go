@@ -0,0 +1,231 @@
+package pipeline
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestDefaultIsValid(t *testing.T) {
+	assert.NoError(t, Default().Validate())
+	assert.Equal(t, "pending", Default().Entry())
+}
+
+func TestValidate(t *testing.T) {
+	base := func() Config {
+		return Config{
+			Stages: []Stage{
+				{Key: "new", Name: "新申请"},
+				{Key: "phone_screen", Name: "电话面试"},
+				{Key: StageHired, Name: "已录用"},
+				{Key: StageRejected, Name: "已淘汰"},
+			},
+			Transitions: map[string][]string{
+				"new":          {"phone_screen", StageRejected},
+				"phone_screen": {StageHired, StageRejected},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(c *Config)
+		wantErr string
+	}{
+		{name: "自定义流程", modify: func(c *Config) {}},
+		{name: "空流程", modify: func(c *Config) { c.Stages = nil }, wantErr: "至少需要一个阶段"},
+		{name: "阶段键格式错误", modify: func(c *Config) { c.Stages[1].Key = "Phone Screen" }, wantErr: "格式错误"},
+		{name: "使用旧状态名", modify: func(c *Config) { c.Stages[1].Key = "accepted" }, wantErr: "保留的旧状态名"},
+		{name: "阶段重复", modify: func(c *Config) { c.Stages[1].Key = "new" }, wantErr: "重复"},
+		{name: "缺少名称", modify: func(c *Config) { c.Stages[1].Name = " " }, wantErr: "缺少名称"},
+		{name: "初始阶段为结束阶段", modify: func(c *Config) { c.Stages[0], c.Stages[3] = c.Stages[3], c.Stages[0] }, wantErr: "初始阶段"},
+		{name: "缺少淘汰阶段", modify: func(c *Config) { c.Stages = c.Stages[:3] }, wantErr: "rejected"},
+		{name: "流转到未知阶段", modify: func(c *Config) { c.Transitions["new"] = []string{"offer"} }, wantErr: "不存在的阶段 offer"},
+		{name: "流转到自身", modify: func(c *Config) { c.Transitions["new"] = []string{"new"} }, wantErr: "不能流转到自身"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := base()
+			tt.modify(&c)
+			err := c.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCanMove(t *testing.T) {
+	c := Default()
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		wantErr string
+	}{
+		{name: "按流程推进", from: "pending", to: "reviewed"},
+		{name: "跳过筛选直接面试", from: "pending", to: "interview"},
+		{name: "未发 Offer 不能录用", from: "interview", to: StageHired, wantErr: "不允许从面试中流转到已录用"},
+		{name: "旧状态名按别名处理", from: "interviewing", to: "offer"},
+		{name: "已淘汰可重新激活", from: StageRejected, to: "pending"},
+		{name: "目标阶段不在流程中", from: "pending", to: "assessment", wantErr: "不在该职位的招聘流程中"},
+		{name: "目标使用旧状态名", from: "offer", to: "accepted", wantErr: "不在该职位的招聘流程中"},
+		{name: "已处于目标阶段", from: "accepted", to: StageHired, wantErr: "已处于已录用阶段"},
+		{name: "流程外的历史状态可流转到任意阶段", from: "archived", to: "offer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := c.CanMove(tt.from, tt.to)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigScan(t *testing.T) {
+	v, err := Default().Value()
+	assert.NoError(t, err)
+
+	var c Config
+	assert.NoError(t, c.Scan([]byte(v.(string))))
+	assert.Equal(t, Default(), c)
+}
+
+func TestFunnel(t *testing.T) {
+	at := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	history := []Transition{
+		{ApplicationID: 1, ToStage: "pending", CreatedAt: at},
+		{ApplicationID: 1, FromStage: "pending", ToStage: "reviewed", CreatedAt: at},
+		{ApplicationID: 1, FromStage: "reviewed", ToStage: StageRejected, CreatedAt: at},
+		{ApplicationID: 2, ToStage: "pending", CreatedAt: at},
+		{ApplicationID: 2, FromStage: "pending", ToStage: "interview", CreatedAt: at},
+		{ApplicationID: 2, FromStage: "interview", ToStage: "offer", CreatedAt: at},
+		{ApplicationID: 2, FromStage: "offer", ToStage: "accepted", CreatedAt: at},
+		{ApplicationID: 3, ToStage: "pending", CreatedAt: at},
+		{ApplicationID: 4, ToStage: "pending", CreatedAt: at},
+	}
+
+	funnel := Funnel(Default(), history)
+	counts := map[string]int{}
+	for _, s := range funnel {
+		counts[s.Key] = s.Count
+	}
+	assert.Len(t, funnel, 5, "淘汰、撤回不在漏斗中")
+	assert.Equal(t, map[string]int{"pending": 4, "reviewed": 2, "interview": 1, "offer": 1, StageHired: 1}, counts, "跳过的阶段也计入，淘汰前到达的阶段保留")
+	assert.Equal(t, 50.0, funnel[1].Rate)
+	assert.Equal(t, 50.0, funnel[2].Conversion)
+	assert.Equal(t, 100.0, funnel[4].Conversion)
+}
+
+func TestTimeInStage(t *testing.T) {
+	at := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	history := []Transition{
+		{ApplicationID: 1, ToStage: "pending", CreatedAt: at},
+		{ApplicationID: 1, ToStage: "reviewed", CreatedAt: at.Add(10 * time.Hour)},
+		{ApplicationID: 1, ToStage: StageRejected, CreatedAt: at.Add(20 * time.Hour)},
+		{ApplicationID: 2, ToStage: "reviewed", CreatedAt: at.Add(30 * time.Hour)},
+		{ApplicationID: 2, ToStage: "pending", CreatedAt: at},
+		{ApplicationID: 3, ToStage: "pending", CreatedAt: at.Add(40 * time.Hour)},
+	}
+
+	stats := TimeInStage(Default(), history, at.Add(48*time.Hour))
+	assert.Len(t, stats, 4, "结束阶段不统计")
+
+	pending := stats[0]
+	assert.Equal(t, "pending", pending.Key)
+	assert.Equal(t, 2, pending.Completed, "流转记录按时间排序")
+	assert.Equal(t, 20.0, pending.AvgHours)
+	assert.Equal(t, 20.0, pending.MedianHours)
+	assert.Equal(t, 1, pending.Current)
+	assert.Equal(t, 8.0, pending.MaxHours)
+
+	reviewed := stats[1]
+	assert.Equal(t, 1, reviewed.Completed)
+	assert.Equal(t, 10.0, reviewed.AvgHours)
+	assert.Equal(t, 1, reviewed.Current)
+	assert.Equal(t, 18.0, reviewed.MaxHours)
+}
+
+func TestCandidateStatus(t *testing.T) {
+	custom := Config{Stages: []Stage{{Key: "new", Name: "新申请"}, {Key: "phone_screen", Name: "电话面试"}, {Key: StageHired, Name: "已录用"}, {Key: StageRejected, Name: "已淘汰"}}}
+
+	tests := []struct {
+		name   string
+		config Config
+		stage  string
+		want   string
+	}{
+		{name: "初始阶段", config: Default(), stage: "pending", want: CandidateSubmitted},
+		{name: "已筛选", config: Default(), stage: "reviewed", want: CandidateInReview},
+		{name: "旧状态名", config: Default(), stage: "interviewing", want: CandidateInterview},
+		{name: "Offer", config: Default(), stage: "offer", want: CandidateOffer},
+		{name: "淘汰不展示原因", config: Default(), stage: StageRejected, want: CandidateClosed},
+		{name: "撤回", config: Default(), stage: StageWithdrawn, want: CandidateWithdrawn},
+		{name: "自定义流程初始阶段", config: custom, stage: "new", want: CandidateSubmitted},
+		{name: "自定义流程内部阶段", config: custom, stage: "phone_screen", want: CandidateInReview},
+		{name: "自定义流程中的 pending", config: custom, stage: "pending", want: CandidateInReview},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.config.CandidateStatus(tt.stage))
+		})
+	}
+	assert.Equal(t, "流程已结束", CandidateLabel(CandidateClosed))
+}
+
+func TestCloseEntryApplications(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&JobPipeline{}))
+	db.Exec(`CREATE TABLE applications (id INTEGER PRIMARY KEY, job_id INTEGER, status TEXT, updated_at DATETIME, deleted_at DATETIME)`)
+	db.Exec(`CREATE TABLE application_stage_transitions (id INTEGER PRIMARY KEY, created_at DATETIME, application_id INTEGER, job_id INTEGER,
+		from_stage TEXT, to_stage TEXT, actor_id INTEGER, actor_name TEXT, reason TEXT, rejection_code TEXT)`)
+
+	custom := Config{Stages: []Stage{{Key: "new", Name: "新申请"}, {Key: "interview", Name: "面试"}, {Key: StageHired, Name: "已录用"}, {Key: StageRejected, Name: "已淘汰"}}}
+	require.NoError(t, db.Create(&JobPipeline{JobID: 2, Config: custom}).Error)
+	db.Exec(`INSERT INTO applications (id, job_id, status) VALUES (1, 1, 'pending'), (2, 1, 'interview'), (3, 2, 'new'), (4, 2, 'pending'), (5, 1, 'pending')`)
+	db.Exec(`UPDATE applications SET deleted_at = CURRENT_TIMESTAMP WHERE id = 5`)
+
+	move := CloseMove{To: StageRejected, RejectionCode: RejectionPositionClosed, Reason: "职位已关闭", ActorName: "系统"}
+	moved, err := CloseEntryApplications(db, 1, move)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{1}, moved, "只处理默认流程初始阶段的申请，已删除的不处理")
+
+	moved, err = CloseEntryApplications(db, 2, move)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{3}, moved, "按职位的自定义流程确定初始阶段")
+
+	var statuses []string
+	db.Table("applications").Order("id").Pluck("status", &statuses)
+	assert.Equal(t, []string{StageRejected, "interview", StageRejected, "pending", "pending"}, statuses)
+
+	var transitions []struct {
+		ApplicationID uint
+		FromStage     string
+		ToStage       string
+		ActorName     string
+		RejectionCode string
+	}
+	db.Table("application_stage_transitions").Order("application_id").Find(&transitions)
+	require.Len(t, transitions, 2)
+	assert.Equal(t, "pending", transitions[0].FromStage)
+	assert.Equal(t, "new", transitions[1].FromStage)
+	assert.Equal(t, StageRejected, transitions[1].ToStage)
+	assert.Equal(t, "系统", transitions[1].ActorName)
+	assert.Equal(t, RejectionPositionClosed, transitions[1].RejectionCode)
+
+	_, err = CloseEntryApplications(db, 2, CloseMove{To: "archived"})
+	assert.Error(t, err, "目标阶段不在流程中")
+}
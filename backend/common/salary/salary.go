@@ -0,0 +1,453 @@
+package salary
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// 发薪周期
+const (
+	PeriodHour  = "hour"
+	PeriodDay   = "day"
+	PeriodMonth = "month"
+	PeriodYear  = "year"
+)
+
+// DefaultCurrency 默认币种
+const DefaultCurrency = "CNY"
+
+// yearlyWanThreshold 未注明周期、以万为单位的薪资下限达到此金额时按年薪处理
+const yearlyWanThreshold = 50000
+
+// 年化换算使用的工作时长
+const (
+	workDaysPerYear = 250
+	hoursPerDay     = 8
+)
+
+// Range 结构化薪资，金额单位为元（或对应币种的基本单位），Max 为 0 表示上不封顶
+type Range struct {
+	Min        float64 `json:"min"`
+	Max        float64 `json:"max"`
+	Currency   string  `gorm:"size:3" json:"currency"`
+	Period     string  `gorm:"size:10" json:"period"` // hour, day, month, year
+	Months     int     `json:"months"`                // 每年发薪月数，如 14 薪，仅按月发薪时有效
+	Negotiable bool    `json:"negotiable"`            // 面议
+	AnnualMin  float64 `gorm:"index" json:"annual_min"`
+	AnnualMax  float64 `gorm:"index" json:"annual_max"`
+}
+
+// IsZero 是否未填写任何薪资信息
+func (r Range) IsZero() bool {
+	return r.Min == 0 && r.Max == 0 && !r.Negotiable
+}
+
+// Known 是否有可比较的金额
+func (r Range) Known() bool {
+	return !r.Negotiable && (r.Min > 0 || r.Max > 0)
+}
+
+// Normalize 补全默认值并计算年化金额
+func (r Range) Normalize() Range {
+	if r.Currency == "" {
+		r.Currency = DefaultCurrency
+	}
+	r.Currency = strings.ToUpper(r.Currency)
+	if r.Period == "" {
+		r.Period = PeriodMonth
+	}
+	if r.Period == PeriodMonth && r.Months <= 0 {
+		r.Months = 12
+	}
+	if r.Period != PeriodMonth {
+		r.Months = 0
+	}
+	if r.Max > 0 && r.Min > r.Max {
+		r.Min, r.Max = r.Max, r.Min
+	}
+	r.AnnualMin = r.annualize(r.Min)
+	r.AnnualMax = r.annualize(r.Max)
+	return r
+}
+
+// Validate 校验结构化薪资
+func (r Range) Validate() error {
+	switch r.Period {
+	case "", PeriodHour, PeriodDay, PeriodMonth, PeriodYear:
+	default:
+		return fmt.Errorf("不支持的薪资周期: %s", r.Period)
+	}
+	if r.Min < 0 || r.Max < 0 {
+		return fmt.Errorf("薪资不能为负数")
+	}
+	if r.Months < 0 || r.Months > 24 {
+		return fmt.Errorf("发薪月数超出范围: %d", r.Months)
+	}
+	return nil
+}
+
+func (r Range) annualize(v float64) float64 {
+	switch r.Period {
+	case PeriodHour:
+		return v * hoursPerDay * workDaysPerYear
+	case PeriodDay:
+		return v * workDaysPerYear
+	case PeriodYear:
+		return v
+	default:
+		return v * float64(r.Months)
+	}
+}
+
+// String 格式化为常见的薪资文本，如 15-25K·14薪、20-30万/年
+func (r Range) String() string {
+	if r.Negotiable {
+		return "面议"
+	}
+	if !r.Known() {
+		return ""
+	}
+
+	prefix := ""
+	if r.Currency != "" && r.Currency != DefaultCurrency {
+		prefix = r.Currency + " "
+	}
+
+	var unit float64
+	var suffix string
+	switch r.Period {
+	case PeriodYear:
+		unit, suffix = 10000, "万/年"
+	case PeriodDay:
+		unit, suffix = 1, "元/天"
+	case PeriodHour:
+		unit, suffix = 1, "元/时"
+	default:
+		unit, suffix = 1000, "K"
+		if r.Months > 12 {
+			suffix += "·" + strconv.Itoa(r.Months) + "薪"
+		}
+	}
+
+	switch {
+	case r.Max == 0:
+		return prefix + formatAmount(r.Min/unit) + suffix + "以上"
+	case r.Min == 0:
+		return prefix + formatAmount(r.Max/unit) + suffix + "以下"
+	case r.Min == r.Max:
+		return prefix + formatAmount(r.Min/unit) + suffix
+	}
+	return prefix + formatAmount(r.Min/unit) + "-" + formatAmount(r.Max/unit) + suffix
+}
+
+func formatAmount(v float64) string {
+	return strconv.FormatFloat(math.Round(v*10)/10, 'f', -1, 64)
+}
+
+var (
+	numberPattern = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*([kK千wW万]?)`)
+	monthsPattern = regexp.MustCompile(`(\d{2})\s*薪`)
+)
+
+// Parse 解析自由文本薪资，如 "15k-25k"、"20-30万"、"25-40K·14薪"、"300元/天"、"面议"
+// 无法识别时返回 false
+func Parse(text string) (Range, bool) {
+	s := strings.TrimSpace(text)
+	if s == "" {
+		return Range{}, false
+	}
+	if strings.Contains(s, "面议") || strings.EqualFold(s, "negotiable") {
+		return Range{Negotiable: true}.Normalize(), true
+	}
+
+	r := Range{Currency: detectCurrency(s)}
+
+	// 发薪月数（14薪），先取出避免被当作金额
+	if m := monthsPattern.FindStringSubmatch(s); m != nil {
+		r.Months, _ = strconv.Atoi(m[1])
+		s = monthsPattern.ReplaceAllString(s, "")
+	}
+
+	matches := numberPattern.FindAllStringSubmatch(s, 2)
+	if len(matches) == 0 {
+		return Range{}, false
+	}
+
+	// "15-25K" 中单位只写在最后，前面的数字沿用同一单位；"8千-1.2万" 则各自按自身单位换算
+	unit := ""
+	units := make([]string, len(matches))
+	for i := len(matches) - 1; i >= 0; i-- {
+		if matches[i][2] != "" {
+			unit = matches[i][2]
+		}
+		units[i] = unit
+	}
+
+	values := make([]float64, len(matches))
+	for i, m := range matches {
+		v, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			return Range{}, false
+		}
+		values[i] = v * multiplier(units[i])
+	}
+
+	r.Period = detectPeriod(s, units[0], r.Months, r.Currency, values[0])
+
+	switch {
+	case len(values) == 2:
+		r.Min, r.Max = values[0], values[1]
+	case containsAny(s, "以上", "起", "+", "above"):
+		r.Min = values[0]
+	case containsAny(s, "以下", "以内", "below"):
+		r.Max = values[0]
+	default:
+		r.Min, r.Max = values[0], values[0]
+	}
+
+	if err := r.Validate(); err != nil {
+		return Range{}, false
+	}
+	return r.Normalize(), true
+}
+
+// FromText 解析文本，无法识别时返回零值
+func FromText(text string) Range {
+	r, _ := Parse(text)
+	return r
+}
+
+func multiplier(unit string) float64 {
+	switch unit {
+	case "k", "K", "千":
+		return 1000
+	case "w", "W", "万":
+		return 10000
+	}
+	return 1
+}
+
+func detectCurrency(s string) string {
+	upper := strings.ToUpper(s)
+	switch {
+	case strings.Contains(upper, "USD") || strings.Contains(s, "$") || strings.Contains(s, "美元"):
+		return "USD"
+	case strings.Contains(upper, "EUR") || strings.Contains(s, "€") || strings.Contains(s, "欧元"):
+		return "EUR"
+	case strings.Contains(upper, "HKD") || strings.Contains(s, "港币"):
+		return "HKD"
+	}
+	return DefaultCurrency
+}
+
+// detectPeriod 推断发薪周期：优先取明确的周期后缀（/月、年薪 等）与发薪月数（·13薪），
+// 否则按下限金额的单位推断，unit 为下限实际使用的单位
+func detectPeriod(s, unit string, months int, currency string, first float64) string {
+	lower := strings.ToLower(s)
+	switch {
+	case containsAny(lower, "/年", "每年", "年薪", "/year", "per year", "annual", "/yr"):
+		return PeriodYear
+	case containsAny(lower, "/月", "每月", "月薪", "/month", "per month", "/mo"):
+		return PeriodMonth
+	case containsAny(lower, "/天", "每天", "日薪", "/day", "per day"):
+		return PeriodDay
+	case containsAny(lower, "/时", "/小时", "时薪", "/hour", "per hour", "/hr"):
+		return PeriodHour
+	}
+	// 写明发薪月数的只能是月薪，如 "2-3万·13薪"
+	if months > 0 {
+		return PeriodMonth
+	}
+	// 国内习惯："20-30万" 指年薪，"15-25K"、"8千-1.2万"、"1.5-2万" 指月薪：以万计的金额下限达到 5 万才按年薪处理
+	if (unit == "w" || unit == "W" || unit == "万") && first >= yearlyWanThreshold {
+		return PeriodYear
+	}
+	// 无单位的大额数字（如 300000）按年薪处理；外币薪资（如 $120k）习惯按年计
+	if unit == "" && first >= 200000 {
+		return PeriodYear
+	}
+	if currency != DefaultCurrency && first >= 20000 {
+		return PeriodYear
+	}
+	return PeriodMonth
+}
+
+func containsAny(s string, subs ...string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match 计算期望薪资与职位薪资的匹配度（0-1）及说明，
+// 任一方未知、面议或币种不同时返回中性分 0.5
+func Match(expected, offered Range) (float64, string) {
+	if !expected.Known() || !offered.Known() {
+		if expected.Negotiable || offered.Negotiable {
+			return 0.6, "薪资面议"
+		}
+		return 0.5, ""
+	}
+	expected, offered = expected.Normalize(), offered.Normalize()
+	if expected.Currency != offered.Currency {
+		return 0.5, "薪资币种不同，无法比较"
+	}
+
+	eMin, eMax := bounds(expected)
+	oMin, oMax := bounds(offered)
+
+	switch {
+	case oMax >= eMax:
+		if oMin > eMax && !math.IsInf(eMax, 1) {
+			return 0.9, "职位薪资高于期望"
+		}
+		return 1.0, "职位薪资完全覆盖期望"
+	case oMax >= eMin:
+		if eMax == eMin || math.IsInf(eMax, 1) {
+			return 0.8, "职位薪资满足期望下限"
+		}
+		ratio := (oMax - eMin) / (eMax - eMin)
+		return 0.6 + 0.4*ratio, "薪资范围部分重叠"
+	default:
+		gap := (eMin - oMax) / eMin
+		score := math.Max(0, 0.5-gap*2)
+		return score, fmt.Sprintf("职位薪资低于期望约%d%%", int(math.Round(gap*100)))
+	}
+}
+
+// bounds 返回年化区间，未填写的一端按开区间处理
+func bounds(r Range) (float64, float64) {
+	lo, hi := r.AnnualMin, r.AnnualMax
+	if hi == 0 {
+		hi = math.Inf(1)
+	}
+	return lo, hi
+}
+
+// FilterBounds 将列表筛选参数（salary_min/salary_max，默认按月薪、12 个月）换算为年化金额
+func FilterBounds(minText, maxText, period string) (float64, float64, error) {
+	r := Range{Period: period}
+	if err := r.Validate(); err != nil {
+		return 0, 0, err
+	}
+	var err error
+	if minText != "" {
+		if r.Min, err = parseAmount(minText); err != nil {
+			return 0, 0, fmt.Errorf("salary_min 格式错误: %s", minText)
+		}
+	}
+	if maxText != "" {
+		if r.Max, err = parseAmount(maxText); err != nil {
+			return 0, 0, fmt.Errorf("salary_max 格式错误: %s", maxText)
+		}
+	}
+	r = r.Normalize()
+	return r.AnnualMin, r.AnnualMax, nil
+}
+
+// parseAmount 解析单个金额，支持 20k、2万 等写法
+func parseAmount(text string) (float64, error) {
+	m := numberPattern.FindStringSubmatch(strings.TrimSpace(text))
+	if m == nil {
+		return 0, fmt.Errorf("invalid amount")
+	}
+	v, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0, err
+	}
+	return v * multiplier(m[2]), nil
+}
+
+// ApplyFilter 按薪资区间筛选，与筛选区间有交集即命中；prefix 为嵌入字段的列前缀，如 "salary_"
+// 支持参数 salary_min、salary_max、salary_period（默认 month）、salary_currency（默认 CNY）
+func ApplyFilter(query *gorm.DB, prefix string, params url.Values) (*gorm.DB, error) {
+	minText, maxText := params.Get("salary_min"), params.Get("salary_max")
+	if minText == "" && maxText == "" {
+		return query, nil
+	}
+
+	lo, hi, err := FilterBounds(minText, maxText, params.Get("salary_period"))
+	if err != nil {
+		return nil, err
+	}
+	currency := strings.ToUpper(params.Get("salary_currency"))
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+
+	query = query.Where(prefix+"currency = ? AND "+prefix+"negotiable = ? AND ("+prefix+"annual_min > 0 OR "+prefix+"annual_max > 0)", currency, false)
+	if lo > 0 {
+		query = query.Where("("+prefix+"annual_max = 0 OR "+prefix+"annual_max >= ?)", lo)
+	}
+	if hi > 0 {
+		query = query.Where(prefix+"annual_min <= ?", hi)
+	}
+	return query, nil
+}
+
+// Resolve 统一自由文本与结构化薪资：未提供结构化薪资时从文本解析，
+// 提供了结构化薪资且文本为空时按结构化薪资生成文本
+func Resolve(text string, r Range) (Range, string, error) {
+	text = strings.TrimSpace(text)
+	if r.IsZero() {
+		return FromText(text), text, nil
+	}
+	if err := r.Validate(); err != nil {
+		return Range{}, text, err
+	}
+	r = r.Normalize()
+	if text == "" {
+		text = r.String()
+	}
+	return r, text, nil
+}
+
+// Columns 返回结构化薪资对应的数据库列，用于 map 方式的局部更新
+func (r Range) Columns(prefix string) map[string]interface{} {
+	return map[string]interface{}{
+		prefix + "min":        r.Min,
+		prefix + "max":        r.Max,
+		prefix + "currency":   r.Currency,
+		prefix + "period":     r.Period,
+		prefix + "months":     r.Months,
+		prefix + "negotiable": r.Negotiable,
+		prefix + "annual_min": r.AnnualMin,
+		prefix + "annual_max": r.AnnualMax,
+	}
+}
+
+// Backfill 将表中尚未结构化的自由文本薪资（textColumn）解析写入结构化列，返回成功迁移的行数
+func Backfill(db *gorm.DB, table, textColumn, prefix string) (int, error) {
+	var rows []struct {
+		ID   uint
+		Text string
+	}
+	err := db.Table(table).
+		Select("id, " + textColumn + " AS text").
+		Where(textColumn + " <> '' AND (" + prefix + "period IS NULL OR " + prefix + "period = '')").
+		Find(&rows).Error
+	if err != nil {
+		return 0, err
+	}
+
+	migrated := 0
+	for _, row := range rows {
+		r, ok := Parse(row.Text)
+		if !ok {
+			continue
+		}
+		if err := db.Table(table).Where("id = ?", row.ID).Updates(r.Columns(prefix)).Error; err != nil {
+			return migrated, err
+		}
+		migrated++
+	}
+	return migrated, nil
+}
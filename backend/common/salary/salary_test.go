@@ -0,0 +1,103 @@
+package salary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		text     string
+		min, max float64
+		period   string
+		months   int
+		currency string
+	}{
+		{"15k-25k", 15000, 25000, PeriodMonth, 12, "CNY"},
+		{"15-25K", 15000, 25000, PeriodMonth, 12, "CNY"},
+		{"25-40K·14薪", 25000, 40000, PeriodMonth, 14, "CNY"},
+		{"20-30万", 200000, 300000, PeriodYear, 0, "CNY"},
+		{"20万-30万/年", 200000, 300000, PeriodYear, 0, "CNY"},
+		{"8000-12000元/月", 8000, 12000, PeriodMonth, 12, "CNY"},
+		{"300元/天", 300, 300, PeriodDay, 0, "CNY"},
+		{"30K以上", 30000, 0, PeriodMonth, 12, "CNY"},
+		{"10K以下", 0, 10000, PeriodMonth, 12, "CNY"},
+		{"$120k-$150k", 120000, 150000, PeriodYear, 0, "USD"},
+		{"8千-1.2万", 8000, 12000, PeriodMonth, 12, "CNY"},
+		{"8千-1.2万/月", 8000, 12000, PeriodMonth, 12, "CNY"},
+		{"2-3万·13薪", 20000, 30000, PeriodMonth, 13, "CNY"},
+		{"1.5-2万", 15000, 20000, PeriodMonth, 12, "CNY"},
+		{"5-8万", 50000, 80000, PeriodYear, 0, "CNY"},
+		{"4.5-6万", 45000, 60000, PeriodMonth, 12, "CNY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			r, ok := Parse(tt.text)
+			assert.True(t, ok)
+			assert.Equal(t, tt.min, r.Min)
+			assert.Equal(t, tt.max, r.Max)
+			assert.Equal(t, tt.period, r.Period)
+			assert.Equal(t, tt.months, r.Months)
+			assert.Equal(t, tt.currency, r.Currency)
+		})
+	}
+}
+
+func TestParseNegotiableAndInvalid(t *testing.T) {
+	r, ok := Parse("面议")
+	assert.True(t, ok)
+	assert.True(t, r.Negotiable)
+	assert.False(t, r.Known())
+
+	_, ok = Parse("待定")
+	assert.False(t, ok)
+}
+
+func TestAnnualAndString(t *testing.T) {
+	r := FromText("25-40K·14薪")
+	assert.Equal(t, 350000.0, r.AnnualMin)
+	assert.Equal(t, 560000.0, r.AnnualMax)
+	assert.Equal(t, "25-40K·14薪", r.String())
+	assert.Equal(t, "20-30万/年", FromText("20-30万").String())
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+		offered  string
+		min, max float64
+	}{
+		{"完全覆盖", "20-25K", "20-30K", 1.0, 1.0},
+		{"部分重叠", "20-30K", "15-25K", 0.7, 0.9},
+		{"低于期望", "30-40K", "15-20K", 0, 0.1},
+		{"年薪与月薪比较", "30-36万", "25-30K", 1.0, 1.0},
+		{"面议", "20-30K", "面议", 0.6, 0.6},
+		{"未知", "", "20-30K", 0.5, 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, _ := Match(FromText(tt.expected), FromText(tt.offered))
+			assert.GreaterOrEqual(t, score, tt.min)
+			assert.LessOrEqual(t, score, tt.max)
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	r, text, err := Resolve("15-25K", Range{})
+	assert.NoError(t, err)
+	assert.Equal(t, 15000.0, r.Min)
+	assert.Equal(t, "15-25K", text)
+
+	r, text, err = Resolve("", Range{Min: 200000, Max: 300000, Period: PeriodYear})
+	assert.NoError(t, err)
+	assert.Equal(t, 300000.0, r.AnnualMax)
+	assert.Equal(t, "20-30万/年", text)
+
+	_, _, err = Resolve("", Range{Min: 1, Period: "week"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,515 @@
+// Package screening 职位筛选问题：问题随职位保存（jobs.screening_questions，由 job-service 维护），
+// 候选人申请时作答，提交后按淘汰条件自动评估，回答和评估结果保存在申请上（resume-service）
+package screening
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// 问题类型
+const (
+	TypeYesNo  = "yes_no"
+	TypeNumber = "number"
+	TypeChoice = "choice"
+	TypeText   = "text"
+)
+
+// 未满足淘汰条件时的处理方式
+const (
+	ActionReject = "reject" // 自动淘汰
+	ActionFlag   = "flag"   // 添加标签，由 HR 人工确认
+)
+
+// 是非题的标准回答
+const (
+	Yes = "yes"
+	No  = "no"
+)
+
+// 评估结果
+const (
+	OutcomePassed   = "passed"
+	OutcomeFlagged  = "flagged"
+	OutcomeRejected = "rejected"
+)
+
+// 长度和数量限制
+const (
+	maxQuestions     = 30
+	maxQuestionLen   = 500
+	maxOptions       = 20
+	maxTagLen        = 50
+	maxTextAnswerLen = 2000
+)
+
+var idPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// Question 职位筛选问题
+type Question struct {
+	ID       string    `json:"id"` // 不填时按顺序生成 q1、q2…
+	Text     string    `json:"text"`
+	Type     string    `json:"type"`              // yes_no, number, choice, text
+	Options  []string  `json:"options,omitempty"` // choice 的可选项
+	Required bool      `json:"required"`
+	Knockout *Knockout `json:"knockout,omitempty"` // 淘汰条件，不对候选人展示
+}
+
+// Knockout 淘汰条件：描述可接受的回答，回答不满足时按 Action 处理；文本题不支持
+type Knockout struct {
+	Action string   `json:"action"`           // reject, flag
+	Accept []string `json:"accept,omitempty"` // yes_no、choice 可接受的回答
+	Min    *float64 `json:"min,omitempty"`    // number 可接受的最小值
+	Max    *float64 `json:"max,omitempty"`    // number 可接受的最大值
+	Tag    string   `json:"tag,omitempty"`    // 标记时添加的标签，不填时使用问题内容
+}
+
+// Questions 以 JSONB 存储的问题列表
+type Questions []Question
+
+// Value 实现 driver.Valuer
+func (q Questions) Value() (driver.Value, error) {
+	if q == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(q)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan 实现 sql.Scanner
+func (q *Questions) Scan(src interface{}) error {
+	return scanJSON(src, q, "Questions")
+}
+
+// GormDataType 指定 GORM 列类型
+func (Questions) GormDataType() string {
+	return "jsonb"
+}
+
+// Answer 候选人对筛选问题的回答
+type Answer struct {
+	QuestionID string `json:"question_id"`
+	Answer     string `json:"answer"`
+}
+
+// Answers 以 JSONB 存储的回答列表
+type Answers []Answer
+
+// Value 实现 driver.Valuer
+func (a Answers) Value() (driver.Value, error) {
+	if a == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan 实现 sql.Scanner
+func (a *Answers) Scan(src interface{}) error {
+	return scanJSON(src, a, "Answers")
+}
+
+// GormDataType 指定 GORM 列类型
+func (Answers) GormDataType() string {
+	return "jsonb"
+}
+
+// Item 单个问题的评估结果
+type Item struct {
+	QuestionID string `json:"question_id"`
+	Question   string `json:"question"`
+	Answer     string `json:"answer"`
+	Passed     bool   `json:"passed"`
+	Action     string `json:"action,omitempty"` // 未通过时的处理方式
+}
+
+// Result 申请提交时的筛选评估结果
+type Result struct {
+	Outcome     string    `json:"outcome"` // passed, flagged, rejected
+	Tags        []string  `json:"tags,omitempty"`
+	Items       []Item    `json:"items"`
+	EvaluatedAt time.Time `json:"evaluated_at"`
+}
+
+// Value 实现 driver.Valuer
+func (r Result) Value() (driver.Value, error) {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan 实现 sql.Scanner
+func (r *Result) Scan(src interface{}) error {
+	return scanJSON(src, r, "Result")
+}
+
+// GormDataType 指定 GORM 列类型
+func (Result) GormDataType() string {
+	return "jsonb"
+}
+
+func scanJSON(src interface{}, dest interface{}, name string) error {
+	var data []byte
+	switch s := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		data = s
+	case string:
+		data = []byte(s)
+	default:
+		return fmt.Errorf("screening: unsupported scan type %T for %s", src, name)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, dest)
+}
+
+// Validate 校验并规范化问题列表：去除空白、生成缺失的问题 ID、清理与类型无关的设置
+func (q Questions) Validate() error {
+	if len(q) > maxQuestions {
+		return fmt.Errorf("筛选问题最多 %d 个", maxQuestions)
+	}
+	seen := make(map[string]bool, len(q))
+	for i := range q {
+		if strings.TrimSpace(q[i].ID) != "" {
+			seen[strings.TrimSpace(q[i].ID)] = true
+		}
+	}
+	next := 1
+	for i := range q {
+		question := &q[i]
+		question.ID = strings.TrimSpace(question.ID)
+		if question.ID == "" {
+			for seen[fmt.Sprintf("q%d", next)] {
+				next++
+			}
+			question.ID = fmt.Sprintf("q%d", next)
+			seen[question.ID] = true
+		}
+		if err := question.validate(); err != nil {
+			return fmt.Errorf("第 %d 个筛选问题: %v", i+1, err)
+		}
+		for j := 0; j < i; j++ {
+			if q[j].ID == question.ID {
+				return fmt.Errorf("筛选问题 ID 重复: %s", question.ID)
+			}
+		}
+	}
+	return nil
+}
+
+func (q *Question) validate() error {
+	if !idPattern.MatchString(q.ID) {
+		return errors.New("问题 ID 只能包含小写字母、数字和下划线，且以字母开头")
+	}
+	q.Text = strings.TrimSpace(q.Text)
+	if q.Text == "" {
+		return errors.New("问题内容不能为空")
+	}
+	if utf8.RuneCountInString(q.Text) > maxQuestionLen {
+		return fmt.Errorf("问题内容不能超过 %d 个字符", maxQuestionLen)
+	}
+
+	switch q.Type {
+	case TypeYesNo, TypeNumber, TypeText:
+		q.Options = nil
+	case TypeChoice:
+		options := make([]string, 0, len(q.Options))
+		seen := make(map[string]bool)
+		for _, o := range q.Options {
+			o = strings.TrimSpace(o)
+			if o != "" && !seen[o] {
+				seen[o] = true
+				options = append(options, o)
+			}
+		}
+		if len(options) < 2 {
+			return errors.New("选择题至少需要两个选项")
+		}
+		if len(options) > maxOptions {
+			return fmt.Errorf("选择题最多 %d 个选项", maxOptions)
+		}
+		q.Options = options
+	default:
+		return fmt.Errorf("不支持的问题类型: %s", q.Type)
+	}
+
+	if q.Knockout == nil {
+		return nil
+	}
+	k := q.Knockout
+	if k.Action != ActionReject && k.Action != ActionFlag {
+		return fmt.Errorf("不支持的淘汰处理方式: %s", k.Action)
+	}
+	k.Tag = strings.TrimSpace(k.Tag)
+	if utf8.RuneCountInString(k.Tag) > maxTagLen {
+		return fmt.Errorf("标签不能超过 %d 个字符", maxTagLen)
+	}
+	switch q.Type {
+	case TypeText:
+		return errors.New("文本题不支持淘汰条件")
+	case TypeNumber:
+		k.Accept = nil
+		if k.Min == nil && k.Max == nil {
+			return errors.New("数字题的淘汰条件需设置最小值或最大值")
+		}
+		if k.Min != nil && k.Max != nil && *k.Min > *k.Max {
+			return errors.New("最小值不能大于最大值")
+		}
+	default:
+		k.Min, k.Max = nil, nil
+		accept := make([]string, 0, len(k.Accept))
+		for _, a := range k.Accept {
+			v, err := q.Normalize(a)
+			if err != nil {
+				return fmt.Errorf("可接受的回答 %q 无效: %v", a, err)
+			}
+			if v != "" {
+				accept = append(accept, v)
+			}
+		}
+		if len(accept) == 0 {
+			return errors.New("淘汰条件需设置可接受的回答")
+		}
+		k.Accept = accept
+	}
+	return nil
+}
+
+// Normalize 校验回答格式并转换为标准形式：是非题转为 yes/no，选择题转为选项原文，数字题去除千分位
+func (q Question) Normalize(answer string) (string, error) {
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return "", nil
+	}
+	switch q.Type {
+	case TypeYesNo:
+		switch strings.ToLower(answer) {
+		case "yes", "y", "true", "1", "是":
+			return Yes, nil
+		case "no", "n", "false", "0", "否":
+			return No, nil
+		}
+		return "", errors.New("请回答是或否")
+	case TypeNumber:
+		v, err := strconv.ParseFloat(strings.ReplaceAll(answer, ",", ""), 64)
+		if err != nil {
+			return "", errors.New("请填写数字")
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case TypeChoice:
+		for _, o := range q.Options {
+			if strings.EqualFold(o, answer) {
+				return o, nil
+			}
+		}
+		return "", errors.New("请从选项中选择")
+	default:
+		if utf8.RuneCountInString(answer) > maxTextAnswerLen {
+			return "", fmt.Errorf("回答不能超过 %d 个字符", maxTextAnswerLen)
+		}
+		return answer, nil
+	}
+}
+
+// Check 校验回答：问题须存在且不能重复回答，格式须符合问题类型；requireAll 为 true 时检查必答问题（候选人提交时使用）。
+// 返回按问题顺序排列的标准化回答，未作答的问题不保留
+func (q Questions) Check(answers Answers, requireAll bool) (Answers, error) {
+	byID := make(map[string]string, len(answers))
+	for _, a := range answers {
+		id := strings.TrimSpace(a.QuestionID)
+		if _, ok := byID[id]; ok {
+			return nil, fmt.Errorf("问题 %s 重复回答", id)
+		}
+		byID[id] = a.Answer
+	}
+
+	out := make(Answers, 0, len(answers))
+	for _, question := range q {
+		raw := byID[question.ID]
+		delete(byID, question.ID)
+		value, err := question.Normalize(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", question.Text, err)
+		}
+		if value == "" {
+			if requireAll && question.Required {
+				return nil, fmt.Errorf("%s 为必答问题", question.Text)
+			}
+			continue
+		}
+		out = append(out, Answer{QuestionID: question.ID, Answer: value})
+	}
+	for id := range byID {
+		return nil, fmt.Errorf("筛选问题不存在: %s", id)
+	}
+	return out, nil
+}
+
+// Public 候选人可见的问题，不含淘汰条件
+func (q Questions) Public() Questions {
+	out := make(Questions, len(q))
+	for i, question := range q {
+		question.Knockout = nil
+		out[i] = question
+	}
+	return out
+}
+
+// accepts 回答是否满足淘汰条件
+func (k Knockout) accepts(q Question, answer string) bool {
+	if q.Type == TypeNumber {
+		v, err := strconv.ParseFloat(answer, 64)
+		if err != nil {
+			return false
+		}
+		return (k.Min == nil || v >= *k.Min) && (k.Max == nil || v <= *k.Max)
+	}
+	for _, a := range k.Accept {
+		if a == answer {
+			return true
+		}
+	}
+	return false
+}
+
+// tag 标记时添加的标签
+func (k Knockout) tag(q Question) string {
+	if k.Tag != "" {
+		return k.Tag
+	}
+	return q.Text
+}
+
+// Evaluate 按淘汰条件评估已校验的回答：任一淘汰条件不满足即为 rejected，
+// 否则有标记条件不满足时为 flagged；未作答的问题不参与评估
+func Evaluate(questions Questions, answers Answers, now time.Time) Result {
+	byID := make(map[string]string, len(answers))
+	for _, a := range answers {
+		byID[a.QuestionID] = a.Answer
+	}
+
+	result := Result{Outcome: OutcomePassed, Items: []Item{}, EvaluatedAt: now}
+	rejected := false
+	for _, q := range questions {
+		answer := byID[q.ID]
+		if answer == "" {
+			continue
+		}
+		item := Item{QuestionID: q.ID, Question: q.Text, Answer: answer, Passed: true}
+		if q.Knockout != nil && !q.Knockout.accepts(q, answer) {
+			item.Passed = false
+			item.Action = q.Knockout.Action
+			if q.Knockout.Action == ActionReject {
+				rejected = true
+			} else {
+				result.Tags = append(result.Tags, q.Knockout.tag(q))
+			}
+		}
+		result.Items = append(result.Items, item)
+	}
+
+	switch {
+	case rejected:
+		result.Outcome = OutcomeRejected
+	case len(result.Tags) > 0:
+		result.Outcome = OutcomeFlagged
+	}
+	return result
+}
+
+// OutcomeLabel 评估结果的中文名称
+func OutcomeLabel(outcome string) string {
+	switch outcome {
+	case OutcomePassed:
+		return "通过"
+	case OutcomeFlagged:
+		return "待确认"
+	case OutcomeRejected:
+		return "未通过"
+	}
+	return ""
+}
+
+// Failed 未满足淘汰条件的问题及回答，如“是否可在上海工作（否）”，以分号分隔
+func (r Result) Failed() string {
+	var parts []string
+	for _, item := range r.Items {
+		if !item.Passed {
+			parts = append(parts, fmt.Sprintf("%s（%s）", item.Question, displayAnswer(item.Answer)))
+		}
+	}
+	return strings.Join(parts, "；")
+}
+
+// AnswerText 问题及回答，如“是否可在上海工作：是”，以分号分隔，用于导出
+func (r Result) AnswerText() string {
+	parts := make([]string, len(r.Items))
+	for i, item := range r.Items {
+		parts[i] = fmt.Sprintf("%s：%s", item.Question, displayAnswer(item.Answer))
+	}
+	return strings.Join(parts, "；")
+}
+
+// Prompt 供 AI 评估参考的筛选问题回答，无回答时返回空
+func (r Result) Prompt() string {
+	if len(r.Items) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("候选人对职位筛选问题的回答：\n")
+	for _, item := range r.Items {
+		fmt.Fprintf(&b, "- %s：%s", item.Question, displayAnswer(item.Answer))
+		switch item.Action {
+		case ActionReject:
+			b.WriteString("（不满足淘汰条件）")
+		case ActionFlag:
+			b.WriteString("（需人工确认）")
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "筛选结果：%s", OutcomeLabel(r.Outcome))
+	return b.String()
+}
+
+// displayAnswer 是非题显示为中文
+func displayAnswer(answer string) string {
+	switch answer {
+	case Yes:
+		return "是"
+	case No:
+		return "否"
+	}
+	return answer
+}
+
+// Load 读取职位的筛选问题，职位不存在时返回空列表
+func Load(db *gorm.DB, jobID uint) (Questions, error) {
+	var row struct {
+		ScreeningQuestions Questions
+	}
+	err := db.Table("jobs").Select("screening_questions").Where("id = ?", jobID).Take(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Questions{}, nil
+	}
+	return row.ScreeningQuestions, err
+}
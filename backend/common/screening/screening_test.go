@@ -0,0 +1,166 @@
+package screening
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func float(v float64) *float64 { return &v }
+
+func sample() Questions {
+	return Questions{
+		{ID: "work_permit", Text: "是否可在上海工作", Type: TypeYesNo, Required: true, Knockout: &Knockout{Action: ActionReject, Accept: []string{Yes}}},
+		{ID: "salary", Text: "期望月薪", Type: TypeNumber, Knockout: &Knockout{Action: ActionFlag, Max: float(40000), Tag: "薪资偏高"}},
+		{ID: "go_years", Text: "Go 开发年限", Type: TypeNumber, Knockout: &Knockout{Action: ActionReject, Min: float(3)}},
+		{ID: "travel", Text: "能否接受出差", Type: TypeChoice, Options: []string{"可以", "偶尔", "不可以"}, Knockout: &Knockout{Action: ActionFlag, Accept: []string{"可以", "偶尔"}}},
+		{ID: "note", Text: "其他说明", Type: TypeText},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(q Questions) Questions
+		wantErr string
+	}{
+		{name: "合法问题", modify: func(q Questions) Questions { return q }},
+		{name: "自动生成 ID", modify: func(q Questions) Questions { q[4].ID = ""; return q }},
+		{name: "ID 格式错误", modify: func(q Questions) Questions { q[0].ID = "Work Permit"; return q }, wantErr: "问题 ID"},
+		{name: "ID 重复", modify: func(q Questions) Questions { q[1].ID = "work_permit"; return q }, wantErr: "重复"},
+		{name: "内容为空", modify: func(q Questions) Questions { q[0].Text = " "; return q }, wantErr: "不能为空"},
+		{name: "类型无效", modify: func(q Questions) Questions { q[0].Type = "date"; return q }, wantErr: "不支持的问题类型"},
+		{name: "选项不足", modify: func(q Questions) Questions { q[3].Options = []string{"可以", "可以"}; return q }, wantErr: "至少需要两个选项"},
+		{name: "处理方式无效", modify: func(q Questions) Questions { q[0].Knockout.Action = "hide"; return q }, wantErr: "淘汰处理方式"},
+		{name: "文本题不能淘汰", modify: func(q Questions) Questions { q[4].Knockout = &Knockout{Action: ActionFlag}; return q }, wantErr: "文本题"},
+		{name: "数字题缺少范围", modify: func(q Questions) Questions { q[1].Knockout.Max = nil; return q }, wantErr: "最小值或最大值"},
+		{name: "数字范围颠倒", modify: func(q Questions) Questions { q[2].Knockout.Max = float(1); return q }, wantErr: "不能大于"},
+		{name: "可接受回答不在选项中", modify: func(q Questions) Questions { q[3].Knockout.Accept = []string{"经常"}; return q }, wantErr: "无效"},
+		{name: "缺少可接受回答", modify: func(q Questions) Questions { q[0].Knockout.Accept = nil; return q }, wantErr: "可接受的回答"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := tt.modify(sample())
+			err := q.Validate()
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+
+	q := Questions{{Text: "是否持有驾照", Type: TypeYesNo, Knockout: &Knockout{Action: ActionReject, Accept: []string{"是"}}}, {ID: "q1", Text: "年限", Type: TypeNumber}}
+	require.NoError(t, q.Validate())
+	assert.Equal(t, "q2", q[0].ID)
+	assert.Equal(t, []string{Yes}, q[0].Knockout.Accept)
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		answers    Answers
+		requireAll bool
+		want       Answers
+		wantErr    string
+	}{
+		{name: "标准化回答并按问题排序", answers: Answers{{QuestionID: "salary", Answer: "30,000"}, {QuestionID: "work_permit", Answer: "是"}, {QuestionID: "travel", Answer: "偶尔"}}, requireAll: true,
+			want: Answers{{QuestionID: "work_permit", Answer: Yes}, {QuestionID: "salary", Answer: "30000"}, {QuestionID: "travel", Answer: "偶尔"}}},
+		{name: "未作答的问题不保留", answers: Answers{{QuestionID: "note", Answer: " "}}, want: Answers{}},
+		{name: "缺少必答问题", answers: Answers{{QuestionID: "salary", Answer: "1"}}, requireAll: true, wantErr: "必答问题"},
+		{name: "录入时不检查必答", answers: Answers{{QuestionID: "salary", Answer: "1"}}, want: Answers{{QuestionID: "salary", Answer: "1"}}},
+		{name: "问题不存在", answers: Answers{{QuestionID: "unknown", Answer: "是"}}, wantErr: "不存在"},
+		{name: "重复回答", answers: Answers{{QuestionID: "salary", Answer: "1"}, {QuestionID: "salary", Answer: "2"}}, wantErr: "重复回答"},
+		{name: "是非题格式错误", answers: Answers{{QuestionID: "work_permit", Answer: "也许"}}, wantErr: "请回答是或否"},
+		{name: "数字格式错误", answers: Answers{{QuestionID: "go_years", Answer: "三年"}}, wantErr: "请填写数字"},
+		{name: "选项不存在", answers: Answers{{QuestionID: "travel", Answer: "经常"}}, wantErr: "请从选项中选择"},
+	}
+	questions := sample()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := questions.Check(tt.answers, tt.requireAll)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		answers   Answers
+		outcome   string
+		tags      []string
+		failed    string
+		evaluated int
+	}{
+		{name: "全部满足", answers: Answers{{QuestionID: "work_permit", Answer: Yes}, {QuestionID: "salary", Answer: "30000"}, {QuestionID: "go_years", Answer: "5"}}, outcome: OutcomePassed, evaluated: 3},
+		{name: "标记", answers: Answers{{QuestionID: "work_permit", Answer: Yes}, {QuestionID: "salary", Answer: "50000"}, {QuestionID: "travel", Answer: "不可以"}},
+			outcome: OutcomeFlagged, tags: []string{"薪资偏高", "能否接受出差"}, failed: "期望月薪（50000）；能否接受出差（不可以）", evaluated: 3},
+		{name: "淘汰优先于标记", answers: Answers{{QuestionID: "work_permit", Answer: No}, {QuestionID: "salary", Answer: "50000"}},
+			outcome: OutcomeRejected, tags: []string{"薪资偏高"}, failed: "是否可在上海工作（否）；期望月薪（50000）", evaluated: 2},
+		{name: "数字下限", answers: Answers{{QuestionID: "go_years", Answer: "2.5"}}, outcome: OutcomeRejected, failed: "Go 开发年限（2.5）", evaluated: 1},
+		{name: "未作答不评估", answers: nil, outcome: OutcomePassed, evaluated: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Evaluate(sample(), tt.answers, now)
+			assert.Equal(t, tt.outcome, r.Outcome)
+			assert.Equal(t, tt.tags, r.Tags)
+			assert.Equal(t, tt.failed, r.Failed())
+			assert.Len(t, r.Items, tt.evaluated)
+			assert.Equal(t, now, r.EvaluatedAt)
+		})
+	}
+}
+
+func TestPrompt(t *testing.T) {
+	assert.Empty(t, Evaluate(sample(), nil, time.Now()).Prompt())
+
+	r := Evaluate(sample(), Answers{{QuestionID: "work_permit", Answer: No}, {QuestionID: "note", Answer: "可随时到岗"}}, time.Now())
+	prompt := r.Prompt()
+	assert.Contains(t, prompt, "- 是否可在上海工作：否（不满足淘汰条件）")
+	assert.Contains(t, prompt, "- 其他说明：可随时到岗\n")
+	assert.Contains(t, prompt, "筛选结果：未通过")
+	assert.Equal(t, "是否可在上海工作：否；其他说明：可随时到岗", r.AnswerText())
+}
+
+func TestPublic(t *testing.T) {
+	q := sample()
+	public := q.Public()
+	for _, question := range public {
+		assert.Nil(t, question.Knockout)
+	}
+	assert.NotNil(t, q[0].Knockout, "不应修改原问题")
+}
+
+func TestLoad(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	db.Exec(`CREATE TABLE jobs (id INTEGER PRIMARY KEY, screening_questions TEXT)`)
+	db.Exec(`INSERT INTO jobs (id, screening_questions) VALUES (1, '[{"id":"work_permit","text":"是否可在上海工作","type":"yes_no","required":true}]'), (2, NULL)`)
+
+	q, err := Load(db, 1)
+	require.NoError(t, err)
+	require.Len(t, q, 1)
+	assert.Equal(t, "work_permit", q[0].ID)
+
+	q, err = Load(db, 2)
+	require.NoError(t, err)
+	assert.Empty(t, q)
+
+	q, err = Load(db, 3)
+	require.NoError(t, err, "职位不存在时返回空列表")
+	assert.Empty(t, q)
+}
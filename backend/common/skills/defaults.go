@@ -0,0 +1,81 @@
+package skills
+
+// DefaultSkills 内置技能字典，技能表为空时作为种子数据写入
+var DefaultSkills = []Skill{
+	// 编程语言
+	{Name: "Java", Category: "language"},
+	{Name: "Python", Category: "language"},
+	{Name: "Go", Aliases: []string{"Golang", "Go语言"}, Category: "language"},
+	{Name: "JavaScript", Aliases: []string{"JS", "ES6"}, Category: "language"},
+	{Name: "TypeScript", Aliases: []string{"TS"}, Category: "language"},
+	{Name: "C++", Aliases: []string{"cpp"}, Category: "language"},
+	{Name: "C#", Aliases: []string{"csharp"}, Category: "language"},
+	{Name: "PHP", Category: "language"},
+	{Name: "Ruby", Category: "language"},
+	{Name: "Swift", Category: "language"},
+	{Name: "Kotlin", Category: "language"},
+	{Name: "Rust", Category: "language"},
+
+	// 前端
+	{Name: "Vue", Aliases: []string{"Vue.js", "VueJS", "Vue2", "Vue3"}, Category: "frontend"},
+	{Name: "React", Aliases: []string{"React.js", "ReactJS"}, Category: "frontend"},
+	{Name: "Angular", Aliases: []string{"AngularJS"}, Category: "frontend"},
+	{Name: "HTML", Aliases: []string{"HTML5"}, Category: "frontend"},
+	{Name: "CSS", Aliases: []string{"CSS3"}, Category: "frontend"},
+	{Name: "SCSS", Aliases: []string{"Sass"}, Category: "frontend", Parent: "CSS"},
+	{Name: "Less", Category: "frontend", Parent: "CSS"},
+	{Name: "Webpack", Category: "frontend"},
+	{Name: "Vite", Category: "frontend"},
+	{Name: "Node.js", Aliases: []string{"NodeJS"}, Category: "backend", Parent: "JavaScript"},
+	{Name: "Next.js", Aliases: []string{"NextJS"}, Category: "frontend", Parent: "React"},
+
+	// 后端
+	{Name: "Spring", Category: "backend", Parent: "Java"},
+	{Name: "Spring Boot", Aliases: []string{"SpringBoot"}, Category: "backend", Parent: "Spring"},
+	{Name: "Django", Category: "backend", Parent: "Python"},
+	{Name: "Flask", Category: "backend", Parent: "Python"},
+	{Name: "FastAPI", Category: "backend", Parent: "Python"},
+	{Name: "Express", Aliases: []string{"Express.js"}, Category: "backend", Parent: "Node.js"},
+	{Name: "Gin", Category: "backend", Parent: "Go"},
+	{Name: "Laravel", Category: "backend", Parent: "PHP"},
+
+	// 数据库
+	{Name: "MySQL", Category: "database"},
+	{Name: "PostgreSQL", Aliases: []string{"Postgres"}, Category: "database"},
+	{Name: "MongoDB", Aliases: []string{"Mongo"}, Category: "database"},
+	{Name: "Redis", Category: "database"},
+	{Name: "Elasticsearch", Category: "database"},
+	{Name: "Oracle", Category: "database"},
+	{Name: "SQL Server", Aliases: []string{"MSSQL"}, Category: "database"},
+
+	// 云和DevOps
+	{Name: "Docker", Category: "devops"},
+	{Name: "Kubernetes", Aliases: []string{"K8s"}, Category: "devops"},
+	{Name: "AWS", Category: "devops"},
+	{Name: "Azure", Category: "devops"},
+	{Name: "GCP", Category: "devops"},
+	{Name: "Linux", Category: "devops"},
+	{Name: "Nginx", Category: "devops"},
+	{Name: "Jenkins", Category: "devops", Parent: "CI/CD"},
+	{Name: "Git", Category: "devops"},
+	{Name: "CI/CD", Aliases: []string{"CICD", "持续集成"}, Category: "devops"},
+
+	// 大数据和AI
+	{Name: "Hadoop", Category: "bigdata"},
+	{Name: "Spark", Category: "bigdata"},
+	{Name: "Flink", Category: "bigdata"},
+	{Name: "TensorFlow", Category: "ai", Parent: "深度学习"},
+	{Name: "PyTorch", Category: "ai", Parent: "深度学习"},
+	{Name: "机器学习", Aliases: []string{"Machine Learning", "ML"}, Category: "ai"},
+	{Name: "深度学习", Aliases: []string{"Deep Learning"}, Category: "ai", Parent: "机器学习"},
+
+	// 架构
+	{Name: "微服务", Aliases: []string{"Microservices", "Microservice"}, Category: "architecture"},
+	{Name: "分布式", Aliases: []string{"Distributed Systems"}, Category: "architecture"},
+	{Name: "高并发", Aliases: []string{"High Concurrency"}, Category: "architecture"},
+	{Name: "消息队列", Aliases: []string{"MQ", "Message Queue"}, Category: "architecture"},
+	{Name: "RabbitMQ", Category: "architecture", Parent: "消息队列"},
+	{Name: "Kafka", Category: "architecture", Parent: "消息队列"},
+	{Name: "gRPC", Category: "architecture"},
+	{Name: "RESTful", Aliases: []string{"REST", "RESTful API"}, Category: "architecture"},
+}
@@ -0,0 +1,262 @@
+package skills
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Handler 技能字典管理接口
+type Handler struct {
+	db   *gorm.DB
+	dict *Dictionary
+}
+
+// NewHandler 创建技能字典管理处理器
+func NewHandler(db *gorm.DB, dict *Dictionary) *Handler {
+	return &Handler{db: db, dict: dict}
+}
+
+// SkillRequest 创建/更新技能请求
+type SkillRequest struct {
+	Name     string   `json:"name" binding:"required"`
+	Aliases  []string `json:"aliases"`
+	Category string   `json:"category"`
+	Parent   string   `json:"parent"`
+}
+
+// RegisterRoutes 注册技能字典路由，adminMiddleware 作用于修改类接口
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup, adminMiddleware ...gin.HandlerFunc) {
+	rg.GET("", h.ListSkills)
+	rg.POST("/normalize", h.NormalizeSkills)
+	rg.GET("/:id", h.GetSkill)
+
+	admin := rg.Group("", adminMiddleware...)
+	admin.POST("", h.CreateSkill)
+	admin.PUT("/:id", h.UpdateSkill)
+	admin.DELETE("/:id", h.DeleteSkill)
+}
+
+// ListSkills 获取技能字典
+func (h *Handler) ListSkills(c *gin.Context) {
+	category := c.Query("category")
+	keyword := strings.ToLower(c.Query("keyword"))
+
+	var list []Skill
+	for _, s := range h.dict.List() {
+		if category != "" && s.Category != category {
+			continue
+		}
+		if keyword != "" && !skillContains(s, keyword) {
+			continue
+		}
+		list = append(list, s)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"skills": list,
+			"total":  len(list),
+		},
+	})
+}
+
+// GetSkill 获取单个技能
+func (h *Handler) GetSkill(c *gin.Context) {
+	var skill Skill
+	if err := h.db.First(&skill, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "Skill not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data":    skill,
+	})
+}
+
+// CreateSkill 新增技能
+func (h *Handler) CreateSkill(c *gin.Context) {
+	var req SkillRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": err.Error()})
+		return
+	}
+
+	skill := Skill{}
+	if msg := h.apply(&skill, req); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": msg})
+		return
+	}
+
+	if err := h.db.Create(&skill).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "Failed to create skill: " + err.Error()})
+		return
+	}
+	h.reload()
+
+	c.JSON(http.StatusCreated, gin.H{
+		"code":    0,
+		"message": "Skill created successfully",
+		"data":    skill,
+	})
+}
+
+// UpdateSkill 更新技能
+func (h *Handler) UpdateSkill(c *gin.Context) {
+	var skill Skill
+	if err := h.db.First(&skill, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "Skill not found"})
+		return
+	}
+
+	var req SkillRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": err.Error()})
+		return
+	}
+
+	if msg := h.apply(&skill, req); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": msg})
+		return
+	}
+
+	if err := h.db.Save(&skill).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "Failed to update skill: " + err.Error()})
+		return
+	}
+	h.reload()
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "Skill updated successfully",
+		"data":    skill,
+	})
+}
+
+// DeleteSkill 删除技能
+func (h *Handler) DeleteSkill(c *gin.Context) {
+	var skill Skill
+	if err := h.db.First(&skill, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "Skill not found"})
+		return
+	}
+
+	var children int64
+	h.db.Model(&Skill{}).Where("parent = ?", skill.Name).Count(&children)
+	if children > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "该技能存在下级技能，请先调整下级技能"})
+		return
+	}
+
+	if err := h.db.Delete(&skill).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "Failed to delete skill"})
+		return
+	}
+	h.reload()
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "Skill deleted successfully",
+	})
+}
+
+// NormalizeSkills 预览技能归一化结果
+func (h *Handler) NormalizeSkills(c *gin.Context) {
+	var req struct {
+		Skills []string `json:"skills"`
+		Text   string   `json:"text"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": err.Error()})
+		return
+	}
+
+	unknown := []string{}
+	for _, s := range req.Skills {
+		if _, ok := h.dict.Canonical(s); !ok && strings.TrimSpace(s) != "" {
+			unknown = append(unknown, strings.TrimSpace(s))
+		}
+	}
+
+	data := gin.H{
+		"skills":  h.dict.Normalize(req.Skills),
+		"unknown": unknown,
+	}
+	if req.Text != "" {
+		data["extracted"] = h.dict.Extract(req.Text)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data":    data,
+	})
+}
+
+// apply 校验请求并写入技能字段，返回错误信息
+func (h *Handler) apply(skill *Skill, req SkillRequest) string {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return "技能名称不能为空"
+	}
+
+	// 名称或同义词不能与其他技能冲突
+	terms := append([]string{name}, req.Aliases...)
+	for _, term := range terms {
+		if existing, ok := h.dict.Get(term); ok && existing.ID != skill.ID && !strings.EqualFold(existing.Name, skill.Name) {
+			return "「" + strings.TrimSpace(term) + "」已被技能 " + existing.Name + " 使用"
+		}
+	}
+
+	parent := strings.TrimSpace(req.Parent)
+	if parent != "" {
+		canonical, ok := h.dict.Canonical(parent)
+		if !ok {
+			return "上级技能不存在: " + parent
+		}
+		if strings.EqualFold(canonical, name) {
+			return "上级技能不能是自身"
+		}
+		for _, ancestor := range h.dict.Ancestors(canonical) {
+			if strings.EqualFold(ancestor, name) {
+				return "上级技能设置会形成循环"
+			}
+		}
+		parent = canonical
+	}
+
+	aliases := make([]string, 0, len(req.Aliases))
+	for _, a := range req.Aliases {
+		if a = strings.TrimSpace(a); a != "" && !strings.EqualFold(a, name) {
+			aliases = append(aliases, a)
+		}
+	}
+
+	skill.Name = name
+	skill.Aliases = aliases
+	skill.Category = strings.TrimSpace(req.Category)
+	skill.Parent = parent
+	return ""
+}
+
+func (h *Handler) reload() {
+	_ = h.dict.Load(h.db)
+}
+
+func skillContains(s Skill, keyword string) bool {
+	if strings.Contains(strings.ToLower(s.Name), keyword) {
+		return true
+	}
+	for _, a := range s.Aliases {
+		if strings.Contains(strings.ToLower(a), keyword) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,343 @@
+package skills
+
+import (
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// Skill 技能字典条目
+type Skill struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	Name      string         `gorm:"size:100;uniqueIndex;not null" json:"name"` // 规范名称，如 Go
+	Aliases   pq.StringArray `gorm:"type:text[]" json:"aliases"`                // 同义词，如 Golang
+	Category  string         `gorm:"size:50;index" json:"category"`             // language, frontend, backend, database, devops, bigdata, ai, architecture
+	Parent    string         `gorm:"size:100" json:"parent"`                    // 上级技能规范名称，如 Spring Boot -> Spring
+}
+
+// TableName 设置表名
+func (Skill) TableName() string {
+	return "skills"
+}
+
+// maxParentDepth 上级技能链的最大深度，防止配置出环
+const maxParentDepth = 8
+
+// Dictionary 技能字典，负责同义词归一化与层级匹配
+type Dictionary struct {
+	mu     sync.RWMutex
+	skills []Skill             // 按配置顺序排列
+	byName map[string]int      // 规范名称小写 -> skills 下标
+	index  map[string]string   // 规范名称/同义词小写 -> 规范名称
+	exact  map[string][]string // 易与英文单词混淆的短词小写 -> 原始写法，见 ambiguous
+}
+
+// ambiguousTerms 与常见英文单词相同的技能名
+var ambiguousTerms = map[string]bool{"go": true}
+
+// ambiguous 单个字母（R、C）或与常见英文单词相同（Go）的技能名，
+// 从文本提取时只按字典中的原始大小写匹配，避免 "go to market" 命中 Go
+func ambiguous(term string) bool {
+	return len(term) == 1 || ambiguousTerms[term]
+}
+
+// NewDictionary 使用内置技能创建字典
+func NewDictionary() *Dictionary {
+	d := &Dictionary{}
+	d.Replace(DefaultSkills)
+	return d
+}
+
+var (
+	defaultDict     *Dictionary
+	defaultDictOnce sync.Once
+)
+
+// Default 获取进程内共享的技能字典
+func Default() *Dictionary {
+	defaultDictOnce.Do(func() {
+		defaultDict = NewDictionary()
+	})
+	return defaultDict
+}
+
+// Replace 用给定技能列表重建字典
+func (d *Dictionary) Replace(list []Skill) {
+	skills := make([]Skill, 0, len(list))
+	byName := make(map[string]int, len(list))
+	index := make(map[string]string, len(list)*2)
+	exact := make(map[string][]string)
+
+	for _, s := range list {
+		name := strings.TrimSpace(s.Name)
+		if name == "" {
+			continue
+		}
+		key := strings.ToLower(name)
+		if _, dup := byName[key]; dup {
+			continue
+		}
+		s.Name = name
+		byName[key] = len(skills)
+		skills = append(skills, s)
+		index[key] = name
+		if ambiguous(key) {
+			exact[key] = append(exact[key], name)
+		}
+	}
+
+	// 同义词不覆盖规范名称
+	for _, s := range skills {
+		for _, alias := range s.Aliases {
+			key := strings.ToLower(strings.TrimSpace(alias))
+			if key == "" {
+				continue
+			}
+			if _, exists := index[key]; !exists {
+				index[key] = s.Name
+			}
+			if ambiguous(key) && index[key] == s.Name {
+				exact[key] = append(exact[key], strings.TrimSpace(alias))
+			}
+		}
+	}
+
+	d.mu.Lock()
+	d.skills = skills
+	d.byName = byName
+	d.index = index
+	d.exact = exact
+	d.mu.Unlock()
+}
+
+// Load 从数据库加载技能字典，表为空时保留当前内容
+func (d *Dictionary) Load(db *gorm.DB) error {
+	if db == nil {
+		return nil
+	}
+	var list []Skill
+	if err := db.Order("id ASC").Find(&list).Error; err != nil {
+		return err
+	}
+	if len(list) > 0 {
+		d.Replace(list)
+	}
+	return nil
+}
+
+// Seed 技能表为空时写入内置技能
+func Seed(db *gorm.DB) error {
+	var count int64
+	if err := db.Model(&Skill{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	seeds := make([]Skill, len(DefaultSkills))
+	copy(seeds, DefaultSkills)
+	return db.Create(&seeds).Error
+}
+
+// Watch 定期从数据库刷新字典，使其他服务的修改生效
+func (d *Dictionary) Watch(db *gorm.DB, interval time.Duration) {
+	if db == nil || interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			_ = d.Load(db)
+		}
+	}()
+}
+
+// List 返回字典中的全部技能
+func (d *Dictionary) List() []Skill {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	out := make([]Skill, len(d.skills))
+	copy(out, d.skills)
+	return out
+}
+
+// Canonical 返回技能的规范名称，未收录时返回去除空白的原值和 false
+func (d *Dictionary) Canonical(name string) (string, bool) {
+	trimmed := strings.TrimSpace(name)
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if canonical, ok := d.index[strings.ToLower(trimmed)]; ok {
+		return canonical, true
+	}
+	return trimmed, false
+}
+
+// Get 按规范名称或同义词查找技能，两次查找在同一把读锁内完成，避免与 Replace 交错
+func (d *Dictionary) Get(name string) (Skill, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	canonical, ok := d.index[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return Skill{}, false
+	}
+	return d.skills[d.byName[strings.ToLower(canonical)]], true
+}
+
+// Category 返回技能分类，未收录时返回空字符串
+func (d *Dictionary) Category(name string) string {
+	skill, ok := d.Get(name)
+	if !ok {
+		return ""
+	}
+	return skill.Category
+}
+
+// Normalize 将技能列表归一化为规范名称并去重，未收录的技能保留原文
+func (d *Dictionary) Normalize(names []string) []string {
+	result := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		canonical, _ := d.Canonical(name)
+		if canonical == "" {
+			continue
+		}
+		key := strings.ToLower(canonical)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, canonical)
+	}
+	return result
+}
+
+// Ancestors 返回技能的上级技能链（不含自身）
+func (d *Dictionary) Ancestors(name string) []string {
+	canonical, ok := d.Canonical(name)
+	if !ok {
+		return nil
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	var chain []string
+	current := canonical
+	for i := 0; i < maxParentDepth; i++ {
+		idx, ok := d.byName[strings.ToLower(current)]
+		if !ok {
+			break
+		}
+		parent := d.skills[idx].Parent
+		if parent == "" {
+			break
+		}
+		if p, ok := d.index[strings.ToLower(parent)]; ok {
+			parent = p
+		}
+		if strings.EqualFold(parent, canonical) {
+			break
+		}
+		chain = append(chain, parent)
+		current = parent
+	}
+	return chain
+}
+
+// Matches 判断候选技能是否满足要求技能：
+// 同一规范名称，或候选技能是要求技能的下级（如 Spring Boot 满足 Spring）
+func (d *Dictionary) Matches(have, want string) bool {
+	haveCanonical, _ := d.Canonical(have)
+	wantCanonical, _ := d.Canonical(want)
+	if haveCanonical == "" || wantCanonical == "" {
+		return false
+	}
+	if strings.EqualFold(haveCanonical, wantCanonical) {
+		return true
+	}
+	for _, ancestor := range d.Ancestors(haveCanonical) {
+		if strings.EqualFold(ancestor, wantCanonical) {
+			return true
+		}
+	}
+	return false
+}
+
+// Extract 从自由文本中提取技能，返回去重后的规范名称（按字典顺序）
+func (d *Dictionary) Extract(text string) []string {
+	lower := strings.ToLower(text)
+
+	d.mu.RLock()
+	found := make(map[string]bool)
+	for term, canonical := range d.index {
+		if d.containsSkill(text, lower, term) {
+			found[strings.ToLower(canonical)] = true
+		}
+	}
+	var result []string
+	for _, s := range d.skills {
+		if found[strings.ToLower(s.Name)] {
+			result = append(result, s.Name)
+		}
+	}
+	d.mu.RUnlock()
+
+	return result
+}
+
+// containsSkill 判断文本中是否出现该技能词，易混淆的短词按原始大小写匹配；调用方需持有读锁
+func (d *Dictionary) containsSkill(text, lower, term string) bool {
+	spellings, ok := d.exact[term]
+	if !ok {
+		return containsTerm(lower, term)
+	}
+	for _, spelling := range spellings {
+		if containsTerm(text, spelling) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsTerm 判断文本中是否出现该词；
+// 以字母数字开头或结尾的英文词需要满足词边界，避免 Go 命中 Google
+func containsTerm(text, term string) bool {
+	start := 0
+	for {
+		i := strings.Index(text[start:], term)
+		if i < 0 {
+			return false
+		}
+		i += start
+		end := i + len(term)
+		if boundaryOK(text, i, end, term) {
+			return true
+		}
+		start = i + 1
+		if start >= len(text) {
+			return false
+		}
+	}
+}
+
+func boundaryOK(text string, start, end int, term string) bool {
+	first := rune(term[0])
+	last := rune(term[len(term)-1])
+	if isASCIIWord(first) && start > 0 && isASCIIWord(rune(text[start-1])) {
+		return false
+	}
+	if isASCIIWord(last) && end < len(text) && isASCIIWord(rune(text[end])) {
+		return false
+	}
+	return true
+}
+
+func isASCIIWord(r rune) bool {
+	return r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
@@ -0,0 +1,130 @@
+package skills
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonical(t *testing.T) {
+	dict := NewDictionary()
+
+	tests := []struct {
+		input    string
+		expected string
+		known    bool
+	}{
+		{"Golang", "Go", true},
+		{" go ", "Go", true},
+		{"Vue3", "Vue", true},
+		{"vue.js", "Vue", true},
+		{"k8s", "Kubernetes", true},
+		{"SpringBoot", "Spring Boot", true},
+		{"Erlang", "Erlang", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			canonical, ok := dict.Canonical(tt.input)
+			assert.Equal(t, tt.expected, canonical)
+			assert.Equal(t, tt.known, ok)
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	dict := NewDictionary()
+
+	result := dict.Normalize([]string{"Golang", "Go", "Vue.js", "Vue3", "", "Erlang"})
+	assert.Equal(t, []string{"Go", "Vue", "Erlang"}, result)
+}
+
+func TestMatches(t *testing.T) {
+	dict := NewDictionary()
+
+	assert.True(t, dict.Matches("Golang", "Go"))
+	assert.True(t, dict.Matches("Spring Boot", "Spring"))
+	assert.True(t, dict.Matches("Spring Boot", "Java"))
+	assert.False(t, dict.Matches("Spring", "Spring Boot"), "上级技能不能满足下级技能要求")
+	assert.False(t, dict.Matches("Java", "JavaScript"))
+}
+
+func TestAncestorsStopsOnCycle(t *testing.T) {
+	dict := &Dictionary{}
+	dict.Replace([]Skill{
+		{Name: "A", Parent: "B"},
+		{Name: "B", Parent: "A"},
+	})
+
+	assert.Equal(t, []string{"B"}, dict.Ancestors("A"))
+}
+
+func TestExtract(t *testing.T) {
+	dict := NewDictionary()
+
+	tests := []struct {
+		name     string
+		text     string
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "同义词归一",
+			text:     "熟练使用Golang和Vue3开发，了解K8s",
+			contains: []string{"Go", "Vue", "Kubernetes"},
+		},
+		{
+			name:     "英文词边界",
+			text:     "Worked at Google on JavaScript tooling",
+			contains: []string{"JavaScript"},
+			excludes: []string{"Go", "Java"},
+		},
+		{
+			name:     "中文上下文",
+			text:     "Go语言开发，使用Gin框架",
+			contains: []string{"Go", "Gin"},
+		},
+		{
+			name:     "英文动词 go 不算技能",
+			text:     "Ready to go the extra mile, will go to market quickly",
+			excludes: []string{"Go"},
+		},
+		{
+			name:     "英文简历中的 Go",
+			text:     "Built microservices in Go and Python",
+			contains: []string{"Go", "Python"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := dict.Extract(tt.text)
+			for _, s := range tt.contains {
+				assert.Contains(t, result, s)
+			}
+			for _, s := range tt.excludes {
+				assert.NotContains(t, result, s)
+			}
+		})
+	}
+}
+
+func TestExtractSingleLetterSkills(t *testing.T) {
+	dict := &Dictionary{}
+	dict.Replace([]Skill{{Name: "R"}, {Name: "C"}, {Name: "C++"}})
+
+	assert.Equal(t, []string{"R", "C"}, dict.Extract("Statistics with R, firmware in C"))
+	assert.Empty(t, dict.Extract("plan a, option c, r&d budget"))
+	assert.Equal(t, []string{"C++"}, dict.Extract("熟悉 c++ 开发"))
+}
+
+func TestGet(t *testing.T) {
+	dict := NewDictionary()
+
+	skill, ok := dict.Get(" golang ")
+	assert.True(t, ok)
+	assert.Equal(t, "Go", skill.Name)
+
+	_, ok = dict.Get("不存在的技能")
+	assert.False(t, ok)
+}
@@ -0,0 +1,173 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 本地签名地址校验失败原因
+var (
+	ErrURLExpired       = errors.New("storage: signed url expired")
+	ErrInvalidSignature = errors.New("storage: invalid signature")
+)
+
+// Local 本地文件系统存储。签名地址指向业务服务的文件接口，由服务调用 Verify 校验后提供文件，
+// 多副本部署时需挂载共享目录，否则应使用 S3 存储
+type Local struct {
+	root      string
+	publicURL string
+	key       []byte
+	now       func() time.Time
+}
+
+// NewLocal 创建本地存储
+func NewLocal(root, publicURL, signingKey string) (*Local, error) {
+	if root == "" {
+		return nil, errors.New("storage: local dir is required")
+	}
+	if signingKey == "" {
+		return nil, errors.New("storage: signing key is required")
+	}
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, err
+	}
+	return &Local{
+		root:      root,
+		publicURL: strings.TrimRight(publicURL, "/"),
+		key:       []byte(signingKey),
+		now:       time.Now,
+	}, nil
+}
+
+// Path 对象在本地磁盘上的路径
+func (l *Local) Path(key string) (string, error) {
+	key, err := CleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(l.root, filepath.FromSlash(key)), nil
+}
+
+// Put 先写临时文件再改名，避免读到写了一半的文件
+func (l *Local) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	p, err := l.Path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+// Get 返回的 *os.File 支持 Seek，可直接用于 http.ServeContent
+func (l *Local) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	p, err := l.Path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(p)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+func (l *Local) Delete(ctx context.Context, key string) error {
+	p, err := l.Path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (l *Local) Exists(ctx context.Context, key string) (bool, error) {
+	p, err := l.Path(key)
+	if err != nil {
+		return false, err
+	}
+	_, err = os.Stat(p)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// SignedURL 生成 {publicURL}/{key}?expires=&signature=[&filename=] 形式的限时地址
+func (l *Local) SignedURL(ctx context.Context, key string, ttl time.Duration, opts SignOptions) (string, error) {
+	key, err := CleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	expires := strconv.FormatInt(l.now().Add(ttl).Unix(), 10)
+
+	q := url.Values{}
+	q.Set("expires", expires)
+	q.Set("signature", l.sign(key, expires, opts.Filename))
+	if opts.Filename != "" {
+		q.Set("filename", opts.Filename)
+	}
+	return l.publicURL + "/" + escapePath(key) + "?" + q.Encode(), nil
+}
+
+// Verify 校验签名地址的参数
+func (l *Local) Verify(key, expires, filename, signature string) error {
+	key, err := CleanKey(key)
+	if err != nil {
+		return err
+	}
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(signature), []byte(l.sign(key, expires, filename))) {
+		return ErrInvalidSignature
+	}
+	if l.now().Unix() > exp {
+		return ErrURLExpired
+	}
+	return nil
+}
+
+func (l *Local) sign(key, expires, filename string) string {
+	mac := hmac.New(sha256.New, l.key)
+	fmt.Fprintf(mac, "%s\n%s\n%s", key, expires, filename)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// escapePath 逐段转义路径，保留“/”
+func escapePath(key string) string {
+	parts := strings.Split(key, "/")
+	for i, p := range parts {
+		parts[i] = url.PathEscape(p)
+	}
+	return strings.Join(parts, "/")
+}
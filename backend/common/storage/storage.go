@@ -0,0 +1,160 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotFound 对象不存在
+var ErrNotFound = errors.New("storage: object not found")
+
+// Storage 对象存储，key 为以“/”分隔的相对路径，如 resumes/2024/06/xxx.pdf
+type Storage interface {
+	// Put 写入对象，size 未知时传 -1
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get 读取对象，不存在时返回 ErrNotFound
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete 删除对象，对象不存在时不报错
+	Delete(ctx context.Context, key string) error
+	// Exists 对象是否存在
+	Exists(ctx context.Context, key string) (bool, error)
+	// SignedURL 生成限时访问地址
+	SignedURL(ctx context.Context, key string, ttl time.Duration, opts SignOptions) (string, error)
+}
+
+// SignOptions 签名地址选项
+type SignOptions struct {
+	// Filename 非空时以附件形式下载并使用该文件名，否则浏览器内联打开（如 PDF 预览）
+	Filename string
+}
+
+// Config 存储配置
+type Config struct {
+	Driver string // local, s3
+
+	// 本地文件系统
+	LocalDir   string // 文件根目录
+	PublicURL  string // 签名地址前缀，由业务服务校验签名后提供文件，如 /api/v1/resumes/file
+	SigningKey string // 本地签名地址的 HMAC 密钥
+
+	// S3 兼容存储（AWS S3、MinIO 等）
+	S3 S3Config
+}
+
+// ConfigFromEnv 从环境变量读取存储配置
+//
+//	STORAGE_DRIVER              local（默认）或 s3
+//	STORAGE_LOCAL_DIR           本地存储目录，默认 defaultDir
+//	STORAGE_PUBLIC_URL          本地签名地址前缀，默认 defaultPublicURL
+//	STORAGE_SIGNING_KEY         本地签名密钥，不与 JWT_SECRET 共用；未配置时每次启动随机生成
+//	STORAGE_S3_ENDPOINT         S3 服务地址，如 http://minio:9000，为空时使用 AWS
+//	STORAGE_S3_PUBLIC_ENDPOINT  签名地址使用的服务地址（浏览器可访问），默认同 ENDPOINT
+//	STORAGE_S3_REGION           区域，默认 us-east-1
+//	STORAGE_S3_BUCKET           存储桶
+//	STORAGE_S3_ACCESS_KEY / STORAGE_S3_SECRET_KEY
+//	STORAGE_S3_PATH_STYLE       是否使用路径风格地址，配置了 ENDPOINT 时默认 true（MinIO 需要）
+func ConfigFromEnv(defaultDir, defaultPublicURL string) Config {
+	endpoint := os.Getenv("STORAGE_S3_ENDPOINT")
+	pathStyle, err := strconv.ParseBool(os.Getenv("STORAGE_S3_PATH_STYLE"))
+	if err != nil {
+		pathStyle = endpoint != ""
+	}
+
+	return Config{
+		Driver:     getEnv("STORAGE_DRIVER", "local"),
+		LocalDir:   getEnv("STORAGE_LOCAL_DIR", defaultDir),
+		PublicURL:  getEnv("STORAGE_PUBLIC_URL", defaultPublicURL),
+		SigningKey: os.Getenv("STORAGE_SIGNING_KEY"),
+		S3: S3Config{
+			Endpoint:       endpoint,
+			PublicEndpoint: os.Getenv("STORAGE_S3_PUBLIC_ENDPOINT"),
+			Region:         getEnv("STORAGE_S3_REGION", "us-east-1"),
+			Bucket:         os.Getenv("STORAGE_S3_BUCKET"),
+			AccessKey:      os.Getenv("STORAGE_S3_ACCESS_KEY"),
+			SecretKey:      os.Getenv("STORAGE_S3_SECRET_KEY"),
+			PathStyle:      pathStyle,
+		},
+	}
+}
+
+// New 按配置创建存储
+func New(cfg Config) (Storage, error) {
+	switch cfg.Driver {
+	case "", "local":
+		key := cfg.SigningKey
+		if key == "" {
+			// 随机密钥签发的地址在重启后失效，多实例部署时也无法互相校验，生产环境应配置 STORAGE_SIGNING_KEY
+			b := make([]byte, 32)
+			if _, err := rand.Read(b); err != nil {
+				return nil, fmt.Errorf("storage: generate signing key: %w", err)
+			}
+			key = hex.EncodeToString(b)
+			log.Printf("storage: STORAGE_SIGNING_KEY is not set, using a random signing key")
+		}
+		return NewLocal(cfg.LocalDir, cfg.PublicURL, key)
+	case "s3":
+		return NewS3(cfg.S3)
+	default:
+		return nil, fmt.Errorf("storage: unknown driver %q", cfg.Driver)
+	}
+}
+
+// CleanKey 规范化对象 key，拒绝越出根目录的路径
+func CleanKey(key string) (string, error) {
+	key = strings.ReplaceAll(key, "\\", "/")
+	cleaned := path.Clean("/" + key)[1:]
+	if cleaned == "" || cleaned != strings.TrimPrefix(key, "/") || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("storage: invalid key %q", key)
+	}
+	return cleaned, nil
+}
+
+// UploadFile 将本地文件写入存储，用于把历史文件迁移到对象存储
+func UploadFile(ctx context.Context, s Storage, key, filePath, contentType string) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	return s.Put(ctx, key, f, info.Size(), contentType)
+}
+
+// ContentType 按扩展名推断简历等常见文件的 Content-Type
+func ContentType(name string) string {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".pdf":
+		return "application/pdf"
+	case ".doc":
+		return "application/msword"
+	case ".docx":
+		return "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	case ".md":
+		return "text/markdown; charset=utf-8"
+	case ".json":
+		return "application/json"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,42 @@
+package textdiff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []Line
+	}{
+		{"相同文本", "a\nb", "a\nb", []Line{{OpEqual, "a"}, {OpEqual, "b"}}},
+		{"新增一行", "a\nc", "a\nb\nc", []Line{{OpEqual, "a"}, {OpInsert, "b"}, {OpEqual, "c"}}},
+		{"删除一行", "a\nb\nc", "a\nc", []Line{{OpEqual, "a"}, {OpDelete, "b"}, {OpEqual, "c"}}},
+		{"修改一行", "a\nb", "a\nx", []Line{{OpEqual, "a"}, {OpDelete, "b"}, {OpInsert, "x"}}},
+		{"空文本", "", "a", []Line{{OpInsert, "a"}}},
+		{"兼容 CRLF", "a\r\nb\r\n", "a\nb", []Line{{OpEqual, "a"}, {OpEqual, "b"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Lines(tt.a, tt.b))
+		})
+	}
+}
+
+func TestChanged(t *testing.T) {
+	assert.False(t, Changed(Lines("a\nb", "a\nb")))
+	assert.True(t, Changed(Lines("a", "b")))
+}
+
+func TestSetDiff(t *testing.T) {
+	added, removed := SetDiff([]string{"Go", "MySQL", "Redis"}, []string{"Go", "PostgreSQL", "Redis"})
+	assert.Equal(t, []string{"PostgreSQL"}, added)
+	assert.Equal(t, []string{"MySQL"}, removed)
+
+	added, removed = SetDiff(nil, nil)
+	assert.Empty(t, added)
+	assert.Empty(t, removed)
+}
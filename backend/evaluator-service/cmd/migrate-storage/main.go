@@ -0,0 +1,101 @@
+// migrate-storage 将候选人简历 PDF 从本地 storage.base_dir 迁移到当前配置的存储，
+// 并把 pdf_path 统一改写为存储 key。可重复执行：
+//
+//	go run ./cmd/migrate-storage -dry-run
+//	RESUME_STORAGE_DRIVER=s3 RESUME_STORAGE_S3_ENDPOINT=http://localhost:9000 ... go run ./cmd/migrate-storage
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+
+	"evaluator-service/internal/config"
+	"evaluator-service/internal/database"
+	"evaluator-service/internal/filestore"
+	"evaluator-service/internal/logging"
+	"evaluator-service/internal/models"
+
+	"common/storage"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "只列出待迁移的文件，不实际迁移")
+	deleteSource := flag.Bool("delete-source", false, "迁移成功后删除本地文件（仅对 s3 存储生效）")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("load config: %v", err)
+	}
+	logger := logging.New()
+	defer logger.Sync()
+
+	if err := database.Init(cfg, logger); err != nil {
+		log.Fatalf("db init failed: %v", err)
+	}
+	if err := filestore.Init(cfg); err != nil {
+		log.Fatalf("storage init failed: %v", err)
+	}
+	// 本地存储与源目录相同，只需改写 pdf_path
+	copyFiles := cfg.Storage.Driver == "s3"
+
+	var candidates []models.Candidate
+	if err := database.DB.Where("pdf_path != ''").Order("id").Find(&candidates).Error; err != nil {
+		log.Fatalf("query candidates: %v", err)
+	}
+
+	ctx := context.Background()
+	var migrated, skipped, missing, failed int
+	for _, cand := range candidates {
+		key := filestore.Key(cand.PDFPath)
+		source := filepath.Join(cfg.Storage.BaseDir, filepath.FromSlash(key))
+
+		if key == cand.PDFPath && (!copyFiles || filestore.Exists(ctx, key)) {
+			skipped++
+			continue
+		}
+		if _, err := os.Stat(source); err != nil {
+			log.Printf("candidate %d: source file missing: %s", cand.ID, source)
+			missing++
+			continue
+		}
+		if *dryRun {
+			log.Printf("candidate %d: %s -> %s", cand.ID, source, key)
+			migrated++
+			continue
+		}
+
+		if copyFiles {
+			if err := storage.UploadFile(ctx, filestore.Store, key, source, storage.ContentType(key)); err != nil {
+				log.Printf("candidate %d: upload failed: %v", cand.ID, err)
+				failed++
+				continue
+			}
+			if !filestore.Exists(ctx, key) {
+				log.Printf("candidate %d: uploaded object not found", cand.ID)
+				failed++
+				continue
+			}
+		}
+		if err := database.DB.Model(&models.Candidate{}).Where("id = ?", cand.ID).Update("pdf_path", key).Error; err != nil {
+			log.Printf("candidate %d: failed to update record: %v", cand.ID, err)
+			failed++
+			continue
+		}
+		if copyFiles && *deleteSource {
+			if err := os.Remove(source); err != nil {
+				log.Printf("candidate %d: failed to delete source: %v", cand.ID, err)
+			}
+		}
+		migrated++
+	}
+
+	log.Printf("Storage migration finished: migrated=%d skipped=%d missing=%d failed=%d dry_run=%v",
+		migrated, skipped, missing, failed, *dryRun)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
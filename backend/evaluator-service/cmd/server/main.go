@@ -11,6 +11,7 @@ import (
 	"evaluator-service/internal/api"
 	"evaluator-service/internal/config"
 	"evaluator-service/internal/database"
+	"evaluator-service/internal/filestore"
 	"evaluator-service/internal/logging"
 	"evaluator-service/internal/repository"
 	"evaluator-service/internal/script"
@@ -30,6 +31,9 @@ func main() {
 	if err := database.Init(cfg, logger); err != nil {
 		logger.Fatal("db init failed", logging.Err(err))
 	}
+	if err := filestore.Init(cfg); err != nil {
+		logger.Fatal("storage init failed", logging.Err(err))
+	}
 
 	// Initialize Coze client
 	coze.Init(cfg)
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"evaluator-service/internal/api/middleware"
+	"evaluator-service/internal/models"
+	"evaluator-service/internal/utils"
+
+	"common/blind"
+
+	"github.com/gin-gonic/gin"
+)
+
+// revealedStatuses 进入面试后不再盲筛
+var revealedStatuses = map[string]bool{"已面试": true, "已录用": true}
+
+// blindScope 当前用户可见范围内开启盲筛的岗位
+type blindScope struct {
+	posts map[string]bool
+}
+
+// post 岗位是否开启了盲筛
+func (s blindScope) post(postID string) bool {
+	return postID != "" && s.posts[postID]
+}
+
+// candidate 候选人是否仍处于盲筛中：岗位开启了盲筛且尚未进入面试
+func (s blindScope) candidate(cand *models.Candidate) bool {
+	return s.post(cand.PostID) && !revealedStatuses[cand.Status]
+}
+
+// blindScope 加载当前用户的盲筛岗位，配置在 blind.reveal_users 中的用户不受盲筛限制
+func (h *Handlers) blindScope(c *gin.Context) (blindScope, error) {
+	username := middleware.GetUsername(c)
+	for _, u := range h.cfg.Blind.RevealUsers {
+		if u != "" && u == username {
+			return blindScope{}, nil
+		}
+	}
+	posts, err := h.getPositionService().BlindPostIDs(middleware.GetUserID(c))
+	if err != nil {
+		return blindScope{}, err
+	}
+	return blindScope{posts: posts}, nil
+}
+
+// redactCandidates 隐藏盲筛中候选人的身份信息，返回被隐藏的候选人
+func (h *Handlers) redactCandidates(c *gin.Context, list []models.Candidate) (map[uint]bool, error) {
+	redacted := map[uint]bool{}
+	if len(list) == 0 {
+		return redacted, nil
+	}
+	scope, err := h.blindScope(c)
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		if scope.candidate(&list[i]) {
+			redactCandidate(&list[i])
+			redacted[list[i].ID] = true
+		}
+	}
+	return redacted, nil
+}
+
+// redactCandidate 以编号代替姓名，并抹去评语、报告和简历中的身份信息；原始文件不可访问
+func redactCandidate(cand *models.Candidate) {
+	id := blind.Identity{Name: cand.Name}
+	cand.Name = blind.Alias(cand.ID)
+	cand.Filename = fmt.Sprintf("candidate-%d.pdf", cand.ID)
+	cand.PDFPath = ""
+	for _, s := range []*string{
+		&cand.AgeReason, &cand.ExperienceReason, &cand.EducationReason,
+		&cand.CompanyReason, &cand.TechReason, &cand.ProjectReason,
+		&cand.ReportMarkdown, &cand.ResumeMarkdown,
+	} {
+		*s = id.RedactText(*s)
+	}
+	var report any
+	if cand.CozeReportJSON != "" && json.Unmarshal([]byte(cand.CozeReportJSON), &report) == nil {
+		if b, err := json.Marshal(id.RedactValue(report)); err == nil {
+			cand.CozeReportJSON = string(b)
+		}
+	} else {
+		cand.CozeReportJSON = id.RedactText(cand.CozeReportJSON)
+	}
+}
+
+// reportHTML 按隐藏后的报告重新生成 HTML
+func reportHTML(markdown string) string {
+	html, _ := utils.MarkdownToHTML(markdown)
+	return html
+}
+
+// SetPositionBlind 开启或关闭岗位的盲筛
+func (h *Handlers) SetPositionBlind(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		bad(c, fmt.Errorf("无效的岗位ID"))
+		return
+	}
+	var req struct {
+		BlindScreening bool `json:"blind_screening"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bad(c, err)
+		return
+	}
+	found, err := h.getPositionService().SetBlindScreening(uint(id), middleware.GetUserID(c), req.BlindScreening)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "岗位不存在"})
+		return
+	}
+	ok(c, gin.H{"success": true, "blind_screening": req.BlindScreening})
+}
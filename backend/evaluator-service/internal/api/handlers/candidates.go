@@ -1,16 +1,22 @@
 package handlers
 
 import (
+	"errors"
+	"mime"
 	"net/http"
-	"os"
 	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
 
 	"evaluator-service/internal/api/middleware"
+	"evaluator-service/internal/filestore"
+	"evaluator-service/internal/logging"
+	"evaluator-service/internal/models"
 	"evaluator-service/internal/repository"
 
+	"common/storage"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -44,6 +50,11 @@ func (h *Handlers) GetCandidates(c *gin.Context) {
 		fail(c, err)
 		return
 	}
+	redacted, err := h.redactCandidates(c, list)
+	if err != nil {
+		fail(c, err)
+		return
+	}
 	out := make([]gin.H, 0, len(list))
 	for _, cnd := range list {
 		out = append(out, gin.H{
@@ -63,6 +74,7 @@ func (h *Handlers) GetCandidates(c *gin.Context) {
 			"status":           cnd.Status,
 			"created_at":       cnd.CreatedAt.Format("2006-01-02 15:04"),
 			"notes":            cnd.Notes,
+			"redacted":         redacted[cnd.ID],
 		})
 	}
 	ok(c, out)
@@ -76,12 +88,19 @@ func (h *Handlers) GetCandidate(c *gin.Context) {
 		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 		return
 	}
+	list := []models.Candidate{*cand}
+	redacted, err := h.redactCandidates(c, list)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	cand = &list[0]
 	ok(c, gin.H{
 		"id":                cand.ID,
 		"name":              cand.Name,
 		"filename":          cand.Filename,
 		"pdf_path":          cand.PDFPath,
-		"has_pdf":           cand.PDFPath != "" && fileExists(cand.PDFPath),
+		"has_pdf":           filestore.Exists(c.Request.Context(), cand.PDFPath),
 		"total_score":       cand.TotalScore,
 		"grade":             cand.Grade,
 		"jd_match":          cand.JDMatch,
@@ -104,6 +123,7 @@ func (h *Handlers) GetCandidate(c *gin.Context) {
 		"status":            cand.Status,
 		"created_at":        cand.CreatedAt.Format("2006-01-02 15:04"),
 		"notes":             cand.Notes,
+		"redacted":          redacted[cand.ID],
 	})
 }
 
@@ -115,15 +135,35 @@ func (h *Handlers) GetCandidateResume(c *gin.Context) {
 		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 		return
 	}
-	if cand.PDFPath == "" || !fileExists(cand.PDFPath) {
+	scope, err := h.blindScope(c)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if scope.candidate(cand) {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "该候选人处于盲筛阶段，暂不提供原始简历"})
+		return
+	}
+	if cand.PDFPath == "" {
 		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "简历文件不存在"})
 		return
 	}
+	rc, err := filestore.Open(c.Request.Context(), cand.PDFPath)
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotFound) {
+			h.log.Error("Failed to open resume PDF", logging.Err(err), logging.KV("path", cand.PDFPath))
+		}
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "简历文件不存在"})
+		return
+	}
+	defer rc.Close()
 	name := cand.Filename
 	if name == "" {
 		name = cand.Name + "_简历.pdf"
 	}
-	c.FileAttachment(cand.PDFPath, filepath.Base(name))
+	c.DataFromReader(http.StatusOK, -1, "application/pdf", rc, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": filepath.Base(name)}),
+	})
 }
 
 func (h *Handlers) UpdateCandidateStatus(c *gin.Context) {
@@ -168,9 +208,7 @@ func (h *Handlers) DeleteCandidate(c *gin.Context) {
 		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 		return
 	}
-	if cand.PDFPath != "" {
-		_ = os.Remove(cand.PDFPath)
-	}
+	_ = filestore.Delete(c.Request.Context(), cand.PDFPath)
 	if err := h.repo.DeleteByUser(uint(id), userID); err != nil {
 		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 		return
@@ -182,9 +220,7 @@ func (h *Handlers) DeleteAllCandidates(c *gin.Context) {
 	userID := middleware.GetUserID(c)
 	list, _ := h.repo.AllByUser(userID)
 	for _, x := range list {
-		if x.PDFPath != "" {
-			_ = os.Remove(x.PDFPath)
-		}
+		_ = filestore.Delete(c.Request.Context(), x.PDFPath)
 	}
 	n, err := h.repo.DeleteAllByUser(userID)
 	if err != nil {
@@ -288,6 +324,11 @@ func (h *Handlers) CompareCandidates(c *gin.Context) {
 		fail(c, err)
 		return
 	}
+	redacted, err := h.redactCandidates(c, list)
+	if err != nil {
+		fail(c, err)
+		return
+	}
 	sort.Slice(list, func(i, j int) bool { return list[i].TotalScore > list[j].TotalScore })
 	out := make([]gin.H, 0, len(list))
 	for _, cnd := range list {
@@ -311,6 +352,7 @@ func (h *Handlers) CompareCandidates(c *gin.Context) {
 			"project_reason":    cnd.ProjectReason,
 			"recommendation":    cnd.Recommendation,
 			"status":            cnd.Status,
+			"redacted":          redacted[cnd.ID],
 		})
 	}
 	ok(c, gin.H{"success": true, "candidates": out})
@@ -331,6 +373,10 @@ func (h *Handlers) ExportCompareReport(c *gin.Context) {
 		fail(c, err)
 		return
 	}
+	if _, err := h.redactCandidates(c, list); err != nil {
+		fail(c, err)
+		return
+	}
 	sort.Slice(list, func(i, j int) bool { return list[i].TotalScore > list[j].TotalScore })
 	b, err := h.exprt.ExcelCompare(list)
 	if err != nil {
@@ -343,6 +389,4 @@ func (h *Handlers) ExportCompareReport(c *gin.Context) {
 	_, _ = c.Writer.Write(b)
 }
 
-func fileExists(p string) bool { _, err := os.Stat(p); return err == nil }
-
 func round1(f float64) float64 { return float64(int(f*10+0.5)) / 10 }
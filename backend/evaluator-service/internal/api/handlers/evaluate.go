@@ -16,9 +16,10 @@ import (
 	"evaluator-service/internal/models"
 	"evaluator-service/internal/repository"
 	"evaluator-service/internal/script"
-	"evaluator-service/internal/thirdparty/coze"
 	"evaluator-service/internal/utils"
 
+	"common/blind"
+
 	"github.com/gin-gonic/gin"
 	"golang.org/x/sync/errgroup"
 )
@@ -32,7 +33,7 @@ func (h *Handlers) Evaluate(c *gin.Context) {
 	userID := middleware.GetUserID(c)
 
 	// 获取 JD：优先使用 position_id，否则使用直接传入的 jd
-	var jd string
+	var jd, postID string
 	positionIDStr := c.PostForm("position_id")
 	if positionIDStr != "" {
 		// 从数据库获取岗位 JD
@@ -52,9 +53,11 @@ func (h *Handlers) Evaluate(c *gin.Context) {
 			return
 		}
 		jd = position.GetJDText()
+		postID = position.PostID
 	} else {
 		jd = c.PostForm("jd")
 	}
+	force := c.PostForm("force") == "true" // 强制重新评估，不复用缓存结果
 
 	tmpPath, err := utils.SaveUploadedTemp(utils.Join(h.cfg.Storage.BaseDir, h.cfg.Storage.TempDir), file)
 	if err != nil {
@@ -80,7 +83,7 @@ func (h *Handlers) Evaluate(c *gin.Context) {
 	defer cancel()
 
 	h.log.Info("Calling Coze workflow")
-	cozeData, cozeErr := coze.RunWorkflow(cozeCtx, name, jd, pdfBytes)
+	cozeData, cached, cozeErr := h.cache.RunWorkflow(cozeCtx, name, jd, "", pdfBytes, force)
 
 	// 如果 Coze 调用失败，记录错误但继续使用空数据（会触发错误）
 	if cozeErr != nil {
@@ -89,6 +92,7 @@ func (h *Handlers) Evaluate(c *gin.Context) {
 	} else {
 		h.log.Info("Coze workflow completed successfully",
 			logging.KV("has_data", cozeData != nil),
+			logging.KV("cached", cached),
 			logging.KV("data_keys", getCozeDataKeys(cozeData)))
 	}
 
@@ -105,8 +109,30 @@ func (h *Handlers) Evaluate(c *gin.Context) {
 		logging.KV("candidate_id", out.Candidate.ID),
 		logging.KV("total_score", out.Candidate.TotalScore))
 
-	// 评估完成后触发自动推送（异步，不阻塞响应）
 	cand := out.Candidate
+	if postID != "" {
+		if err := h.repo.SetPostID(cand.ID, postID); err != nil {
+			fail(c, err)
+			return
+		}
+		cand.PostID = postID
+	}
+	// 盲筛岗位的结果隐藏候选人身份信息，不修改已保存的记录
+	scope, err := h.blindScope(c)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	reportMD, reportHTMLText, savedPath := out.ReportMD, out.ReportHTML, out.ReportMDPath
+	redacted := scope.candidate(cand)
+	if redacted {
+		view := *cand
+		redactCandidate(&view)
+		cand = &view
+		reportMD, reportHTMLText, savedPath = view.ReportMarkdown, reportHTML(view.ReportMarkdown), ""
+	}
+
+	// 评估完成后触发自动推送（异步，不阻塞响应）
 	go h.triggerAutoPushIfEnabled(userID, []models.Candidate{*cand})
 
 	ok(c, gin.H{
@@ -125,13 +151,20 @@ func (h *Handlers) Evaluate(c *gin.Context) {
 		},
 		"jd_match":         cand.JDMatch,
 		"recommendation":   cand.Recommendation,
-		"report_markdown":  out.ReportMD,
-		"report_html":      out.ReportHTML,
+		"report_markdown":  reportMD,
+		"report_html":      reportHTMLText,
 		"coze_report_json": cand.CozeReportJSON,
-		"saved_path":       out.ReportMDPath,
+		"saved_path":       savedPath,
+		"cached":           cached,
+		"redacted":         redacted,
 	})
 }
 
+// EvaluationCacheStats 评估结果缓存的命中统计
+func (h *Handlers) EvaluationCacheStats(c *gin.Context) {
+	ok(c, h.cache.Stats())
+}
+
 // EvaluationResultType 评估结果类型
 type EvaluationResultType string
 
@@ -174,6 +207,12 @@ func (h *Handlers) EvaluateBatch(c *gin.Context) {
 		"WT_PASSWORD": password,
 	}
 
+	scope, err := h.blindScope(c)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+
 	// 设置 SSE 响应头
 	c.Header("Content-Type", "text/event-stream")
 	c.Header("Cache-Control", "no-cache")
@@ -228,8 +267,12 @@ func (h *Handlers) EvaluateBatch(c *gin.Context) {
 		// 构建重复候选人信息
 		duplicatesInfo := make([]gin.H, 0, len(dupResult.Duplicates))
 		for _, dup := range dupResult.Duplicates {
+			name := dup.Name
+			if scope.post(applyIDToItem[dup.ApplyID].PostID) {
+				name = blind.MaskName
+			}
 			duplicatesInfo = append(duplicatesInfo, gin.H{
-				"name":         dup.Name,
+				"name":         name,
 				"apply_id":     dup.ApplyID,
 				"evaluated_at": dup.EvaluatedAt.Format("2006-01-02 15:04"),
 				"total_score":  dup.TotalScore,
@@ -309,6 +352,8 @@ func (h *Handlers) EvaluateBatch(c *gin.Context) {
 		SavedPath       string               `json:"saved_path"`
 		Rank            int                  `json:"rank"`
 		ResultType      EvaluationResultType `json:"result_type"`
+		Cached          bool                 `json:"cached"` // 复用了有效期内相同简历和 JD 的评估结果
+		Redacted        bool                 `json:"redacted,omitempty"`
 		Error           string               `json:"error,omitempty"`
 	}
 
@@ -370,7 +415,7 @@ func (h *Handlers) EvaluateBatch(c *gin.Context) {
 
 			// Call Coze with in-memory bytes (no temp file)
 			cozeCtx, cancel := context.WithTimeout(c.Request.Context(), 300*time.Second)
-			cozeData, cozeErr := coze.RunWorkflow(cozeCtx, name, jdText, pdfBytes)
+			cozeData, cached, cozeErr := h.cache.RunWorkflow(cozeCtx, name, jdText, criteria, pdfBytes, forceReevaluate)
 			cancel()
 			// Coze 错误不阻断后续评估流程，仅记录
 			if cozeErr != nil {
@@ -380,6 +425,7 @@ func (h *Handlers) EvaluateBatch(c *gin.Context) {
 
 			// Continue evaluation using in-memory bytes with ApplyID
 			out, evalErr := h.svc.EvaluateSingleBytesWithApplyID(pdfBytes, filename, jdText, criteria, cozeData, userID, item.ApplyID, existingCandidate)
+			current := name
 			if evalErr != nil {
 				results[i] = res{
 					Filename:      filename,
@@ -388,8 +434,27 @@ func (h *Handlers) EvaluateBatch(c *gin.Context) {
 					ResultType:    resultType,
 					Error:         firstErrMsg(cozeErr, evalErr),
 				}
+				if scope.post(item.PostID) {
+					current = blind.MaskName
+					results[i].Filename, results[i].CandidateName, results[i].Redacted = "", blind.MaskName, true
+				}
 			} else {
 				cand := out.Candidate
+				if item.PostID != "" {
+					if err := h.repo.SetPostID(cand.ID, item.PostID); err != nil {
+						h.log.Warn("Failed to record candidate post", logging.Err(err), logging.KV("candidate_id", cand.ID))
+					}
+					cand.PostID = item.PostID
+				}
+				reportMD, reportHTMLText, savedPath := out.ReportMD, out.ReportHTML, out.ReportMDPath
+				redacted := scope.candidate(cand)
+				if redacted {
+					view := *cand
+					redactCandidate(&view)
+					cand = &view
+					filename, current = view.Filename, view.Name
+					reportMD, reportHTMLText, savedPath = view.ReportMarkdown, reportHTML(view.ReportMarkdown), ""
+				}
 				results[i] = res{
 					Filename:        filename,
 					CandidateID:     cand.ID,
@@ -405,11 +470,13 @@ func (h *Handlers) EvaluateBatch(c *gin.Context) {
 					TechScore:       cand.TechScore,
 					ProjectScore:    cand.ProjectScore,
 					Recommendation:  cand.Recommendation,
-					ReportHTML:      out.ReportHTML,
-					ReportMarkdown:  out.ReportMD,
+					ReportHTML:      reportHTMLText,
+					ReportMarkdown:  reportMD,
 					CozeReportJSON:  cand.CozeReportJSON,
-					SavedPath:       out.ReportMDPath,
+					SavedPath:       savedPath,
 					ResultType:      resultType,
+					Cached:          cached,
+					Redacted:        redacted,
 				}
 			}
 
@@ -419,7 +486,7 @@ func (h *Handlers) EvaluateBatch(c *gin.Context) {
 				"completed":   done,
 				"total":       evalTotal,
 				"percent":     int(float64(done) / float64(evalTotal) * 100),
-				"current":     name,
+				"current":     current,
 				"result_type": resultType,
 			})
 
@@ -682,6 +749,7 @@ func (h *Handlers) EvaluateBatchGraduate(c *gin.Context) {
 		SavedPath       string               `json:"saved_path"`
 		Rank            int                  `json:"rank"`
 		ResultType      EvaluationResultType `json:"result_type"`
+		Cached          bool                 `json:"cached"` // 复用了有效期内相同简历和 JD 的评估结果
 		Error           string               `json:"error,omitempty"`
 	}
 
@@ -743,7 +811,7 @@ func (h *Handlers) EvaluateBatchGraduate(c *gin.Context) {
 
 			// 调用 Coze 工作流
 			cozeCtx, cancel := context.WithTimeout(c.Request.Context(), 300*time.Second)
-			cozeData, cozeErr := coze.RunWorkflow(cozeCtx, name, jdText, pdfBytes)
+			cozeData, cached, cozeErr := h.cache.RunWorkflow(cozeCtx, name, jdText, criteria, pdfBytes, forceReevaluate)
 			cancel()
 			if cozeErr != nil {
 				h.log.Error("Coze workflow failed", logging.Err(cozeErr), logging.KV("candidate", name))
@@ -782,6 +850,7 @@ func (h *Handlers) EvaluateBatchGraduate(c *gin.Context) {
 					CozeReportJSON:  cand.CozeReportJSON,
 					SavedPath:       out.ReportMDPath,
 					ResultType:      resultType,
+					Cached:          cached,
 				}
 			}
 
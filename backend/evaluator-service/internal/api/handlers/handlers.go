@@ -9,6 +9,7 @@ import (
 	"evaluator-service/internal/logging"
 	"evaluator-service/internal/repository"
 	"evaluator-service/internal/service"
+	"evaluator-service/internal/thirdparty/coze"
 
 	"github.com/gin-gonic/gin"
 )
@@ -24,6 +25,7 @@ type Handlers struct {
 	exprt     *service.ExportService
 	dtService *service.DingTalkService
 	authSvc   *service.AuthService
+	cache     *service.EvaluationCache
 }
 
 func New(cfg *config.Config, log *logging.Logger, dtService *service.DingTalkService, authSvc *service.AuthService) *Handlers {
@@ -44,6 +46,7 @@ func New(cfg *config.Config, log *logging.Logger, dtService *service.DingTalkSer
 		exprt:     ex,
 		dtService: dtService,
 		authSvc:   authSvc,
+		cache:     service.NewEvaluationCache(cfg, log, repository.NewEvaluationCacheRepository(database.DB), coze.RunWorkflow),
 	}
 }
 
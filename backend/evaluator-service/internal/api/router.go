@@ -46,11 +46,13 @@ func NewRouter(cfg *config.Config, log *logging.Logger, dtService *service.DingT
 		api.POST("/evaluate", h.Evaluate)
 		api.POST("/evaluate/batch", h.EvaluateBatch)
 		api.POST("/evaluate/batch/graduate", h.EvaluateBatchGraduate) // 从毕业设计后台获取简历评估
+		api.GET("/evaluate/cache/stats", h.EvaluationCacheStats)      // 评估结果缓存命中统计
 
 		// Position endpoints
 		positions := api.Group("/positions")
 		positions.GET("", h.GetPositions)
 		positions.POST("/sync", h.SyncPositions)
+		positions.PUT("/:id/blind", h.SetPositionBlind) // 开启或关闭盲筛
 
 		// Credentials endpoints
 		creds := api.Group("/credentials")
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/viper"
@@ -36,6 +37,19 @@ type StorageCfg struct {
 	Resumes   string `mapstructure:"resumes"`
 	TempDir   string `mapstructure:"temp_dir"`
 	StaticDir string `mapstructure:"static_dir"`
+	// 简历文件存储：local 存放在 base_dir 下，s3 为 S3 兼容对象存储（如 MinIO）
+	Driver string `mapstructure:"driver"`
+	S3     S3Cfg  `mapstructure:"s3"`
+}
+
+type S3Cfg struct {
+	Endpoint       string `mapstructure:"endpoint"`
+	PublicEndpoint string `mapstructure:"public_endpoint"`
+	Region         string `mapstructure:"region"`
+	Bucket         string `mapstructure:"bucket"`
+	AccessKey      string `mapstructure:"access_key"`
+	SecretKey      string `mapstructure:"secret_key"`
+	PathStyle      bool   `mapstructure:"path_style"`
 }
 
 type AICfg struct {
@@ -59,6 +73,11 @@ type CozeCfg struct {
 	WorkflowID string `mapstructure:"workflow_id"`
 }
 
+// CacheCfg 评估结果缓存：相同简历、JD、评分标准和工作流版本在有效期内复用 Coze 工作流的结果
+type CacheCfg struct {
+	TTL time.Duration `mapstructure:"ttl"` // 为 0 时关闭缓存
+}
+
 type CredentialsCfg struct {
 	EncKey string `mapstructure:"enc_key"`
 }
@@ -71,6 +90,11 @@ type GraduateCfg struct {
 	APIUrl string `mapstructure:"api_url"` // 毕业设计后台 API 地址
 }
 
+// BlindCfg 盲筛：开启盲筛的岗位在面试前隐藏候选人姓名、联系方式、学校等身份信息
+type BlindCfg struct {
+	RevealUsers []string `mapstructure:"reveal_users"` // 可查看盲筛中候选人身份信息的用户名
+}
+
 type Config struct {
 	Server      ServerCfg      `mapstructure:"server"`
 	DB          DBCfg          `mapstructure:"db"`
@@ -79,9 +103,11 @@ type Config struct {
 	Export      ExportCfg      `mapstructure:"export"`
 	Batch       BatchCfg       `mapstructure:"batch"`
 	Coze        CozeCfg        `mapstructure:"coze"`
+	Cache       CacheCfg       `mapstructure:"cache"`
 	Credentials CredentialsCfg `mapstructure:"credentials"`
 	Python      PythonCfg      `mapstructure:"python"`
 	Graduate    GraduateCfg    `mapstructure:"graduate"`
+	Blind       BlindCfg       `mapstructure:"blind"`
 }
 
 func Load() (*Config, error) {
@@ -120,6 +146,14 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("storage.resumes", "resumes")
 	v.SetDefault("storage.temp_dir", "tmp")
 	v.SetDefault("storage.static_dir", "static")
+	v.SetDefault("storage.driver", "local")
+	v.SetDefault("storage.s3.endpoint", "")
+	v.SetDefault("storage.s3.public_endpoint", "")
+	v.SetDefault("storage.s3.region", "us-east-1")
+	v.SetDefault("storage.s3.bucket", "")
+	v.SetDefault("storage.s3.access_key", "")
+	v.SetDefault("storage.s3.secret_key", "")
+	v.SetDefault("storage.s3.path_style", true)
 
 	// 导出配置
 	v.SetDefault("export.pdf_engine", "wkhtmltopdf")
@@ -136,6 +170,9 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("coze.token", "")
 	v.SetDefault("coze.workflow_id", "")
 
+	// 评估结果缓存
+	v.SetDefault("cache.ttl", "24h")
+
 	// 凭据加密密钥 -
 	v.SetDefault("credentials.enc_key", "")
 
@@ -144,4 +181,7 @@ func setDefaults(v *viper.Viper) {
 
 	// 毕业设计后台配置
 	v.SetDefault("graduate.api_url", "http://localhost:8084")
+
+	// 盲筛配置
+	v.SetDefault("blind.reveal_users", []string{})
 }
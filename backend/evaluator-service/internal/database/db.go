@@ -57,7 +57,7 @@ func setupPool(sqlDB *sql.DB, cfg *config.Config) {
 }
 
 func migrate(db *gorm.DB) error {
-	return db.AutoMigrate(&models.User{}, &models.Candidate{}, &models.Credential{}, &models.DingTalkConfig{}, &models.Position{})
+	return db.AutoMigrate(&models.User{}, &models.Candidate{}, &models.Credential{}, &models.DingTalkConfig{}, &models.Position{}, &models.EvaluationCache{})
 }
 
 func ensureDirs(cfg *config.Config) error {
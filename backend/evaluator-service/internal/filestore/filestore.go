@@ -0,0 +1,83 @@
+// Package filestore 简历 PDF 存储，支持本地目录和 S3 兼容对象存储。
+//
+// 候选人的 PDFPath 保存存储 key（如 resumes/1/xxx.pdf）；本地存储以 storage.base_dir 为根目录，
+// 迁移前保存的 data/resumes/1/xxx.pdf 形式的路径会自动换算为对应的 key。
+package filestore
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"evaluator-service/internal/config"
+
+	"common/storage"
+)
+
+var (
+	Store   storage.Storage
+	baseDir string
+)
+
+// Init 按配置初始化简历文件存储
+func Init(cfg *config.Config) error {
+	s3 := cfg.Storage.S3
+	s, err := storage.New(storage.Config{
+		Driver:   cfg.Storage.Driver,
+		LocalDir: cfg.Storage.BaseDir,
+		// 本服务自行读取文件返回给前端，不对外提供本地签名地址
+		SigningKey: "evaluator-service",
+		S3: storage.S3Config{
+			Endpoint:       s3.Endpoint,
+			PublicEndpoint: s3.PublicEndpoint,
+			Region:         s3.Region,
+			Bucket:         s3.Bucket,
+			AccessKey:      s3.AccessKey,
+			SecretKey:      s3.SecretKey,
+			PathStyle:      s3.PathStyle,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	Store = s
+	baseDir = cfg.Storage.BaseDir
+	return nil
+}
+
+// Key 将 PDFPath 换算为存储 key，兼容迁移前保存的本地路径
+func Key(pdfPath string) string {
+	p := filepath.ToSlash(filepath.Clean(pdfPath))
+	if base := filepath.ToSlash(filepath.Clean(baseDir)); base != "." && strings.HasPrefix(p, base+"/") {
+		return strings.TrimPrefix(p, base+"/")
+	}
+	return p
+}
+
+// Put 写入文件
+func Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	return Store.Put(ctx, key, r, size, storage.ContentType(key))
+}
+
+// Open 读取文件，不存在时返回 storage.ErrNotFound
+func Open(ctx context.Context, pdfPath string) (io.ReadCloser, error) {
+	return Store.Get(ctx, Key(pdfPath))
+}
+
+// Exists 文件是否存在
+func Exists(ctx context.Context, pdfPath string) bool {
+	if pdfPath == "" {
+		return false
+	}
+	ok, err := Store.Exists(ctx, Key(pdfPath))
+	return err == nil && ok
+}
+
+// Delete 删除文件，文件不存在时不报错
+func Delete(ctx context.Context, pdfPath string) error {
+	if pdfPath == "" {
+		return nil
+	}
+	return Store.Delete(ctx, Key(pdfPath))
+}
@@ -7,6 +7,7 @@ type Candidate struct {
 	UserID           uint      `json:"user_id" gorm:"index"` // 关联用户，数据隔离
 	ApplyID          string    `json:"apply_id" gorm:"size:100;index:idx_user_apply,unique,priority:2"` // 招聘系统申请ID，与UserID组合唯一
 	Name             string    `json:"name" gorm:"index;size:100"`
+	PostID           string    `json:"post_id" gorm:"size:50;index"` // 评估所依据的招聘系统岗位ID，用于判断是否处于盲筛
 	Filename         string    `json:"filename" gorm:"size:500"`
 	PDFPath          string    `json:"pdf_path" gorm:"size:500"`
 	TotalScore       float64   `json:"total_score"`
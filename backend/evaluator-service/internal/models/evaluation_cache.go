@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// EvaluationCache Coze 工作流评估结果缓存，CacheKey 由简历内容、JD、评分标准和工作流版本决定
+type EvaluationCache struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CacheKey  string    `gorm:"size:64;uniqueIndex" json:"cache_key"`
+	Version   string    `gorm:"size:200" json:"version"` // 评估服务版本，如 coze:<workflow_id>
+	Result    string    `gorm:"type:text" json:"-"`      // 工作流返回数据的 JSON
+	CreatedAt time.Time `gorm:"index" json:"created_at"` // 评估时间，缓存有效期从此算起
+}
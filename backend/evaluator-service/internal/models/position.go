@@ -11,6 +11,7 @@ type Position struct {
 	RecruitType      string    `json:"recruit_type" gorm:"size:20"`
 	ServiceCondition string    `json:"service_condition" gorm:"type:text"`
 	WorkContent      string    `json:"work_content" gorm:"type:text"`
+	BlindScreening   bool      `json:"blind_screening" gorm:"default:false"` // 盲筛：面试前隐藏候选人身份信息，同步岗位时保留
 	CreatedAt        time.Time `json:"created_at"`
 	UpdatedAt        time.Time `json:"updated_at"`
 }
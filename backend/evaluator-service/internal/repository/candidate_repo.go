@@ -40,6 +40,11 @@ func (r *CandidateRepository) UpdateByUser(c *models.Candidate, userID uint) err
 	return r.db.Save(c).Error
 }
 
+// SetPostID 记录候选人评估所依据的岗位
+func (r *CandidateRepository) SetPostID(id uint, postID string) error {
+	return r.db.Model(&models.Candidate{}).Where("id = ?", id).Update("post_id", postID).Error
+}
+
 func (r *CandidateRepository) Delete(id uint) error {
 	return r.db.Delete(&models.Candidate{}, id).Error
 }
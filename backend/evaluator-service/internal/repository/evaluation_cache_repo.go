@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"evaluator-service/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type EvaluationCacheRepository struct{ db *gorm.DB }
+
+func NewEvaluationCacheRepository(db *gorm.DB) *EvaluationCacheRepository {
+	return &EvaluationCacheRepository{db: db}
+}
+
+// Get 查询 since 之后写入的缓存，不存在或已过期返回 nil
+func (r *EvaluationCacheRepository) Get(key string, since time.Time) (*models.EvaluationCache, error) {
+	var e models.EvaluationCache
+	if err := r.db.Where("cache_key = ? AND created_at > ?", key, since).First(&e).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &e, nil
+}
+
+// Put 写入缓存，相同键覆盖旧结果并重新计算有效期
+func (r *EvaluationCacheRepository) Put(e *models.EvaluationCache) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "cache_key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"version", "result", "created_at"}),
+	}).Create(e).Error
+}
+
+// DeleteBefore 删除 before 之前写入的缓存
+func (r *EvaluationCacheRepository) DeleteBefore(before time.Time) (int64, error) {
+	res := r.db.Where("created_at <= ?", before).Delete(&models.EvaluationCache{})
+	return res.RowsAffected, res.Error
+}
+
+// Count 统计 since 之后写入的缓存条数
+func (r *EvaluationCacheRepository) Count(since time.Time) (int64, error) {
+	var n int64
+	err := r.db.Model(&models.EvaluationCache{}).Where("created_at > ?", since).Count(&n).Error
+	return n, err
+}
@@ -89,6 +89,25 @@ func (r *PositionRepository) FindByIDAndUser(id, userID uint) (*models.Position,
 	return &p, nil
 }
 
+// SetBlindScreening 开启或关闭岗位的盲筛，岗位不存在时返回 false
+func (r *PositionRepository) SetBlindScreening(id, userID uint, on bool) (bool, error) {
+	res := r.db.Model(&models.Position{}).Where("id = ? AND user_id = ?", id, userID).Update("blind_screening", on)
+	return res.RowsAffected > 0, res.Error
+}
+
+// BlindPostIDsByUser 用户开启了盲筛的岗位 PostID
+func (r *PositionRepository) BlindPostIDsByUser(userID uint) (map[string]bool, error) {
+	var ids []string
+	if err := r.db.Model(&models.Position{}).Where("user_id = ? AND blind_screening = ?", userID, true).Pluck("post_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	out := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		out[id] = true
+	}
+	return out, nil
+}
+
 // CountByUser 统计用户的岗位数量
 func (r *PositionRepository) CountByUser(userID uint) (int64, error) {
 	var count int64
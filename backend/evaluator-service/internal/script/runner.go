@@ -41,6 +41,7 @@ type WintalentItem struct {
 	Name         string                 `json:"name"`
 	ApplyID      string                 `json:"apply_id"`  // 招聘系统申请ID
 	ResumeID     string                 `json:"resume_id"` // 简历ID
+	PostID       string                 `json:"post_id"`   // 岗位ID
 	JD           map[string]any         `json:"jd"`
 	ResumePDFB64 string                 `json:"resume_pdf_b64"`
 	Extra        map[string]interface{} `json:"-"`
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"evaluator-service/internal/config"
+	"evaluator-service/internal/logging"
+	"evaluator-service/internal/models"
+	"evaluator-service/internal/repository"
+)
+
+// WorkflowFunc 调用评估工作流，返回工作流输出
+type WorkflowFunc func(ctx context.Context, name, jdText string, resume []byte) (map[string]any, error)
+
+// EvaluationCache 评估结果缓存：相同简历内容、JD、评分标准和工作流版本在有效期内复用工作流输出，
+// 避免重复评估时再次调用 Coze。命中统计只记录本实例自启动以来的请求
+type EvaluationCache struct {
+	repo    *repository.EvaluationCacheRepository
+	log     *logging.Logger
+	run     WorkflowFunc
+	ttl     time.Duration
+	version string
+
+	hits     atomic.Int64
+	misses   atomic.Int64
+	bypassed atomic.Int64
+}
+
+func NewEvaluationCache(cfg *config.Config, log *logging.Logger, repo *repository.EvaluationCacheRepository, run WorkflowFunc) *EvaluationCache {
+	return &EvaluationCache{
+		repo:    repo,
+		log:     log,
+		run:     run,
+		ttl:     cfg.Cache.TTL,
+		version: "coze:" + cfg.Coze.WorkflowID,
+	}
+}
+
+// EvaluationCacheKey 缓存键：简历内容哈希 + JD 文本哈希 + 评分标准 + 评估服务版本
+func EvaluationCacheKey(resume []byte, jdText, criteria, version string) string {
+	resumeSum := sha256.Sum256(resume)
+	jdSum := sha256.Sum256([]byte(jdText))
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		hex.EncodeToString(resumeSum[:]), hex.EncodeToString(jdSum[:]), criteria, version,
+	}, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// Enabled 是否启用缓存
+func (c *EvaluationCache) Enabled() bool {
+	return c.ttl > 0
+}
+
+// RunWorkflow 有效期内评估过相同简历和 JD 时返回缓存的工作流输出，否则调用工作流并写入缓存。
+// force 为 true 时跳过缓存重新评估，新结果覆盖旧缓存。返回的 cached 表示结果是否来自缓存
+func (c *EvaluationCache) RunWorkflow(ctx context.Context, name, jdText, criteria string, resume []byte, force bool) (map[string]any, bool, error) {
+	if !c.Enabled() || len(resume) == 0 {
+		data, err := c.run(ctx, name, jdText, resume)
+		return data, false, err
+	}
+
+	key := EvaluationCacheKey(resume, jdText, criteria, c.version)
+	if force {
+		c.bypassed.Add(1)
+	} else if data, ok := c.get(key); ok {
+		c.hits.Add(1)
+		return data, true, nil
+	} else {
+		c.misses.Add(1)
+	}
+
+	data, err := c.run(ctx, name, jdText, resume)
+	if err != nil || extractCozeReportJSON(data) == "" {
+		// 没有评估报告的输出不缓存，下次评估重新调用工作流
+		return data, false, err
+	}
+	c.put(key, data)
+	return data, false, nil
+}
+
+// get 读取有效期内的缓存，读取或解析失败按未命中处理
+func (c *EvaluationCache) get(key string) (map[string]any, bool) {
+	entry, err := c.repo.Get(key, time.Now().Add(-c.ttl))
+	if err != nil {
+		c.log.Warn("Evaluation cache lookup failed", logging.Err(err))
+		return nil, false
+	}
+	if entry == nil {
+		return nil, false
+	}
+	var data map[string]any
+	if err := json.Unmarshal([]byte(entry.Result), &data); err != nil {
+		c.log.Warn("Evaluation cache entry is invalid", logging.Err(err), logging.KV("id", entry.ID))
+		return nil, false
+	}
+	return data, true
+}
+
+// put 写入缓存并清理已过期的条目，失败只记录日志
+func (c *EvaluationCache) put(key string, data map[string]any) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		c.log.Warn("Evaluation cache encode failed", logging.Err(err))
+		return
+	}
+	if err := c.repo.Put(&models.EvaluationCache{CacheKey: key, Version: c.version, Result: string(b), CreatedAt: time.Now()}); err != nil {
+		c.log.Warn("Evaluation cache store failed", logging.Err(err))
+		return
+	}
+	if _, err := c.repo.DeleteBefore(time.Now().Add(-c.ttl)); err != nil {
+		c.log.Warn("Evaluation cache cleanup failed", logging.Err(err))
+	}
+}
+
+// EvaluationCacheStats 缓存命中统计
+type EvaluationCacheStats struct {
+	Enabled    bool    `json:"enabled"`
+	TTLSeconds int64   `json:"ttl_seconds"`
+	Version    string  `json:"version"`
+	Hits       int64   `json:"hits"`
+	Misses     int64   `json:"misses"`
+	Bypassed   int64   `json:"bypassed"` // 强制重新评估的次数
+	HitRate    float64 `json:"hit_rate"` // 命中次数 / (命中 + 未命中)，强制重新评估不计入
+	Entries    int64   `json:"entries"`  // 有效期内的缓存条数
+}
+
+// Stats 本实例的缓存命中统计
+func (c *EvaluationCache) Stats() EvaluationCacheStats {
+	s := EvaluationCacheStats{
+		Enabled:    c.Enabled(),
+		TTLSeconds: int64(c.ttl / time.Second),
+		Version:    c.version,
+		Hits:       c.hits.Load(),
+		Misses:     c.misses.Load(),
+		Bypassed:   c.bypassed.Load(),
+	}
+	if total := s.Hits + s.Misses; total > 0 {
+		s.HitRate = float64(s.Hits) / float64(total)
+	}
+	if s.Enabled {
+		s.Entries, _ = c.repo.Count(time.Now().Add(-c.ttl))
+	}
+	return s
+}
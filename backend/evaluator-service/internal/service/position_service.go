@@ -134,6 +134,16 @@ func (s *PositionService) GetByID(id uint) (*models.Position, error) {
 	return s.repo.FindByID(id)
 }
 
+// SetBlindScreening 开启或关闭岗位的盲筛
+func (s *PositionService) SetBlindScreening(id, userID uint, on bool) (bool, error) {
+	return s.repo.SetBlindScreening(id, userID, on)
+}
+
+// BlindPostIDs 用户开启了盲筛的岗位 PostID
+func (s *PositionService) BlindPostIDs(userID uint) (map[string]bool, error) {
+	return s.repo.BlindPostIDsByUser(userID)
+}
+
 // GetByIDAndUser 根据 ID 和用户 ID 获取岗位
 func (s *PositionService) GetByIDAndUser(id, userID uint) (*models.Position, error) {
 	return s.repo.FindByIDAndUser(id, userID)
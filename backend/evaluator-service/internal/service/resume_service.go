@@ -1,15 +1,17 @@
 package service
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"os"
-	"path/filepath"
+	"path"
 	"strings"
 	"time"
 
 	"evaluator-service/internal/ai"
 	"evaluator-service/internal/config"
+	"evaluator-service/internal/filestore"
 	"evaluator-service/internal/logging"
 	"evaluator-service/internal/models"
 	"evaluator-service/internal/repository"
@@ -30,32 +32,24 @@ func NewResumeService(cfg *config.Config, log *logging.Logger, repo *repository.
 // ==================== PDF 存储相关 ====================
 
 // SaveResumePDF 保存简历PDF文件到服务器
-// 返回保存的文件路径，如果保存失败返回空字符串（不阻断流程）
+// 返回保存的存储 key，如果保存失败返回空字符串（不阻断流程）
 func (s *ResumeService) SaveResumePDF(pdfBytes []byte, userID uint, candidateName string) string {
 	if len(pdfBytes) == 0 {
 		return ""
 	}
 
-	// 构建存储目录: data/resumes/{userID}/
-	resumeDir := filepath.Join(s.cfg.Storage.BaseDir, s.cfg.Storage.Resumes, fmt.Sprintf("%d", userID))
-	if err := os.MkdirAll(resumeDir, 0755); err != nil {
-		s.log.Error("Failed to create resume directory", logging.Err(err), logging.KV("dir", resumeDir))
-		return ""
-	}
-
-	// 生成唯一文件名: {timestamp}_{candidateName}.pdf
+	// 生成唯一文件名: resumes/{userID}/{timestamp}_{candidateName}.pdf
 	safeName := sanitizeFilename(candidateName)
 	filename := fmt.Sprintf("%d_%s.pdf", time.Now().UnixNano(), safeName)
-	pdfPath := filepath.Join(resumeDir, filename)
+	key := path.Join(s.cfg.Storage.Resumes, fmt.Sprintf("%d", userID), filename)
 
-	// 写入文件
-	if err := os.WriteFile(pdfPath, pdfBytes, 0644); err != nil {
-		s.log.Error("Failed to save resume PDF", logging.Err(err), logging.KV("path", pdfPath))
+	if err := filestore.Put(context.Background(), key, bytes.NewReader(pdfBytes), int64(len(pdfBytes))); err != nil {
+		s.log.Error("Failed to save resume PDF", logging.Err(err), logging.KV("key", key))
 		return ""
 	}
 
-	s.log.Info("Resume PDF saved", logging.KV("path", pdfPath), logging.KV("size", len(pdfBytes)))
-	return pdfPath
+	s.log.Info("Resume PDF saved", logging.KV("key", key), logging.KV("size", len(pdfBytes)))
+	return key
 }
 
 // sanitizeFilename 清理文件名中的非法字符
@@ -494,7 +488,7 @@ func (s *ResumeService) EvaluateSingleBytesWithApplyID(pdfBytes []byte, filename
 	if existingCandidate != nil {
 		// 重新评估：删除旧的PDF文件
 		if existingCandidate.PDFPath != "" {
-			if err := os.Remove(existingCandidate.PDFPath); err != nil {
+			if err := filestore.Delete(context.Background(), existingCandidate.PDFPath); err != nil {
 				s.log.Warn("Failed to remove old PDF file", logging.Err(err), logging.KV("path", existingCandidate.PDFPath))
 			}
 		}
@@ -1,14 +1,19 @@
 package main
 
 import (
+	"fmt"
 	"log"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
 	"os"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"common/headcount"
+
 	"github.com/gin-gonic/gin"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -152,15 +157,25 @@ func (h *StatsHandler) GetDashboardStats(c *gin.Context) {
 	})
 }
 
+// GetRecruitmentFunnel 招聘漏斗：按申请阶段流转历史统计到达过各阶段的申请数，
+// 跳过的阶段也计入（如直接安排面试的申请同时计入已筛选）
 func (h *StatsHandler) GetRecruitmentFunnel(c *gin.Context) {
 	var resumes, screened, interviewed, passed, hired int64
 
 	if db != nil {
-		db.Table("resumes").Count(&resumes)
-		db.Table("resumes").Where("status IN ?", []string{"reviewing", "interviewed", "offered", "hired"}).Count(&screened)
-		db.Table("resumes").Where("status IN ?", []string{"interviewed", "offered", "hired"}).Count(&interviewed)
-		db.Table("resumes").Where("status IN ?", []string{"offered", "hired"}).Count(&passed)
-		db.Table("resumes").Where("status = ?", "hired").Count(&hired)
+		reached := func(stages []string, count *int64) {
+			query := db.Table("application_stage_transitions t").
+				Joins("JOIN applications a ON a.id = t.application_id AND a.deleted_at IS NULL")
+			if stages != nil {
+				query = query.Where("t.to_stage IN ?", stages)
+			}
+			query.Distinct("t.application_id").Count(count)
+		}
+		reached(nil, &resumes)
+		reached(stagesFrom("reviewed"), &screened)
+		reached(stagesFrom("interview"), &interviewed)
+		reached(stagesFrom("offer"), &passed)
+		reached(stagesFrom("hired"), &hired)
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -176,6 +191,32 @@ func (h *StatsHandler) GetRecruitmentFunnel(c *gin.Context) {
 	})
 }
 
+// 与 common/pipeline 的默认流程保持一致，stageAliases 为历史数据中的旧状态名
+var (
+	funnelStages = []string{"pending", "reviewed", "interview", "offer", "hired"}
+	stageAliases = map[string][]string{
+		"reviewed":  {"reviewing"},
+		"interview": {"interviewing", "interviewed"},
+		"offer":     {"offered"},
+		"hired":     {"accepted"},
+	}
+)
+
+// stagesFrom 默认流程中从 stage 开始的各阶段（含旧状态名）
+func stagesFrom(stage string) []string {
+	var stages []string
+	for i, s := range funnelStages {
+		if s == stage {
+			for _, later := range funnelStages[i:] {
+				stages = append(stages, later)
+				stages = append(stages, stageAliases[later]...)
+			}
+			break
+		}
+	}
+	return stages
+}
+
 func (h *StatsHandler) GetChannelStats(c *gin.Context) {
 	type ChannelStat struct {
 		Name  string `json:"name"`
@@ -210,27 +251,106 @@ func (h *StatsHandler) GetChannelStats(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"code": 0, "message": "success", "data": result})
 }
 
+// GetDepartmentProgress 部门招聘进度：编制计划人数 vs 周期内已录用 vs 流程中候选人
 func (h *StatsHandler) GetDepartmentProgress(c *gin.Context) {
-	type DeptStat struct {
-		Department string `json:"department"`
-		Count      int64  `json:"count"`
+	period := strings.ToUpper(c.DefaultQuery("period", strconv.Itoa(time.Now().Year())))
+	start, end, ok := periodRange(period)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "周期格式错误，应为 2025、2025-Q1 或 2025-03"})
+		return
 	}
-	var stats []DeptStat
 
-	if db != nil {
-		db.Table("jobs").Select("department, count(*) as count").Where("department IS NOT NULL").Group("department").Scan(&stats)
+	type DeptStat struct {
+		Department string
+		Count      int64
+	}
+	planned := map[string]int64{}
+	hired := map[string]int64{}
+	pipeline := map[string]int64{}
+	openings := map[string]int64{}
+	var departments []string
+
+	collect := func(stats []DeptStat, into map[string]int64) {
+		for _, s := range stats {
+			if _, seen := planned[s.Department]; !seen {
+				planned[s.Department] = 0
+				departments = append(departments, s.Department)
+			}
+			into[s.Department] = s.Count
+		}
 	}
 
-	result := make([]gin.H, len(stats))
-	for i, s := range stats {
-		target := int(s.Count) + 5
-		progress := int(s.Count * 100 / int64(target))
-		result[i] = gin.H{"department": s.Department, "target": target, "hired": s.Count, "progress": progress}
+	if db != nil {
+		var planStats, hiredStats, pipelineStats, openingStats []DeptStat
+		db.Table("headcount_plans").Select("department, planned as count").
+			Where("period = ?", period).Order("department").Scan(&planStats)
+		db.Table("applications").Joins("JOIN jobs ON applications.job_id = jobs.id").
+			Select("jobs.department, count(*) as count").
+			Where("applications.status IN ? AND applications.deleted_at IS NULL", headcount.HiredStatuses).
+			Where("applications.hired_at >= ? AND applications.hired_at < ?", start, end).
+			Group("jobs.department").Scan(&hiredStats)
+		db.Table("applications").Joins("JOIN jobs ON applications.job_id = jobs.id").
+			Select("jobs.department, count(*) as count").
+			Where("applications.status NOT IN ? AND applications.deleted_at IS NULL", headcount.ClosedStatuses).
+			Where("jobs.status IN ? AND jobs.deleted_at IS NULL", []string{"open", "on_hold"}).
+			Group("jobs.department").Scan(&pipelineStats)
+		db.Table("jobs").Select("department, sum(GREATEST(COALESCE(headcount, 0) - COALESCE(hired, 0), 0)) as count").
+			Where("status IN ? AND deleted_at IS NULL AND department <> ''", []string{"open", "on_hold"}).
+			Group("department").Scan(&openingStats)
+
+		collect(planStats, planned)
+		collect(hiredStats, hired)
+		collect(pipelineStats, pipeline)
+		collect(openingStats, openings)
+	}
+
+	result := make([]gin.H, 0, len(departments))
+	for _, dept := range departments {
+		progress := int64(0)
+		if planned[dept] > 0 {
+			progress = hired[dept] * 100 / planned[dept]
+		}
+		result = append(result, gin.H{
+			"department":     dept,
+			"period":         period,
+			"planned":        planned[dept],
+			"target":         planned[dept],
+			"hired":          hired[dept],
+			"in_pipeline":    pipeline[dept],
+			"open_positions": openings[dept],
+			"progress":       progress,
+		})
 	}
 
 	c.JSON(http.StatusOK, gin.H{"code": 0, "message": "success", "data": result})
 }
 
+// periodRange 解析编制周期（2025、2025-Q1、2025-03），返回 [start, end)
+func periodRange(period string) (time.Time, time.Time, bool) {
+	var year, n int
+	switch {
+	case len(period) == 4:
+		if _, err := fmt.Sscanf(period, "%4d", &year); err != nil {
+			return time.Time{}, time.Time{}, false
+		}
+		start := time.Date(year, 1, 1, 0, 0, 0, 0, time.Local)
+		return start, start.AddDate(1, 0, 0), true
+	case len(period) == 7 && period[5] == 'Q':
+		if _, err := fmt.Sscanf(period, "%4d-Q%1d", &year, &n); err != nil || n < 1 || n > 4 {
+			return time.Time{}, time.Time{}, false
+		}
+		start := time.Date(year, time.Month((n-1)*3+1), 1, 0, 0, 0, 0, time.Local)
+		return start, start.AddDate(0, 3, 0), true
+	case len(period) == 7:
+		if _, err := fmt.Sscanf(period, "%4d-%2d", &year, &n); err != nil || n < 1 || n > 12 {
+			return time.Time{}, time.Time{}, false
+		}
+		start := time.Date(year, time.Month(n), 1, 0, 0, 0, 0, time.Local)
+		return start, start.AddDate(0, 1, 0), true
+	}
+	return time.Time{}, time.Time{}, false
+}
+
 func (h *StatsHandler) GetInterviewerRank(c *gin.Context) {
 	type InterviewerStat struct {
 		Interviewer string `json:"name"`
@@ -300,7 +420,7 @@ func (h *StatsHandler) GetJobRank(c *gin.Context) {
 
 	if len(result) == 0 {
 		if db != nil {
-			db.Table("jobs").Select("title, headcount as count").Order("headcount desc").Limit(5).Scan(&stats)
+			db.Table("jobs").Select("title, headcount as count").Where("deleted_at IS NULL AND headcount IS NOT NULL").Order("headcount desc").Limit(5).Scan(&stats)
 			result = make([]gin.H, len(stats))
 			for i, s := range stats {
 				result[i] = gin.H{"title": s.Title, "count": s.Count}
@@ -379,10 +499,18 @@ func main() {
 	// 人才服务
 	api.Any("/talents", ReverseProxy(serviceRegistry["talent"]))
 	api.Any("/talents/*path", ReverseProxy(serviceRegistry["talent"]))
+	api.Any("/custom-fields", ReverseProxy(serviceRegistry["talent"]))
+	api.Any("/custom-fields/*path", ReverseProxy(serviceRegistry["talent"]))
 
 	// 职位服务
 	api.Any("/jobs", ReverseProxy(serviceRegistry["job"]))
 	api.Any("/jobs/*path", ReverseProxy(serviceRegistry["job"]))
+	api.Any("/headcount-plans", ReverseProxy(serviceRegistry["job"]))
+	api.Any("/headcount-plans/*path", ReverseProxy(serviceRegistry["job"]))
+	api.Any("/approval-chains", ReverseProxy(serviceRegistry["job"]))
+	api.Any("/approval-chains/*path", ReverseProxy(serviceRegistry["job"]))
+	api.Any("/skills", ReverseProxy(serviceRegistry["job"]))
+	api.Any("/skills/*path", ReverseProxy(serviceRegistry["job"]))
 
 	// 简历服务
 	api.Any("/resumes", ReverseProxy(serviceRegistry["resume"]))
@@ -399,6 +527,7 @@ func main() {
 	// 消息服务
 	api.Any("/messages", ReverseProxy(serviceRegistry["message"]))
 	api.Any("/messages/*path", ReverseProxy(serviceRegistry["message"]))
+	api.GET("/ws", ReverseProxy(serviceRegistry["message"])) // 实时推送，内部推送接口 /ws/push 不对外转发
 
 	// 面试服务
 	api.Any("/interviews", ReverseProxy(serviceRegistry["interview"]))
@@ -0,0 +1,520 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"job-service/models"
+	"net/http"
+	"strings"
+	"time"
+
+	"common/notify"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errApprovalChanged 加锁后发现审批节点已被他人处理
+var errApprovalChanged = errors.New("approval step already decided")
+
+// ApprovalHandler 职位需求审批流程
+type ApprovalHandler struct {
+	DB       *gorm.DB
+	Notifier *notify.Client
+}
+
+func NewApprovalHandler(db *gorm.DB, notifier *notify.Client) *ApprovalHandler {
+	return &ApprovalHandler{DB: db, Notifier: notifier}
+}
+
+// SubmitJob 提交职位审批（草稿 -> 待审批）
+func (h *ApprovalHandler) SubmitJob(c *gin.Context) {
+	userID, role := currentUser(c)
+
+	var job models.Job
+	if err := h.DB.First(&job, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "Job not found"})
+		return
+	}
+	if job.Status != models.JobStatusDraft {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "只有草稿状态的职位可以提交审批"})
+		return
+	}
+	if !canManage(job, userID, role) {
+		c.JSON(http.StatusForbidden, gin.H{"code": 1, "message": "只有职位创建人可以提交审批"})
+		return
+	}
+
+	steps, err := h.chainSteps(job.Department)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "Failed to load approval chain"})
+		return
+	}
+	// 未配置审批链（含默认审批链）时只有管理员可以免审批直接发布
+	if len(steps) == 0 && role != "admin" {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "未配置审批链，请联系管理员配置后再提交"})
+		return
+	}
+
+	var approvals []models.JobApproval
+	err = h.DB.Transaction(func(tx *gorm.DB) error {
+		job.ApprovalRound++
+		// 未配置审批链的部门无需审批，提交即发布
+		job.Status = models.JobStatusPendingApproval
+		if len(steps) == 0 {
+			job.Status = job.PublishStatus(time.Now())
+		}
+		if err := tx.Model(&job).Updates(map[string]interface{}{
+			"status":         job.Status,
+			"approval_round": job.ApprovalRound,
+		}).Error; err != nil {
+			return err
+		}
+
+		for _, step := range steps {
+			approvals = append(approvals, models.JobApproval{
+				JobID:      job.ID,
+				Round:      job.ApprovalRound,
+				StepOrder:  step.StepOrder,
+				StepName:   step.Name,
+				ApproverID: step.ApproverID,
+				Status:     models.ApprovalPending,
+			})
+		}
+		if len(approvals) > 0 {
+			return tx.Create(&approvals).Error
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "Failed to submit job"})
+		return
+	}
+
+	if len(approvals) > 0 {
+		h.notifyApprover(job, approvals[0])
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "Job submitted successfully",
+		"data": gin.H{
+			"job":       job,
+			"approvals": approvals,
+		},
+	})
+}
+
+// WithdrawJob 撤回审批（待审批 -> 草稿）
+func (h *ApprovalHandler) WithdrawJob(c *gin.Context) {
+	userID, role := currentUser(c)
+
+	var job models.Job
+	if err := h.DB.First(&job, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "Job not found"})
+		return
+	}
+	if job.Status != models.JobStatusPendingApproval {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "职位不在审批中"})
+		return
+	}
+	if !canManage(job, userID, role) {
+		c.JSON(http.StatusForbidden, gin.H{"code": 1, "message": "只有职位创建人可以撤回审批"})
+		return
+	}
+
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := h.cancelPending(tx, job); err != nil {
+			return err
+		}
+		return tx.Model(&job).Update("status", models.JobStatusDraft).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "Failed to withdraw job"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "Job withdrawn successfully",
+		"data":    job,
+	})
+}
+
+// ApproveJob 审批通过当前节点，最后一个节点通过后职位发布
+func (h *ApprovalHandler) ApproveJob(c *gin.Context) {
+	var req struct {
+		Comment string `json:"comment"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": err.Error()})
+		return
+	}
+	h.decide(c, models.ApprovalApproved, strings.TrimSpace(req.Comment))
+}
+
+// RejectJob 驳回审批，职位退回草稿
+func (h *ApprovalHandler) RejectJob(c *gin.Context) {
+	var req struct {
+		Comment string `json:"comment" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Comment) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "驳回时请填写原因"})
+		return
+	}
+	h.decide(c, models.ApprovalRejected, strings.TrimSpace(req.Comment))
+}
+
+// decide 处理当前审批节点的通过/驳回
+func (h *ApprovalHandler) decide(c *gin.Context, decision, comment string) {
+	userID, role := currentUser(c)
+
+	var job models.Job
+	if err := h.DB.First(&job, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "Job not found"})
+		return
+	}
+	if job.Status != models.JobStatusPendingApproval {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "职位不在审批中"})
+		return
+	}
+
+	current, err := h.currentStep(h.DB, job)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "没有待处理的审批节点"})
+		return
+	}
+	if current.ApproverID != userID && role != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"code": 1, "message": "当前节点的审批人不是你"})
+		return
+	}
+
+	var next *models.JobApproval
+	err = h.DB.Transaction(func(tx *gorm.DB) error {
+		// 锁定职位后重新读取当前节点，避免多位审批人同时处理同一节点
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&job, job.ID).Error; err != nil {
+			return err
+		}
+		if job.Status != models.JobStatusPendingApproval {
+			return errApprovalChanged
+		}
+		if locked, err := h.currentStep(tx, job); err != nil || locked.ID != current.ID {
+			return errApprovalChanged
+		}
+
+		now := time.Now()
+		if err := tx.Model(&current).Updates(map[string]interface{}{
+			"status":     decision,
+			"comment":    comment,
+			"decided_at": &now,
+		}).Error; err != nil {
+			return err
+		}
+
+		if decision == models.ApprovalRejected {
+			job.Status = models.JobStatusDraft
+			if err := h.cancelPending(tx, job); err != nil {
+				return err
+			}
+		} else if step, err := h.currentStep(tx, job); err == nil {
+			next = &step
+		} else if err == gorm.ErrRecordNotFound {
+			job.Status = job.PublishStatus(time.Now())
+		} else {
+			return err
+		}
+		return tx.Model(&job).Update("status", job.Status).Error
+	})
+	if errors.Is(err, errApprovalChanged) {
+		c.JSON(http.StatusConflict, gin.H{"code": 1, "message": "该审批节点已被处理，请刷新后重试"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "Failed to save approval"})
+		return
+	}
+
+	switch {
+	case next != nil:
+		h.notifyApprover(job, *next)
+	case decision == models.ApprovalRejected:
+		h.notifyCreator(job, "职位审批被驳回",
+			fmt.Sprintf("职位「%s」在「%s」节点被驳回：%s", job.Title, current.StepName, comment))
+	case job.Status == models.JobStatusScheduled:
+		h.notifyCreator(job, "职位审批已通过",
+			fmt.Sprintf("职位「%s」已通过全部审批，将于 %s 自动发布", job.Title, job.PublishAt.Format("2006-01-02 15:04")))
+	default:
+		h.notifyCreator(job, "职位审批已通过",
+			fmt.Sprintf("职位「%s」已通过全部审批并发布", job.Title))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "Approval saved successfully",
+		"data":    job,
+	})
+}
+
+// AddComment 添加审批评论
+func (h *ApprovalHandler) AddComment(c *gin.Context) {
+	userID, _ := currentUser(c)
+
+	var req struct {
+		Content string `json:"content" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": err.Error()})
+		return
+	}
+
+	var job models.Job
+	if err := h.DB.First(&job, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "Job not found"})
+		return
+	}
+
+	comment := models.JobApprovalComment{
+		JobID:   job.ID,
+		Round:   job.ApprovalRound,
+		UserID:  userID,
+		Content: strings.TrimSpace(req.Content),
+	}
+	if err := h.DB.Create(&comment).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "Failed to add comment"})
+		return
+	}
+
+	// 评论通知创建人和当前审批人（不通知评论者本人）
+	var msgs []notify.Message
+	content := fmt.Sprintf("职位「%s」有新的审批评论：%s", job.Title, comment.Content)
+	if job.CreatedBy != 0 && job.CreatedBy != userID {
+		msgs = append(msgs, approvalMessage(job.CreatedBy, "职位审批评论", content))
+	}
+	if current, err := h.currentStep(h.DB, job); err == nil && current.ApproverID != userID && current.ApproverID != job.CreatedBy {
+		msgs = append(msgs, approvalMessage(current.ApproverID, "职位审批评论", content))
+	}
+	h.Notifier.SendAsync(msgs...)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"code":    0,
+		"message": "Comment added successfully",
+		"data":    comment,
+	})
+}
+
+// GetApprovals 获取职位审批记录和评论
+func (h *ApprovalHandler) GetApprovals(c *gin.Context) {
+	var job models.Job
+	if err := h.DB.First(&job, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "Job not found"})
+		return
+	}
+
+	var approvals []models.JobApproval
+	h.DB.Where("job_id = ?", job.ID).Order("round DESC, step_order ASC").Find(&approvals)
+
+	var comments []models.JobApprovalComment
+	h.DB.Where("job_id = ?", job.ID).Order("created_at ASC").Find(&comments)
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"status":    job.Status,
+			"round":     job.ApprovalRound,
+			"approvals": approvals,
+			"comments":  comments,
+		},
+	})
+}
+
+// ListMyPendingApprovals 获取当前用户待审批的职位
+func (h *ApprovalHandler) ListMyPendingApprovals(c *gin.Context) {
+	userID, _ := currentUser(c)
+
+	// 只返回轮到当前用户审批的节点（前序节点均已通过）
+	var approvals []models.JobApproval
+	h.DB.Table("job_approvals AS a").
+		Joins("JOIN jobs ON jobs.id = a.job_id AND jobs.approval_round = a.round AND jobs.status = ? AND jobs.deleted_at IS NULL", models.JobStatusPendingApproval).
+		Where("a.approver_id = ? AND a.status = ?", userID, models.ApprovalPending).
+		Where("NOT EXISTS (SELECT 1 FROM job_approvals p WHERE p.job_id = a.job_id AND p.round = a.round AND p.status = ? AND p.step_order < a.step_order)", models.ApprovalPending).
+		Select("a.*").
+		Order("a.created_at ASC").
+		Find(&approvals)
+
+	type PendingItem struct {
+		models.JobApproval
+		JobTitle   string `json:"job_title"`
+		Department string `json:"department"`
+	}
+
+	items := make([]PendingItem, len(approvals))
+	for i, a := range approvals {
+		var job models.Job
+		h.DB.Select("title", "department").First(&job, a.JobID)
+		items[i] = PendingItem{JobApproval: a, JobTitle: job.Title, Department: job.Department}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"approvals": items,
+			"total":     len(items),
+		},
+	})
+}
+
+// ListChains 获取审批链配置
+func (h *ApprovalHandler) ListChains(c *gin.Context) {
+	var chains []models.ApprovalChain
+	if err := h.DB.Preload("Steps", func(db *gorm.DB) *gorm.DB {
+		return db.Order("step_order ASC")
+	}).Order("department ASC").Find(&chains).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "Failed to fetch approval chains"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data":    chains,
+	})
+}
+
+// SaveChain 创建或替换部门审批链
+func (h *ApprovalHandler) SaveChain(c *gin.Context) {
+	var req struct {
+		Department string `json:"department"`
+		Steps      []struct {
+			Name       string `json:"name" binding:"required"`
+			ApproverID uint   `json:"approver_id" binding:"required"`
+		} `json:"steps" binding:"required,min=1,dive"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": err.Error()})
+		return
+	}
+
+	chain := models.ApprovalChain{Department: strings.TrimSpace(req.Department)}
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("department = ?", chain.Department).FirstOrCreate(&chain).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("chain_id = ?", chain.ID).Delete(&models.ApprovalStep{}).Error; err != nil {
+			return err
+		}
+		chain.Steps = nil
+		for i, s := range req.Steps {
+			chain.Steps = append(chain.Steps, models.ApprovalStep{
+				ChainID:    chain.ID,
+				StepOrder:  i + 1,
+				Name:       strings.TrimSpace(s.Name),
+				ApproverID: s.ApproverID,
+			})
+		}
+		return tx.Create(&chain.Steps).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "Failed to save approval chain"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "Approval chain saved successfully",
+		"data":    chain,
+	})
+}
+
+// DeleteChain 删除审批链（已提交的审批不受影响）
+func (h *ApprovalHandler) DeleteChain(c *gin.Context) {
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("chain_id = ?", c.Param("id")).Delete(&models.ApprovalStep{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.ApprovalChain{}, c.Param("id")).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "Failed to delete approval chain"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "Approval chain deleted successfully",
+	})
+}
+
+// chainSteps 获取部门审批链，部门未配置时使用默认审批链
+func (h *ApprovalHandler) chainSteps(department string) ([]models.ApprovalStep, error) {
+	for _, dept := range []string{department, ""} {
+		var chain models.ApprovalChain
+		err := h.DB.Preload("Steps", func(db *gorm.DB) *gorm.DB {
+			return db.Order("step_order ASC")
+		}).Where("department = ?", dept).First(&chain).Error
+		if err == nil {
+			return chain.Steps, nil
+		}
+		if err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// currentStep 获取本轮审批中第一个待处理节点
+func (h *ApprovalHandler) currentStep(db *gorm.DB, job models.Job) (models.JobApproval, error) {
+	var step models.JobApproval
+	err := db.Where("job_id = ? AND round = ? AND status = ?", job.ID, job.ApprovalRound, models.ApprovalPending).
+		Order("step_order ASC").First(&step).Error
+	return step, err
+}
+
+// cancelPending 取消本轮所有未处理的审批节点
+func (h *ApprovalHandler) cancelPending(tx *gorm.DB, job models.Job) error {
+	return tx.Model(&models.JobApproval{}).
+		Where("job_id = ? AND round = ? AND status = ?", job.ID, job.ApprovalRound, models.ApprovalPending).
+		Update("status", models.ApprovalCancelled).Error
+}
+
+func (h *ApprovalHandler) notifyApprover(job models.Job, step models.JobApproval) {
+	h.Notifier.SendAsync(approvalMessage(step.ApproverID, "待审批职位："+job.Title,
+		fmt.Sprintf("%s部门的职位「%s」等待你审批（%s）", job.Department, job.Title, step.StepName)))
+}
+
+func (h *ApprovalHandler) notifyCreator(job models.Job, title, content string) {
+	if job.CreatedBy == 0 {
+		return
+	}
+	h.Notifier.SendAsync(approvalMessage(job.CreatedBy, title, content))
+}
+
+func approvalMessage(receiverID uint, title, content string) notify.Message {
+	return notify.Message{
+		ReceiverID: receiverID,
+		Title:      title,
+		Content:    content,
+		Type:       notify.TypeApproval,
+	}
+}
+
+// canManage 职位创建人或管理员可以提交、撤回审批；没有创建人的职位只有管理员可以操作
+func canManage(job models.Job, userID uint, role string) bool {
+	if role == "admin" {
+		return true
+	}
+	return job.CreatedBy != 0 && job.CreatedBy == userID
+}
+
+// currentUser 从 JWT 上下文读取当前用户
+func currentUser(c *gin.Context) (uint, string) {
+	var userID uint
+	if v, ok := c.Get("user_id"); ok {
+		userID, _ = v.(uint)
+	}
+	role := c.GetString("role")
+	return userID, role
+}
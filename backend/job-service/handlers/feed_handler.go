@@ -0,0 +1,440 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"job-service/models"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"common/salary"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxFeedItems 订阅源和站点地图中最多包含的职位数
+const maxFeedItems = 500
+
+// FeedHandler 对外公开的职位订阅源（RSS/Atom、JSON-LD、站点地图），无需登录
+type FeedHandler struct {
+	DB          *gorm.DB
+	PortalURL   string // 求职门户地址，用于生成职位链接
+	CompanyName string
+}
+
+func NewFeedHandler(db *gorm.DB) *FeedHandler {
+	return &FeedHandler{
+		DB:          db,
+		PortalURL:   strings.TrimRight(getEnv("PORTAL_BASE_URL", "http://localhost:5173"), "/"),
+		CompanyName: getEnv("COMPANY_NAME", "Talent Platform"),
+	}
+}
+
+// RSS 输出开放职位的 RSS 2.0 订阅源
+func (h *FeedHandler) RSS(c *gin.Context) {
+	if h.notModified(c, 0) {
+		return
+	}
+	jobs, err := h.openJobs()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch jobs"})
+		return
+	}
+
+	type rssItem struct {
+		Title       string   `xml:"title"`
+		Link        string   `xml:"link"`
+		GUID        string   `xml:"guid"`
+		Description string   `xml:"description"`
+		Category    []string `xml:"category"`
+		PubDate     string   `xml:"pubDate"`
+	}
+	type rssChannel struct {
+		Title         string    `xml:"title"`
+		Link          string    `xml:"link"`
+		Description   string    `xml:"description"`
+		Language      string    `xml:"language"`
+		LastBuildDate string    `xml:"lastBuildDate"`
+		Items         []rssItem `xml:"item"`
+	}
+	type rss struct {
+		XMLName xml.Name   `xml:"rss"`
+		Version string     `xml:"version,attr"`
+		Channel rssChannel `xml:"channel"`
+	}
+
+	feed := rss{Version: "2.0", Channel: rssChannel{
+		Title:         h.CompanyName + " - 招聘职位",
+		Link:          h.PortalURL + "/portal/jobs",
+		Description:   h.CompanyName + " 正在招聘的职位",
+		Language:      "zh-cn",
+		LastBuildDate: time.Now().Format(time.RFC1123Z),
+	}}
+	for _, job := range jobs {
+		feed.Channel.Items = append(feed.Channel.Items, rssItem{
+			Title:       jobHeadline(job),
+			Link:        h.jobURL(job),
+			GUID:        h.jobURL(job),
+			Description: jobSummary(job),
+			Category:    append([]string{job.Department}, job.Skills...),
+			PubDate:     job.CreatedAt.Format(time.RFC1123Z),
+		})
+	}
+
+	writeXML(c, "application/rss+xml; charset=utf-8", feed)
+}
+
+// Atom 输出开放职位的 Atom 订阅源
+func (h *FeedHandler) Atom(c *gin.Context) {
+	if h.notModified(c, 0) {
+		return
+	}
+	jobs, err := h.openJobs()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch jobs"})
+		return
+	}
+
+	type atomLink struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr,omitempty"`
+	}
+	type atomCategory struct {
+		Term string `xml:"term,attr"`
+	}
+	type atomEntry struct {
+		Title      string         `xml:"title"`
+		ID         string         `xml:"id"`
+		Link       atomLink       `xml:"link"`
+		Published  string         `xml:"published"`
+		Updated    string         `xml:"updated"`
+		Summary    string         `xml:"summary"`
+		Categories []atomCategory `xml:"category"`
+	}
+	type atomFeed struct {
+		XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+		Title   string      `xml:"title"`
+		ID      string      `xml:"id"`
+		Links   []atomLink  `xml:"link"`
+		Updated string      `xml:"updated"`
+		Author  string      `xml:"author>name"`
+		Entries []atomEntry `xml:"entry"`
+	}
+
+	feed := atomFeed{
+		Title: h.CompanyName + " - 招聘职位",
+		ID:    h.PortalURL + "/portal/jobs",
+		Links: []atomLink{
+			{Href: h.PortalURL + "/portal/jobs"},
+			{Href: h.PortalURL + c.Request.URL.Path, Rel: "self"},
+		},
+		Updated: time.Now().UTC().Format(time.RFC3339),
+		Author:  h.CompanyName,
+	}
+	for _, job := range jobs {
+		if job.UpdatedAt.UTC().Format(time.RFC3339) > feed.Updated || len(feed.Entries) == 0 {
+			feed.Updated = job.UpdatedAt.UTC().Format(time.RFC3339)
+		}
+		entry := atomEntry{
+			Title:     jobHeadline(job),
+			ID:        h.jobURL(job),
+			Link:      atomLink{Href: h.jobURL(job)},
+			Published: job.CreatedAt.UTC().Format(time.RFC3339),
+			Updated:   job.UpdatedAt.UTC().Format(time.RFC3339),
+			Summary:   jobSummary(job),
+		}
+		for _, skill := range job.Skills {
+			entry.Categories = append(entry.Categories, atomCategory{Term: skill})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	writeXML(c, "application/atom+xml; charset=utf-8", feed)
+}
+
+// Sitemap 输出职位站点地图
+func (h *FeedHandler) Sitemap(c *gin.Context) {
+	if h.notModified(c, 0) {
+		return
+	}
+	jobs, err := h.openJobs()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch jobs"})
+		return
+	}
+
+	type sitemapURL struct {
+		Loc        string `xml:"loc"`
+		LastMod    string `xml:"lastmod,omitempty"`
+		ChangeFreq string `xml:"changefreq,omitempty"`
+	}
+	type urlSet struct {
+		XMLName xml.Name     `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+		URLs    []sitemapURL `xml:"url"`
+	}
+
+	set := urlSet{URLs: []sitemapURL{{Loc: h.PortalURL + "/portal/jobs", ChangeFreq: "daily"}}}
+	for _, job := range jobs {
+		set.URLs = append(set.URLs, sitemapURL{
+			Loc:        h.jobURL(job),
+			LastMod:    job.UpdatedAt.UTC().Format(time.RFC3339),
+			ChangeFreq: "weekly",
+		})
+	}
+
+	writeXML(c, "application/xml; charset=utf-8", set)
+}
+
+// JobPosting 输出单个职位的 schema.org JobPosting JSON-LD
+func (h *FeedHandler) JobPosting(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	if id <= 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+	if h.notModified(c, uint(id)) {
+		return
+	}
+
+	var job models.Job
+	if err := h.DB.Where("status = ?", models.JobStatusOpen).First(&job, id).Error; err != nil || job.Expired(time.Now()) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+
+	c.Header("Content-Type", "application/ld+json; charset=utf-8")
+	c.JSON(http.StatusOK, h.jobPosting(job))
+}
+
+// jobPosting 按 schema.org JobPosting 规范映射职位字段
+func (h *FeedHandler) jobPosting(job models.Job) gin.H {
+	posting := gin.H{
+		"@context":    "https://schema.org/",
+		"@type":       "JobPosting",
+		"title":       job.Title,
+		"description": jobDescriptionHTML(job),
+		"datePosted":  job.CreatedAt.Format("2006-01-02"),
+		"url":         h.jobURL(job),
+		"identifier": gin.H{
+			"@type": "PropertyValue",
+			"name":  h.CompanyName,
+			"value": strconv.Itoa(int(job.ID)),
+		},
+		"hiringOrganization": gin.H{
+			"@type": "Organization",
+			"name":  h.CompanyName,
+		},
+		"totalJobOpenings": job.Headcount,
+	}
+
+	if job.PublishAt != nil {
+		posting["datePosted"] = job.PublishAt.Format("2006-01-02")
+	}
+	if job.ExpiresAt != nil {
+		posting["validThrough"] = job.ExpiresAt.Format(time.RFC3339)
+	}
+
+	if t := employmentTypes[job.Type]; t != "" {
+		posting["employmentType"] = t
+	}
+	if job.Department != "" {
+		posting["industry"] = job.Department
+	}
+	if len(job.Skills) > 0 {
+		posting["skills"] = strings.Join(job.Skills, ", ")
+	}
+	if job.MinExperience > 0 {
+		posting["experienceRequirements"] = gin.H{
+			"@type":              "OccupationalExperienceRequirements",
+			"monthsOfExperience": job.MinExperience * 12,
+		}
+	}
+	if category := educationCategories[job.Education]; category != "" {
+		posting["educationRequirements"] = gin.H{
+			"@type":              "EducationalOccupationalCredential",
+			"credentialCategory": category,
+		}
+	}
+
+	if job.Location == "远程" || strings.EqualFold(job.Location, "remote") {
+		posting["jobLocationType"] = "TELECOMMUTE"
+		posting["applicantLocationRequirements"] = gin.H{"@type": "Country", "name": "CN"}
+	} else if job.Location != "" {
+		posting["jobLocation"] = gin.H{
+			"@type": "Place",
+			"address": gin.H{
+				"@type":           "PostalAddress",
+				"addressLocality": job.Location,
+				"addressCountry":  "CN",
+			},
+		}
+	}
+
+	if r := job.SalaryRange; r.Known() {
+		value := gin.H{"@type": "QuantitativeValue", "unitText": salaryUnits[r.Period]}
+		switch {
+		case r.Min > 0 && r.Max > 0 && r.Min != r.Max:
+			value["minValue"], value["maxValue"] = r.Min, r.Max
+		case r.Max > 0:
+			value["value"] = r.Max
+		default:
+			value["minValue"] = r.Min
+		}
+		posting["baseSalary"] = gin.H{
+			"@type":    "MonetaryAmount",
+			"currency": r.Currency,
+			"value":    value,
+		}
+	}
+
+	return posting
+}
+
+// notModified 根据职位最近修改时间处理 ETag / Last-Modified 条件请求，jobID 为 0 表示全部开放职位。
+// 职位到达 publish_at / expires_at 时订阅内容即会变化，而记录要等调度器下一轮扫描才更新，
+// 因此已经过去的最近一次上线/到期时间点也计入修改时间，避免客户端在此期间一直拿到 304。
+func (h *FeedHandler) notModified(c *gin.Context, jobID uint) bool {
+	now := time.Now()
+	// 包含已删除职位，职位被删除或下线后订阅源也能及时失效
+	scope := func() *gorm.DB {
+		query := h.DB.Unscoped().Model(&models.Job{})
+		if jobID != 0 {
+			query = query.Where("id = ?", jobID)
+		}
+		return query
+	}
+
+	var count int64
+	if err := scope().Where("status = ? AND deleted_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", models.JobStatusOpen, now).
+		Count(&count).Error; err != nil {
+		return false
+	}
+	var modified time.Time
+	for _, column := range []string{"updated_at", "deleted_at", "publish_at", "expires_at"} {
+		var latest []time.Time
+		if err := scope().Where(column+" <= ?", now).Order(column+" DESC").Limit(1).Pluck(column, &latest).Error; err != nil {
+			return false
+		}
+		if len(latest) > 0 && latest[0].After(modified) {
+			modified = latest[0]
+		}
+	}
+	if modified.IsZero() {
+		return false
+	}
+	modified = modified.UTC().Truncate(time.Second)
+	etag := fmt.Sprintf(`W/"jobs-%d-%d-%d"`, jobID, count, modified.Unix())
+
+	c.Header("ETag", etag)
+	c.Header("Last-Modified", modified.Format(http.TimeFormat))
+	c.Header("Cache-Control", "public, max-age=300")
+
+	if match := c.GetHeader("If-None-Match"); match != "" {
+		if match == etag || match == "*" {
+			c.Status(http.StatusNotModified)
+			return true
+		}
+		return false
+	}
+	if since := c.GetHeader("If-Modified-Since"); since != "" {
+		if t, err := http.ParseTime(since); err == nil && !modified.After(t) {
+			c.Status(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+func (h *FeedHandler) openJobs() ([]models.Job, error) {
+	var jobs []models.Job
+	err := h.DB.Where("status = ? AND (expires_at IS NULL OR expires_at > ?)", models.JobStatusOpen, time.Now()).
+		Order("updated_at DESC").Limit(maxFeedItems).Find(&jobs).Error
+	return jobs, err
+}
+
+func (h *FeedHandler) jobURL(job models.Job) string {
+	return h.PortalURL + "/portal/jobs/" + strconv.Itoa(int(job.ID))
+}
+
+// employmentTypes 职位类型到 schema.org employmentType 的映射
+var employmentTypes = map[string]string{
+	"full-time":  "FULL_TIME",
+	"part-time":  "PART_TIME",
+	"contract":   "CONTRACTOR",
+	"internship": "INTERN",
+}
+
+// educationCategories 学历到 schema.org credentialCategory 的映射
+var educationCategories = map[string]string{
+	"高中": "high school",
+	"大专": "associate degree",
+	"本科": "bachelor degree",
+	"硕士": "postgraduate degree",
+	"博士": "postgraduate degree",
+}
+
+// salaryUnits 发薪周期到 schema.org unitText 的映射
+var salaryUnits = map[string]string{
+	salary.PeriodHour:  "HOUR",
+	salary.PeriodDay:   "DAY",
+	salary.PeriodMonth: "MONTH",
+	salary.PeriodYear:  "YEAR",
+}
+
+func jobHeadline(job models.Job) string {
+	parts := []string{job.Title}
+	if job.Location != "" {
+		parts = append(parts, job.Location)
+	}
+	if job.Salary != "" {
+		parts = append(parts, job.Salary)
+	}
+	return strings.Join(parts, " | ")
+}
+
+func jobSummary(job models.Job) string {
+	summary := []rune(strings.TrimSpace(job.Description))
+	if len(summary) > 300 {
+		summary = append(summary[:300], []rune("…")...)
+	}
+	return string(summary)
+}
+
+// jobDescriptionHTML 生成 JobPosting 要求的 HTML 描述（职位描述 + 任职要求）
+func jobDescriptionHTML(job models.Job) string {
+	var b strings.Builder
+	for _, line := range strings.Split(strings.TrimSpace(job.Description), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			b.WriteString("<p>" + html.EscapeString(line) + "</p>")
+		}
+	}
+	if len(job.Requirements) > 0 {
+		b.WriteString("<p>任职要求：</p><ul>")
+		for _, r := range job.Requirements {
+			b.WriteString("<li>" + html.EscapeString(r) + "</li>")
+		}
+		b.WriteString("</ul>")
+	}
+	return b.String()
+}
+
+func writeXML(c *gin.Context, contentType string, v interface{}) {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render feed"})
+		return
+	}
+	c.Data(http.StatusOK, contentType, append([]byte(xml.Header), data...))
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
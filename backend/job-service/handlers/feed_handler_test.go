@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"encoding/json"
+	"job-service/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"common/salary"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupFeedDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{NowFunc: func() time.Time { return time.Now().UTC() }})
+	assert.NoError(t, err)
+	assert.NoError(t, db.AutoMigrate(&models.Job{}))
+	return db
+}
+
+func setupFeedRouter(db *gorm.DB) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	handler := &FeedHandler{DB: db, PortalURL: "https://jobs.example.com", CompanyName: "Acme"}
+
+	api := r.Group("/api/v1/jobs")
+	api.GET("/public/:id/jsonld", handler.JobPosting)
+	api.GET("/feed/rss", handler.RSS)
+	api.GET("/feed/atom", handler.Atom)
+	api.GET("/sitemap.xml", handler.Sitemap)
+	return r
+}
+
+func seedFeedJobs(t *testing.T, db *gorm.DB) (open, expired models.Job) {
+	past := time.Now().UTC().Add(-time.Hour)
+	future := time.Now().UTC().Add(24 * time.Hour)
+	open = models.Job{
+		Title: "Go 开发工程师", Description: "负责招聘平台后端开发", Requirements: []string{"熟悉 Go"},
+		Location: "上海", Type: "full-time", Status: models.JobStatusOpen, Department: "研发部",
+		Skills: []string{"Go"}, Headcount: 2, ExpiresAt: &future,
+		SalaryRange: salary.Range{Min: 20000, Max: 30000, Currency: "CNY", Period: salary.PeriodMonth},
+	}
+	// 已过截止时间但调度器尚未关闭
+	expired = models.Job{Title: "测试工程师", Location: "北京", Status: models.JobStatusOpen, Headcount: 1, ExpiresAt: &past}
+	draft := models.Job{Title: "产品经理", Status: models.JobStatusDraft, Headcount: 1}
+	for _, job := range []*models.Job{&open, &expired, &draft} {
+		assert.NoError(t, db.Create(job).Error)
+	}
+	// 到期时间晚于最后一次修改，模拟到期前创建、到期后调度器尚未扫描
+	db.Model(&models.Job{}).Where("1 = 1").UpdateColumn("updated_at", past.Add(-time.Hour))
+	return open, expired
+}
+
+func TestFeeds(t *testing.T) {
+	db := setupFeedDB(t)
+	router := setupFeedRouter(db)
+	seedFeedJobs(t, db)
+
+	tests := []struct {
+		name        string
+		path        string
+		contentType string
+		contains    []string
+		excludes    []string
+	}{
+		{
+			"RSS 只包含未到期的开放职位", "/api/v1/jobs/feed/rss", "application/rss+xml",
+			[]string{`<rss version="2.0">`, "<title>Go 开发工程师 | 上海</title>", "https://jobs.example.com/portal/jobs/1", "<category>研发部</category>"},
+			[]string{"测试工程师", "产品经理"},
+		},
+		{
+			"Atom 只包含未到期的开放职位", "/api/v1/jobs/feed/atom", "application/atom+xml",
+			[]string{`<feed xmlns="http://www.w3.org/2005/Atom">`, "<id>https://jobs.example.com/portal/jobs/1</id>", `<category term="Go"></category>`},
+			[]string{"测试工程师", "产品经理"},
+		},
+		{
+			"站点地图包含职位列表页与开放职位", "/api/v1/jobs/sitemap.xml", "application/xml",
+			[]string{"<loc>https://jobs.example.com/portal/jobs</loc>", "<loc>https://jobs.example.com/portal/jobs/1</loc>"},
+			[]string{"/portal/jobs/2<", "/portal/jobs/3<"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", tt.path, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Contains(t, w.Header().Get("Content-Type"), tt.contentType)
+			assert.NotEmpty(t, w.Header().Get("ETag"))
+			for _, s := range tt.contains {
+				assert.Contains(t, w.Body.String(), s)
+			}
+			for _, s := range tt.excludes {
+				assert.NotContains(t, w.Body.String(), s)
+			}
+		})
+	}
+}
+
+func TestJobPosting(t *testing.T) {
+	db := setupFeedDB(t)
+	router := setupFeedRouter(db)
+	open, _ := seedFeedJobs(t, db)
+
+	t.Run("开放职位输出 JobPosting", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/jobs/public/1/jsonld", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var posting map[string]interface{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &posting))
+		assert.Equal(t, "JobPosting", posting["@type"])
+		assert.Equal(t, open.Title, posting["title"])
+		assert.Equal(t, "FULL_TIME", posting["employmentType"])
+		assert.Equal(t, "<p>负责招聘平台后端开发</p><p>任职要求：</p><ul><li>熟悉 Go</li></ul>", posting["description"])
+		assert.NotEmpty(t, posting["validThrough"])
+		baseSalary := posting["baseSalary"].(map[string]interface{})
+		assert.Equal(t, "CNY", baseSalary["currency"])
+		value := baseSalary["value"].(map[string]interface{})
+		assert.Equal(t, "MONTH", value["unitText"])
+		assert.Equal(t, float64(20000), value["minValue"])
+		assert.Equal(t, float64(30000), value["maxValue"])
+	})
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{"已到期职位返回 404", "/api/v1/jobs/public/2/jsonld"},
+		{"草稿职位返回 404", "/api/v1/jobs/public/3/jsonld"},
+		{"无效 ID 返回 404", "/api/v1/jobs/public/abc/jsonld"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", tt.path, nil)
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusNotFound, w.Code)
+		})
+	}
+}
+
+func TestFeedConditionalRequests(t *testing.T) {
+	db := setupFeedDB(t)
+	router := setupFeedRouter(db)
+	_, expired := seedFeedJobs(t, db)
+
+	get := func(path string, header map[string]string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	first := get("/api/v1/jobs/feed/rss", nil)
+	assert.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	lastModified := first.Header().Get("Last-Modified")
+
+	t.Run("内容未变化时返回 304", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotModified, get("/api/v1/jobs/feed/rss", map[string]string{"If-None-Match": etag}).Code)
+		assert.Equal(t, http.StatusNotModified, get("/api/v1/jobs/feed/rss", map[string]string{"If-Modified-Since": lastModified}).Code)
+	})
+
+	t.Run("修改时间包含已过的截止时间", func(t *testing.T) {
+		modified, err := http.ParseTime(lastModified)
+		assert.NoError(t, err)
+		assert.Equal(t, expired.ExpiresAt.UTC().Truncate(time.Second), modified)
+	})
+
+	t.Run("职位到期后调度器未扫描也不再返回 304", func(t *testing.T) {
+		// 开放职位在上次请求之后到期，记录本身没有更新
+		soon := time.Now().UTC().Add(-time.Second)
+		db.Model(&models.Job{}).Where("id = ?", 1).UpdateColumn("expires_at", soon)
+		db.Model(&models.Job{}).Where("id = ?", 1).UpdateColumn("updated_at", soon.Add(-2*time.Hour))
+
+		w := get("/api/v1/jobs/feed/rss", map[string]string{"If-None-Match": etag})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotEqual(t, etag, w.Header().Get("ETag"))
+		assert.NotContains(t, w.Body.String(), "Go 开发工程师")
+	})
+}
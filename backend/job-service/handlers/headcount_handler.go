@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"fmt"
+	"job-service/models"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// HeadcountHandler 部门编制计划
+type HeadcountHandler struct {
+	DB *gorm.DB
+}
+
+func NewHeadcountHandler(db *gorm.DB) *HeadcountHandler {
+	return &HeadcountHandler{DB: db}
+}
+
+// HeadcountPlanRequest 创建/更新编制计划请求
+type HeadcountPlanRequest struct {
+	Department string `json:"department" binding:"required"`
+	Period     string `json:"period" binding:"required"`
+	Planned    int    `json:"planned"`
+	Notes      string `json:"notes"`
+}
+
+// ListPlans 获取编制计划列表
+func (h *HeadcountHandler) ListPlans(c *gin.Context) {
+	query := h.DB.Model(&models.HeadcountPlan{})
+	if department := c.Query("department"); department != "" {
+		query = query.Where("department = ?", department)
+	}
+	if period := c.Query("period"); period != "" {
+		query = query.Where("period = ?", period)
+	}
+
+	var plans []models.HeadcountPlan
+	if err := query.Order("period_start DESC, department ASC").Find(&plans).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "Failed to fetch headcount plans"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"plans": plans,
+			"total": len(plans),
+		},
+	})
+}
+
+// CreatePlan 新建编制计划
+func (h *HeadcountHandler) CreatePlan(c *gin.Context) {
+	var plan models.HeadcountPlan
+	if msg := h.bind(c, &plan); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": msg})
+		return
+	}
+
+	var count int64
+	h.DB.Model(&models.HeadcountPlan{}).Where("department = ? AND period = ?", plan.Department, plan.Period).Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "该部门在此周期已有编制计划"})
+		return
+	}
+
+	if userID, exists := c.Get("user_id"); exists {
+		plan.CreatedBy, _ = userID.(uint)
+	}
+
+	if err := h.DB.Create(&plan).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "Failed to create headcount plan"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"code":    0,
+		"message": "Headcount plan created successfully",
+		"data":    plan,
+	})
+}
+
+// UpdatePlan 更新编制计划
+func (h *HeadcountHandler) UpdatePlan(c *gin.Context) {
+	var plan models.HeadcountPlan
+	if err := h.DB.First(&plan, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "Headcount plan not found"})
+		return
+	}
+
+	if msg := h.bind(c, &plan); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": msg})
+		return
+	}
+
+	var count int64
+	h.DB.Model(&models.HeadcountPlan{}).
+		Where("department = ? AND period = ? AND id <> ?", plan.Department, plan.Period, plan.ID).
+		Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "该部门在此周期已有编制计划"})
+		return
+	}
+
+	if err := h.DB.Save(&plan).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "Failed to update headcount plan"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "Headcount plan updated successfully",
+		"data":    plan,
+	})
+}
+
+// DeletePlan 删除编制计划
+func (h *HeadcountHandler) DeletePlan(c *gin.Context) {
+	if err := h.DB.Delete(&models.HeadcountPlan{}, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "Failed to delete headcount plan"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "Headcount plan deleted successfully",
+	})
+}
+
+// bind 解析请求并写入计划字段，返回错误信息
+func (h *HeadcountHandler) bind(c *gin.Context, plan *models.HeadcountPlan) string {
+	var req HeadcountPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return err.Error()
+	}
+	if req.Planned < 0 {
+		return "计划人数不能为负数"
+	}
+
+	period := strings.ToUpper(strings.TrimSpace(req.Period))
+	start, end, err := parsePeriod(period)
+	if err != nil {
+		return err.Error()
+	}
+
+	plan.Department = strings.TrimSpace(req.Department)
+	plan.Period = period
+	plan.PeriodStart = start
+	plan.PeriodEnd = end
+	plan.Planned = req.Planned
+	plan.Notes = req.Notes
+	return ""
+}
+
+var periodPattern = regexp.MustCompile(`^(\d{4})(?:-(Q[1-4]|\d{2}))?$`)
+
+// parsePeriod 解析编制周期（年 2025、季度 2025-Q1、月 2025-03），返回 [start, end)
+func parsePeriod(period string) (time.Time, time.Time, error) {
+	m := periodPattern.FindStringSubmatch(period)
+	if m == nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("周期格式错误，应为 2025、2025-Q1 或 2025-03")
+	}
+
+	year, _ := strconv.Atoi(m[1])
+	switch {
+	case m[2] == "":
+		start := time.Date(year, 1, 1, 0, 0, 0, 0, time.Local)
+		return start, start.AddDate(1, 0, 0), nil
+	case m[2][0] == 'Q':
+		quarter := int(m[2][1] - '0')
+		start := time.Date(year, time.Month((quarter-1)*3+1), 1, 0, 0, 0, 0, time.Local)
+		return start, start.AddDate(0, 3, 0), nil
+	default:
+		month, _ := strconv.Atoi(m[2])
+		if month < 1 || month > 12 {
+			return time.Time{}, time.Time{}, fmt.Errorf("月份超出范围: %s", period)
+		}
+		start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.Local)
+		return start, start.AddDate(0, 1, 0), nil
+	}
+}
@@ -2,19 +2,66 @@ package handlers
 
 import (
 	"job-service/models"
+	"job-service/scheduler"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
+
+	"common/customfields"
+	"common/export"
+	"common/headcount"
+	"common/jdextract"
+	"common/salary"
+	"common/skills"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
+// maxExportRows 单次导出的最大行数
+const maxExportRows = 5000
+
 type JobHandler struct {
-	DB *gorm.DB
+	DB        *gorm.DB
+	Skills    *skills.Dictionary
+	Extractor *jdextract.Extractor
+	AutoFill  bool // 默认是否用职位描述的解析结果补全空字段，可被 auto_fill 查询参数覆盖
+
+	// Scheduler 人工关闭职位时与到期关闭一样处理待处理的申请并通知候选人，为空时不处理
+	Scheduler *scheduler.Scheduler
+}
+
+// JobManagerRoles 可以新建、修改、删除、复制职位，手动变更职位状态、调整招聘流程，
+// 以及查看筛选问题淘汰条件的角色（hr 为自助注册的 HR 账号）
+var JobManagerRoles = []string{"admin", "hr_manager", "hr", "recruiter"}
+
+// isJobManager 当前用户是否为招聘管理角色（角色由 JWT 中间件写入）
+func isJobManager(c *gin.Context) bool {
+	_, role := currentUser(c)
+	for _, r := range JobManagerRoles {
+		if role == r {
+			return true
+		}
+	}
+	return false
+}
+
+// hideKnockouts 非招聘管理角色只能看到筛选问题本身，不返回淘汰条件，避免候选人按条件作答
+func hideKnockouts(c *gin.Context, job *models.Job) {
+	if !isJobManager(c) {
+		job.ScreeningQuestions = job.ScreeningQuestions.Public()
+	}
 }
 
 func NewJobHandler(db *gorm.DB) *JobHandler {
-	return &JobHandler{DB: db}
+	autoFill, _ := strconv.ParseBool(getEnv("JD_AUTO_FILL", "false"))
+	return &JobHandler{
+		DB:        db,
+		Skills:    skills.Default(),
+		Extractor: jdextract.New(skills.Default()),
+		AutoFill:  autoFill,
+	}
 }
 
 // CreateJob 创建职位
@@ -25,45 +72,218 @@ func (h *JobHandler) CreateJob(c *gin.Context) {
 		return
 	}
 
-	if userID, exists := c.Get("user_id"); exists {
-		job.CreatedBy = userID.(uint)
+	// 创建人以登录用户为准，忽略请求中的 created_by
+	job.CreatedBy, _ = currentUser(c)
+
+	// 新职位一律为草稿，需提交审批通过后才对外发布
+	job.Status = models.JobStatusDraft
+	job.ApprovalRound = 0
+	job.Hired = 0
+	job.ExpiryWarnedAt = nil
+	if msg := validateSchedule(job, nil); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+	// 未填写招聘名额时默认 1 个，与更新时的校验保持一致
+	if job.Headcount == 0 {
+		job.Headcount = 1
+	}
+	if job.Headcount < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "招聘名额至少为 1"})
+		return
+	}
+	if err := job.ScreeningQuestions.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	suggestions, filled := h.suggest(c, &job)
+	job.Skills = h.Skills.Normalize(job.Skills)
+
+	var err error
+	if job.SalaryRange, job.Salary, err = salary.Resolve(job.Salary, job.SalaryRange); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	defs, err := customfields.LoadDefinitions(h.DB, customfields.EntityJob)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load custom fields"})
+		return
+	}
+	if job.CustomFields, err = customfields.Validate(defs, job.CustomFields, true); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	if err := h.DB.Create(&job).Error; err != nil {
+	job.Version = 1
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&job).Error; err != nil {
+			return err
+		}
+		return recordVersion(tx, job.Snapshot(), job.CreatedBy, job.ChangeNote)
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create job"})
 		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
-		"code":    0,
-		"message": "Job created successfully",
-		"data":    job,
+		"code":        0,
+		"message":     "Job created successfully",
+		"data":        job,
+		"suggestions": suggestions,
+		"auto_filled": filled,
 	})
 }
 
 // ListJobs 获取职位列表
 func (h *JobHandler) ListJobs(c *gin.Context) {
+	h.listJobs(c, c.Query("status"), false)
+}
+
+// ListPublicJobs 候选人端职位列表，只返回审批通过且开放中的职位
+func (h *JobHandler) ListPublicJobs(c *gin.Context) {
+	h.listJobs(c, models.JobStatusOpen, true)
+}
+
+// GetPublicJob 候选人端职位详情
+func (h *JobHandler) GetPublicJob(c *gin.Context) {
+	var job models.Job
+	if err := h.DB.Where("status = ?", models.JobStatusOpen).First(&job, c.Param("id")).Error; err != nil || job.Expired(time.Now()) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+	// 淘汰条件不对候选人展示
+	job.ScreeningQuestions = job.ScreeningQuestions.Public()
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data":    job,
+	})
+}
+
+// listJobs 分页查询职位；筛选问题的淘汰条件只返回给招聘管理角色，public 为 true 时（候选人端）一律不返回
+func (h *JobHandler) listJobs(c *gin.Context, status string, public bool) {
 	var jobs []models.Job
 
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
-	status := c.Query("status")
+	sortBy := c.DefaultQuery("sort_by", "created_at")
+	sortOrder := c.DefaultQuery("sort_order", "desc")
+
+	offset := (page - 1) * pageSize
+
+	query, _, err := h.listQuery(c, status)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var total int64
+	query.Count(&total)
+
+	// 排序
+	allowedSortFields := map[string]string{"created_at": "created_at", "salary": "salary_annual_max", "title": "title"}
+	sortColumn, ok := allowedSortFields[sortBy]
+	if !ok {
+		sortColumn = "created_at"
+	}
+	if sortOrder != "asc" && sortOrder != "desc" {
+		sortOrder = "desc"
+	}
+	orderClause := sortColumn + " " + sortOrder
+	if sortBy == "salary" {
+		orderClause += " NULLS LAST"
+	}
+
+	if err := query.Order(orderClause).Offset(offset).Limit(pageSize).Find(&jobs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch jobs"})
+		return
+	}
+
+	// 查询每个职位的申请人数
+	type JobWithApplicants struct {
+		models.Job
+		Applicants        int64 `json:"applicants"`
+		RemainingOpenings int   `json:"remaining_openings"`
+	}
+
+	jobsWithApplicants := make([]JobWithApplicants, len(jobs))
+	for i, job := range jobs {
+		var count int64
+		h.DB.Table("applications").Where("job_id = ?", job.ID).Count(&count)
+		if public {
+			job.ScreeningQuestions = job.ScreeningQuestions.Public()
+		} else {
+			hideKnockouts(c, &job)
+		}
+		jobsWithApplicants[i] = JobWithApplicants{
+			Job:               job,
+			Applicants:        count,
+			RemainingOpenings: job.RemainingOpenings(),
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"jobs":      jobsWithApplicants,
+			"total":     total,
+			"page":      page,
+			"page_size": pageSize,
+		},
+	})
+}
+
+// ExportJobs 导出职位列表（CSV，包含自定义字段，筛选条件同列表）
+func (h *JobHandler) ExportJobs(c *gin.Context) {
+	query, defs, err := h.listQuery(c, c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var jobs []models.Job
+	if err := query.Order("created_at DESC").Limit(maxExportRows).Find(&jobs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export jobs"})
+		return
+	}
+
+	header := []string{"ID", "职位名称", "部门", "地点", "类型", "级别", "薪资", "技能", "状态", "创建时间"}
+	header = append(header, customfields.Header(defs)...)
+
+	rows := make([][]string, 0, len(jobs))
+	for _, j := range jobs {
+		row := []string{
+			strconv.Itoa(int(j.ID)), j.Title, j.Department, j.Location, j.Type, j.Level,
+			j.Salary, strings.Join(j.Skills, ", "), j.Status, j.CreatedAt.Format("2006-01-02 15:04"),
+		}
+		rows = append(rows, append(row, customfields.Cells(defs, j.CustomFields)...))
+	}
+
+	export.WriteCSV(c, "jobs", header, rows)
+}
+
+// listQuery 构建职位列表/导出的筛选条件
+func (h *JobHandler) listQuery(c *gin.Context, status string) (*gorm.DB, []customfields.Definition, error) {
 	jobType := c.Query("type")
 	location := c.Query("location")
 	search := c.Query("search")
 	keyword := c.Query("keyword")
 	level := c.Query("level")
 	experience := c.Query("experience")
-	sortBy := c.DefaultQuery("sort_by", "created_at")
-	sortOrder := c.DefaultQuery("sort_order", "desc")
-
-	offset := (page - 1) * pageSize
 
 	query := h.DB.Model(&models.Job{})
 
 	if status != "" {
 		query = query.Where("status = ?", status)
 	}
+	// 已过截止时间但调度器尚未关闭的职位不再展示给候选人
+	if status == models.JobStatusOpen {
+		query = query.Where("expires_at IS NULL OR expires_at > ?", time.Now())
+	}
 
 	if jobType != "" {
 		query = query.Where("type = ?", jobType)
@@ -101,50 +321,23 @@ func (h *JobHandler) ListJobs(c *gin.Context) {
 		}
 	}
 
-	var total int64
-	query.Count(&total)
-
-	// 排序
-	allowedSortFields := map[string]bool{"created_at": true, "salary": true, "title": true}
-	if !allowedSortFields[sortBy] {
-		sortBy = "created_at"
-	}
-	if sortOrder != "asc" && sortOrder != "desc" {
-		sortOrder = "desc"
-	}
-	orderClause := sortBy + " " + sortOrder
-
-	if err := query.Order(orderClause).Offset(offset).Limit(pageSize).Find(&jobs).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch jobs"})
-		return
+	// 薪资区间筛选（salary_min / salary_max）
+	query, err := salary.ApplyFilter(query, "salary_", c.Request.URL.Query())
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// 查询每个职位的申请人数
-	type JobWithApplicants struct {
-		models.Job
-		Applicants int64 `json:"applicants"`
+	// 自定义字段筛选
+	defs, err := customfields.LoadDefinitions(h.DB, customfields.EntityJob)
+	if err != nil {
+		return nil, nil, err
 	}
-
-	jobsWithApplicants := make([]JobWithApplicants, len(jobs))
-	for i, job := range jobs {
-		var count int64
-		h.DB.Table("applications").Where("job_id = ?", job.ID).Count(&count)
-		jobsWithApplicants[i] = JobWithApplicants{
-			Job:        job,
-			Applicants: count,
-		}
+	query, err = customfields.ApplyFilters(query, defs, c.Request.URL.Query(), "custom_fields")
+	if err != nil {
+		return nil, nil, err
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"code":    0,
-		"message": "success",
-		"data": gin.H{
-			"jobs":      jobsWithApplicants,
-			"total":     total,
-			"page":      page,
-			"page_size": pageSize,
-		},
-	})
+	return query, defs, nil
 }
 
 // GetJob 获取职位详情
@@ -156,6 +349,7 @@ func (h *JobHandler) GetJob(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
 		return
 	}
+	hideKnockouts(c, &job)
 
 	c.JSON(http.StatusOK, gin.H{
 		"code":    0,
@@ -174,19 +368,237 @@ func (h *JobHandler) UpdateJob(c *gin.Context) {
 		return
 	}
 
+	if job.Status == models.JobStatusPendingApproval {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "审批中的职位不能修改，请先撤回审批"})
+		return
+	}
+
+	// 状态只能通过审批流程或状态接口变更，版本号由系统维护
+	status, round, createdBy, hired, version := job.Status, job.ApprovalRound, job.CreatedBy, job.Hired, job.Version
+	before := job.Snapshot()
+	previous := job
+
+	// 自定义字段按增量合并，未提交的字段保持原值
+	existing := job.CustomFields
+	job.CustomFields = nil
+
+	// 未提交结构化薪资时按薪资文本重新解析
+	oldSalary := job.Salary
+	job.SalaryRange = salary.Range{}
+
+	// 筛选问题整体替换，未提交时保持原值
+	questions := job.ScreeningQuestions
+	job.ScreeningQuestions = nil
+
 	if err := c.ShouldBindJSON(&job); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if job.ScreeningQuestions == nil {
+		job.ScreeningQuestions = questions
+	}
+
+	job.Status, job.ApprovalRound, job.CreatedBy, job.Hired, job.Version = status, round, createdBy, hired, version
+	if msg := validateSchedule(job, &previous); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+	// 延长或修改截止时间后重新发送到期提醒
+	job.ExpiryWarnedAt = previous.ExpiryWarnedAt
+	if !sameTime(job.ExpiresAt, previous.ExpiresAt) {
+		job.ExpiryWarnedAt = nil
+	}
+	if !job.SalaryRange.IsZero() && job.Salary == oldSalary {
+		job.Salary = ""
+	}
+	var err error
+	if job.SalaryRange, job.Salary, err = salary.Resolve(job.Salary, job.SalaryRange); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if job.Headcount < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "招聘名额至少为 1"})
+		return
+	}
+	if err := job.ScreeningQuestions.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	suggestions, filled := h.suggest(c, &job)
+	job.Skills = h.Skills.Normalize(job.Skills)
 
-	if err := h.DB.Save(&job).Error; err != nil {
+	defs, err := customfields.LoadDefinitions(h.DB, customfields.EntityJob)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load custom fields"})
+		return
+	}
+	if job.CustomFields, err = customfields.Patch(defs, existing, job.CustomFields); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 内容有变化时生成新版本，旧版本保持不变
+	changed := contentChanged(before, job.Snapshot())
+	if changed {
+		job.Version = version + 1
+	}
+	userID, _ := currentUser(c)
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&job).Error; err != nil {
+			return err
+		}
+		if !changed {
+			return nil
+		}
+		// 历史职位可能还没有基线版本，先补记修改前的内容
+		var count int64
+		tx.Model(&models.JobVersion{}).Where("job_id = ? AND version = ?", job.ID, before.Version).Count(&count)
+		if count == 0 {
+			if err := recordVersion(tx, before, createdBy, ""); err != nil {
+				return err
+			}
+		}
+		return recordVersion(tx, job.Snapshot(), userID, job.ChangeNote)
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update job"})
 		return
 	}
 
+	// 名额调整后重新计算是否已招满
+	if err := headcount.SyncJob(h.DB, job.ID); err == nil {
+		h.DB.First(&job, job.ID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":        0,
+		"message":     "Job updated successfully",
+		"data":        job,
+		"suggestions": suggestions,
+		"auto_filled": filled,
+	})
+}
+
+// ExtractRequirements 预览从职位描述中提取的技能、任职要求、职级、年限和学历，不保存
+func (h *JobHandler) ExtractRequirements(c *gin.Context) {
+	var req struct {
+		Title       string `json:"title"`
+		Description string `json:"description" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data":    h.Extractor.Extract(req.Title, req.Description),
+	})
+}
+
+// suggest 解析职位描述得到建议值；开启自动补全时用建议值填充为空的字段，返回被补全的字段名
+func (h *JobHandler) suggest(c *gin.Context, job *models.Job) (jdextract.Suggestions, []string) {
+	s := h.Extractor.Extract(job.Title, job.Description)
+
+	autoFill := h.AutoFill
+	if v, err := strconv.ParseBool(c.Query("auto_fill")); err == nil {
+		autoFill = v
+	}
+	filled := []string{}
+	if !autoFill {
+		return s, filled
+	}
+
+	if len(job.Skills) == 0 && len(s.Skills) > 0 {
+		job.Skills = s.Skills
+		filled = append(filled, "skills")
+	}
+	if len(job.Requirements) == 0 && len(s.Requirements) > 0 {
+		job.Requirements = s.Requirements
+		filled = append(filled, "requirements")
+	}
+	if job.Level == "" && s.Level != "" {
+		job.Level = s.Level
+		filled = append(filled, "level")
+	}
+	if job.MinExperience == 0 && s.MinExperience > 0 {
+		job.MinExperience = s.MinExperience
+		filled = append(filled, "min_experience")
+	}
+	if job.Education == "" && s.Education != "" {
+		job.Education = s.Education
+		filled = append(filled, "education")
+	}
+	return s, filled
+}
+
+// validateSchedule 校验发布时间与截止时间，previous 为更新前的职位（新建时为 nil）
+func validateSchedule(job models.Job, previous *models.Job) string {
+	if job.PublishAt != nil && job.ExpiresAt != nil && !job.ExpiresAt.After(*job.PublishAt) {
+		return "截止时间必须晚于发布时间"
+	}
+	changed := previous == nil || !sameTime(job.ExpiresAt, previous.ExpiresAt)
+	if changed && job.Expired(time.Now()) {
+		return "截止时间不能早于当前时间"
+	}
+	return ""
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// ChangeJobStatus 手动变更职位状态（暂停、恢复、关闭、招满等）
+func (h *JobHandler) ChangeJobStatus(c *gin.Context) {
+	var job models.Job
+	if err := h.DB.First(&job, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+
+	var req struct {
+		Status string `json:"status" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !models.CanTransition(job.Status, req.Status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "职位状态不能从 " + job.Status + " 变更为 " + req.Status})
+		return
+	}
+	if req.Status == models.JobStatusOpen && job.Expired(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "职位已过截止时间，请先修改截止时间"})
+		return
+	}
+
+	var candidates []uint
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&job).Update("status", req.Status).Error; err != nil {
+			return err
+		}
+		if req.Status != models.JobStatusClosed || h.Scheduler == nil {
+			return nil
+		}
+		var err error
+		candidates, err = h.Scheduler.CloseApplications(tx, job.ID, "职位已关闭")
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update job status"})
+		return
+	}
+	if h.Scheduler != nil {
+		h.Scheduler.NotifyApplicants(job, candidates)
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"code":    0,
-		"message": "Job updated successfully",
+		"message": "Job status updated successfully",
 		"data":    job,
 	})
 }
@@ -209,16 +621,22 @@ func (h *JobHandler) DeleteJob(c *gin.Context) {
 // GetJobStats 获取职位统计
 func (h *JobHandler) GetJobStats(c *gin.Context) {
 	var stats struct {
-		TotalJobs  int64 `json:"total_jobs"`
-		OpenJobs   int64 `json:"open_jobs"`
-		ClosedJobs int64 `json:"closed_jobs"`
-		FilledJobs int64 `json:"filled_jobs"`
+		TotalJobs   int64 `json:"total_jobs"`
+		DraftJobs   int64 `json:"draft_jobs"`
+		PendingJobs int64 `json:"pending_jobs"`
+		OpenJobs    int64 `json:"open_jobs"`
+		OnHoldJobs  int64 `json:"on_hold_jobs"`
+		ClosedJobs  int64 `json:"closed_jobs"`
+		FilledJobs  int64 `json:"filled_jobs"`
 	}
 
 	h.DB.Model(&models.Job{}).Count(&stats.TotalJobs)
-	h.DB.Model(&models.Job{}).Where("status = ?", "open").Count(&stats.OpenJobs)
-	h.DB.Model(&models.Job{}).Where("status = ?", "closed").Count(&stats.ClosedJobs)
-	h.DB.Model(&models.Job{}).Where("status = ?", "filled").Count(&stats.FilledJobs)
+	h.DB.Model(&models.Job{}).Where("status = ?", models.JobStatusDraft).Count(&stats.DraftJobs)
+	h.DB.Model(&models.Job{}).Where("status = ?", models.JobStatusPendingApproval).Count(&stats.PendingJobs)
+	h.DB.Model(&models.Job{}).Where("status = ?", models.JobStatusOpen).Count(&stats.OpenJobs)
+	h.DB.Model(&models.Job{}).Where("status = ?", models.JobStatusOnHold).Count(&stats.OnHoldJobs)
+	h.DB.Model(&models.Job{}).Where("status = ?", models.JobStatusClosed).Count(&stats.ClosedJobs)
+	h.DB.Model(&models.Job{}).Where("status = ?", models.JobStatusFilled).Count(&stats.FilledJobs)
 
 	c.JSON(http.StatusOK, gin.H{
 		"code":    0,
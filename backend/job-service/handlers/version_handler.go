@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"job-service/models"
+	"log"
+	"net/http"
+	"reflect"
+	"strconv"
+
+	"common/textdiff"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// VersionHandler 职位版本历史、对比与复制
+type VersionHandler struct {
+	DB *gorm.DB
+}
+
+func NewVersionHandler(db *gorm.DB) *VersionHandler {
+	return &VersionHandler{DB: db}
+}
+
+// FieldChange 两个版本间一个字段的变化
+type FieldChange struct {
+	Field   string          `json:"field"`
+	From    interface{}     `json:"from,omitempty"`
+	To      interface{}     `json:"to,omitempty"`
+	Added   []string        `json:"added,omitempty"`   // 列表字段新增项
+	Removed []string        `json:"removed,omitempty"` // 列表字段移除项
+	Lines   []textdiff.Line `json:"lines,omitempty"`   // 职位描述逐行差异
+}
+
+// ListVersions 获取职位的版本列表（按版本号倒序）
+func (h *VersionHandler) ListVersions(c *gin.Context) {
+	var job models.Job
+	if err := h.DB.First(&job, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+
+	var versions []models.JobVersion
+	if err := h.DB.Where("job_id = ?", job.ID).Order("version DESC").Find(&versions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch job versions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"current":  job.Version,
+			"versions": versions,
+			"total":    len(versions),
+		},
+	})
+}
+
+// GetVersion 获取职位的某个版本
+func (h *VersionHandler) GetVersion(c *gin.Context) {
+	version, err := h.findVersion(c.Param("id"), c.Param("version"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data":    version,
+	})
+}
+
+// DiffVersions 对比职位的两个版本，默认 to 为当前版本、from 为其上一个版本
+func (h *VersionHandler) DiffVersions(c *gin.Context) {
+	var job models.Job
+	if err := h.DB.First(&job, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+
+	to := job.Version
+	if v := c.Query("to"); v != "" {
+		to, _ = strconv.Atoi(v)
+	}
+	from := to - 1
+	if v := c.Query("from"); v != "" {
+		from, _ = strconv.Atoi(v)
+	}
+	if from < 1 || to < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "版本号必须大于 0"})
+		return
+	}
+
+	var versions []models.JobVersion
+	h.DB.Where("job_id = ? AND version IN ?", job.ID, []int{from, to}).Find(&versions)
+	byNumber := make(map[int]models.JobVersion, len(versions))
+	for _, v := range versions {
+		byNumber[v.Version] = v
+	}
+	a, okA := byNumber[from]
+	b, okB := byNumber[to]
+	if !okA || !okB {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job version not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"job_id":  job.ID,
+			"from":    from,
+			"to":      to,
+			"changes": diffVersions(a, b),
+		},
+	})
+}
+
+// CloneJob 复制职位（或其某个历史版本）为新的草稿职位
+func (h *VersionHandler) CloneJob(c *gin.Context) {
+	var source models.Job
+	if err := h.DB.First(&source, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+
+	var req struct {
+		Version int    `json:"version"` // 为空时复制当前内容
+		Title   string `json:"title"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	content := source.Snapshot()
+	if req.Version > 0 {
+		v, err := h.findVersion(c.Param("id"), strconv.Itoa(req.Version))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		content = v
+	}
+
+	var job models.Job
+	content.Restore(&job)
+	job.Title = content.Title + " (副本)"
+	if req.Title != "" {
+		job.Title = req.Title
+	}
+	// 筛选问题不属于版本内容，按源职位当前的设置复制
+	job.ScreeningQuestions = source.ScreeningQuestions
+	job.Status = models.JobStatusDraft
+	job.Version = 1
+	job.CreatedBy, _ = currentUser(c)
+
+	note := fmt.Sprintf("复制自职位 #%d 版本 %d", source.ID, content.Version)
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&job).Error; err != nil {
+			return err
+		}
+		return recordVersion(tx, job.Snapshot(), job.CreatedBy, note)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to clone job"})
+		return
+	}
+	hideKnockouts(c, &job)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"code":    0,
+		"message": "Job cloned successfully",
+		"data":    job,
+	})
+}
+
+func (h *VersionHandler) findVersion(jobID, version string) (models.JobVersion, error) {
+	var v models.JobVersion
+	err := h.DB.Where("job_id = ? AND version = ?", jobID, version).First(&v).Error
+	if err != nil {
+		return v, errors.New("Job version not found")
+	}
+	return v, nil
+}
+
+// recordVersion 保存职位内容快照
+func recordVersion(tx *gorm.DB, v models.JobVersion, changedBy uint, note string) error {
+	v.ChangedBy = changedBy
+	v.ChangeNote = note
+	return tx.Create(&v).Error
+}
+
+// contentChanged 判断两份快照的职位内容是否不同
+func contentChanged(a, b models.JobVersion) bool {
+	return len(diffVersions(a, b)) > 0
+}
+
+// diffVersions 逐字段对比两个版本
+func diffVersions(a, b models.JobVersion) []FieldChange {
+	changes := make([]FieldChange, 0)
+
+	if lines := textdiff.Lines(a.Description, b.Description); textdiff.Changed(lines) {
+		changes = append(changes, FieldChange{Field: "description", Lines: lines})
+	}
+
+	scalars := []struct {
+		field    string
+		from, to interface{}
+	}{
+		{"title", a.Title, b.Title},
+		{"salary", a.Salary, b.Salary},
+		{"location", a.Location, b.Location},
+		{"type", a.Type, b.Type},
+		{"department", a.Department, b.Department},
+		{"level", a.Level, b.Level},
+		{"min_experience", a.MinExperience, b.MinExperience},
+		{"education", a.Education, b.Education},
+		{"headcount", a.Headcount, b.Headcount},
+		{"salary_range", a.SalaryRange, b.SalaryRange},
+		{"custom_fields", a.CustomFields, b.CustomFields},
+	}
+	for _, s := range scalars {
+		if !reflect.DeepEqual(s.from, s.to) && !(isEmptyMap(s.from) && isEmptyMap(s.to)) {
+			changes = append(changes, FieldChange{Field: s.field, From: s.from, To: s.to})
+		}
+	}
+
+	lists := []struct {
+		field    string
+		from, to []string
+	}{
+		{"requirements", a.Requirements, b.Requirements},
+		{"skills", a.Skills, b.Skills},
+		{"benefits", a.Benefits, b.Benefits},
+	}
+	for _, l := range lists {
+		added, removed := textdiff.SetDiff(l.from, l.to)
+		switch {
+		case len(added) > 0 || len(removed) > 0:
+			changes = append(changes, FieldChange{Field: l.field, Added: added, Removed: removed})
+		case !reflect.DeepEqual([]string(l.from), []string(l.to)) && len(l.from)+len(l.to) > 0:
+			// 仅调整了顺序
+			changes = append(changes, FieldChange{Field: l.field, From: l.from, To: l.to})
+		}
+	}
+
+	return changes
+}
+
+// isEmptyMap 自定义字段 nil 与空对象视为相同
+func isEmptyMap(v interface{}) bool {
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Map && rv.Len() == 0
+}
+
+// BackfillVersions 为尚无版本记录的历史职位补建当前版本快照
+func BackfillVersions(db *gorm.DB) {
+	var jobs []models.Job
+	db.Where("NOT EXISTS (SELECT 1 FROM job_versions v WHERE v.job_id = jobs.id)").Find(&jobs)
+	for _, job := range jobs {
+		if job.Version < 1 {
+			job.Version = 1
+			db.Model(&job).UpdateColumn("version", 1)
+		}
+		if err := recordVersion(db, job.Snapshot(), job.CreatedBy, "初始版本"); err != nil {
+			log.Printf("Failed to backfill version for job %d: %v", job.ID, err)
+		}
+	}
+}
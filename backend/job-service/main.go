@@ -3,9 +3,14 @@ package main
 import (
 	"job-service/handlers"
 	"job-service/models"
+	"job-service/scheduler"
 	"log"
 
 	"common/middleware"
+	"common/notify"
+	"common/pipeline"
+	"common/salary"
+	"common/skills"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/driver/postgres"
@@ -19,16 +24,67 @@ func main() {
 		log.Fatal("Failed to connect database:", err)
 	}
 
-	if err := db.AutoMigrate(&models.Job{}); err != nil {
+	if err := db.AutoMigrate(
+		&models.Job{},
+		&models.JobVersion{},
+		&models.ApprovalChain{},
+		&models.ApprovalStep{},
+		&models.JobApproval{},
+		&models.JobApprovalComment{},
+		&models.HeadcountPlan{},
+		&skills.Skill{},
+		&pipeline.JobPipeline{},
+	); err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
 
+	// 将历史自由文本薪资迁移为结构化薪资
+	if n, err := salary.Backfill(db, "jobs", "salary", "salary_"); err != nil {
+		log.Printf("Warning: Failed to migrate job salaries: %v", err)
+	} else if n > 0 {
+		log.Printf("Migrated %d job salaries to structured ranges", n)
+	}
+
+	// 为历史职位建立初始版本
+	handlers.BackfillVersions(db)
+
+	// 技能字典由 job-service 维护：首次启动写入内置技能
+	if err := skills.Seed(db); err != nil {
+		log.Printf("Warning: Failed to seed skill dictionary: %v", err)
+	}
+	if err := skills.Default().Load(db); err != nil {
+		log.Printf("Warning: Failed to load skill dictionary: %v", err)
+	}
+
+	notifier := notify.NewClientFromEnv()
+
+	// 定时发布、到期关闭与到期提醒
+	jobScheduler := scheduler.NewFromEnv(db, notifier)
+	jobScheduler.Start()
+
 	r := gin.Default()
 
 	r.Use(middleware.CORS())
 	r.Use(middleware.SimpleOperationLog("job-service"))
 
+	registerRoutes(r, db, notifier, jobScheduler)
+
+	log.Println("Job service is running on :8082")
+	if err := r.Run(":8082"); err != nil {
+		log.Fatal("Failed to start server:", err)
+	}
+}
+
+// registerRoutes 注册职位服务的全部路由
+func registerRoutes(r *gin.Engine, db *gorm.DB, notifier *notify.Client, jobScheduler *scheduler.Scheduler) {
 	jobHandler := handlers.NewJobHandler(db)
+	jobHandler.Scheduler = jobScheduler
+	approvalHandler := handlers.NewApprovalHandler(db, notifier)
+	headcountHandler := handlers.NewHeadcountHandler(db)
+	skillHandler := skills.NewHandler(db, skills.Default())
+	feedHandler := handlers.NewFeedHandler(db)
+	versionHandler := handlers.NewVersionHandler(db)
+	pipelineHandler := pipeline.NewHandler(db)
 
 	// 健康检查
 	r.GET("/health", func(c *gin.Context) {
@@ -37,16 +93,75 @@ func main() {
 
 	api := r.Group("/api/v1/jobs")
 	{
-		api.POST("", jobHandler.CreateJob)
-		api.GET("", jobHandler.ListJobs)
+		// 新建、修改和删除职位需登录且具备招聘管理角色
+		manage := api.Group("", middleware.JWTAuth(), middleware.RoleAuth(handlers.JobManagerRoles...))
+		manage.POST("", jobHandler.CreateJob)
+		manage.PUT("/:id", jobHandler.UpdateJob)
+		manage.DELETE("/:id", jobHandler.DeleteJob)
+
+		// 登录的招聘管理角色可以看到筛选问题的淘汰条件
+		api.GET("", middleware.OptionalJWTAuth(), jobHandler.ListJobs)
 		api.GET("/stats", jobHandler.GetJobStats)
-		api.GET("/:id", jobHandler.GetJob)
-		api.PUT("/:id", jobHandler.UpdateJob)
-		api.DELETE("/:id", jobHandler.DeleteJob)
+		api.GET("/export", jobHandler.ExportJobs)
+		api.POST("/extract", jobHandler.ExtractRequirements)
+		api.GET("/:id", middleware.OptionalJWTAuth(), jobHandler.GetJob)
+
+		// 职位版本历史
+		api.GET("/:id/versions", versionHandler.ListVersions)
+		api.GET("/:id/versions/diff", versionHandler.DiffVersions)
+		api.GET("/:id/versions/:version", versionHandler.GetVersion)
+
+		// 职位招聘流程（阶段与允许的流转）
+		api.GET("/:id/pipeline", pipelineHandler.GetPipeline)
+
+		// 候选人端只展示审批通过且开放中的职位
+		api.GET("/public", jobHandler.ListPublicJobs)
+		api.GET("/public/:id", jobHandler.GetPublicJob)
+
+		// 搜索引擎与招聘聚合站点使用的订阅源，无需登录
+		api.GET("/public/:id/jsonld", feedHandler.JobPosting)
+		api.GET("/feed/rss", feedHandler.RSS)
+		api.GET("/feed/atom", feedHandler.Atom)
+		api.GET("/sitemap.xml", feedHandler.Sitemap)
+
+		// 职位需求审批流程
+		workflow := api.Group("", middleware.JWTAuth())
+		workflow.GET("/approvals/pending", approvalHandler.ListMyPendingApprovals)
+		workflow.POST("/:id/submit", approvalHandler.SubmitJob)
+		workflow.POST("/:id/withdraw", approvalHandler.WithdrawJob)
+		workflow.POST("/:id/approve", approvalHandler.ApproveJob)
+		workflow.POST("/:id/reject", approvalHandler.RejectJob)
+		workflow.POST("/:id/comments", approvalHandler.AddComment)
+		workflow.GET("/:id/approvals", approvalHandler.GetApprovals)
+
+		// 手动变更状态、复制职位和调整招聘流程与新建、修改职位使用同一组招聘管理角色：
+		// 手动流转不能绕过审批（只能从已审批的定时发布/暂停状态开放），复制出的职位为草稿，仍需重新提交审批
+		jobManage := workflow.Group("", middleware.RoleAuth(handlers.JobManagerRoles...))
+		jobManage.POST("/:id/status", jobHandler.ChangeJobStatus)
+		jobManage.POST("/:id/clone", versionHandler.CloneJob)
+		jobManage.PUT("/:id/pipeline", pipelineHandler.SavePipeline)
+		jobManage.DELETE("/:id/pipeline", pipelineHandler.ResetPipeline)
 	}
 
-	log.Println("Job service is running on :8082")
-	if err := r.Run(":8082"); err != nil {
-		log.Fatal("Failed to start server:", err)
+	// 部门审批链配置（修改需管理员权限）
+	chains := r.Group("/api/v1/approval-chains", middleware.JWTAuth())
+	{
+		chains.GET("", approvalHandler.ListChains)
+		chains.PUT("", middleware.RoleAuth("admin"), approvalHandler.SaveChain)
+		chains.DELETE("/:id", middleware.RoleAuth("admin"), approvalHandler.DeleteChain)
 	}
+
+	// 部门编制计划（修改需管理员或 HR 主管权限）
+	plans := r.Group("/api/v1/headcount-plans")
+	{
+		plans.GET("", headcountHandler.ListPlans)
+
+		admin := plans.Group("", middleware.JWTAuth(), middleware.RoleAuth("admin", "hr_manager"))
+		admin.POST("", headcountHandler.CreatePlan)
+		admin.PUT("/:id", headcountHandler.UpdatePlan)
+		admin.DELETE("/:id", headcountHandler.DeletePlan)
+	}
+
+	// 技能字典管理（修改需管理员权限）
+	skillHandler.RegisterRoutes(r.Group("/api/v1/skills"), middleware.JWTAuth(), middleware.RoleAuth("admin"))
 }
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"common/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTestRouter(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	r := gin.New()
+	registerRoutes(r, db, nil, nil)
+	return r
+}
+
+func TestJobManageRoutesRequireRole(t *testing.T) {
+	router := setupTestRouter(t)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		role   string
+		status int
+	}{
+		{"未登录不能修改招聘流程", "PUT", "/api/v1/jobs/1/pipeline", "", http.StatusUnauthorized},
+		{"候选人不能修改招聘流程", "PUT", "/api/v1/jobs/1/pipeline", "candidate", http.StatusForbidden},
+		{"面试官不能重置招聘流程", "DELETE", "/api/v1/jobs/1/pipeline", "interviewer", http.StatusForbidden},
+		{"候选人不能复制职位", "POST", "/api/v1/jobs/1/clone", "candidate", http.StatusForbidden},
+		{"面试官不能变更职位状态", "POST", "/api/v1/jobs/1/status", "interviewer", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, nil)
+			if tt.role != "" {
+				token, err := middleware.GenerateToken(1, "tester", tt.role)
+				assert.NoError(t, err)
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
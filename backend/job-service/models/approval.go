@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// 审批记录状态
+const (
+	ApprovalPending   = "pending"
+	ApprovalApproved  = "approved"
+	ApprovalRejected  = "rejected"
+	ApprovalCancelled = "cancelled"
+)
+
+// ApprovalChain 部门职位审批链，Department 为空表示默认审批链
+type ApprovalChain struct {
+	ID         uint           `gorm:"primarykey" json:"id"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	Department string         `gorm:"size:100;uniqueIndex" json:"department"`
+	Steps      []ApprovalStep `gorm:"foreignKey:ChainID;constraint:OnDelete:CASCADE" json:"steps"`
+}
+
+// ApprovalStep 审批链中的一个节点，如用人经理、HRBP、财务
+type ApprovalStep struct {
+	ID         uint   `gorm:"primarykey" json:"id"`
+	ChainID    uint   `gorm:"index;not null" json:"chain_id"`
+	StepOrder  int    `gorm:"not null" json:"step_order"`
+	Name       string `gorm:"size:50;not null" json:"name"`
+	ApproverID uint   `gorm:"not null" json:"approver_id"`
+}
+
+// JobApproval 职位每次提交审批时生成的审批节点记录
+type JobApproval struct {
+	ID         uint       `gorm:"primarykey" json:"id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	JobID      uint       `gorm:"index;not null" json:"job_id"`
+	Round      int        `gorm:"not null" json:"round"`
+	StepOrder  int        `gorm:"not null" json:"step_order"`
+	StepName   string     `gorm:"size:50" json:"step_name"`
+	ApproverID uint       `gorm:"index;not null" json:"approver_id"`
+	Status     string     `gorm:"size:20;default:'pending'" json:"status"` // pending, approved, rejected, cancelled
+	Comment    string     `gorm:"type:text" json:"comment"`
+	DecidedAt  *time.Time `json:"decided_at,omitempty"`
+}
+
+// JobApprovalComment 审批过程中的评论
+type JobApprovalComment struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	JobID     uint      `gorm:"index;not null" json:"job_id"`
+	Round     int       `json:"round"`
+	UserID    uint      `json:"user_id"`
+	Content   string    `gorm:"type:text;not null" json:"content"`
+}
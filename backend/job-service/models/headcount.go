@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// HeadcountPlan 部门在某一周期内的招聘编制计划
+type HeadcountPlan struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Department  string    `gorm:"size:100;not null;uniqueIndex:idx_headcount_dept_period" json:"department"`
+	Period      string    `gorm:"size:20;not null;uniqueIndex:idx_headcount_dept_period" json:"period"` // 2025、2025-Q1、2025-03
+	PeriodStart time.Time `gorm:"not null" json:"period_start"`
+	PeriodEnd   time.Time `gorm:"not null" json:"period_end"` // 不含
+	Planned     int       `gorm:"not null" json:"planned"`
+	Notes       string    `gorm:"type:text" json:"notes"`
+	CreatedBy   uint      `json:"created_by"`
+}
@@ -3,25 +3,95 @@ package models
 import (
 	"time"
 
+	"common/customfields"
+	"common/salary"
+	"common/screening"
+
 	"github.com/lib/pq"
 	"gorm.io/gorm"
 )
 
+// 职位状态
+const (
+	JobStatusDraft           = "draft"
+	JobStatusPendingApproval = "pending_approval"
+	JobStatusScheduled       = "scheduled" // 审批通过，等待定时发布
+	JobStatusOpen            = "open"      // 审批通过，对候选人可见
+	JobStatusOnHold          = "on_hold"
+	JobStatusClosed          = "closed"
+	JobStatusFilled          = "filled"
+)
+
+// jobTransitions 允许手动变更的状态流转（提交审批、审批通过/驳回由审批流程驱动）
+var jobTransitions = map[string][]string{
+	JobStatusDraft:     {JobStatusClosed},
+	JobStatusScheduled: {JobStatusOpen, JobStatusClosed}, // 可提前手动发布
+	JobStatusOpen:      {JobStatusOnHold, JobStatusClosed, JobStatusFilled},
+	JobStatusOnHold:    {JobStatusOpen, JobStatusClosed},
+	JobStatusClosed:    {JobStatusDraft},
+	JobStatusFilled:    {JobStatusClosed},
+}
+
+// CanTransition 判断职位状态能否手动从 from 变更为 to
+func CanTransition(from, to string) bool {
+	for _, s := range jobTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
 type Job struct {
-	ID           uint           `gorm:"primarykey" json:"id"`
-	CreatedAt    time.Time      `json:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at"`
-	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
-	Title        string         `gorm:"size:200;not null" json:"title"`
-	Description  string         `gorm:"type:text" json:"description"`
-	Requirements pq.StringArray `gorm:"type:text[]" json:"requirements"`
-	Salary       string         `gorm:"size:100" json:"salary"`
-	Location     string         `gorm:"size:100" json:"location"`
-	Type         string         `gorm:"size:20;default:'full-time'" json:"type"` // full-time, part-time, contract, internship
-	Status       string         `gorm:"size:20;default:'open'" json:"status"`    // open, closed, filled
-	CreatedBy    uint           `json:"created_by"`
-	Department   string         `gorm:"size:100" json:"department"`
-	Level        string         `gorm:"size:50" json:"level"` // junior, mid, senior, lead
-	Skills       pq.StringArray `gorm:"type:text[]" json:"skills"`
-	Benefits     pq.StringArray `gorm:"type:text[]" json:"benefits"`
+	ID                 uint                `gorm:"primarykey" json:"id"`
+	CreatedAt          time.Time           `json:"created_at"`
+	UpdatedAt          time.Time           `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt      `gorm:"index" json:"-"`
+	Title              string              `gorm:"size:200;not null" json:"title"`
+	Description        string              `gorm:"type:text" json:"description"`
+	Requirements       pq.StringArray      `gorm:"type:text[]" json:"requirements"`
+	Salary             string              `gorm:"size:100" json:"salary"`
+	Location           string              `gorm:"size:100" json:"location"`
+	Type               string              `gorm:"size:20;default:'full-time'" json:"type"` // full-time, part-time, contract, internship
+	Status             string              `gorm:"size:20;default:'draft'" json:"status"`   // draft, pending_approval, scheduled, open, on_hold, closed, filled
+	CreatedBy          uint                `json:"created_by"`
+	Department         string              `gorm:"size:100" json:"department"`
+	Level              string              `gorm:"size:50" json:"level"`            // junior, mid, senior, expert, management
+	MinExperience      int                 `gorm:"default:0" json:"min_experience"` // 最低工作年限
+	Education          string              `gorm:"size:20" json:"education"`        // 最低学历要求：大专、本科、硕士、博士
+	Skills             pq.StringArray      `gorm:"type:text[]" json:"skills"`
+	Benefits           pq.StringArray      `gorm:"type:text[]" json:"benefits"`
+	CustomFields       customfields.Values `gorm:"type:jsonb;default:'{}'" json:"custom_fields"`        // 管理员定义的自定义字段
+	ApprovalRound      int                 `gorm:"default:0" json:"approval_round"`                     // 第几次提交审批
+	Headcount          int                 `gorm:"default:1" json:"headcount"`                          // 招聘名额，默认 1 个
+	Hired              int                 `gorm:"default:0" json:"hired"`                              // 已录用人数，由申请录用自动维护
+	SalaryRange        salary.Range        `gorm:"embedded;embeddedPrefix:salary_" json:"salary_range"` // 结构化薪资，由 salary 文本解析或直接提交
+	Version            int                 `gorm:"default:1" json:"version"`                            // 当前内容版本号，见 JobVersion
+	PublishAt          *time.Time          `gorm:"index" json:"publish_at"`                             // 定时发布时间，审批通过后到点自动开放
+	ExpiresAt          *time.Time          `gorm:"index" json:"expires_at"`                             // 截止时间，到期自动关闭
+	ExpiryWarnedAt     *time.Time          `json:"expiry_warned_at,omitempty"`                          // 已发送到期提醒的时间
+	BlindScreening     bool                `gorm:"default:false" json:"blind_screening"`                // 盲筛：筛选阶段对权限不足的用户隐藏候选人身份信息，见 common/blind
+	ScreeningQuestions screening.Questions `gorm:"type:jsonb;default:'[]'" json:"screening_questions"`  // 申请时回答的筛选问题及淘汰条件，见 common/screening
+	ChangeNote         string              `gorm:"-" json:"change_note,omitempty"`                      // 更新时提交的变更说明，记录到版本历史
+}
+
+// PublishStatus 审批通过后的状态：设置了未来的发布时间时等待定时发布，否则立即开放
+func (j Job) PublishStatus(now time.Time) string {
+	if j.PublishAt != nil && j.PublishAt.After(now) {
+		return JobStatusScheduled
+	}
+	return JobStatusOpen
+}
+
+// Expired 是否已过截止时间
+func (j Job) Expired(now time.Time) bool {
+	return j.ExpiresAt != nil && !j.ExpiresAt.After(now)
+}
+
+// RemainingOpenings 剩余名额
+func (j Job) RemainingOpenings() int {
+	if j.Hired >= j.Headcount {
+		return 0
+	}
+	return j.Headcount - j.Hired
 }
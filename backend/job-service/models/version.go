@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+
+	"common/customfields"
+	"common/salary"
+
+	"github.com/lib/pq"
+)
+
+// JobVersion 职位内容的不可变快照，每次内容变更生成一个新版本
+type JobVersion struct {
+	ID            uint                `gorm:"primarykey" json:"id"`
+	CreatedAt     time.Time           `json:"created_at"`
+	JobID         uint                `gorm:"not null;uniqueIndex:idx_job_version" json:"job_id"`
+	Version       int                 `gorm:"not null;uniqueIndex:idx_job_version" json:"version"`
+	Title         string              `gorm:"size:200;not null" json:"title"`
+	Description   string              `gorm:"type:text" json:"description"`
+	Requirements  pq.StringArray      `gorm:"type:text[]" json:"requirements"`
+	Salary        string              `gorm:"size:100" json:"salary"`
+	Location      string              `gorm:"size:100" json:"location"`
+	Type          string              `gorm:"size:20" json:"type"`
+	Department    string              `gorm:"size:100" json:"department"`
+	Level         string              `gorm:"size:50" json:"level"`
+	MinExperience int                 `json:"min_experience"`
+	Education     string              `gorm:"size:20" json:"education"`
+	Skills        pq.StringArray      `gorm:"type:text[]" json:"skills"`
+	Benefits      pq.StringArray      `gorm:"type:text[]" json:"benefits"`
+	Headcount     int                 `json:"headcount"`
+	CustomFields  customfields.Values `gorm:"type:jsonb;default:'{}'" json:"custom_fields"`
+	SalaryRange   salary.Range        `gorm:"embedded;embeddedPrefix:salary_" json:"salary_range"`
+	ChangedBy     uint                `json:"changed_by"`
+	ChangeNote    string              `gorm:"size:500" json:"change_note"`
+}
+
+// Snapshot 生成职位当前内容的版本快照（不含状态、录用人数等流程字段）
+func (j Job) Snapshot() JobVersion {
+	return JobVersion{
+		JobID:         j.ID,
+		Version:       j.Version,
+		Title:         j.Title,
+		Description:   j.Description,
+		Requirements:  j.Requirements,
+		Salary:        j.Salary,
+		Location:      j.Location,
+		Type:          j.Type,
+		Department:    j.Department,
+		Level:         j.Level,
+		MinExperience: j.MinExperience,
+		Education:     j.Education,
+		Skills:        j.Skills,
+		Benefits:      j.Benefits,
+		Headcount:     j.Headcount,
+		CustomFields:  j.CustomFields,
+		SalaryRange:   j.SalaryRange,
+	}
+}
+
+// Restore 将版本内容写回职位
+func (v JobVersion) Restore(j *Job) {
+	j.Title = v.Title
+	j.Description = v.Description
+	j.Requirements = v.Requirements
+	j.Salary = v.Salary
+	j.Location = v.Location
+	j.Type = v.Type
+	j.Department = v.Department
+	j.Level = v.Level
+	j.MinExperience = v.MinExperience
+	j.Education = v.Education
+	j.Skills = v.Skills
+	j.Benefits = v.Benefits
+	j.Headcount = v.Headcount
+	j.CustomFields = v.CustomFields
+	j.SalaryRange = v.SalaryRange
+}
@@ -0,0 +1,222 @@
+package scheduler
+
+import (
+	"fmt"
+	"job-service/models"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"common/notify"
+	"common/pipeline"
+
+	"gorm.io/gorm"
+)
+
+// Scheduler 职位定时任务：到点发布、到期关闭、到期前提醒创建人
+type Scheduler struct {
+	DB       *gorm.DB
+	Notifier *notify.Client
+
+	Interval   time.Duration // 扫描间隔
+	WarnBefore time.Duration // 到期前多久提醒创建人
+
+	// 职位到期关闭时，仍处于职位流程初始阶段的申请会流转到 ExpiredApplicationStatus 并通知候选人
+	ExpiredApplicationStatus string
+}
+
+// NewFromEnv 按环境变量创建调度器
+//
+//	JOB_SCHEDULER_INTERVAL          扫描间隔，默认 1m
+//	JOB_EXPIRY_WARNING_DAYS         到期前几天提醒，默认 3
+//	JOB_EXPIRED_APPLICATION_STATUS  到期后待处理申请的状态，rejected 或 withdrawn，默认 rejected
+func NewFromEnv(db *gorm.DB, notifier *notify.Client) *Scheduler {
+	interval, err := time.ParseDuration(os.Getenv("JOB_SCHEDULER_INTERVAL"))
+	if err != nil || interval <= 0 {
+		interval = time.Minute
+	}
+	warnDays, err := strconv.Atoi(os.Getenv("JOB_EXPIRY_WARNING_DAYS"))
+	if err != nil || warnDays < 0 {
+		warnDays = 3
+	}
+	// 各职位可自定义流程，只有系统结束阶段在所有流程中都存在；录用会计入名额，不能作为到期状态
+	status := pipeline.Normalize(os.Getenv("JOB_EXPIRED_APPLICATION_STATUS"))
+	switch status {
+	case "":
+		status = pipeline.StageRejected
+	case pipeline.StageRejected, pipeline.StageWithdrawn:
+	default:
+		log.Printf("Job scheduler: invalid JOB_EXPIRED_APPLICATION_STATUS %q, falling back to %s", status, pipeline.StageRejected)
+		status = pipeline.StageRejected
+	}
+
+	return &Scheduler{
+		DB:                       db,
+		Notifier:                 notifier,
+		Interval:                 interval,
+		WarnBefore:               time.Duration(warnDays) * 24 * time.Hour,
+		ExpiredApplicationStatus: status,
+	}
+}
+
+// Start 后台定期执行
+func (s *Scheduler) Start() {
+	go func() {
+		s.RunOnce(time.Now())
+		ticker := time.NewTicker(s.Interval)
+		defer ticker.Stop()
+		for now := range ticker.C {
+			s.RunOnce(now)
+		}
+	}()
+}
+
+// RunOnce 执行一轮扫描；各步骤按条件更新，多实例同时运行也不会重复处理
+func (s *Scheduler) RunOnce(now time.Time) {
+	if err := s.publishDue(now); err != nil {
+		log.Printf("Job scheduler: failed to publish jobs: %v", err)
+	}
+	if err := s.closeExpired(now); err != nil {
+		log.Printf("Job scheduler: failed to close expired jobs: %v", err)
+	}
+	if s.WarnBefore > 0 {
+		if err := s.warnExpiring(now); err != nil {
+			log.Printf("Job scheduler: failed to send expiry warnings: %v", err)
+		}
+	}
+}
+
+// publishDue 发布到达发布时间的职位
+func (s *Scheduler) publishDue(now time.Time) error {
+	var jobs []models.Job
+	if err := s.DB.Where("status = ? AND (publish_at IS NULL OR publish_at <= ?)", models.JobStatusScheduled, now).
+		Find(&jobs).Error; err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		res := s.DB.Model(&models.Job{}).
+			Where("id = ? AND status = ?", job.ID, models.JobStatusScheduled).
+			Update("status", models.JobStatusOpen)
+		if res.Error != nil {
+			log.Printf("Job scheduler: failed to publish job %d: %v", job.ID, res.Error)
+			continue
+		}
+		if res.RowsAffected == 0 {
+			continue
+		}
+		s.send(job.CreatedBy, notify.TypeSystem, "职位已发布",
+			fmt.Sprintf("职位「%s」已按计划发布", job.Title))
+	}
+	return nil
+}
+
+// closeExpired 关闭已过截止时间的职位，并处理仍在等待的申请
+func (s *Scheduler) closeExpired(now time.Time) error {
+	var jobs []models.Job
+	active := []string{models.JobStatusScheduled, models.JobStatusOpen, models.JobStatusOnHold}
+	if err := s.DB.Where("status IN ? AND expires_at IS NOT NULL AND expires_at <= ?", active, now).
+		Find(&jobs).Error; err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		var candidates []uint
+		closed := false
+		err := s.DB.Transaction(func(tx *gorm.DB) error {
+			res := tx.Model(&models.Job{}).
+				Where("id = ? AND status IN ?", job.ID, active).
+				Update("status", models.JobStatusClosed)
+			if res.Error != nil || res.RowsAffected == 0 {
+				return res.Error
+			}
+			closed = true
+
+			var err error
+			candidates, err = s.CloseApplications(tx, job.ID, "职位已到期关闭")
+			return err
+		})
+		if err != nil {
+			log.Printf("Job scheduler: failed to close job %d: %v", job.ID, err)
+			continue
+		}
+		if !closed {
+			continue
+		}
+
+		s.send(job.CreatedBy, notify.TypeSystem, "职位已到期关闭",
+			fmt.Sprintf("职位「%s」已于 %s 到期，系统已自动关闭", job.Title, job.ExpiresAt.Format("2006-01-02 15:04")))
+		s.NotifyApplicants(job, candidates)
+	}
+	return nil
+}
+
+// NotifyApplicants 通知申请被关闭的候选人职位已停止招聘
+func (s *Scheduler) NotifyApplicants(job models.Job, users []uint) {
+	for _, userID := range users {
+		s.send(userID, notify.TypeSystem, "职位已停止招聘",
+			fmt.Sprintf("您申请的职位「%s」已截止招聘，感谢您的关注", job.Title))
+	}
+}
+
+// CloseApplications 职位关闭（到期或人工关闭）时按职位流程将待处理的申请流转到配置的阶段并记录流转历史，
+// 返回需要通知的候选人用户 ID
+func (s *Scheduler) CloseApplications(tx *gorm.DB, jobID uint, reason string) ([]uint, error) {
+	if s.ExpiredApplicationStatus == "" {
+		return nil, nil
+	}
+
+	ids, err := pipeline.CloseEntryApplications(tx, jobID, pipeline.CloseMove{
+		To:            s.ExpiredApplicationStatus,
+		RejectionCode: pipeline.RejectionPositionClosed,
+		Reason:        reason,
+		ActorName:     "系统",
+	})
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	// 候选人通过人才档案关联到登录用户，未注册的候选人无法接收站内消息
+	var users []uint
+	err = tx.Table("applications").
+		Joins("JOIN talents ON talents.id = applications.talent_id").
+		Where("applications.id IN ? AND talents.user_id IS NOT NULL", ids).
+		Distinct().Pluck("talents.user_id", &users).Error
+	return users, err
+}
+
+// warnExpiring 在职位到期前提醒创建人，每个截止时间只提醒一次
+func (s *Scheduler) warnExpiring(now time.Time) error {
+	var jobs []models.Job
+	if err := s.DB.Where("status IN ? AND expires_at > ? AND expires_at <= ? AND expiry_warned_at IS NULL",
+		[]string{models.JobStatusOpen, models.JobStatusOnHold}, now, now.Add(s.WarnBefore)).
+		Find(&jobs).Error; err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		res := s.DB.Model(&models.Job{}).
+			Where("id = ? AND expiry_warned_at IS NULL", job.ID).
+			Update("expiry_warned_at", now)
+		if res.Error != nil || res.RowsAffected == 0 {
+			continue
+		}
+		s.send(job.CreatedBy, notify.TypeReminder, "职位即将到期",
+			fmt.Sprintf("职位「%s」将于 %s 到期自动关闭，如需继续招聘请及时延长截止时间",
+				job.Title, job.ExpiresAt.Format("2006-01-02 15:04")))
+	}
+	return nil
+}
+
+func (s *Scheduler) send(receiverID uint, msgType, title, content string) {
+	if receiverID == 0 {
+		return
+	}
+	s.Notifier.SendAsync(notify.Message{
+		ReceiverID: receiverID,
+		Title:      title,
+		Content:    content,
+		Type:       msgType,
+	})
+}
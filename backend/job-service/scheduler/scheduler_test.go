@@ -0,0 +1,27 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFromEnvExpiredApplicationStatus(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"未配置时默认淘汰", "", "rejected"},
+		{"允许配置为撤回", "withdrawn", "withdrawn"},
+		{"录用会计入名额，回退为淘汰", "hired", "rejected"},
+		{"未知状态回退为淘汰", "rejectd", "rejected"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("JOB_EXPIRED_APPLICATION_STATUS", tt.value)
+			assert.Equal(t, tt.want, NewFromEnv(nil, nil).ExpiredApplicationStatus)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"message-service/models"
+	"message-service/websocket"
 	"net/http"
 	"strconv"
 
@@ -10,11 +11,12 @@ import (
 )
 
 type MessageHandler struct {
-	DB *gorm.DB
+	DB  *gorm.DB
+	Hub *websocket.Hub // 接收人在线时实时推送新消息
 }
 
-func NewMessageHandler(db *gorm.DB) *MessageHandler {
-	return &MessageHandler{DB: db}
+func NewMessageHandler(db *gorm.DB, hub *websocket.Hub) *MessageHandler {
+	return &MessageHandler{DB: db, Hub: hub}
 }
 
 // SendMessage 发送消息
@@ -29,6 +31,9 @@ func (h *MessageHandler) SendMessage(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send message"})
 		return
 	}
+	if h.Hub != nil && h.Hub.IsUserOnline(message.ReceiverID) {
+		h.Hub.SendToUser(message.ReceiverID, "new_message", message)
+	}
 
 	c.JSON(http.StatusCreated, gin.H{
 		"code":    0,
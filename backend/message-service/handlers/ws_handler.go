@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"message-service/websocket"
+	"net/http"
+
+	"common/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WSHandler WebSocket 连接与推送接口
+type WSHandler struct {
+	Hub *websocket.Hub
+}
+
+func NewWSHandler(hub *websocket.Hub) *WSHandler {
+	return &WSHandler{Hub: hub}
+}
+
+// Connect 建立 WebSocket 连接，浏览器无法设置请求头，token 通过查询参数传递
+func (h *WSHandler) Connect(c *gin.Context) {
+	claims, err := middleware.ParseToken(c.Query("token"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+		return
+	}
+	websocket.ServeWs(h.Hub, c.Writer, c.Request, claims.UserID)
+}
+
+// PushRequest 推送请求，UserID 为 0 时广播给所有在线用户
+type PushRequest struct {
+	UserID uint        `json:"user_id"`
+	Type   string      `json:"type" binding:"required"`
+	Data   interface{} `json:"data"`
+}
+
+// Push 供内部服务向在线用户推送实时事件，不保存为站内消息
+func (h *WSHandler) Push(c *gin.Context) {
+	var req PushRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	online := true
+	if req.UserID == 0 {
+		h.Hub.Broadcast(req.Type, req.Data)
+	} else if online = h.Hub.IsUserOnline(req.UserID); online {
+		h.Hub.SendToUser(req.UserID, req.Type, req.Data)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data":    gin.H{"online": online},
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"log"
 	"message-service/handlers"
 	"message-service/models"
+	"message-service/websocket"
 
 	"common/middleware"
 
@@ -28,7 +29,11 @@ func main() {
 	r.Use(middleware.CORS())
 	r.Use(middleware.SimpleOperationLog("message-service"))
 
-	messageHandler := handlers.NewMessageHandler(db)
+	hub := websocket.NewHub()
+	go hub.Run()
+
+	messageHandler := handlers.NewMessageHandler(db, hub)
+	wsHandler := handlers.NewWSHandler(hub)
 
 	// 健康检查
 	r.GET("/health", func(c *gin.Context) {
@@ -44,6 +49,13 @@ func main() {
 		api.DELETE("/:id", messageHandler.DeleteMessage)
 	}
 
+	// 实时推送：浏览器连接 /ws，内部服务通过 /ws/push 推送事件（网关只转发 /ws）
+	ws := r.Group("/api/v1/ws")
+	{
+		ws.GET("", wsHandler.Connect)
+		ws.POST("/push", wsHandler.Push)
+	}
+
 	log.Println("Message service is running on :8085")
 	if err := r.Run(":8085"); err != nil {
 		log.Fatal("Failed to start server:", err)
@@ -4,8 +4,13 @@ import (
 	"math"
 	"net/http"
 	"sort"
+	"strconv"
 	"strings"
 
+	"common/jdextract"
+	"common/salary"
+	"common/skills"
+
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
@@ -19,26 +24,30 @@ func NewRecommendationHandler(db *gorm.DB) *RecommendationHandler {
 }
 
 type TalentProfile struct {
-	ID              uint     `json:"id"`
-	Name            string   `json:"name"`
-	Skills          []string `json:"skills"`
-	Experience      int      `json:"experience"`
-	Education       string   `json:"education"`
-	Location        string   `json:"location"`
-	Salary          string   `json:"salary"`
-	CurrentCompany  string   `json:"current_company"`
-	CurrentPosition string   `json:"current_position"`
+	ID              uint         `json:"id"`
+	Name            string       `json:"name"`
+	Skills          []string     `json:"skills"`
+	Experience      int          `json:"experience"`
+	Education       string       `json:"education"`
+	Location        string       `json:"location"`
+	Salary          string       `json:"salary"`
+	SalaryRange     salary.Range `json:"salary_range"`
+	CurrentCompany  string       `json:"current_company"`
+	CurrentPosition string       `json:"current_position"`
 }
 
 type JobProfile struct {
-	ID           uint     `json:"id"`
-	Title        string   `json:"title"`
-	Skills       []string `json:"skills"`
-	Location     string   `json:"location"`
-	Requirements []string `json:"requirements"`
-	Level        string   `json:"level"`
-	Salary       string   `json:"salary"`
-	Department   string   `json:"department"`
+	ID            uint         `json:"id"`
+	Title         string       `json:"title"`
+	Skills        []string     `json:"skills"`
+	Location      string       `json:"location"`
+	Requirements  []string     `json:"requirements"`
+	Level         string       `json:"level"`
+	MinExperience int          `json:"min_experience"` // 职位要求的最低工作年限
+	Education     string       `json:"education"`      // 职位要求的最低学历
+	Salary        string       `json:"salary"`
+	SalaryRange   salary.Range `json:"salary_range"`
+	Department    string       `json:"department"`
 }
 
 type Recommendation struct {
@@ -50,7 +59,7 @@ type Recommendation struct {
 	MatchDetails []string `json:"match_details"`
 }
 
-// SkillWeight 技能权重配置
+// SkillWeight 技能权重配置（键为规范技能名称的小写形式）
 var skillWeights = map[string]float64{
 	"go":         1.2,
 	"python":     1.1,
@@ -89,6 +98,9 @@ func calculateAdvancedMatchScore(talent TalentProfile, job JobProfile) (float64,
 
 	// 2. 经验匹配 (20%)
 	expScore, expDetail := calculateExperienceMatch(talent.Experience, job.Level)
+	if job.MinExperience > 0 {
+		expScore, expDetail = calculateMinExperienceMatch(talent.Experience, job.MinExperience)
+	}
 	totalScore += expScore * 0.2
 	details = append(details, expDetail)
 
@@ -99,11 +111,14 @@ func calculateAdvancedMatchScore(talent TalentProfile, job JobProfile) (float64,
 
 	// 4. 学历匹配 (10%)
 	eduScore, eduDetail := calculateEducationMatch(talent.Education, job.Level)
+	if job.Education != "" {
+		eduScore, eduDetail = calculateRequiredEducationMatch(talent.Education, job.Education)
+	}
 	totalScore += eduScore * 0.1
 	details = append(details, eduDetail)
 
 	// 5. 薪资匹配 (5%)
-	salaryScore, salaryDetail := calculateSalaryMatch(talent.Salary, job.Salary)
+	salaryScore, salaryDetail := calculateSalaryMatch(talent, job)
 	totalScore += salaryScore * 0.05
 	if salaryDetail != "" {
 		details = append(details, salaryDetail)
@@ -113,26 +128,29 @@ func calculateAdvancedMatchScore(talent TalentProfile, job JobProfile) (float64,
 }
 
 // calculateSkillMatch 计算技能匹配度
+// 技能先经共享技能字典归一（如 Golang -> Go），下级技能可满足上级要求（如 Spring Boot -> Spring）
 func calculateSkillMatch(talentSkills, jobSkills []string) (float64, []string) {
 	if len(jobSkills) == 0 {
 		return 0.5, []string{"职位未指定技能要求"}
 	}
 
+	dict := skills.Default()
+	normalizedTalent := dict.Normalize(talentSkills)
+	normalizedJob := dict.Normalize(jobSkills)
+
 	matchedSkills := []string{}
 	totalWeight := 0.0
 	matchedWeight := 0.0
 
-	for _, js := range jobSkills {
-		jsLower := strings.ToLower(strings.TrimSpace(js))
-		weight := skillWeights[jsLower]
+	for _, js := range normalizedJob {
+		weight := skillWeights[strings.ToLower(js)]
 		if weight == 0 {
 			weight = 1.0
 		}
 		totalWeight += weight
 
-		for _, ts := range talentSkills {
-			tsLower := strings.ToLower(strings.TrimSpace(ts))
-			if jsLower == tsLower || strings.Contains(tsLower, jsLower) || strings.Contains(jsLower, tsLower) {
+		for _, ts := range normalizedTalent {
+			if skillSatisfies(dict, ts, js) {
 				matchedWeight += weight
 				matchedSkills = append(matchedSkills, js)
 				break
@@ -145,14 +163,29 @@ func calculateSkillMatch(talentSkills, jobSkills []string) (float64, []string) {
 	if len(matchedSkills) > 0 {
 		details = append(details, "匹配技能: "+strings.Join(matchedSkills, ", "))
 	}
-	if len(matchedSkills) < len(jobSkills) {
-		missingCount := len(jobSkills) - len(matchedSkills)
-		details = append(details, "缺少 "+string(rune('0'+missingCount))+" 项技能")
+	if len(matchedSkills) < len(normalizedJob) {
+		missingCount := len(normalizedJob) - len(matchedSkills)
+		details = append(details, "缺少 "+strconv.Itoa(missingCount)+" 项技能")
 	}
 
 	return score, details
 }
 
+// skillSatisfies 判断人才技能是否满足职位技能；两者均未收录时退回模糊包含匹配
+func skillSatisfies(dict *skills.Dictionary, talentSkill, jobSkill string) bool {
+	if dict.Matches(talentSkill, jobSkill) {
+		return true
+	}
+	_, talentKnown := dict.Canonical(talentSkill)
+	_, jobKnown := dict.Canonical(jobSkill)
+	if talentKnown || jobKnown {
+		return false
+	}
+	ts := strings.ToLower(talentSkill)
+	js := strings.ToLower(jobSkill)
+	return strings.Contains(ts, js) || strings.Contains(js, ts)
+}
+
 // calculateExperienceMatch 计算经验匹配度
 func calculateExperienceMatch(experience int, level string) (float64, string) {
 	levelRequirements := map[string]struct{ min, ideal, max int }{
@@ -190,6 +223,18 @@ func calculateExperienceMatch(experience int, level string) (float64, string) {
 	return score, detail
 }
 
+// calculateMinExperienceMatch 按职位明确要求的最低年限计算经验匹配度
+func calculateMinExperienceMatch(experience, minExperience int) (float64, string) {
+	switch {
+	case experience >= minExperience*2+3:
+		return 0.8, "经验远超要求，可能期望更高"
+	case experience >= minExperience:
+		return 1.0, "满足 " + strconv.Itoa(minExperience) + " 年以上经验要求"
+	default:
+		return float64(experience) / float64(minExperience) * 0.6, "经验低于 " + strconv.Itoa(minExperience) + " 年要求"
+	}
+}
+
 // calculateLocationMatch 计算地理位置匹配度
 func calculateLocationMatch(talentLoc, jobLoc string) (float64, string) {
 	if talentLoc == "" || jobLoc == "" {
@@ -257,13 +302,33 @@ func calculateEducationMatch(education, level string) (float64, string) {
 	return eduScore / required, "学历略低于要求"
 }
 
-// calculateSalaryMatch 计算薪资匹配度
-func calculateSalaryMatch(talentSalary, jobSalary string) (float64, string) {
-	if talentSalary == "" || jobSalary == "" {
-		return 0.5, ""
+// calculateRequiredEducationMatch 按职位明确要求的最低学历计算学历匹配度
+func calculateRequiredEducationMatch(education, required string) (float64, string) {
+	have, want := jdextract.EducationRank(education), jdextract.EducationRank(required)
+	switch {
+	case want == 0:
+		return 1.0, "学历符合要求"
+	case have == 0:
+		return 0.5, "学历信息不完整"
+	case have >= want:
+		return 1.0, "学历符合" + required + "要求"
+	default:
+		return float64(have) / float64(want), "学历低于" + required + "要求"
+	}
+}
+
+// calculateSalaryMatch 计算薪资匹配度：比较期望薪资与职位薪资的年化区间重叠程度，
+// 优先使用结构化薪资，缺失时解析薪资文本
+func calculateSalaryMatch(talent TalentProfile, job JobProfile) (float64, string) {
+	expected := talent.SalaryRange
+	if expected.IsZero() {
+		expected = salary.FromText(talent.Salary)
+	}
+	offered := job.SalaryRange
+	if offered.IsZero() {
+		offered = salary.FromText(job.Salary)
 	}
-	// 简化处理，实际应解析薪资范围
-	return 0.8, "薪资范围基本匹配"
+	return salary.Match(expected, offered)
 }
 
 // RecommendJobsForTalent 为人才推荐职位
@@ -278,13 +343,17 @@ func (h *RecommendationHandler) RecommendJobsForTalent(c *gin.Context) {
 	var jobs []JobProfile
 	if h.DB != nil {
 		var dbJobs []struct {
-			ID         uint   `json:"id"`
-			Title      string `json:"title"`
-			Skills     string `json:"skills"`
-			Location   string `json:"location"`
-			Level      string `json:"level"`
-			Salary     string `json:"salary"`
-			Department string `json:"department"`
+			ID            uint   `json:"id"`
+			Title         string `json:"title"`
+			Skills        string `json:"skills"`
+			Location      string `json:"location"`
+			Level         string `json:"level"`
+			MinExperience int    `json:"min_experience"`
+			Education     string `json:"education"`
+			Salary        string `json:"salary"`
+			Department    string `json:"department"`
+
+			SalaryRange salary.Range `gorm:"embedded;embeddedPrefix:salary_"`
 		}
 		h.DB.Table("jobs").Where("status = ?", "open").Limit(20).Find(&dbJobs)
 		for _, j := range dbJobs {
@@ -297,13 +366,16 @@ func (h *RecommendationHandler) RecommendJobsForTalent(c *gin.Context) {
 				}
 			}
 			jobs = append(jobs, JobProfile{
-				ID:         j.ID,
-				Title:      j.Title,
-				Skills:     skills,
-				Location:   j.Location,
-				Level:      j.Level,
-				Salary:     j.Salary,
-				Department: j.Department,
+				ID:            j.ID,
+				Title:         j.Title,
+				Skills:        skills,
+				Location:      j.Location,
+				Level:         j.Level,
+				MinExperience: j.MinExperience,
+				Education:     j.Education,
+				Salary:        j.Salary,
+				SalaryRange:   j.SalaryRange,
+				Department:    j.Department,
 			})
 		}
 	}
@@ -381,6 +453,8 @@ func (h *RecommendationHandler) RecommendTalentsForJob(c *gin.Context) {
 			Education  string `json:"education"`
 			Location   string `json:"location"`
 			Salary     string `json:"salary"`
+
+			SalaryRange salary.Range `gorm:"embedded;embeddedPrefix:salary_"`
 		}
 		h.DB.Table("talents").Where("status = ?", "active").Limit(20).Find(&dbTalents)
 		for _, t := range dbTalents {
@@ -393,13 +467,14 @@ func (h *RecommendationHandler) RecommendTalentsForJob(c *gin.Context) {
 				}
 			}
 			talents = append(talents, TalentProfile{
-				ID:         t.ID,
-				Name:       t.Name,
-				Skills:     skills,
-				Experience: t.Experience,
-				Education:  t.Education,
-				Location:   t.Location,
-				Salary:     t.Salary,
+				ID:          t.ID,
+				Name:        t.Name,
+				Skills:      skills,
+				Experience:  t.Experience,
+				Education:   t.Education,
+				Location:    t.Location,
+				Salary:      t.Salary,
+				SalaryRange: t.SalaryRange,
 			})
 		}
 	}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"bytes"
 	"encoding/json"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -313,3 +314,53 @@ func TestLocationMatch(t *testing.T) {
 		})
 	}
 }
+
+func TestSalaryMatch(t *testing.T) {
+	tests := []struct {
+		name         string
+		talentSalary string
+		jobSalary    string
+		minScore     float64
+		maxScore     float64
+	}{
+		{name: "职位薪资覆盖期望", talentSalary: "25-30K", jobSalary: "25-40K", minScore: 1.0, maxScore: 1.0},
+		{name: "年薪与月薪换算后比较", talentSalary: "40万", jobSalary: "25-35K·14薪", minScore: 1.0, maxScore: 1.0},
+		{name: "部分重叠", talentSalary: "30-40K", jobSalary: "25-35K", minScore: 0.6, maxScore: 0.9},
+		{name: "明显低于期望", talentSalary: "40-50K", jobSalary: "15-20K", minScore: 0, maxScore: 0.1},
+		{name: "缺少薪资信息", talentSalary: "", jobSalary: "20-30K", minScore: 0.5, maxScore: 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, _ := calculateSalaryMatch(TalentProfile{Salary: tt.talentSalary}, JobProfile{Salary: tt.jobSalary})
+			assert.GreaterOrEqual(t, score, tt.minScore)
+			assert.LessOrEqual(t, score, tt.maxScore)
+		})
+	}
+}
+
+func TestRequiredExperienceAndEducationMatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		experience int
+		minYears   int
+		education  string
+		required   string
+		minScore   float64
+		maxScore   float64
+	}{
+		{name: "满足年限和学历", experience: 4, minYears: 3, education: "本科", required: "本科", minScore: 1.0, maxScore: 1.0},
+		{name: "年限不足", experience: 1, minYears: 3, education: "硕士", required: "本科", minScore: 0.1, maxScore: 0.3},
+		{name: "学历低于要求", experience: 5, minYears: 3, education: "大专", required: "本科", minScore: 0.6, maxScore: 0.7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expScore, _ := calculateMinExperienceMatch(tt.experience, tt.minYears)
+			eduScore, _ := calculateRequiredEducationMatch(tt.education, tt.required)
+			score := math.Min(expScore, eduScore)
+			assert.GreaterOrEqual(t, score, tt.minScore)
+			assert.LessOrEqual(t, score, tt.maxScore)
+		})
+	}
+}
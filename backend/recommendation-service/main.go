@@ -4,6 +4,9 @@ import (
 	"log"
 	"os"
 	"recommendation-service/handlers"
+	"time"
+
+	"common/skills"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/driver/postgres"
@@ -20,6 +23,15 @@ func main() {
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
 	if err != nil {
 		log.Printf("Warning: Failed to connect to database: %v", err)
+		db = nil
+	}
+
+	// 加载共享技能字典（由 job-service 维护），并定期刷新
+	if db != nil {
+		if err := skills.Default().Load(db); err != nil {
+			log.Printf("Warning: Failed to load skill dictionary: %v", err)
+		}
+		skills.Default().Watch(db, 5*time.Minute)
 	}
 
 	r := gin.Default()
@@ -0,0 +1,203 @@
+package calibration
+
+import (
+	"strings"
+	"time"
+
+	"common/pipeline"
+
+	"gorm.io/gorm"
+)
+
+// Filter 报告范围，按评估时间筛选
+type Filter struct {
+	From       *time.Time
+	To         *time.Time
+	JobID      uint
+	Department string
+}
+
+// applicationRow 申请及其对应的 AI 评估
+type applicationRow struct {
+	ApplicationID uint
+	JobID         uint
+	TalentID      uint
+	Status        string
+	HiredAt       *time.Time
+	JobTitle      string
+	Department    string
+	EvaluatedAt   time.Time
+	TotalScore    float64
+	Grade         string
+}
+
+// interviewRow 面试及其反馈，没有反馈时 Rating 取面试记录上的评分
+type interviewRow struct {
+	CandidateID    uint
+	PositionID     uint
+	Rating         int
+	Recommendation string
+}
+
+// inClauseSize 单次 IN 查询的最大参数数
+const inClauseSize = 1000
+
+// Load 读取申请的 AI 评估分数及招聘结果。每个申请取简历针对该职位（或未指定职位）的最近一次评估；
+// 招聘结果按阶段流转历史、录用时间和面试反馈确定
+func Load(db *gorm.DB, f Filter) ([]Sample, error) {
+	query := db.Table("applications AS a").
+		Select(`DISTINCT ON (a.id) a.id AS application_id, a.job_id, a.talent_id, a.status, a.hired_at,
+			COALESCE(j.title, '') AS job_title, COALESCE(j.department, '') AS department,
+			e.created_at AS evaluated_at, e.total_score, e.grade`).
+		Joins("JOIN evaluations e ON e.resume_id = a.resume_id AND (e.job_id = a.job_id OR e.job_id IS NULL)").
+		Joins("LEFT JOIN jobs j ON j.id = a.job_id").
+		Where("a.deleted_at IS NULL")
+	if f.From != nil {
+		query = query.Where("e.created_at >= ?", *f.From)
+	}
+	if f.To != nil {
+		query = query.Where("e.created_at < ?", *f.To)
+	}
+	if f.JobID != 0 {
+		query = query.Where("a.job_id = ?", f.JobID)
+	}
+	if f.Department != "" {
+		query = query.Where("j.department = ?", f.Department)
+	}
+
+	var apps []applicationRow
+	if err := query.Order("a.id, e.job_id IS NULL, e.created_at DESC, e.id DESC").Scan(&apps).Error; err != nil {
+		return nil, err
+	}
+	if len(apps) == 0 {
+		return []Sample{}, nil
+	}
+
+	appIDs := make([]uint, len(apps))
+	talentIDs := make([]uint, 0, len(apps))
+	seenTalent := map[uint]bool{}
+	for i, a := range apps {
+		appIDs[i] = a.ApplicationID
+		if !seenTalent[a.TalentID] {
+			seenTalent[a.TalentID] = true
+			talentIDs = append(talentIDs, a.TalentID)
+		}
+	}
+
+	reached := map[uint]map[string]bool{}
+	for _, ids := range chunks(appIDs) {
+		var transitions []struct {
+			ApplicationID uint
+			ToStage       string
+		}
+		if err := db.Table("application_stage_transitions").Select("application_id, to_stage").
+			Where("application_id IN ?", ids).Scan(&transitions).Error; err != nil {
+			return nil, err
+		}
+		for _, t := range transitions {
+			if reached[t.ApplicationID] == nil {
+				reached[t.ApplicationID] = map[string]bool{}
+			}
+			reached[t.ApplicationID][pipeline.Normalize(t.ToStage)] = true
+		}
+	}
+
+	type candidateJob struct{ talent, job uint }
+	interviews := map[candidateJob][]interviewRow{}
+	for _, ids := range chunks(talentIDs) {
+		var rows []interviewRow
+		if err := db.Table("interviews AS i").
+			Select("i.candidate_id, i.position_id, COALESCE(f.rating, i.rating, 0) AS rating, COALESCE(f.recommendation, '') AS recommendation").
+			Joins("LEFT JOIN interview_feedbacks f ON f.interview_id = i.id AND f.deleted_at IS NULL").
+			Where("i.deleted_at IS NULL AND i.status <> ? AND i.candidate_id IN ?", "cancelled", ids).
+			Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			k := candidateJob{r.CandidateID, r.PositionID}
+			interviews[k] = append(interviews[k], r)
+		}
+	}
+
+	samples := make([]Sample, len(apps))
+	for i, a := range apps {
+		samples[i] = sampleFor(a, reached[a.ApplicationID], interviews[candidateJob{a.TalentID, a.JobID}])
+	}
+	return samples, nil
+}
+
+// sampleFor 由申请的阶段历史和面试确定招聘结果。进入面试、Offer、录用以到达过对应阶段为准；
+// 面试通过以面试官的建议为准，没有建议时按是否进入 Offer 判断。申请已结束（录用、淘汰、撤回）时未达成的结果记为否，
+// 仍在流程中的记为未确定
+func sampleFor(a applicationRow, reached map[string]bool, interviews []interviewRow) Sample {
+	status := pipeline.Normalize(a.Status)
+	has := func(stage string) bool { return reached[stage] || status == stage }
+	closed := pipeline.IsClosed(status)
+
+	hired := has(pipeline.StageHired) || a.HiredAt != nil
+	offered := hired || has("offer")
+	interviewed := offered || has("interview") || len(interviews) > 0
+
+	s := Sample{
+		ApplicationID: a.ApplicationID,
+		JobID:         a.JobID,
+		JobTitle:      a.JobTitle,
+		Department:    a.Department,
+		EvaluatedAt:   a.EvaluatedAt,
+		Score:         a.TotalScore,
+		Grade:         a.Grade,
+		Interviewed:   decided(interviewed, closed),
+		Offered:       decided(offered, closed),
+		Hired:         decided(hired, closed),
+	}
+
+	pass, fail, ratings, ratingSum := 0, 0, 0, 0
+	for _, iv := range interviews {
+		switch strings.ToLower(strings.TrimSpace(iv.Recommendation)) {
+		case "pass":
+			pass++
+		case "fail":
+			fail++
+		}
+		if iv.Rating > 0 {
+			ratings++
+			ratingSum += iv.Rating
+		}
+	}
+	if ratings > 0 {
+		avg := float64(ratingSum) / float64(ratings)
+		s.Rating = &avg
+	}
+
+	switch {
+	case pass > fail:
+		s.InterviewPassed = boolPtr(true)
+	case fail > 0:
+		s.InterviewPassed = boolPtr(false)
+	case offered:
+		s.InterviewPassed = boolPtr(true)
+	case interviewed && closed:
+		s.InterviewPassed = boolPtr(false)
+	}
+	return s
+}
+
+// decided 结果已达成，或申请已结束时记为未达成；否则结果未确定
+func decided(achieved, closed bool) *bool {
+	if achieved || closed {
+		return boolPtr(achieved)
+	}
+	return nil
+}
+
+func boolPtr(b bool) *bool { return &b }
+
+// chunks 按 inClauseSize 切分 ID 列表
+func chunks(ids []uint) [][]uint {
+	var out [][]uint
+	for len(ids) > inClauseSize {
+		out = append(out, ids[:inClauseSize])
+		ids = ids[inClauseSize:]
+	}
+	return append(out, ids)
+}
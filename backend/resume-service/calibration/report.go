@@ -0,0 +1,454 @@
+// Package calibration AI 评分校准：将申请对应的 AI 评估分数与后续招聘结果（进入面试、面试通过、Offer、录用）对照，
+// 统计相关性、按评级的结果分布和分数分布随时间的漂移，用于判断 AI 筛选是否可信。
+package calibration
+
+import (
+	"fmt"
+	"math"
+	"resume-service/evaluator"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 招聘结果
+const (
+	OutcomeInterview     = "interview"      // 进入面试
+	OutcomeInterviewPass = "interview_pass" // 面试通过
+	OutcomeOffer         = "offer"          // 发放 Offer
+	OutcomeHired         = "hired"          // 录用
+)
+
+// Outcomes 按招聘流程顺序排列的结果
+var Outcomes = []string{OutcomeInterview, OutcomeInterviewPass, OutcomeOffer, OutcomeHired}
+
+// Grades 评级，从高到低
+var Grades = []string{"A", "B", "C", "D"}
+
+// recommendedGrades 视为 AI 推荐的评级，用于混淆矩阵
+var recommendedGrades = map[string]bool{"A": true, "B": true}
+
+// Sample 一个申请的 AI 评估分数及其招聘结果。结果为 nil 表示尚未确定（申请仍在流程中且未到达该阶段）
+type Sample struct {
+	ApplicationID uint
+	JobID         uint
+	JobTitle      string
+	Department    string
+	EvaluatedAt   time.Time
+	Score         float64
+	Grade         string
+
+	Interviewed     *bool
+	InterviewPassed *bool
+	Offered         *bool
+	Hired           *bool
+	Rating          *float64 // 面试官评分均值（1-5），没有面试反馈时为空
+}
+
+// outcome 取样本在指定结果上的取值
+func (s *Sample) outcome(name string) *bool {
+	switch name {
+	case OutcomeInterview:
+		return s.Interviewed
+	case OutcomeInterviewPass:
+		return s.InterviewPassed
+	case OutcomeOffer:
+		return s.Offered
+	case OutcomeHired:
+		return s.Hired
+	}
+	return nil
+}
+
+// grade 样本的评级，记录的评级不合法时按分数确定
+func (s *Sample) grade() string {
+	g := strings.ToUpper(strings.TrimSpace(s.Grade))
+	for _, valid := range Grades {
+		if g == valid {
+			return g
+		}
+	}
+	return evaluator.GradeFor(s.Score)
+}
+
+// Options 报告选项
+type Options struct {
+	Period  string // 分数分布的统计周期：month（默认）或 week
+	GroupBy string // 分组：job、department，为空时不分组
+}
+
+// Report 校准报告
+type Report struct {
+	Samples      int                  `json:"samples"`
+	Outcomes     []OutcomeRate        `json:"outcomes"`
+	Correlations []Correlation        `json:"correlations"`
+	Grades       []GradeOutcomes      `json:"grades"`
+	Confusion    []Confusion          `json:"confusion"`
+	Distribution []PeriodDistribution `json:"distribution"`
+	Groups       []GroupReport        `json:"groups,omitempty"`
+}
+
+// GroupReport 按职位或部门分组的报告
+type GroupReport struct {
+	Key  string `json:"key"`  // 职位 ID 或部门名称
+	Name string `json:"name"` // 职位名称或部门名称
+	Report
+}
+
+// OutcomeRate 结果的整体比例，只统计已确定结果的申请
+type OutcomeRate struct {
+	Outcome  string  `json:"outcome"`
+	Decided  int     `json:"decided"`
+	Positive int     `json:"positive"`
+	Rate     float64 `json:"rate"`
+}
+
+// Correlation AI 分数与结果的相关系数（结果为是否时即点二列相关），样本不足或无差异时 R 为空
+type Correlation struct {
+	Outcome string   `json:"outcome"` // 招聘结果，或 interview_rating（面试官评分）
+	N       int      `json:"n"`
+	R       *float64 `json:"r"`
+}
+
+// GradeOutcomes 某一评级的申请在各结果上的数量和比例
+type GradeOutcomes struct {
+	Grade     string        `json:"grade"`
+	Total     int           `json:"total"`
+	AvgScore  float64       `json:"avg_score"`
+	Outcomes  []OutcomeRate `json:"outcomes"`
+	AvgRating *float64      `json:"avg_rating"` // 面试官评分均值
+}
+
+// Confusion 以 A、B 评级作为 AI 推荐，与实际结果对照
+type Confusion struct {
+	Outcome       string   `json:"outcome"`
+	TruePositive  int      `json:"true_positive"`  // 推荐且达成
+	FalsePositive int      `json:"false_positive"` // 推荐但未达成
+	FalseNegative int      `json:"false_negative"` // 未推荐但达成
+	TrueNegative  int      `json:"true_negative"`  // 未推荐且未达成
+	Precision     *float64 `json:"precision"`      // 推荐的申请中达成的比例
+	Recall        *float64 `json:"recall"`         // 达成的申请中被推荐的比例
+}
+
+// PeriodDistribution 一个统计周期内的 AI 分数分布
+type PeriodDistribution struct {
+	Period    string         `json:"period"` // 如 2026-09 或 2026-W38
+	Count     int            `json:"count"`
+	Mean      float64        `json:"mean"`
+	StdDev    float64        `json:"std_dev"`
+	P25       float64        `json:"p25"`
+	Median    float64        `json:"median"`
+	P75       float64        `json:"p75"`
+	Grades    map[string]int `json:"grades"`
+	MeanShift float64        `json:"mean_shift"` // 与上一周期平均分之差
+	// PSI 评级分布相对第一个周期的稳定性指数：< 0.1 稳定，0.1-0.2 轻微漂移，>= 0.2 明显漂移
+	PSI   float64 `json:"psi"`
+	Drift string  `json:"drift"` // stable、moderate、significant
+}
+
+// Build 生成校准报告
+func Build(samples []Sample, opts Options) Report {
+	r := build(samples, opts.Period)
+	switch opts.GroupBy {
+	case "job":
+		r.Groups = groupReports(samples, opts.Period, func(s *Sample) (string, string) {
+			return strconv.FormatUint(uint64(s.JobID), 10), s.JobTitle
+		})
+	case "department":
+		r.Groups = groupReports(samples, opts.Period, func(s *Sample) (string, string) {
+			return s.Department, s.Department
+		})
+	}
+	return r
+}
+
+func build(samples []Sample, period string) Report {
+	r := Report{
+		Samples:      len(samples),
+		Outcomes:     make([]OutcomeRate, 0, len(Outcomes)),
+		Correlations: make([]Correlation, 0, len(Outcomes)+1),
+		Grades:       make([]GradeOutcomes, 0, len(Grades)),
+		Confusion:    make([]Confusion, 0, len(Outcomes)),
+	}
+	for _, o := range Outcomes {
+		r.Outcomes = append(r.Outcomes, outcomeRate(samples, o))
+		r.Correlations = append(r.Correlations, outcomeCorrelation(samples, o))
+		r.Confusion = append(r.Confusion, confusion(samples, o))
+	}
+	r.Correlations = append(r.Correlations, ratingCorrelation(samples))
+
+	byGrade := map[string][]Sample{}
+	for _, s := range samples {
+		byGrade[s.grade()] = append(byGrade[s.grade()], s)
+	}
+	for _, g := range Grades {
+		r.Grades = append(r.Grades, gradeOutcomes(g, byGrade[g]))
+	}
+
+	r.Distribution = distribution(samples, period)
+	return r
+}
+
+// groupReports 按 key 分组生成报告，分组按样本数从多到少排列
+func groupReports(samples []Sample, period string, key func(*Sample) (string, string)) []GroupReport {
+	groups := map[string][]Sample{}
+	names := map[string]string{}
+	for i := range samples {
+		k, name := key(&samples[i])
+		groups[k] = append(groups[k], samples[i])
+		if name != "" {
+			names[k] = name
+		}
+	}
+
+	out := make([]GroupReport, 0, len(groups))
+	for k, g := range groups {
+		out = append(out, GroupReport{Key: k, Name: names[k], Report: build(g, period)})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Samples != out[j].Samples {
+			return out[i].Samples > out[j].Samples
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
+
+func outcomeRate(samples []Sample, outcome string) OutcomeRate {
+	r := OutcomeRate{Outcome: outcome}
+	for i := range samples {
+		if v := samples[i].outcome(outcome); v != nil {
+			r.Decided++
+			if *v {
+				r.Positive++
+			}
+		}
+	}
+	if r.Decided > 0 {
+		r.Rate = round(float64(r.Positive) / float64(r.Decided))
+	}
+	return r
+}
+
+func outcomeCorrelation(samples []Sample, outcome string) Correlation {
+	var xs, ys []float64
+	for i := range samples {
+		if v := samples[i].outcome(outcome); v != nil {
+			xs = append(xs, samples[i].Score)
+			ys = append(ys, boolValue(*v))
+		}
+	}
+	return Correlation{Outcome: outcome, N: len(xs), R: pearson(xs, ys)}
+}
+
+func ratingCorrelation(samples []Sample) Correlation {
+	var xs, ys []float64
+	for _, s := range samples {
+		if s.Rating != nil {
+			xs = append(xs, s.Score)
+			ys = append(ys, *s.Rating)
+		}
+	}
+	return Correlation{Outcome: "interview_rating", N: len(xs), R: pearson(xs, ys)}
+}
+
+func gradeOutcomes(grade string, samples []Sample) GradeOutcomes {
+	g := GradeOutcomes{Grade: grade, Total: len(samples), Outcomes: make([]OutcomeRate, 0, len(Outcomes))}
+	var scoreSum, ratingSum float64
+	ratings := 0
+	for _, s := range samples {
+		scoreSum += s.Score
+		if s.Rating != nil {
+			ratingSum += *s.Rating
+			ratings++
+		}
+	}
+	if len(samples) > 0 {
+		g.AvgScore = round(scoreSum / float64(len(samples)))
+	}
+	if ratings > 0 {
+		avg := round(ratingSum / float64(ratings))
+		g.AvgRating = &avg
+	}
+	for _, o := range Outcomes {
+		g.Outcomes = append(g.Outcomes, outcomeRate(samples, o))
+	}
+	return g
+}
+
+func confusion(samples []Sample, outcome string) Confusion {
+	c := Confusion{Outcome: outcome}
+	for i := range samples {
+		v := samples[i].outcome(outcome)
+		if v == nil {
+			continue
+		}
+		recommended := recommendedGrades[samples[i].grade()]
+		switch {
+		case recommended && *v:
+			c.TruePositive++
+		case recommended:
+			c.FalsePositive++
+		case *v:
+			c.FalseNegative++
+		default:
+			c.TrueNegative++
+		}
+	}
+	c.Precision = ratio(c.TruePositive, c.TruePositive+c.FalsePositive)
+	c.Recall = ratio(c.TruePositive, c.TruePositive+c.FalseNegative)
+	return c
+}
+
+// distribution 按周期统计分数分布，并与上一周期、第一个周期比较
+func distribution(samples []Sample, period string) []PeriodDistribution {
+	byPeriod := map[string][]float64{}
+	grades := map[string]map[string]int{}
+	for i := range samples {
+		p := periodKey(samples[i].EvaluatedAt, period)
+		byPeriod[p] = append(byPeriod[p], samples[i].Score)
+		if grades[p] == nil {
+			grades[p] = map[string]int{}
+		}
+		grades[p][samples[i].grade()]++
+	}
+
+	periods := make([]string, 0, len(byPeriod))
+	for p := range byPeriod {
+		periods = append(periods, p)
+	}
+	sort.Strings(periods)
+
+	out := make([]PeriodDistribution, 0, len(periods))
+	for i, p := range periods {
+		scores := byPeriod[p]
+		sort.Float64s(scores)
+		mean, std := meanStdDev(scores)
+		d := PeriodDistribution{
+			Period: p,
+			Count:  len(scores),
+			Mean:   round(mean),
+			StdDev: round(std),
+			P25:    round(quantile(scores, 0.25)),
+			Median: round(quantile(scores, 0.5)),
+			P75:    round(quantile(scores, 0.75)),
+			Grades: grades[p],
+			Drift:  "stable",
+		}
+		if i > 0 {
+			d.MeanShift = round(d.Mean - out[i-1].Mean)
+			d.PSI = round(psi(grades[periods[0]], grades[p]))
+			d.Drift = driftLevel(d.PSI)
+		}
+		out = append(out, d)
+	}
+	return out
+}
+
+// periodKey 统计周期：month 为 2006-01，week 为 ISO 周如 2026-W38
+func periodKey(t time.Time, period string) string {
+	if period == "week" {
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	}
+	return t.Format("2006-01")
+}
+
+// psi 评级分布的稳定性指数，空的评级按极小比例计算避免除零
+func psi(baseline, current map[string]int) float64 {
+	const epsilon = 0.0001
+	baseTotal, curTotal := 0, 0
+	for _, g := range Grades {
+		baseTotal += baseline[g]
+		curTotal += current[g]
+	}
+	if baseTotal == 0 || curTotal == 0 {
+		return 0
+	}
+	var sum float64
+	for _, g := range Grades {
+		e := math.Max(float64(baseline[g])/float64(baseTotal), epsilon)
+		a := math.Max(float64(current[g])/float64(curTotal), epsilon)
+		sum += (a - e) * math.Log(a/e)
+	}
+	return sum
+}
+
+func driftLevel(psi float64) string {
+	switch {
+	case psi >= 0.2:
+		return "significant"
+	case psi >= 0.1:
+		return "moderate"
+	default:
+		return "stable"
+	}
+}
+
+// pearson 皮尔逊相关系数，样本少于 3 个或任一变量没有差异时返回 nil
+func pearson(xs, ys []float64) *float64 {
+	n := len(xs)
+	if n < 3 || n != len(ys) {
+		return nil
+	}
+	mx, sx := meanStdDev(xs)
+	my, sy := meanStdDev(ys)
+	if sx == 0 || sy == 0 {
+		return nil
+	}
+	var cov float64
+	for i := range xs {
+		cov += (xs[i] - mx) * (ys[i] - my)
+	}
+	r := round(cov / float64(n) / (sx * sy))
+	return &r
+}
+
+// meanStdDev 平均值和总体标准差
+func meanStdDev(xs []float64) (float64, float64) {
+	if len(xs) == 0 {
+		return 0, 0
+	}
+	var sum float64
+	for _, x := range xs {
+		sum += x
+	}
+	mean := sum / float64(len(xs))
+	var sq float64
+	for _, x := range xs {
+		sq += (x - mean) * (x - mean)
+	}
+	return mean, math.Sqrt(sq / float64(len(xs)))
+}
+
+// quantile 已排序数据的分位数，线性插值
+func quantile(sorted []float64, q float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	pos := q * float64(len(sorted)-1)
+	lo := int(math.Floor(pos))
+	hi := int(math.Ceil(pos))
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(pos-float64(lo))
+}
+
+func ratio(a, b int) *float64 {
+	if b == 0 {
+		return nil
+	}
+	r := round(float64(a) / float64(b))
+	return &r
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// round 保留三位小数
+func round(f float64) float64 {
+	return math.Round(f*1000) / 1000
+}
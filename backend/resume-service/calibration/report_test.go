@@ -0,0 +1,158 @@
+package calibration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func yes() *bool { return boolPtr(true) }
+func no() *bool  { return boolPtr(false) }
+
+func TestBuild(t *testing.T) {
+	sep := time.Date(2026, 9, 10, 0, 0, 0, 0, time.UTC)
+	oct := time.Date(2026, 10, 10, 0, 0, 0, 0, time.UTC)
+	samples := []Sample{
+		{JobID: 1, JobTitle: "Go 工程师", Department: "技术部", EvaluatedAt: sep, Score: 90, Grade: "A", Interviewed: yes(), InterviewPassed: yes(), Offered: yes(), Hired: yes()},
+		{JobID: 1, JobTitle: "Go 工程师", Department: "技术部", EvaluatedAt: sep, Score: 80, Grade: "B", Interviewed: yes(), InterviewPassed: no(), Offered: no(), Hired: no()},
+		{JobID: 1, JobTitle: "Go 工程师", Department: "技术部", EvaluatedAt: sep, Score: 60, Grade: "C", Interviewed: no(), Offered: no(), Hired: no()},
+		{JobID: 2, JobTitle: "销售", Department: "销售部", EvaluatedAt: oct, Score: 40, Grade: "d", Interviewed: yes(), InterviewPassed: yes(), Offered: yes(), Hired: nil},
+		{JobID: 2, JobTitle: "销售", Department: "销售部", EvaluatedAt: oct, Score: 88, Grade: "", Interviewed: nil},
+	}
+
+	r := Build(samples, Options{GroupBy: "department"})
+	assert.Equal(t, 5, r.Samples)
+
+	require.Len(t, r.Outcomes, 4)
+	assert.Equal(t, OutcomeRate{Outcome: OutcomeInterview, Decided: 4, Positive: 3, Rate: 0.75}, r.Outcomes[0])
+	assert.Equal(t, OutcomeRate{Outcome: OutcomeHired, Decided: 3, Positive: 1, Rate: 0.333}, r.Outcomes[3], "未确定的结果不计入")
+
+	require.Len(t, r.Grades, 4)
+	assert.Equal(t, 2, r.Grades[0].Total, "未记录评级时按分数确定")
+	assert.Equal(t, 1, r.Grades[3].Total, "评级不区分大小写")
+
+	hired := r.Confusion[3]
+	assert.Equal(t, Confusion{Outcome: OutcomeHired, TruePositive: 1, FalsePositive: 1, FalseNegative: 0, TrueNegative: 1,
+		Precision: hired.Precision, Recall: hired.Recall}, hired)
+	assert.Equal(t, 0.5, *hired.Precision)
+	assert.Equal(t, 1.0, *hired.Recall)
+
+	require.Len(t, r.Distribution, 2)
+	assert.Equal(t, "2026-09", r.Distribution[0].Period)
+	assert.Equal(t, 76.667, r.Distribution[0].Mean)
+	assert.Equal(t, "stable", r.Distribution[0].Drift)
+	assert.Equal(t, 64.0, r.Distribution[1].Mean)
+	assert.Equal(t, -12.667, r.Distribution[1].MeanShift)
+	assert.Equal(t, "significant", r.Distribution[1].Drift, "评级分布从 A/B/C 变为 A/D")
+
+	require.Len(t, r.Groups, 2)
+	assert.Equal(t, "技术部", r.Groups[0].Key, "样本多的分组在前")
+	assert.Equal(t, 3, r.Groups[0].Samples)
+	assert.Empty(t, r.Groups[0].Groups)
+}
+
+func TestBuildWeeklyAndByJob(t *testing.T) {
+	samples := []Sample{
+		{JobID: 7, JobTitle: "产品经理", EvaluatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Score: 70},
+	}
+	r := Build(samples, Options{Period: "week", GroupBy: "job"})
+	assert.Equal(t, "2026-W01", r.Distribution[0].Period)
+	require.Len(t, r.Groups, 1)
+	assert.Equal(t, "7", r.Groups[0].Key)
+	assert.Equal(t, "产品经理", r.Groups[0].Name)
+}
+
+func TestCorrelation(t *testing.T) {
+	tests := []struct {
+		name   string
+		scores []float64
+		hired  []bool
+		want   *float64
+	}{
+		{name: "高分录用", scores: []float64{90, 85, 50, 40}, hired: []bool{true, true, false, false}, want: ptr(0.983)},
+		{name: "低分录用", scores: []float64{90, 85, 50, 40}, hired: []bool{false, false, true, true}, want: ptr(-0.983)},
+		{name: "结果没有差异", scores: []float64{90, 85, 50}, hired: []bool{true, true, true}},
+		{name: "样本不足", scores: []float64{90, 40}, hired: []bool{true, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			samples := make([]Sample, len(tt.scores))
+			for i := range tt.scores {
+				samples[i] = Sample{Score: tt.scores[i], Hired: boolPtr(tt.hired[i])}
+			}
+			got := outcomeCorrelation(samples, OutcomeHired)
+			assert.Equal(t, len(tt.scores), got.N)
+			assert.Equal(t, tt.want, got.R)
+		})
+	}
+}
+
+func TestSampleFor(t *testing.T) {
+	hiredAt := time.Now()
+	tests := []struct {
+		name       string
+		app        applicationRow
+		reached    map[string]bool
+		interviews []interviewRow
+		want       [4]*bool // 进入面试、面试通过、Offer、录用
+		wantRating *float64
+	}{
+		{
+			name: "流程中未到面试",
+			app:  applicationRow{Status: "reviewed"},
+			want: [4]*bool{nil, nil, nil, nil},
+		},
+		{
+			name:    "录用",
+			app:     applicationRow{Status: "hired", HiredAt: &hiredAt},
+			reached: map[string]bool{"pending": true, "interview": true, "offer": true, "hired": true},
+			want:    [4]*bool{yes(), yes(), yes(), yes()},
+		},
+		{
+			name:       "面试未通过后淘汰",
+			app:        applicationRow{Status: "rejected"},
+			reached:    map[string]bool{"interview": true},
+			interviews: []interviewRow{{Rating: 2, Recommendation: "fail"}, {Rating: 3, Recommendation: "pending"}},
+			want:       [4]*bool{yes(), no(), no(), no()},
+			wantRating: ptr(2.5),
+		},
+		{
+			name:       "面试通过等待 Offer",
+			app:        applicationRow{Status: "interview"},
+			interviews: []interviewRow{{Rating: 5, Recommendation: "Pass"}},
+			want:       [4]*bool{yes(), yes(), nil, nil},
+			wantRating: ptr(5),
+		},
+		{
+			name: "旧状态名按阶段处理",
+			app:  applicationRow{Status: "offered"},
+			want: [4]*bool{yes(), yes(), yes(), nil},
+		},
+		{
+			name: "未面试即淘汰",
+			app:  applicationRow{Status: "rejected"},
+			want: [4]*bool{no(), nil, no(), no()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := sampleFor(tt.app, tt.reached, tt.interviews)
+			assert.Equal(t, tt.want, [4]*bool{s.Interviewed, s.InterviewPassed, s.Offered, s.Hired})
+			assert.Equal(t, tt.wantRating, s.Rating)
+		})
+	}
+}
+
+func TestPSI(t *testing.T) {
+	base := map[string]int{"A": 10, "B": 20, "C": 40, "D": 30}
+	assert.Equal(t, 0.0, psi(base, base))
+	assert.Less(t, psi(base, map[string]int{"A": 11, "B": 21, "C": 38, "D": 30}), 0.1)
+	assert.GreaterOrEqual(t, psi(base, map[string]int{"A": 40, "B": 40, "C": 10, "D": 10}), 0.2)
+	assert.Equal(t, 0.0, psi(base, map[string]int{}), "没有样本")
+}
+
+func ptr(f float64) *float64 { return &f }
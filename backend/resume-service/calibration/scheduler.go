@@ -0,0 +1,127 @@
+package calibration
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"resume-service/models"
+	"strings"
+	"time"
+
+	"common/notify"
+
+	"gorm.io/gorm"
+)
+
+// Scheduler 定期生成校准报告快照，并通知管理员查看
+type Scheduler struct {
+	DB       *gorm.DB
+	Notifier *notify.Client
+
+	Interval time.Duration // 生成间隔，为 0 时不生成
+	Window   time.Duration // 报告覆盖最近多长时间内的评估
+	Roles    []string      // 接收通知的用户角色
+}
+
+// NewFromEnv 按环境变量创建调度器
+//
+//	CALIBRATION_REPORT_INTERVAL  生成间隔，默认 168h（每周），设为 0 关闭
+//	CALIBRATION_REPORT_WINDOW    报告覆盖的评估时间范围，默认 2160h（90 天）
+//	CALIBRATION_REPORT_ROLES     接收通知的角色，逗号分隔，默认 admin,hr_manager
+func NewFromEnv(db *gorm.DB, notifier *notify.Client) *Scheduler {
+	interval := 7 * 24 * time.Hour
+	if v := os.Getenv("CALIBRATION_REPORT_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			interval = d
+		}
+	}
+	window, err := time.ParseDuration(os.Getenv("CALIBRATION_REPORT_WINDOW"))
+	if err != nil || window <= 0 {
+		window = 90 * 24 * time.Hour
+	}
+	roles := []string{"admin", "hr_manager"}
+	if v := os.Getenv("CALIBRATION_REPORT_ROLES"); v != "" {
+		roles = strings.Split(v, ",")
+	}
+
+	return &Scheduler{DB: db, Notifier: notifier, Interval: interval, Window: window, Roles: roles}
+}
+
+// Start 后台定期检查，距上次生成满 Interval 时生成新报告
+func (s *Scheduler) Start() {
+	if s.Interval <= 0 {
+		return
+	}
+	go func() {
+		s.RunOnce(time.Now())
+		ticker := time.NewTicker(min(s.Interval, time.Hour))
+		defer ticker.Stop()
+		for now := range ticker.C {
+			s.RunOnce(now)
+		}
+	}()
+}
+
+// RunOnce 距上次生成不足 Interval 时跳过，多个实例运行时以最先生成的为准
+func (s *Scheduler) RunOnce(now time.Time) {
+	var recent int64
+	if err := s.DB.Model(&models.CalibrationReport{}).Where("created_at > ?", now.Add(-s.Interval)).Count(&recent).Error; err != nil {
+		log.Printf("Calibration report: failed to check last report: %v", err)
+		return
+	}
+	if recent > 0 {
+		return
+	}
+
+	snapshot, err := Generate(s.DB, now.Add(-s.Window), now)
+	if err != nil {
+		log.Printf("Calibration report: failed to generate report: %v", err)
+		return
+	}
+	s.notify(snapshot)
+}
+
+// Generate 生成指定评估时间范围的报告（按部门分组）并保存快照
+func Generate(db *gorm.DB, from, to time.Time) (*models.CalibrationReport, error) {
+	samples, err := Load(db, Filter{From: &from, To: &to})
+	if err != nil {
+		return nil, err
+	}
+	report := Build(samples, Options{GroupBy: "department"})
+
+	b, err := json.Marshal(report)
+	if err != nil {
+		return nil, err
+	}
+	var content models.JSONObject
+	if err := json.Unmarshal(b, &content); err != nil {
+		return nil, err
+	}
+
+	snapshot := &models.CalibrationReport{PeriodStart: from, PeriodEnd: to, Samples: report.Samples, Report: content}
+	if err := db.Create(snapshot).Error; err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// notify 通知配置角色的用户报告已生成
+func (s *Scheduler) notify(snapshot *models.CalibrationReport) {
+	if s.Notifier == nil || len(s.Roles) == 0 {
+		return
+	}
+	var receivers []uint
+	if err := s.DB.Table("users").Where("role IN ? AND deleted_at IS NULL", s.Roles).Pluck("id", &receivers).Error; err != nil {
+		log.Printf("Calibration report: failed to load receivers: %v", err)
+		return
+	}
+
+	content := fmt.Sprintf("已生成 %s 至 %s 的 AI 评分校准报告（#%d），共 %d 份评估与招聘结果对照，请在 AI 评估分析中查看",
+		snapshot.PeriodStart.Format("2006-01-02"), snapshot.PeriodEnd.Format("2006-01-02"), snapshot.ID, snapshot.Samples)
+	msgs := make([]notify.Message, 0, len(receivers))
+	for _, id := range receivers {
+		msgs = append(msgs, notify.Message{ReceiverID: id, Title: "AI 评分校准报告", Content: content, Type: notify.TypeSystem})
+	}
+	s.Notifier.SendAsync(msgs...)
+}
@@ -0,0 +1,117 @@
+// migrate-storage 将历史简历文件从本地 uploads 目录迁移到当前配置的对象存储。
+//
+// 只处理尚未迁移（storage_key 为空）的简历，可重复执行：
+//
+//	go run ./cmd/migrate-storage -dry-run
+//	STORAGE_DRIVER=s3 STORAGE_S3_ENDPOINT=http://localhost:9000 ... go run ./cmd/migrate-storage -delete-source
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"resume-service/handlers"
+	"resume-service/models"
+
+	"common/storage"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "只列出待迁移的文件，不实际迁移")
+	deleteSource := flag.Bool("delete-source", false, "迁移成功后删除本地文件")
+	batchSize := flag.Int("batch", 100, "每批处理的简历数")
+	flag.Parse()
+
+	dsn := "host=" + getEnv("DB_HOST", "localhost") + " user=" + getEnv("DB_USER", "qinyang") +
+		" dbname=" + getEnv("DB_NAME", "talent_platform") + " port=" + getEnv("DB_PORT", "5432") +
+		" sslmode=disable TimeZone=Asia/Shanghai"
+	if password := os.Getenv("DB_PASSWORD"); password != "" {
+		dsn += " password=" + password
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		log.Fatal("Failed to connect database:", err)
+	}
+	if err := db.AutoMigrate(&models.Resume{}); err != nil {
+		log.Fatal("Failed to migrate database:", err)
+	}
+
+	store, err := storage.New(storage.ConfigFromEnv(handlers.DefaultUploadDir(), "/api/v1/resumes/file"))
+	if err != nil {
+		log.Fatal("Failed to init storage:", err)
+	}
+
+	ctx := context.Background()
+	var migrated, missing, failed int
+	lastID := uint(0)
+	for {
+		// 按 ID 分批，迁移失败的记录不会被重复查出
+		var resumes []models.Resume
+		if err := db.Unscoped().
+			Where("id > ? AND (storage_key = '' OR storage_key IS NULL) AND file_path != ''", lastID).
+			Order("id").Limit(*batchSize).Find(&resumes).Error; err != nil {
+			log.Fatal("Failed to query resumes:", err)
+		}
+		if len(resumes) == 0 {
+			break
+		}
+
+		for _, resume := range resumes {
+			lastID = resume.ID
+			if _, err := os.Stat(resume.FilePath); err != nil {
+				log.Printf("resume %d: source file missing: %s", resume.ID, resume.FilePath)
+				missing++
+				continue
+			}
+
+			// 历史文件名已带时间戳前缀，直接作为 key 保证重复执行时写入同一对象
+			key := "resumes/legacy/" + filepath.Base(resume.FilePath)
+			if *dryRun {
+				log.Printf("resume %d: %s -> %s", resume.ID, resume.FilePath, key)
+				migrated++
+				continue
+			}
+
+			if err := storage.UploadFile(ctx, store, key, resume.FilePath, storage.ContentType(resume.FilePath)); err != nil {
+				log.Printf("resume %d: upload failed: %v", resume.ID, err)
+				failed++
+				continue
+			}
+			if ok, err := store.Exists(ctx, key); err != nil || !ok {
+				log.Printf("resume %d: uploaded object not found: %v", resume.ID, err)
+				failed++
+				continue
+			}
+			if err := db.Unscoped().Model(&models.Resume{}).Where("id = ?", resume.ID).
+				Updates(map[string]interface{}{"storage_key": key, "file_url": ""}).Error; err != nil {
+				log.Printf("resume %d: failed to update record: %v", resume.ID, err)
+				failed++
+				continue
+			}
+			if *deleteSource {
+				if err := os.Remove(resume.FilePath); err != nil {
+					log.Printf("resume %d: failed to delete source: %v", resume.ID, err)
+				}
+			}
+			migrated++
+		}
+	}
+
+	log.Printf("Storage migration finished: migrated=%d missing=%d failed=%d dry_run=%v", migrated, missing, failed, *dryRun)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,112 @@
+// Package evalcache AI 评估结果缓存：相同简历内容、JD、评分标准和评估服务版本在有效期内复用已有的评估记录，
+// 不再调用评估服务。缓存数据就是 evaluations 表，多个实例共享；命中统计只记录本实例自启动以来的请求。
+package evalcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"resume-service/models"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DefaultTTL 未配置 EVAL_CACHE_TTL 时的缓存有效期
+const DefaultTTL = 24 * time.Hour
+
+// Cache 评估结果缓存
+type Cache struct {
+	DB  *gorm.DB
+	TTL time.Duration // 评估记录可复用的时长，为 0 时关闭缓存
+
+	hits     atomic.Int64
+	misses   atomic.Int64
+	bypassed atomic.Int64 // 强制重新评估跳过缓存的次数
+}
+
+// NewFromEnv 按环境变量创建缓存
+//
+//	EVAL_CACHE_TTL   缓存有效期，默认 24h，设为 0 关闭缓存
+func NewFromEnv(db *gorm.DB) *Cache {
+	ttl := DefaultTTL
+	if v := os.Getenv("EVAL_CACHE_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			ttl = d
+		}
+	}
+	return &Cache{DB: db, TTL: ttl}
+}
+
+// Key 缓存键：简历内容哈希、JD 文本哈希、评分标准和评估服务版本（服务名称与模型）共同决定。
+// 简历内容哈希为空时不缓存，返回空字符串
+func Key(resumeHash, jdHash, criteria, provider, model string) string {
+	if resumeHash == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(strings.Join([]string{resumeHash, jdHash, criteria, provider, model}, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// Enabled 是否启用缓存
+func (c *Cache) Enabled() bool {
+	return c != nil && c.TTL > 0
+}
+
+// Lookup 查找有效期内最近一次相同键的评估记录，未命中返回 nil。
+// force 为 true 时跳过缓存，只计入统计
+func (c *Cache) Lookup(key string, force bool) (*models.Evaluation, error) {
+	if !c.Enabled() || key == "" {
+		return nil, nil
+	}
+	if force {
+		c.bypassed.Add(1)
+		return nil, nil
+	}
+
+	var evaluation models.Evaluation
+	err := c.DB.Where("cache_key = ? AND created_at > ?", key, time.Now().Add(-c.TTL)).
+		Order("created_at DESC, id DESC").Take(&evaluation).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.misses.Add(1)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.hits.Add(1)
+	return &evaluation, nil
+}
+
+// Stats 缓存命中统计
+type Stats struct {
+	Enabled    bool    `json:"enabled"`
+	TTLSeconds int64   `json:"ttl_seconds"`
+	Hits       int64   `json:"hits"`
+	Misses     int64   `json:"misses"`
+	Bypassed   int64   `json:"bypassed"` // 强制重新评估的次数
+	HitRate    float64 `json:"hit_rate"` // 命中次数 / (命中 + 未命中)，强制重新评估不计入
+	Entries    int64   `json:"entries"`  // 有效期内可复用的评估记录数
+}
+
+// Stats 本实例的缓存命中统计
+func (c *Cache) Stats() Stats {
+	s := Stats{
+		Enabled:    c.Enabled(),
+		TTLSeconds: int64(c.TTL / time.Second),
+		Hits:       c.hits.Load(),
+		Misses:     c.misses.Load(),
+		Bypassed:   c.bypassed.Load(),
+	}
+	if total := s.Hits + s.Misses; total > 0 {
+		s.HitRate = float64(s.Hits) / float64(total)
+	}
+	if s.Enabled {
+		c.DB.Model(&models.Evaluation{}).
+			Where("cache_key <> '' AND created_at > ?", time.Now().Add(-c.TTL)).Count(&s.Entries)
+	}
+	return s
+}
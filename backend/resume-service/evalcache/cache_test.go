@@ -0,0 +1,70 @@
+package evalcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKey(t *testing.T) {
+	base := Key("resume", "jd", "", "coze", "wf-1")
+	assert.Len(t, base, 64)
+	assert.Equal(t, base, Key("resume", "jd", "", "coze", "wf-1"), "相同输入键相同")
+
+	tests := []struct {
+		name string
+		key  string
+	}{
+		{name: "简历内容不同", key: Key("resume2", "jd", "", "coze", "wf-1")},
+		{name: "JD 不同", key: Key("resume", "jd2", "", "coze", "wf-1")},
+		{name: "评分标准不同", key: Key("resume", "jd", "重视项目经验", "coze", "wf-1")},
+		{name: "评估服务不同", key: Key("resume", "jd", "", "openai", "wf-1")},
+		{name: "模型版本不同", key: Key("resume", "jd", "", "coze", "wf-2")},
+		{name: "字段边界不同", key: Key("resum", "ejd", "", "coze", "wf-1")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NotEqual(t, base, tt.key)
+		})
+	}
+
+	assert.Empty(t, Key("", "jd", "", "coze", "wf-1"), "没有简历内容哈希时不缓存")
+}
+
+func TestLookupWithoutQuery(t *testing.T) {
+	// 以下情况不查询数据库，DB 为空也不会出错
+	var disabled *Cache
+	got, err := disabled.Lookup("key", false)
+	require.NoError(t, err)
+	assert.Nil(t, got, "未创建缓存")
+
+	c := &Cache{TTL: 0}
+	got, err = c.Lookup("key", false)
+	require.NoError(t, err)
+	assert.Nil(t, got, "TTL 为 0 关闭缓存")
+
+	c = &Cache{TTL: time.Hour}
+	got, err = c.Lookup("", false)
+	require.NoError(t, err)
+	assert.Nil(t, got, "缓存键为空")
+
+	got, err = c.Lookup("key", true)
+	require.NoError(t, err)
+	assert.Nil(t, got, "强制重新评估")
+
+	assert.Equal(t, int64(1), c.bypassed.Load())
+	assert.Equal(t, int64(0), c.hits.Load()+c.misses.Load(), "跳过缓存不计入命中率")
+}
+
+func TestNewFromEnv(t *testing.T) {
+	t.Setenv("EVAL_CACHE_TTL", "")
+	assert.Equal(t, DefaultTTL, NewFromEnv(nil).TTL)
+
+	t.Setenv("EVAL_CACHE_TTL", "2h")
+	assert.Equal(t, 2*time.Hour, NewFromEnv(nil).TTL)
+
+	t.Setenv("EVAL_CACHE_TTL", "0")
+	assert.False(t, NewFromEnv(nil).Enabled(), "设为 0 关闭缓存")
+}
@@ -0,0 +1,376 @@
+// Package evalqueue 批量 AI 评估队列：任务和条目保存在 Postgres，由有限数量的工作协程逐条处理，
+// 失败按指数退避重试，支持取消；服务重启或实例崩溃后，超时未完成的条目会重新排队。
+package evalqueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"resume-service/models"
+	"strconv"
+	"sync"
+	"time"
+
+	"common/notify"
+
+	"gorm.io/gorm"
+)
+
+// 推送给提交人的事件类型
+const (
+	EventProgress = "evaluation_batch_progress"
+	EventFinished = "evaluation_batch_finished"
+)
+
+// EvaluateFunc 评估一份简历，返回评估记录 ID
+type EvaluateFunc func(ctx context.Context, batch *models.EvaluationBatch, item *models.EvaluationBatchItem) (uint, error)
+
+// permanentError 不可重试的错误（如简历不存在）
+type permanentError struct{ error }
+
+func (e permanentError) Unwrap() error { return e.error }
+
+// Permanent 标记错误不可重试，条目直接失败
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err}
+}
+
+// Queue 批量评估队列
+type Queue struct {
+	DB       *gorm.DB
+	Evaluate EvaluateFunc
+	Notifier *notify.Client
+
+	Workers      int           // 并发处理的条目数
+	MaxAttempts  int           // 每个条目最多尝试次数
+	Backoff      time.Duration // 首次重试等待时间，之后每次翻倍
+	MaxBackoff   time.Duration
+	ItemTimeout  time.Duration // 单个条目的评估超时
+	PollInterval time.Duration // 没有待处理条目时的轮询间隔
+
+	instance string
+	wake     chan struct{}
+
+	mu      sync.Mutex
+	running map[uint]runningItem // 本实例处理中的条目
+}
+
+type runningItem struct {
+	batchID uint
+	cancel  context.CancelFunc
+}
+
+// NewFromEnv 按环境变量创建队列
+//
+//	EVAL_QUEUE_WORKERS   并发数，默认 2
+//	EVAL_MAX_ATTEMPTS    每份简历最多尝试次数，默认 3
+//	EVAL_RETRY_BACKOFF   首次重试等待时间，默认 30s，之后每次翻倍，最长 30m
+//	EVAL_ITEM_TIMEOUT    单份简历评估超时，默认 5m
+func NewFromEnv(db *gorm.DB, evaluate EvaluateFunc, notifier *notify.Client) *Queue {
+	workers, err := strconv.Atoi(os.Getenv("EVAL_QUEUE_WORKERS"))
+	if err != nil || workers <= 0 {
+		workers = 2
+	}
+	attempts, err := strconv.Atoi(os.Getenv("EVAL_MAX_ATTEMPTS"))
+	if err != nil || attempts <= 0 {
+		attempts = 3
+	}
+	backoff, err := time.ParseDuration(os.Getenv("EVAL_RETRY_BACKOFF"))
+	if err != nil || backoff <= 0 {
+		backoff = 30 * time.Second
+	}
+	timeout, err := time.ParseDuration(os.Getenv("EVAL_ITEM_TIMEOUT"))
+	if err != nil || timeout <= 0 {
+		timeout = 5 * time.Minute
+	}
+
+	return &Queue{
+		DB:           db,
+		Evaluate:     evaluate,
+		Notifier:     notifier,
+		Workers:      workers,
+		MaxAttempts:  attempts,
+		Backoff:      backoff,
+		MaxBackoff:   30 * time.Minute,
+		ItemTimeout:  timeout,
+		PollInterval: 5 * time.Second,
+	}
+}
+
+// Start 启动工作协程
+func (q *Queue) Start() {
+	host, _ := os.Hostname()
+	q.instance = fmt.Sprintf("%s-%d-%d", host, os.Getpid(), time.Now().UnixNano())
+	q.wake = make(chan struct{}, q.Workers)
+	q.running = map[uint]runningItem{}
+
+	for i := 0; i < q.Workers; i++ {
+		go q.work()
+	}
+	log.Printf("Evaluation queue started with %d workers", q.Workers)
+}
+
+// Notify 有新条目入队时唤醒空闲的工作协程
+func (q *Queue) Notify() {
+	for i := 0; i < q.Workers; i++ {
+		select {
+		case q.wake <- struct{}{}:
+		default:
+			return
+		}
+	}
+}
+
+// Submit 创建批量评估任务，resumeIDs 应已去重
+func (q *Queue) Submit(batch *models.EvaluationBatch, resumeIDs []uint) error {
+	now := time.Now()
+	batch.Status = models.BatchQueued
+	batch.Total = len(resumeIDs)
+
+	err := q.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(batch).Error; err != nil {
+			return err
+		}
+		items := make([]models.EvaluationBatchItem, len(resumeIDs))
+		for i, id := range resumeIDs {
+			items[i] = models.EvaluationBatchItem{BatchID: batch.ID, ResumeID: id, Status: models.ItemQueued, NextAttemptAt: now}
+		}
+		return tx.CreateInBatches(&items, 500).Error
+	})
+	if err != nil {
+		return err
+	}
+	q.Notify()
+	return nil
+}
+
+// Cancel 取消任务：未开始的条目直接取消，本实例处理中的条目中断评估。
+// 任务已结束时返回 false
+func (q *Queue) Cancel(batchID uint) (bool, error) {
+	var cancelled bool
+	err := q.DB.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.EvaluationBatch{}).
+			Where("id = ? AND status IN ?", batchID, []string{models.BatchQueued, models.BatchRunning}).
+			Update("status", models.BatchCancelled)
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		cancelled = true
+		return tx.Model(&models.EvaluationBatchItem{}).
+			Where("batch_id = ? AND status = ?", batchID, models.ItemQueued).
+			Update("status", models.ItemCancelled).Error
+	})
+	if err != nil || !cancelled {
+		return false, err
+	}
+
+	q.mu.Lock()
+	for _, item := range q.running {
+		if item.batchID == batchID {
+			item.cancel()
+		}
+	}
+	q.mu.Unlock()
+
+	q.finish(batchID)
+	return true, nil
+}
+
+// work 工作协程：领取条目并处理，没有可处理的条目时等待唤醒或轮询
+func (q *Queue) work() {
+	ticker := time.NewTicker(q.PollInterval)
+	defer ticker.Stop()
+	for {
+		item, err := q.claim()
+		if err != nil {
+			log.Printf("Evaluation queue: failed to claim item: %v", err)
+		}
+		if item != nil {
+			q.process(item)
+			continue
+		}
+		select {
+		case <-q.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// claim 领取一个到期的排队条目。使用 SKIP LOCKED，多实例同时运行不会重复领取；
+// 处理中但超过租约时间未完成的条目（实例崩溃或重启）会被重新领取
+func (q *Queue) claim() (*models.EvaluationBatchItem, error) {
+	now := time.Now()
+	lease := now.Add(-(q.ItemTimeout + time.Minute))
+
+	var items []models.EvaluationBatchItem
+	err := q.DB.Raw(`
+		UPDATE evaluation_batch_items SET status = ?, locked_by = ?, locked_at = ?, attempts = attempts + 1, updated_at = ?
+		WHERE id = (
+			SELECT id FROM evaluation_batch_items
+			WHERE (status = ? AND next_attempt_at <= ?) OR (status = ? AND locked_at < ?)
+			ORDER BY next_attempt_at, id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		models.ItemRunning, q.instance, now, now,
+		models.ItemQueued, now, models.ItemRunning, lease,
+	).Scan(&items).Error
+	if err != nil || len(items) == 0 {
+		return nil, err
+	}
+	item := &items[0]
+
+	q.DB.Model(&models.EvaluationBatch{}).
+		Where("id = ? AND status = ?", item.BatchID, models.BatchQueued).
+		Updates(map[string]interface{}{"status": models.BatchRunning, "started_at": now})
+	return item, nil
+}
+
+// process 评估一个条目并记录结果
+func (q *Queue) process(item *models.EvaluationBatchItem) {
+	var batch models.EvaluationBatch
+	if err := q.DB.First(&batch, item.BatchID).Error; err != nil {
+		q.fail(item, Permanent(fmt.Errorf("批量任务不存在: %w", err)))
+		return
+	}
+	if batch.Status == models.BatchCancelled {
+		q.update(item, map[string]interface{}{"status": models.ItemCancelled})
+		q.finish(batch.ID)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), q.ItemTimeout)
+	q.mu.Lock()
+	q.running[item.ID] = runningItem{batchID: batch.ID, cancel: cancel}
+	q.mu.Unlock()
+
+	evaluationID, err := q.Evaluate(ctx, &batch, item)
+	cancelled := errors.Is(ctx.Err(), context.Canceled)
+
+	q.mu.Lock()
+	delete(q.running, item.ID)
+	q.mu.Unlock()
+	cancel()
+
+	switch {
+	case err == nil:
+		q.update(item, map[string]interface{}{"status": models.ItemSucceeded, "evaluation_id": evaluationID, "last_error": ""})
+	case cancelled:
+		q.update(item, map[string]interface{}{"status": models.ItemCancelled, "last_error": "已取消"})
+	default:
+		q.fail(item, err)
+	}
+	q.progress(batch.ID)
+}
+
+// fail 记录失败：可重试且未达上限时按退避时间重新排队，否则标记失败
+func (q *Queue) fail(item *models.EvaluationBatchItem, err error) {
+	var permanent permanentError
+	msg := err.Error()
+	if len(msg) > 1000 {
+		msg = msg[:1000]
+	}
+
+	if !errors.As(err, &permanent) && item.Attempts < q.MaxAttempts {
+		wait := q.backoff(item.Attempts)
+		log.Printf("Evaluation queue: item %d (resume %d) attempt %d failed, retry in %s: %v", item.ID, item.ResumeID, item.Attempts, wait, err)
+		q.update(item, map[string]interface{}{"status": models.ItemQueued, "last_error": msg, "next_attempt_at": time.Now().Add(wait)})
+		return
+	}
+	log.Printf("Evaluation queue: item %d (resume %d) failed: %v", item.ID, item.ResumeID, err)
+	q.update(item, map[string]interface{}{"status": models.ItemFailed, "last_error": msg})
+}
+
+// backoff 第 attempts 次失败后的等待时间
+func (q *Queue) backoff(attempts int) time.Duration {
+	wait := q.Backoff
+	for i := 1; i < attempts && wait < q.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, q.MaxBackoff)
+}
+
+// update 更新本实例持有的条目；条目已被其他实例重新领取时不覆盖
+func (q *Queue) update(item *models.EvaluationBatchItem, updates map[string]interface{}) {
+	updates["locked_by"] = ""
+	updates["locked_at"] = nil
+	err := q.DB.Model(&models.EvaluationBatchItem{}).
+		Where("id = ? AND locked_by = ?", item.ID, q.instance).
+		Updates(updates).Error
+	if err != nil {
+		log.Printf("Evaluation queue: failed to update item %d: %v", item.ID, err)
+	}
+}
+
+// progress 推送任务进度，所有条目结束后将任务标记为完成
+func (q *Queue) progress(batchID uint) {
+	if q.finish(batchID) {
+		return
+	}
+	var batch models.EvaluationBatch
+	if err := q.DB.First(&batch, batchID).Error; err != nil || batch.CreatedBy == nil {
+		return
+	}
+	q.Notifier.PushAsync(notify.Event{UserID: *batch.CreatedBy, Type: EventProgress, Data: q.Summary(&batch)})
+}
+
+// finish 任务没有待处理条目时结束任务并推送结果，返回是否结束
+func (q *Queue) finish(batchID uint) bool {
+	var pending int64
+	q.DB.Model(&models.EvaluationBatchItem{}).
+		Where("batch_id = ? AND status IN ?", batchID, []string{models.ItemQueued, models.ItemRunning}).
+		Count(&pending)
+	if pending > 0 {
+		return false
+	}
+
+	now := time.Now()
+	res := q.DB.Model(&models.EvaluationBatch{}).
+		Where("id = ? AND finished_at IS NULL", batchID).
+		Updates(map[string]interface{}{
+			"finished_at": now,
+			"status":      gorm.Expr("CASE WHEN status = ? THEN status ELSE ? END", models.BatchCancelled, models.BatchCompleted),
+		})
+	if res.Error != nil || res.RowsAffected == 0 {
+		return res.Error == nil
+	}
+
+	var batch models.EvaluationBatch
+	if err := q.DB.First(&batch, batchID).Error; err == nil && batch.CreatedBy != nil {
+		q.Notifier.PushAsync(notify.Event{UserID: *batch.CreatedBy, Type: EventFinished, Data: q.Summary(&batch)})
+	}
+	return true
+}
+
+// Summary 任务及各状态条目数
+type Summary struct {
+	*models.EvaluationBatch
+	Counts map[string]int64 `json:"counts"` // 各状态条目数
+	Done   int64            `json:"done"`   // 已结束的条目数
+}
+
+// Summary 统计任务各状态条目数
+func (q *Queue) Summary(batch *models.EvaluationBatch) Summary {
+	type row struct {
+		Status string
+		Count  int64
+	}
+	var rows []row
+	q.DB.Model(&models.EvaluationBatchItem{}).Select("status, COUNT(*) AS count").
+		Where("batch_id = ?", batch.ID).Group("status").Scan(&rows)
+
+	s := Summary{EvaluationBatch: batch, Counts: map[string]int64{}}
+	for _, r := range rows {
+		s.Counts[r.Status] = r.Count
+		if r.Status != models.ItemQueued && r.Status != models.ItemRunning {
+			s.Done += r.Count
+		}
+	}
+	return s
+}
@@ -0,0 +1,42 @@
+package evalqueue
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff(t *testing.T) {
+	q := &Queue{Backoff: 30 * time.Second, MaxBackoff: 5 * time.Minute}
+
+	tests := []struct {
+		name     string
+		attempts int
+		want     time.Duration
+	}{
+		{name: "首次失败", attempts: 1, want: 30 * time.Second},
+		{name: "第二次失败翻倍", attempts: 2, want: time.Minute},
+		{name: "第三次失败", attempts: 3, want: 2 * time.Minute},
+		{name: "不超过上限", attempts: 10, want: 5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, q.backoff(tt.attempts))
+		})
+	}
+}
+
+func TestPermanent(t *testing.T) {
+	cause := errors.New("简历不存在")
+	err := fmt.Errorf("评估失败: %w", Permanent(cause))
+
+	var permanent permanentError
+	assert.True(t, errors.As(err, &permanent), "包装后仍可识别")
+	assert.ErrorIs(t, err, cause)
+	assert.Nil(t, Permanent(nil))
+	assert.False(t, errors.As(errors.New("timeout"), &permanent))
+}
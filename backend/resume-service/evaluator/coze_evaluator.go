@@ -26,25 +26,6 @@ type CozeEvaluator struct {
 	httpClient *http.Client
 }
 
-// EvaluationResult AI 评估结果
-type EvaluationResult struct {
-	Name            string                 `json:"name"`
-	TotalScore      float64                `json:"total_score"`
-	Grade           string                 `json:"grade"`
-	JDMatchScore    int                    `json:"jd_match_score"`
-	AgeScore        int                    `json:"age_score"`
-	ExperienceScore int                    `json:"experience_score"`
-	EducationScore  int                    `json:"education_score"`
-	CompanyScore    int                    `json:"company_score"`
-	TechScore       int                    `json:"tech_score"`
-	ProjectScore    int                    `json:"project_score"`
-	Recommendation  string                 `json:"recommendation"`
-	MatchedSkills   []string               `json:"matched_skills"`
-	MissingSkills   []string               `json:"missing_skills"`
-	Summary         string                 `json:"summary"`
-	RawResult       map[string]interface{} `json:"raw_result"`
-}
-
 // NewCozeEvaluator 创建 Coze 评估器
 func NewCozeEvaluator() *CozeEvaluator {
 	config := CozeConfig{
@@ -72,6 +53,23 @@ func (e *CozeEvaluator) IsConfigured() bool {
 	return e.config.Token != "" && e.config.WorkflowID != ""
 }
 
+// Provider 评估服务名称，记录在评估结果中
+func (e *CozeEvaluator) Provider() string {
+	return "coze"
+}
+
+// Model 评估使用的工作流 ID
+func (e *CozeEvaluator) Model() string {
+	return e.config.WorkflowID
+}
+
+// WithModel 使用指定工作流的评估器副本
+func (e *CozeEvaluator) WithModel(model string) Evaluator {
+	config := e.config
+	config.WorkflowID = model
+	return &CozeEvaluator{config: config, httpClient: e.httpClient}
+}
+
 // uploadFile 上传文件到 Coze
 func (e *CozeEvaluator) uploadFile(ctx context.Context, filename string, data []byte) (string, error) {
 	url := fmt.Sprintf("%s/v1/files/upload", e.config.BaseURL)
@@ -0,0 +1,127 @@
+package evaluator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Evaluator 简历评估服务
+type Evaluator interface {
+	// EvaluateResume 按 JD 评估一份 PDF 简历
+	EvaluateResume(ctx context.Context, name string, jdText string, resumePDF []byte) (*EvaluationResult, error)
+	// IsConfigured 是否已配置，未配置时评估接口返回 503
+	IsConfigured() bool
+	// Provider 评估服务名称，记录在评估结果中
+	Provider() string
+	// Model 评估使用的模型或工作流
+	Model() string
+}
+
+// ModelSelector 可按配置切换模型的评估服务
+type ModelSelector interface {
+	WithModel(model string) Evaluator
+}
+
+// EvaluationResult AI 评估结果
+type EvaluationResult struct {
+	Name            string                 `json:"name"`
+	TotalScore      float64                `json:"total_score"`
+	Grade           string                 `json:"grade"`
+	JDMatchScore    int                    `json:"jd_match_score"`
+	AgeScore        int                    `json:"age_score"`
+	ExperienceScore int                    `json:"experience_score"`
+	EducationScore  int                    `json:"education_score"`
+	CompanyScore    int                    `json:"company_score"`
+	TechScore       int                    `json:"tech_score"`
+	ProjectScore    int                    `json:"project_score"`
+	Recommendation  string                 `json:"recommendation"`
+	MatchedSkills   []string               `json:"matched_skills"`
+	MissingSkills   []string               `json:"missing_skills"`
+	Summary         string                 `json:"summary"`
+	RawResult       map[string]interface{} `json:"raw_result"`
+}
+
+// ErrUnknownProvider 未注册的评估服务
+var ErrUnknownProvider = errors.New("未知的评估服务")
+
+// Registry 已注册的评估服务，按名称选择
+type Registry struct {
+	providers map[string]Evaluator
+	fallback  string
+}
+
+// NewRegistry 创建评估服务注册表，fallback 为未指定服务时使用的默认服务
+func NewRegistry(fallback string, evaluators ...Evaluator) *Registry {
+	r := &Registry{providers: map[string]Evaluator{}, fallback: fallback}
+	for _, e := range evaluators {
+		r.providers[e.Provider()] = e
+	}
+	return r
+}
+
+// NewRegistryFromEnv 按环境变量注册评估服务
+//
+//	EVAL_PROVIDER      默认评估服务：coze（默认）、openai、rules（离线规则评分）
+//	EVAL_ENABLE_FAKE   为 true 时注册确定性的 fake 服务，用于测试和本地开发
+//
+// 各服务的配置见 NewCozeEvaluator、NewOpenAIEvaluator
+func NewRegistryFromEnv() *Registry {
+	evaluators := []Evaluator{NewCozeEvaluator(), NewOpenAIEvaluator(), NewRulesEvaluator()}
+	if getEnv("EVAL_ENABLE_FAKE", "") == "true" {
+		evaluators = append(evaluators, &FakeEvaluator{})
+	}
+	return NewRegistry(getEnv("EVAL_PROVIDER", "coze"), evaluators...)
+}
+
+// Default 默认评估服务名称
+func (r *Registry) Default() string {
+	return r.fallback
+}
+
+// Has 是否注册了指定服务
+func (r *Registry) Has(provider string) bool {
+	_, ok := r.providers[provider]
+	return ok
+}
+
+// Resolve 按服务名称和模型取评估服务，provider 为空时使用默认服务，model 为空时使用服务的默认模型
+func (r *Registry) Resolve(provider, model string) (Evaluator, error) {
+	if provider == "" {
+		provider = r.fallback
+	}
+	e, ok := r.providers[provider]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, provider)
+	}
+	if model != "" {
+		if s, ok := e.(ModelSelector); ok {
+			e = s.WithModel(model)
+		}
+	}
+	return e, nil
+}
+
+// ProviderInfo 评估服务的配置状态
+type ProviderInfo struct {
+	Name       string `json:"name"`
+	Model      string `json:"model"`
+	Configured bool   `json:"configured"`
+	Default    bool   `json:"default"`
+}
+
+// Providers 已注册的评估服务，按名称排序
+func (r *Registry) Providers() []ProviderInfo {
+	infos := make([]ProviderInfo, 0, len(r.providers))
+	for name, e := range r.providers {
+		infos = append(infos, ProviderInfo{
+			Name:       name,
+			Model:      e.Model(),
+			Configured: e.IsConfigured(),
+			Default:    name == r.fallback,
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
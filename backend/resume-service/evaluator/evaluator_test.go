@@ -0,0 +1,208 @@
+package evaluator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const validOutput = `{"name":"张三","total_score":82.5,"grade":"B","jd_match_score":80,"age_score":70,
+"experience_score":85,"education_score":75,"company_score":60,"tech_score":88,"project_score":80,
+"recommendation":"推荐面试","matched_skills":["Go","MySQL"],"missing_skills":["Kubernetes"],"summary":"后端经验扎实"}`
+
+func TestParseResult(t *testing.T) {
+	tests := []struct {
+		name         string
+		output       string
+		wantErr      bool
+		wantProblems []string
+	}{
+		{name: "符合约定", output: validOutput},
+		{name: "代码块和前后说明", output: "评估结果如下：\n```json\n" + validOutput + "\n```\n以上。"},
+		{name: "不是 JSON", output: "候选人不错，推荐面试", wantErr: true},
+		{name: "缺少总分", output: `{"grade":"A"}`, wantErr: true},
+		{
+			name:   "字段类型和范围错误",
+			output: `{"total_score":"90","grade":"优秀","jd_match_score":120,"age_score":70,"experience_score":85,"education_score":75,"company_score":60,"tech_score":88,"project_score":"高","recommendation":"推荐","matched_skills":"Go","missing_skills":[],"summary":""}`,
+			wantProblems: []string{
+				"jd_match_score 应在 0-100 之间",
+				"project_score 应为数字",
+				"grade 应为 A、B、C、D 之一",
+				"matched_skills 应为字符串数组",
+			},
+		},
+		{
+			name:         "缺少字段",
+			output:       `{"total_score":60,"grade":"c","jd_match_score":60,"age_score":60,"experience_score":60,"education_score":60,"company_score":60,"tech_score":60,"project_score":60,"matched_skills":[],"missing_skills":[]}`,
+			wantProblems: []string{"缺少 recommendation", "缺少 summary"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, problems, err := ParseResult(tt.output)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidOutput)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantProblems, problems)
+			assert.NotNil(t, result.RawResult)
+		})
+	}
+}
+
+func TestRepair(t *testing.T) {
+	result := &EvaluationResult{
+		TotalScore:    120,
+		Grade:         "优秀",
+		JDMatchScore:  -5,
+		TechScore:     150,
+		MatchedSkills: []string{"Go", " go ", "", "MySQL"},
+	}
+	Repair(result)
+
+	assert.Equal(t, 100.0, result.TotalScore)
+	assert.Equal(t, "A", result.Grade, "评级不合法时按总分确定")
+	assert.Equal(t, 0, result.JDMatchScore)
+	assert.Equal(t, 100, result.TechScore)
+	assert.Equal(t, []string{"Go", "MySQL"}, result.MatchedSkills)
+	assert.Equal(t, []string{}, result.MissingSkills)
+
+	result = &EvaluationResult{TotalScore: 60, Grade: " b "}
+	Repair(result)
+	assert.Equal(t, "B", result.Grade, "合法评级只做规范化，不按总分改写")
+}
+
+func TestGradeFor(t *testing.T) {
+	assert.Equal(t, "A", GradeFor(85))
+	assert.Equal(t, "B", GradeFor(84.9))
+	assert.Equal(t, "C", GradeFor(55))
+	assert.Equal(t, "D", GradeFor(0))
+}
+
+// chatServer 依次返回 outputs 中的模型输出，并记录收到的消息
+func chatServer(t *testing.T, outputs ...string) (*httptest.Server, *[][]chatMessage) {
+	var requests [][]chatMessage
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer sk-test", r.Header.Get("Authorization"))
+
+		var body struct {
+			Model    string        `json:"model"`
+			Messages []chatMessage `json:"messages"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "qwen2.5", body.Model)
+		requests = append(requests, body.Messages)
+
+		output := outputs[min(len(requests), len(outputs))-1]
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []interface{}{map[string]interface{}{"message": chatMessage{Role: "assistant", Content: output}}},
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestOpenAIEvaluateText(t *testing.T) {
+	tests := []struct {
+		name         string
+		outputs      []string
+		wantRequests int
+		wantErr      bool
+		wantGrade    string
+	}{
+		{name: "一次成功", outputs: []string{validOutput}, wantRequests: 1, wantGrade: "B"},
+		{name: "格式错误后修正", outputs: []string{"候选人不错", validOutput}, wantRequests: 2, wantGrade: "B"},
+		{
+			name:         "修正次数用尽后本地修复",
+			outputs:      []string{`{"total_score":90,"grade":"优秀"}`},
+			wantRequests: 3,
+			wantGrade:    "A",
+		},
+		{name: "始终不是 JSON", outputs: []string{"无法评估"}, wantRequests: 3, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, requests := chatServer(t, tt.outputs...)
+			e := NewOpenAIEvaluatorWithConfig(OpenAIConfig{BaseURL: srv.URL + "/v1/", APIKey: "sk-test", Model: "qwen2.5", MaxRepairs: 2})
+
+			result, err := e.EvaluateText(context.Background(), "resume.pdf", "Go 后端工程师", "张三，5 年 Go 开发经验")
+			assert.Len(t, *requests, tt.wantRequests)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidOutput)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantGrade, result.Grade)
+			assert.Equal(t, tt.wantRequests-1, result.RawResult["repairs"])
+		})
+	}
+}
+
+func TestOpenAIRepairPrompt(t *testing.T) {
+	srv, requests := chatServer(t, `{"total_score":90}`, validOutput)
+	e := NewOpenAIEvaluatorWithConfig(OpenAIConfig{BaseURL: srv.URL + "/v1", APIKey: "sk-test", Model: "qwen2.5", MaxRepairs: 1})
+
+	result, err := e.EvaluateText(context.Background(), "resume.pdf", "JD", "简历")
+	require.NoError(t, err)
+	assert.Equal(t, "张三", result.Name)
+
+	retry := (*requests)[1]
+	require.Len(t, retry, 4, "修正请求带上原输出和问题")
+	assert.Equal(t, "assistant", retry[2].Role)
+	assert.Contains(t, retry[3].Content, "缺少 grade")
+}
+
+func TestFakeEvaluator(t *testing.T) {
+	e := &FakeEvaluator{}
+	a, err := e.EvaluateResume(context.Background(), "a.pdf", "JD", []byte("resume"))
+	require.NoError(t, err)
+	b, _ := e.EvaluateResume(context.Background(), "a.pdf", "JD", []byte("resume"))
+	c, _ := e.EvaluateResume(context.Background(), "a.pdf", "另一个 JD", []byte("resume"))
+
+	assert.Equal(t, a, b, "相同输入结果相同")
+	assert.NotEqual(t, a.RawResult, c.RawResult)
+	assert.Equal(t, GradeFor(a.TotalScore), a.Grade)
+	assert.GreaterOrEqual(t, a.TechScore, 40)
+	assert.LessOrEqual(t, a.TechScore, 100)
+
+	failing := &FakeEvaluator{Err: errors.New("timeout")}
+	_, err = failing.EvaluateResume(context.Background(), "a.pdf", "JD", nil)
+	assert.EqualError(t, err, "timeout")
+}
+
+func TestRegistryResolve(t *testing.T) {
+	openai := NewOpenAIEvaluatorWithConfig(OpenAIConfig{BaseURL: "http://localhost:11434/v1", Model: "qwen2.5"})
+	r := NewRegistry("openai", openai, &FakeEvaluator{})
+
+	e, err := r.Resolve("", "")
+	require.NoError(t, err)
+	assert.Equal(t, "openai", e.Provider())
+	assert.Equal(t, "qwen2.5", e.Model())
+
+	e, err = r.Resolve("openai", "llama3")
+	require.NoError(t, err)
+	assert.Equal(t, "llama3", e.Model(), "按配置切换模型")
+	assert.Equal(t, "qwen2.5", openai.Model(), "不影响注册的评估服务")
+
+	e, err = r.Resolve("fake", "other")
+	require.NoError(t, err)
+	assert.Equal(t, "fake-v1", e.Model(), "不支持切换模型的服务忽略模型配置")
+
+	_, err = r.Resolve("coze", "")
+	assert.ErrorIs(t, err, ErrUnknownProvider)
+
+	providers := r.Providers()
+	require.Len(t, providers, 2)
+	assert.Equal(t, "fake", providers[0].Name)
+	assert.True(t, providers[1].Default)
+}
@@ -0,0 +1,223 @@
+package evaluator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"resume-service/extractor"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OpenAIConfig OpenAI 兼容的 chat completions 接口配置，可指向本地或自建的模型服务
+type OpenAIConfig struct {
+	BaseURL    string // 如 https://api.openai.com/v1、http://localhost:11434/v1
+	APIKey     string // 本地服务可为空
+	Model      string
+	JSONMode   bool // 请求 response_format=json_object，服务不支持时关闭
+	MaxRepairs int  // 输出不符合约定时让模型修正的次数
+}
+
+// OpenAIEvaluator 通过 OpenAI 兼容接口评估简历：提取简历文本，与 JD 一起交给模型，
+// 按约定的 JSON 格式返回评估结果
+type OpenAIEvaluator struct {
+	config     OpenAIConfig
+	httpClient *http.Client
+}
+
+// maxResumeRunes 发送给模型的简历文本上限
+const maxResumeRunes = 20000
+
+// NewOpenAIEvaluator 按环境变量创建评估器
+//
+//	OPENAI_BASE_URL     接口地址，未设置时不启用
+//	OPENAI_API_KEY      API Key
+//	OPENAI_MODEL        模型名称
+//	OPENAI_JSON_MODE    是否请求 JSON 输出，默认 true
+//	OPENAI_MAX_REPAIRS  输出格式错误时的修正次数，默认 2
+func NewOpenAIEvaluator() *OpenAIEvaluator {
+	repairs, err := strconv.Atoi(getEnv("OPENAI_MAX_REPAIRS", "2"))
+	if err != nil || repairs < 0 {
+		repairs = 2
+	}
+	return NewOpenAIEvaluatorWithConfig(OpenAIConfig{
+		BaseURL:    getEnv("OPENAI_BASE_URL", ""),
+		APIKey:     getEnv("OPENAI_API_KEY", ""),
+		Model:      getEnv("OPENAI_MODEL", ""),
+		JSONMode:   getEnv("OPENAI_JSON_MODE", "true") == "true",
+		MaxRepairs: repairs,
+	})
+}
+
+// NewOpenAIEvaluatorWithConfig 使用指定配置创建评估器
+func NewOpenAIEvaluatorWithConfig(config OpenAIConfig) *OpenAIEvaluator {
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+	return &OpenAIEvaluator{
+		config:     config,
+		httpClient: &http.Client{Timeout: 300 * time.Second},
+	}
+}
+
+// IsConfigured 检查是否已配置
+func (e *OpenAIEvaluator) IsConfigured() bool {
+	return e.config.BaseURL != "" && e.config.Model != ""
+}
+
+// Provider 评估服务名称
+func (e *OpenAIEvaluator) Provider() string {
+	return "openai"
+}
+
+// Model 模型名称
+func (e *OpenAIEvaluator) Model() string {
+	return e.config.Model
+}
+
+// WithModel 使用指定模型的评估器副本
+func (e *OpenAIEvaluator) WithModel(model string) Evaluator {
+	config := e.config
+	config.Model = model
+	return &OpenAIEvaluator{config: config, httpClient: e.httpClient}
+}
+
+// systemPrompt 评估规则和输出格式
+const systemPrompt = `你是资深招聘专家，根据职位描述（JD）评估候选人简历。
+只输出一个 JSON 对象，不要输出任何其他内容，字段如下：
+{
+  "name": "候选人姓名，简历中没有则为空字符串",
+  "total_score": 0-100 的数字，综合得分,
+  "grade": "A"（85 分及以上）、"B"（70-84）、"C"（55-69）或 "D"（55 分以下）,
+  "jd_match_score": 0-100 的整数，与 JD 的匹配度,
+  "age_score": 0-100 的整数，年龄与职级的匹配度，简历未提供时给 60,
+  "experience_score": 0-100 的整数，工作经验,
+  "education_score": 0-100 的整数，学历背景,
+  "company_score": 0-100 的整数，过往公司背景,
+  "tech_score": 0-100 的整数，技术能力,
+  "project_score": 0-100 的整数，项目经历,
+  "recommendation": "录用建议，如：推荐面试、待定、不推荐",
+  "matched_skills": ["JD 要求且简历具备的技能"],
+  "missing_skills": ["JD 要求但简历未体现的技能"],
+  "summary": "一段话的匹配总结"
+}
+只根据简历中的事实评分，不要臆测。`
+
+// EvaluateResume 评估简历
+func (e *OpenAIEvaluator) EvaluateResume(ctx context.Context, name string, jdText string, resumePDF []byte) (*EvaluationResult, error) {
+	if !e.IsConfigured() {
+		return nil, fmt.Errorf("OpenAI 兼容服务未配置，请设置 OPENAI_BASE_URL 和 OPENAI_MODEL 环境变量")
+	}
+
+	resumeText, err := extractor.ExtractPDF(bytes.NewReader(resumePDF), int64(len(resumePDF)))
+	if err != nil {
+		return nil, fmt.Errorf("提取简历文本失败: %w", err)
+	}
+	return e.EvaluateText(ctx, name, jdText, resumeText)
+}
+
+// EvaluateText 按 JD 评估简历文本
+func (e *OpenAIEvaluator) EvaluateText(ctx context.Context, name string, jdText string, resumeText string) (*EvaluationResult, error) {
+	if runes := []rune(resumeText); len(runes) > maxResumeRunes {
+		resumeText = string(runes[:maxResumeRunes])
+	}
+
+	messages := []chatMessage{
+		{Role: "system", Content: systemPrompt},
+		{Role: "user", Content: "## 职位描述\n" + jdText + "\n\n## 候选人简历\n" + resumeText},
+	}
+
+	// 输出不符合约定时把问题反馈给模型修正，仍有问题则在本地修复
+	for repairs := 0; ; repairs++ {
+		output, err := e.complete(ctx, messages)
+		if err != nil {
+			return nil, err
+		}
+
+		result, problems, err := ParseResult(output)
+		if (err != nil || len(problems) > 0) && repairs < e.config.MaxRepairs {
+			issue := strings.Join(problems, "；")
+			if err != nil {
+				issue = err.Error()
+			}
+			messages = append(messages,
+				chatMessage{Role: "assistant", Content: output},
+				chatMessage{Role: "user", Content: "上面的输出不符合要求：" + issue + "。请按约定的字段重新输出完整的 JSON 对象，不要输出其他内容。"},
+			)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		Repair(result)
+		if result.Name == "" {
+			result.Name = name
+		}
+		result.RawResult = map[string]interface{}{"output": result.RawResult, "repairs": repairs}
+		return result, nil
+	}
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// complete 调用 chat completions，返回模型输出
+func (e *OpenAIEvaluator) complete(ctx context.Context, messages []chatMessage) (string, error) {
+	requestBody := map[string]interface{}{
+		"model":       e.config.Model,
+		"messages":    messages,
+		"temperature": 0,
+	}
+	if e.config.JSONMode {
+		requestBody["response_format"] = map[string]string{"type": "json_object"}
+	}
+
+	jsonBody, err := json.Marshal(requestBody)
+	if err != nil {
+		return "", fmt.Errorf("序列化请求失败: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.config.BaseURL+"/chat/completions", bytes.NewReader(jsonBody))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.config.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.config.APIKey)
+	}
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("openai http %d: %s", resp.StatusCode, string(body))
+	}
+
+	var out struct {
+		Choices []struct {
+			Message chatMessage `json:"message"`
+		} `json:"choices"`
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return "", fmt.Errorf("解析响应失败: %w", err)
+	}
+	if out.Error != nil {
+		return "", fmt.Errorf("openai error: %s", out.Error.Message)
+	}
+	if len(out.Choices) == 0 {
+		return "", fmt.Errorf("openai 响应缺少 choices")
+	}
+	return out.Choices[0].Message.Content, nil
+}
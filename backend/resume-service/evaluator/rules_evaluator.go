@@ -0,0 +1,302 @@
+package evaluator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"resume-service/extractor"
+	"resume-service/parser"
+	"strconv"
+	"strings"
+
+	"common/jdextract"
+	"common/skills"
+)
+
+// RulesEvaluator 离线规则评分：解析简历文本和 JD，按技能、工作年限、学历和项目经历计算分数，
+// 不访问外部服务，相同输入结果不变。用于大模型不可用时的兜底和离线部署
+type RulesEvaluator struct {
+	dict   *skills.Dictionary
+	parser *parser.ResumeParser
+	jd     *jdextract.Extractor
+}
+
+// NewRulesEvaluator 使用共享技能字典创建规则评分
+func NewRulesEvaluator() *RulesEvaluator {
+	return NewRulesEvaluatorWithDictionary(skills.Default())
+}
+
+// NewRulesEvaluatorWithDictionary 使用指定技能字典创建规则评分
+func NewRulesEvaluatorWithDictionary(dict *skills.Dictionary) *RulesEvaluator {
+	return &RulesEvaluator{
+		dict:   dict,
+		parser: parser.NewResumeParserWithDictionary(dict),
+		jd:     jdextract.New(dict),
+	}
+}
+
+// IsConfigured 无需配置，总是可用
+func (e *RulesEvaluator) IsConfigured() bool {
+	return true
+}
+
+// Provider 评估服务名称
+func (e *RulesEvaluator) Provider() string {
+	return "rules"
+}
+
+// Model 规则版本，规则调整时递增，便于区分历史评估
+func (e *RulesEvaluator) Model() string {
+	return "rules-v1"
+}
+
+// 各维度在总分中的权重
+const (
+	rulesWeightJDMatch    = 0.30
+	rulesWeightTech       = 0.25
+	rulesWeightExperience = 0.20
+	rulesWeightProject    = 0.15
+	rulesWeightEducation  = 0.10
+)
+
+// 简历或 JD 未提供相关信息时的中性分数
+const rulesNeutralScore = 60
+
+// EvaluateResume 提取简历文本后评分，name 为文件名时按扩展名识别格式，无法识别时按 PDF 处理
+func (e *RulesEvaluator) EvaluateResume(ctx context.Context, name string, jdText string, resumeFile []byte) (*EvaluationResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	text, err := extractor.Extract(bytes.NewReader(resumeFile), int64(len(resumeFile)), name)
+	if errors.Is(err, extractor.ErrUnsupported) {
+		text, err = extractor.ExtractPDF(bytes.NewReader(resumeFile), int64(len(resumeFile)))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("提取简历文本失败: %w", err)
+	}
+	return e.EvaluateText(name, jdText, text)
+}
+
+// EvaluateText 按 JD 为简历文本评分
+func (e *RulesEvaluator) EvaluateText(name string, jdText string, resumeText string) (*EvaluationResult, error) {
+	resume, err := e.parser.Parse(resumeText)
+	if err != nil {
+		return nil, fmt.Errorf("解析简历失败: %w", err)
+	}
+	title, description, _ := strings.Cut(strings.TrimSpace(jdText), "\n")
+	return e.Score(resume, e.jd.Extract(title, description), name), nil
+}
+
+// Score 按 JD 要求为解析后的简历评分
+func (e *RulesEvaluator) Score(resume *parser.ParsedResume, jd jdextract.Suggestions, name string) *EvaluationResult {
+	matched, missing := e.matchSkills(resume.Skills, jd.Skills)
+	years := resumeYears(resume)
+
+	result := &EvaluationResult{
+		Name:            resume.Name,
+		JDMatchScore:    e.parser.CalculateMatchScore(resume, jd.Skills, jd.MinExperience, jd.Education),
+		TechScore:       skillCoverage(len(matched), len(jd.Skills)),
+		ExperienceScore: experienceScore(years, jd.MinExperience, jd.Level),
+		EducationScore:  educationScore(resume.Education, jd.Education),
+		ProjectScore:    e.projectScore(resume.Projects, jd.Skills),
+		AgeScore:        rulesNeutralScore, // 简历通常不含年龄，不参与比较
+		CompanyScore:    rulesNeutralScore, // 没有公司背景数据
+		MatchedSkills:   matched,
+		MissingSkills:   missing,
+	}
+	if result.Name == "" {
+		result.Name = name
+	}
+	if len(resume.WorkExperiences) == 0 && years == 0 {
+		result.CompanyScore = 40
+	}
+
+	total := float64(result.JDMatchScore)*rulesWeightJDMatch +
+		float64(result.TechScore)*rulesWeightTech +
+		float64(result.ExperienceScore)*rulesWeightExperience +
+		float64(result.ProjectScore)*rulesWeightProject +
+		float64(result.EducationScore)*rulesWeightEducation
+	result.TotalScore = math.Round(total*10) / 10
+	result.Grade = GradeFor(result.TotalScore)
+	result.Recommendation = rulesRecommendation(result.TotalScore)
+	result.Summary = rulesSummary(resume, jd, result, years)
+	result.RawResult = map[string]interface{}{
+		"jd_skills":          jd.Skills,
+		"jd_min_experience":  jd.MinExperience,
+		"jd_education":       jd.Education,
+		"jd_level":           jd.Level,
+		"resume_skills":      resume.Skills,
+		"resume_years":       years,
+		"resume_education":   resume.Education,
+		"resume_projects":    len(resume.Projects),
+		"resume_experiences": len(resume.WorkExperiences),
+	}
+	return result
+}
+
+// matchSkills JD 技能中简历具备和缺少的技能
+func (e *RulesEvaluator) matchSkills(have, want []string) (matched, missing []string) {
+	matched, missing = []string{}, []string{}
+	for _, w := range want {
+		found := false
+		for _, h := range have {
+			if e.dict.Matches(h, w) {
+				found = true
+				break
+			}
+		}
+		if found {
+			matched = append(matched, w)
+		} else {
+			missing = append(missing, w)
+		}
+	}
+	return matched, missing
+}
+
+// skillCoverage JD 技能的覆盖率，JD 未列出技能时给中性分
+func skillCoverage(matched, required int) int {
+	if required == 0 {
+		return rulesNeutralScore
+	}
+	return matched * 100 / required
+}
+
+// resumeYears 工作年限：优先按工作经历时间区间计算，否则取简历中写明的年限
+func resumeYears(resume *parser.ParsedResume) float64 {
+	if resume.TotalYears > 0 {
+		return resume.TotalYears
+	}
+	if years, err := strconv.Atoi(strings.TrimSuffix(resume.Experience, "年")); err == nil {
+		return float64(years)
+	}
+	return 0
+}
+
+// levelYears 未写明年限要求时按职级估计的年限区间
+var levelYears = map[string]struct{ min, ideal, max float64 }{
+	jdextract.LevelJunior:     {0, 1, 2},
+	jdextract.LevelMid:        {2, 4, 6},
+	jdextract.LevelSenior:     {5, 7, 10},
+	jdextract.LevelExpert:     {8, 10, 15},
+	jdextract.LevelManagement: {5, 8, 15},
+}
+
+// experienceScore 工作年限得分，与人才推荐的经验匹配规则一致：
+// 达到要求满分，远超要求略减（期望可能更高），不足按比例
+func experienceScore(years float64, minYears int, level string) int {
+	if minYears > 0 {
+		required := float64(minYears)
+		switch {
+		case years >= required*2+3:
+			return 80
+		case years >= required:
+			return 100
+		default:
+			return int(years / required * 60)
+		}
+	}
+
+	req, ok := levelYears[level]
+	if !ok {
+		if years == 0 {
+			return rulesNeutralScore
+		}
+		return 100
+	}
+	switch {
+	case years < req.min:
+		return int(years / req.min * 60)
+	case years > req.max:
+		return 70
+	case years >= req.ideal:
+		return 100
+	default:
+		return 80
+	}
+}
+
+// educationScore 学历得分：达到要求满分，不足按等级比例；简历未识别出学历时给中性分
+func educationScore(education, required string) int {
+	have, want := jdextract.EducationRank(education), jdextract.EducationRank(required)
+	switch {
+	case have == 0:
+		return rulesNeutralScore
+	case want == 0:
+		// 未要求学历时按学历高低给分，本科 80
+		return min(100, 50+have*10)
+	case have >= want:
+		return 100
+	default:
+		return have * 100 / want
+	}
+}
+
+// projectScore 项目经历得分：项目数量（最多计 3 段）占 60 分，项目中用到 JD 技能的比例占 40 分
+func (e *RulesEvaluator) projectScore(projects []parser.Project, jdSkills []string) int {
+	if len(projects) == 0 {
+		return 30
+	}
+	score := min(len(projects), 3) * 20
+
+	if len(jdSkills) == 0 {
+		return score + 20
+	}
+	var text strings.Builder
+	for _, p := range projects {
+		text.WriteString(p.Name + "\n" + p.Role + "\n" + p.Description + "\n")
+	}
+	used, _ := e.matchSkills(e.dict.Extract(text.String()), jdSkills)
+	return score + len(used)*40/len(jdSkills)
+}
+
+// rulesRecommendation 按总分给出录用建议
+func rulesRecommendation(total float64) string {
+	switch {
+	case total >= 75:
+		return "推荐面试"
+	case total >= 60:
+		return "待定"
+	default:
+		return "不推荐"
+	}
+}
+
+// rulesSummary 评分依据的文字说明
+func rulesSummary(resume *parser.ParsedResume, jd jdextract.Suggestions, result *EvaluationResult, years float64) string {
+	var parts []string
+
+	if len(jd.Skills) > 0 {
+		s := fmt.Sprintf("JD 要求的 %d 项技能匹配 %d 项", len(jd.Skills), len(result.MatchedSkills))
+		if len(result.MissingSkills) > 0 {
+			s += "，缺少 " + strings.Join(result.MissingSkills, "、")
+		}
+		parts = append(parts, s)
+	} else {
+		parts = append(parts, "JD 未列出可识别的技能要求")
+	}
+
+	exp := "工作年限未知"
+	if years > 0 {
+		exp = fmt.Sprintf("工作约 %.1f 年", years)
+	}
+	if jd.MinExperience > 0 {
+		exp += fmt.Sprintf("，要求 %d 年以上", jd.MinExperience)
+	}
+	parts = append(parts, exp)
+
+	edu := "学历未识别"
+	if resume.Education != "" {
+		edu = "学历" + resume.Education
+	}
+	if jd.Education != "" {
+		edu += "，要求" + jd.Education + "及以上"
+	}
+	parts = append(parts, edu)
+	parts = append(parts, fmt.Sprintf("项目经历 %d 段", len(resume.Projects)))
+
+	return "离线规则评分：" + strings.Join(parts, "；") + "。"
+}
@@ -0,0 +1,118 @@
+package evaluator
+
+import (
+	"context"
+	"resume-service/parser"
+	"testing"
+
+	"common/jdextract"
+	"common/skills"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRulesEvaluateText(t *testing.T) {
+	e := NewRulesEvaluatorWithDictionary(skills.NewDictionary())
+
+	jd := "高级 Go 后端工程师\n\n负责交易系统后端开发。\n任职要求：\n- 本科及以上学历，5 年以上后端开发经验\n- 精通 Go、MySQL、Redis\n- 熟悉 Kubernetes"
+	resume := `
+姓名：张三
+手机：13812345678
+教育背景：
+本科 - 计算机科学与技术
+工作经验：6年
+技能：Golang、MySQL、Redis、Docker
+`
+
+	result, err := e.EvaluateText("zhangsan.pdf", jd, resume)
+	require.NoError(t, err)
+
+	assert.Equal(t, "张三", result.Name)
+	assert.Equal(t, []string{"Go", "MySQL", "Redis"}, result.MatchedSkills, "同义词按技能字典归一")
+	assert.Equal(t, []string{"Kubernetes"}, result.MissingSkills)
+	assert.Equal(t, 75, result.TechScore)
+	assert.Equal(t, 100, result.ExperienceScore)
+	assert.Equal(t, 100, result.EducationScore)
+	assert.Equal(t, 30, result.ProjectScore, "没有项目经历")
+	assert.Equal(t, GradeFor(result.TotalScore), result.Grade)
+	assert.Contains(t, result.Summary, "缺少 Kubernetes")
+	assert.Contains(t, result.Summary, "要求 5 年以上")
+
+	again, _ := e.EvaluateText("zhangsan.pdf", jd, resume)
+	assert.Equal(t, result, again, "相同输入结果相同")
+}
+
+func TestRulesScore(t *testing.T) {
+	e := NewRulesEvaluatorWithDictionary(skills.NewDictionary())
+	jd := jdextract.Suggestions{Skills: []string{"Go", "MySQL"}, MinExperience: 3, Education: "本科"}
+
+	tests := []struct {
+		name      string
+		resume    parser.ParsedResume
+		wantGrade string
+		wantRec   string
+	}{
+		{
+			name: "完全匹配",
+			resume: parser.ParsedResume{
+				Skills: []string{"Go", "MySQL"}, Experience: "4年", TotalYears: 4, Education: "硕士",
+				Projects: []parser.Project{{Name: "交易系统", Description: "使用 Go 和 MySQL 开发"}, {Name: "网关"}, {Name: "监控"}},
+			},
+			wantGrade: "A",
+			wantRec:   "推荐面试",
+		},
+		{
+			name:      "技能和经验都不足",
+			resume:    parser.ParsedResume{Skills: []string{"Java"}, Experience: "1年", TotalYears: 1, Education: "大专"},
+			wantGrade: "D",
+			wantRec:   "不推荐",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := e.Score(&tt.resume, jd, "resume.pdf")
+			assert.Equal(t, tt.wantGrade, result.Grade)
+			assert.Equal(t, tt.wantRec, result.Recommendation)
+			assert.Equal(t, "resume.pdf", result.Name, "简历未识别姓名时使用文件名")
+		})
+	}
+}
+
+func TestExperienceScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		years    float64
+		minYears int
+		level    string
+		want     int
+	}{
+		{name: "满足年限要求", years: 5, minYears: 3, want: 100},
+		{name: "远超年限要求", years: 10, minYears: 3, want: 80},
+		{name: "不足年限要求", years: 1.5, minYears: 3, want: 30},
+		{name: "按职级估计", years: 7, level: jdextract.LevelSenior, want: 100},
+		{name: "职级下限以上", years: 5, level: jdextract.LevelSenior, want: 80},
+		{name: "超出职级范围", years: 20, level: jdextract.LevelJunior, want: 70},
+		{name: "无要求且未知年限", years: 0, want: rulesNeutralScore},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, experienceScore(tt.years, tt.minYears, tt.level))
+		})
+	}
+}
+
+func TestEducationScore(t *testing.T) {
+	assert.Equal(t, 100, educationScore("硕士", "本科"))
+	assert.Equal(t, 66, educationScore("大专", "本科"))
+	assert.Equal(t, 80, educationScore("本科", ""))
+	assert.Equal(t, rulesNeutralScore, educationScore("", "本科"), "未识别学历")
+}
+
+func TestRulesEvaluateResumeUnreadable(t *testing.T) {
+	e := NewRulesEvaluatorWithDictionary(skills.NewDictionary())
+	_, err := e.EvaluateResume(context.Background(), "resume.pdf", "JD", []byte("not a pdf"))
+	assert.ErrorContains(t, err, "提取简历文本失败")
+}
@@ -0,0 +1,206 @@
+package evaluator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// 评级按总分划分
+var grades = []struct {
+	grade string
+	min   float64
+}{
+	{"A", 85},
+	{"B", 70},
+	{"C", 55},
+	{"D", 0},
+}
+
+// GradeFor 按总分确定评级
+func GradeFor(score float64) string {
+	for _, g := range grades {
+		if score >= g.min {
+			return g.grade
+		}
+	}
+	return "D"
+}
+
+// validGrade 评级是否合法
+func validGrade(grade string) bool {
+	for _, g := range grades {
+		if grade == g.grade {
+			return true
+		}
+	}
+	return false
+}
+
+// scoreFields 分数字段，取值 0-100
+var scoreFields = []string{
+	"total_score", "jd_match_score", "age_score", "experience_score",
+	"education_score", "company_score", "tech_score", "project_score",
+}
+
+// ErrInvalidOutput 模型输出无法作为评估结果使用
+var ErrInvalidOutput = errors.New("评估结果格式错误")
+
+// ParseResult 解析模型输出的评估 JSON。输出不是 JSON 对象或缺少总分时返回错误；
+// 其他不符合约定的字段作为问题列表返回，可让模型修正，或由 Repair 在本地修复
+func ParseResult(output string) (*EvaluationResult, []string, error) {
+	output = cleanJSONString(output)
+	if start, end := strings.Index(output, "{"), strings.LastIndex(output, "}"); start >= 0 && end > start {
+		output = output[start : end+1]
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(output), &fields); err != nil {
+		return nil, nil, fmt.Errorf("%w: 不是有效的 JSON 对象", ErrInvalidOutput)
+	}
+
+	var problems []string
+	scores := map[string]float64{}
+	for _, key := range scoreFields {
+		v, ok := fields[key]
+		if !ok {
+			problems = append(problems, "缺少 "+key)
+			continue
+		}
+		score, ok := number(v)
+		if !ok {
+			problems = append(problems, key+" 应为数字")
+			continue
+		}
+		if score < 0 || score > 100 {
+			problems = append(problems, key+" 应在 0-100 之间")
+		}
+		scores[key] = score
+	}
+	if _, ok := scores["total_score"]; !ok {
+		return nil, problems, fmt.Errorf("%w: total_score 缺失或不是数字", ErrInvalidOutput)
+	}
+
+	result := &EvaluationResult{
+		TotalScore:      scores["total_score"],
+		JDMatchScore:    int(math.Round(scores["jd_match_score"])),
+		AgeScore:        int(math.Round(scores["age_score"])),
+		ExperienceScore: int(math.Round(scores["experience_score"])),
+		EducationScore:  int(math.Round(scores["education_score"])),
+		CompanyScore:    int(math.Round(scores["company_score"])),
+		TechScore:       int(math.Round(scores["tech_score"])),
+		ProjectScore:    int(math.Round(scores["project_score"])),
+		RawResult:       fields,
+	}
+
+	if v, ok := fields["grade"]; !ok {
+		problems = append(problems, "缺少 grade")
+	} else if result.Grade, _ = v.(string); !validGrade(strings.ToUpper(strings.TrimSpace(result.Grade))) {
+		problems = append(problems, "grade 应为 A、B、C、D 之一")
+	}
+
+	strs := []struct {
+		key      string
+		dst      *string
+		required bool
+	}{
+		{"name", &result.Name, false},
+		{"recommendation", &result.Recommendation, true},
+		{"summary", &result.Summary, true},
+	}
+	for _, s := range strs {
+		v, ok := fields[s.key]
+		if !ok {
+			if s.required {
+				problems = append(problems, "缺少 "+s.key)
+			}
+			continue
+		}
+		if *s.dst, ok = v.(string); !ok {
+			problems = append(problems, s.key+" 应为字符串")
+		}
+	}
+
+	lists := []struct {
+		key string
+		dst *[]string
+	}{
+		{"matched_skills", &result.MatchedSkills},
+		{"missing_skills", &result.MissingSkills},
+	}
+	for _, l := range lists {
+		v, ok := fields[l.key]
+		if !ok {
+			problems = append(problems, "缺少 "+l.key)
+			continue
+		}
+		items, ok := v.([]interface{})
+		if !ok {
+			problems = append(problems, l.key+" 应为字符串数组")
+			continue
+		}
+		for _, item := range items {
+			if str, ok := item.(string); ok {
+				*l.dst = append(*l.dst, str)
+			} else {
+				problems = append(problems, l.key+" 应为字符串数组")
+				break
+			}
+		}
+	}
+
+	return result, problems, nil
+}
+
+// number 取数字，兼容以字符串输出的数字
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// Repair 在本地修复评估结果：分数限制在 0-100，评级不合法时按总分确定，技能去重
+func Repair(result *EvaluationResult) {
+	result.TotalScore = math.Max(0, math.Min(100, result.TotalScore))
+	for _, score := range []*int{
+		&result.JDMatchScore, &result.AgeScore, &result.ExperienceScore, &result.EducationScore,
+		&result.CompanyScore, &result.TechScore, &result.ProjectScore,
+	} {
+		*score = max(0, min(100, *score))
+	}
+
+	result.Grade = strings.ToUpper(strings.TrimSpace(result.Grade))
+	if !validGrade(result.Grade) {
+		result.Grade = GradeFor(result.TotalScore)
+	}
+
+	result.Name = strings.TrimSpace(result.Name)
+	result.Recommendation = strings.TrimSpace(result.Recommendation)
+	result.Summary = strings.TrimSpace(result.Summary)
+	result.MatchedSkills = uniqueStrings(result.MatchedSkills)
+	result.MissingSkills = uniqueStrings(result.MissingSkills)
+}
+
+// uniqueStrings 去除空白和重复项，保留原顺序
+func uniqueStrings(items []string) []string {
+	out := make([]string, 0, len(items))
+	seen := map[string]bool{}
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		key := strings.ToLower(item)
+		if item == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, item)
+	}
+	return out
+}
@@ -0,0 +1,265 @@
+package extractor
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/ledongthuc/pdf"
+	"golang.org/x/text/unicode/norm"
+)
+
+// 提取失败原因，错误信息直接展示给用户
+var (
+	ErrUnsupported = errors.New("暂不支持该文件格式，请上传 PDF 或 DOCX 格式的简历")
+	ErrLegacyDoc   = errors.New("暂不支持旧版 Word (.doc) 格式，请另存为 DOCX 或 PDF 后重新上传")
+	ErrEncrypted   = errors.New("PDF 文件已加密，请去除密码保护后重新上传")
+	ErrCorrupted   = errors.New("文件已损坏或格式不正确，无法读取")
+	ErrNoText      = errors.New("未能从文件中提取到文字，可能是扫描件或图片格式的简历，请上传文字版简历")
+)
+
+// minTextRunes 有效文字少于该数量时视为未提取到内容（扫描件通常只有零星的页眉文字）
+const minTextRunes = 30
+
+// maxDocumentXML DOCX 正文 XML 的最大解压大小，防止压缩炸弹
+const maxDocumentXML = 20 << 20
+
+// ExtractFile 按扩展名从简历文件中提取纯文本
+func ExtractFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	return Extract(f, info.Size(), path)
+}
+
+// Extract 按文件名的扩展名从内容中提取纯文本
+func Extract(r io.ReaderAt, size int64, filename string) (string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".pdf":
+		return ExtractPDF(r, size)
+	case ".docx":
+		return ExtractDOCX(r, size)
+	case ".doc":
+		return "", ErrLegacyDoc
+	default:
+		return "", ErrUnsupported
+	}
+}
+
+// ExtractPDF 提取 PDF 文本，按坐标还原行结构
+func ExtractPDF(r io.ReaderAt, size int64) (text string, err error) {
+	// 第三方 PDF 解析在遇到异常文件时会 panic
+	defer func() {
+		if p := recover(); p != nil {
+			text, err = "", fmt.Errorf("%w: %v", ErrCorrupted, p)
+		}
+	}()
+
+	reader, err := pdf.NewReader(r, size)
+	if err != nil {
+		if errors.Is(err, pdf.ErrInvalidPassword) {
+			return "", ErrEncrypted
+		}
+		return "", fmt.Errorf("%w: %v", ErrCorrupted, err)
+	}
+
+	var b strings.Builder
+	for i := 1; i <= reader.NumPage(); i++ {
+		page := reader.Page(i)
+		if page.V.IsNull() {
+			continue
+		}
+		b.WriteString(pageText(page.Content().Text))
+		b.WriteString("\n")
+	}
+
+	return finish(b.String())
+}
+
+// pageText 将页面上的文字块按从上到下、从左到右拼接，同一行内相距较远的块之间补空格
+func pageText(texts []pdf.Text) string {
+	const sameLine = 2.0 // 纵坐标相差不超过该值视为同一行
+
+	sort.SliceStable(texts, func(i, j int) bool {
+		if math.Abs(texts[i].Y-texts[j].Y) > sameLine {
+			return texts[i].Y > texts[j].Y
+		}
+		return texts[i].X < texts[j].X
+	})
+
+	var b strings.Builder
+	for i, t := range texts {
+		if i > 0 {
+			prev := texts[i-1]
+			switch {
+			case math.Abs(t.Y-prev.Y) > sameLine:
+				b.WriteString("\n")
+			case t.X-(prev.X+prev.W) > t.FontSize*0.25:
+				b.WriteString(" ")
+			}
+		}
+		b.WriteString(t.S)
+	}
+	return b.String()
+}
+
+// ExtractDOCX 提取 DOCX 正文文本，段落（含表格单元格内的段落）和换行各自成行
+func ExtractDOCX(r io.ReaderAt, size int64) (string, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrCorrupted, err)
+	}
+
+	var doc *zip.File
+	for _, f := range zr.File {
+		if f.Name == "word/document.xml" {
+			doc = f
+			break
+		}
+	}
+	if doc == nil {
+		return "", fmt.Errorf("%w: 缺少 word/document.xml", ErrCorrupted)
+	}
+
+	rc, err := doc.Open()
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrCorrupted, err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, maxDocumentXML+1))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrCorrupted, err)
+	}
+	if len(data) > maxDocumentXML {
+		return "", fmt.Errorf("%w: 文档内容过大", ErrCorrupted)
+	}
+
+	var b strings.Builder
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	inText := false
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", ErrCorrupted, err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				b.WriteString("\t")
+			case "br", "cr":
+				b.WriteString("\n")
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				b.WriteString("\n")
+			}
+		case xml.CharData:
+			if inText {
+				b.Write(t)
+			}
+		}
+	}
+
+	return finish(b.String())
+}
+
+// finish 清理提取结果，内容过少时返回 ErrNoText
+func finish(raw string) (string, error) {
+	text := Clean(raw)
+
+	count := 0
+	for _, r := range text {
+		if !unicode.IsSpace(r) {
+			count++
+		}
+	}
+	if count < minTextRunes {
+		return "", ErrNoText
+	}
+	return text, nil
+}
+
+// Clean 规范化提取的文本：部首和兼容汉字转为常用汉字、去除控制字符、合并多余空白，
+// 并去掉在多页重复出现的水印/页眉行
+func Clean(raw string) string {
+	raw = foldIdeographs(raw)
+
+	lines := strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n")
+	cleaned := make([]string, 0, len(lines))
+	counts := make(map[string]int)
+	for _, line := range lines {
+		line = strings.Map(func(r rune) rune {
+			if r == '\t' {
+				return ' '
+			}
+			if unicode.IsControl(r) || r == '�' {
+				return -1
+			}
+			return r
+		}, line)
+		line = strings.Join(strings.Fields(line), " ")
+		cleaned = append(cleaned, line)
+		counts[line]++
+	}
+
+	var b strings.Builder
+	blank := true
+	for _, line := range cleaned {
+		if line == "" {
+			if !blank {
+				b.WriteString("\n")
+			}
+			blank = true
+			continue
+		}
+		if counts[line] >= 3 && len([]rune(line)) >= 16 {
+			continue
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+		blank = false
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// foldIdeographs 将 PDF 中常见的康熙部首（如“⼯”“⾼”）和兼容汉字按 NFKC 转为常用汉字。
+// 只处理这几个区段，全角标点保持原样，简历解析的正则依赖中文标点
+func foldIdeographs(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		if (r >= 0x2E80 && r <= 0x2FDF) || (r >= 0xF900 && r <= 0xFAFF) || (r >= 0x2F800 && r <= 0x2FA1F) {
+			b.WriteString(norm.NFKC.String(string(r)))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
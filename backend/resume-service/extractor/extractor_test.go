@@ -0,0 +1,125 @@
+package extractor
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// buildDOCX 生成只包含正文的最小 DOCX
+func buildDOCX(t *testing.T, body string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("word/document.xml")
+	assert.NoError(t, err)
+	_, err = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		body + `</w:body></w:document>`))
+	assert.NoError(t, err)
+	assert.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestExtractDOCX(t *testing.T) {
+	data := buildDOCX(t,
+		`<w:p><w:r><w:t>张三</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>手机：</w:t></w:r><w:r><w:t>13812345678</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>邮箱</w:t><w:tab/><w:t>zhangsan@example.com</w:t></w:r></w:p>`+
+			`<w:tbl><w:tr><w:tc><w:p><w:r><w:t>2018-2023</w:t></w:r></w:p></w:tc>`+
+			`<w:tc><w:p><w:r><w:t>某科技公司 高级Go开发工程师</w:t></w:r></w:p></w:tc></w:tr></w:tbl>`)
+
+	text, err := ExtractDOCX(bytes.NewReader(data), int64(len(data)))
+	assert.NoError(t, err)
+	assert.Equal(t, "张三\n手机：13812345678\n邮箱 zhangsan@example.com\n2018-2023\n某科技公司 高级Go开发工程师", text)
+}
+
+func TestExtractErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		extract  func([]byte) error
+		expected error
+	}{
+		{
+			name:     "非 PDF 文件",
+			data:     []byte("this is not a pdf"),
+			extract:  func(b []byte) error { _, err := ExtractPDF(bytes.NewReader(b), int64(len(b))); return err },
+			expected: ErrCorrupted,
+		},
+		{
+			name:     "非 DOCX 文件",
+			data:     []byte("this is not a zip"),
+			extract:  func(b []byte) error { _, err := ExtractDOCX(bytes.NewReader(b), int64(len(b))); return err },
+			expected: ErrCorrupted,
+		},
+		{
+			name:     "DOCX 内容过少",
+			data:     buildDOCX(t, `<w:p><w:r><w:t>简历</w:t></w:r></w:p>`),
+			extract:  func(b []byte) error { _, err := ExtractDOCX(bytes.NewReader(b), int64(len(b))); return err },
+			expected: ErrNoText,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.extract(tt.data), tt.expected)
+		})
+	}
+}
+
+func TestExtractFileByExtension(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name     string
+		file     string
+		expected error
+	}{
+		{name: "旧版 Word", file: "resume.doc", expected: ErrLegacyDoc},
+		{name: "不支持的格式", file: "resume.txt", expected: ErrUnsupported},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			assert.NoError(t, os.WriteFile(path, []byte("content"), 0644))
+			_, err := ExtractFile(path)
+			assert.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
+
+func TestClean(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		expected string
+	}{
+		{
+			name:     "康熙部首归一",
+			raw:      "⼯作经历\n⾼级⼯程师",
+			expected: "工作经历\n高级工程师",
+		},
+		{
+			name:     "合并空白与空行",
+			raw:      "  张三 \t 男 \n\n\n\n本科  ",
+			expected: "张三 男\n\n本科",
+		},
+		{
+			name:     "去除多页重复的水印行",
+			raw:      "f7a249668c0e1d5b3a\n第一页\nf7a249668c0e1d5b3a\n第二页\nf7a249668c0e1d5b3a",
+			expected: "第一页\n第二页",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Clean(tt.raw))
+		})
+	}
+}
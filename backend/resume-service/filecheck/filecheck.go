@@ -0,0 +1,235 @@
+// Package filecheck 校验上传的简历文件：按内容识别真实类型、按类型限制大小、
+// 清理文件名，并检查 DOCX 压缩炸弹。
+package filecheck
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 支持的文件类型，取值与扩展名一致
+const (
+	TypePDF  = ".pdf"
+	TypeDOCX = ".docx"
+	TypeDOC  = ".doc"
+)
+
+// MaxFileSize 任何类型的文件都不能超过的上限，读取存储中的简历时也以此为界
+const MaxFileSize = 50 << 20
+
+// 拒绝原因，记录在拒收日志中
+const (
+	ReasonType      = "type"       // 内容不是支持的格式或与扩展名不符
+	ReasonSize      = "size"       // 超过大小限制
+	ReasonArchive   = "archive"    // DOCX 结构异常或疑似压缩炸弹
+	ReasonMalware   = "malware"    // 病毒扫描命中
+	ReasonScanError = "scan_error" // 要求扫描但扫描服务不可用
+)
+
+// RejectError 文件未通过校验，Message 可直接展示给用户
+type RejectError struct {
+	Reason  string
+	Message string
+}
+
+func (e *RejectError) Error() string { return e.Message }
+
+func reject(reason, format string, args ...interface{}) *RejectError {
+	return &RejectError{Reason: reason, Message: fmt.Sprintf(format, args...)}
+}
+
+// Policy 上传校验策略
+type Policy struct {
+	MaxSize map[string]int64 // 各类型的大小上限
+
+	// DOCX 解压限制
+	MaxDocxEntries      int     // 压缩包内最多文件数
+	MaxDocxUncompressed int64   // 解压后总大小上限
+	MaxDocxRatio        float64 // 单个文件的最大压缩比
+}
+
+// DefaultPolicy 默认策略：PDF、DOCX 10MB，DOC 5MB
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxSize: map[string]int64{
+			TypePDF:  10 << 20,
+			TypeDOCX: 10 << 20,
+			TypeDOC:  5 << 20,
+		},
+		MaxDocxEntries:      1000,
+		MaxDocxUncompressed: 100 << 20,
+		MaxDocxRatio:        100,
+	}
+}
+
+// PolicyFromEnv 从环境变量读取策略，未配置的项使用默认值
+//
+//	UPLOAD_MAX_PDF_MB / UPLOAD_MAX_DOCX_MB / UPLOAD_MAX_DOC_MB  各类型大小上限（MB），不超过 MaxFileSize
+//	UPLOAD_DOCX_MAX_ENTRIES                                    DOCX 内最多文件数
+//	UPLOAD_DOCX_MAX_UNCOMPRESSED_MB                            DOCX 解压后总大小上限（MB）
+//	UPLOAD_DOCX_MAX_RATIO                                      DOCX 单个文件的最大压缩比
+func PolicyFromEnv() Policy {
+	p := DefaultPolicy()
+	for typ, key := range map[string]string{TypePDF: "UPLOAD_MAX_PDF_MB", TypeDOCX: "UPLOAD_MAX_DOCX_MB", TypeDOC: "UPLOAD_MAX_DOC_MB"} {
+		if mb := envFloat(key); mb > 0 {
+			p.MaxSize[typ] = min(int64(mb*(1<<20)), MaxFileSize)
+		}
+	}
+	if n := envFloat("UPLOAD_DOCX_MAX_ENTRIES"); n > 0 {
+		p.MaxDocxEntries = int(n)
+	}
+	if mb := envFloat("UPLOAD_DOCX_MAX_UNCOMPRESSED_MB"); mb > 0 {
+		p.MaxDocxUncompressed = int64(mb * (1 << 20))
+	}
+	if ratio := envFloat("UPLOAD_DOCX_MAX_RATIO"); ratio > 0 {
+		p.MaxDocxRatio = ratio
+	}
+	return p
+}
+
+// MaxUploadSize 所有类型中最大的上限，用于读取内容前的快速拒绝
+func (p Policy) MaxUploadSize() int64 {
+	var largest int64
+	for _, size := range p.MaxSize {
+		largest = max(largest, size)
+	}
+	return largest
+}
+
+// Detect 按文件头识别文件类型，无法识别时返回空字符串
+func Detect(data []byte) string {
+	switch {
+	case isPDF(data):
+		return TypePDF
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		return TypeDOCX
+	case bytes.HasPrefix(data, []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}):
+		// OLE 复合文档，加密的 DOCX 也是这种格式
+		return TypeDOC
+	default:
+		return ""
+	}
+}
+
+// isPDF 与 PDF 阅读器一致，允许文件头前有少量垃圾字节
+func isPDF(data []byte) bool {
+	head := data[:min(len(data), 1024)]
+	return bytes.Contains(head, []byte("%PDF-"))
+}
+
+// Validate 校验文件内容，返回识别出的类型；filename 应为清理后的文件名
+func Validate(data []byte, filename string, p Policy) (string, error) {
+	typ := Detect(data)
+	if typ == "" {
+		return "", reject(ReasonType, "文件内容不是有效的 PDF、DOC 或 DOCX 文档")
+	}
+	if ext := strings.ToLower(path.Ext(filename)); ext != typ {
+		return "", reject(ReasonType, "文件内容与扩展名不符：实际为 %s 格式，请检查文件后重新上传", typeName(typ))
+	}
+
+	if limit := p.MaxSize[typ]; limit > 0 && int64(len(data)) > limit {
+		return "", reject(ReasonSize, "%s 文件大小不能超过 %s", typeName(typ), formatSize(limit))
+	}
+
+	if typ == TypeDOCX {
+		if err := checkDOCX(data, p); err != nil {
+			return "", err
+		}
+	}
+	return typ, nil
+}
+
+// checkDOCX 校验 DOCX 的压缩包结构，拒绝解压后体积异常的文件
+func checkDOCX(data []byte, p Policy) error {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return reject(ReasonArchive, "DOCX 文件已损坏，无法读取")
+	}
+	if p.MaxDocxEntries > 0 && len(zr.File) > p.MaxDocxEntries {
+		return reject(ReasonArchive, "DOCX 文件结构异常：包含 %d 个文件", len(zr.File))
+	}
+
+	var total uint64
+	var hasContentTypes, hasDocument bool
+	for _, f := range zr.File {
+		switch f.Name {
+		case "[Content_Types].xml":
+			hasContentTypes = true
+		case "word/document.xml":
+			hasDocument = true
+		}
+
+		total += f.UncompressedSize64
+		if p.MaxDocxUncompressed > 0 && total > uint64(p.MaxDocxUncompressed) {
+			return reject(ReasonArchive, "DOCX 文件解压后超过 %s，疑似压缩炸弹", formatSize(p.MaxDocxUncompressed))
+		}
+		// 小文件压缩比天然偏高，只检查解压后超过 1MB 的文件
+		if p.MaxDocxRatio > 0 && f.UncompressedSize64 > 1<<20 &&
+			float64(f.UncompressedSize64) > float64(f.CompressedSize64)*p.MaxDocxRatio {
+			return reject(ReasonArchive, "DOCX 文件压缩比异常，疑似压缩炸弹")
+		}
+	}
+	if !hasContentTypes || !hasDocument {
+		return reject(ReasonType, "文件不是有效的 Word 文档")
+	}
+	return nil
+}
+
+// maxNameRunes 文件名（不含扩展名）的最大字符数
+const maxNameRunes = 100
+
+// SanitizeFilename 清理用户上传的文件名：去掉目录、控制字符和文字方向控制符，替换系统保留字符并限制长度
+func SanitizeFilename(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r < 0x20 || r == 0x7f || r == utf8.RuneError:
+			return -1
+		case unicode.Is(unicode.Cf, r): // U+202E 等可伪装扩展名的控制符
+			return -1
+		case strings.ContainsRune(`/\:*?"<>|`, r):
+			return '_'
+		}
+		return r
+	}, name)
+	name = strings.TrimRight(strings.TrimSpace(name), " .")
+
+	ext := path.Ext(name)
+	if len(ext) > 10 || strings.ContainsRune(ext, ' ') {
+		ext = ""
+	}
+	base := strings.Trim(strings.TrimSuffix(name, ext), " .")
+	if runes := []rune(base); len(runes) > maxNameRunes {
+		base = string(runes[:maxNameRunes])
+	}
+	if base == "" {
+		base = "resume"
+	}
+	return base + strings.ToLower(ext)
+}
+
+func typeName(typ string) string {
+	return strings.ToUpper(strings.TrimPrefix(typ, "."))
+}
+
+func formatSize(n int64) string {
+	if n%(1<<20) == 0 {
+		return fmt.Sprintf("%dMB", n>>20)
+	}
+	return fmt.Sprintf("%.1fMB", float64(n)/(1<<20))
+}
+
+func envFloat(key string) float64 {
+	v, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
@@ -0,0 +1,220 @@
+package filecheck
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildZip 生成包含指定文件的压缩包
+func buildZip(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func validDOCX(t *testing.T) []byte {
+	return buildZip(t, map[string][]byte{
+		"[Content_Types].xml": []byte(`<Types/>`),
+		"word/document.xml":   []byte(`<w:document/>`),
+	})
+}
+
+var oleHeader = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1, 0, 0}
+
+func TestValidate(t *testing.T) {
+	pdf := []byte("%PDF-1.7\n1 0 obj\n%%EOF")
+	policy := DefaultPolicy()
+	policy.MaxSize[TypePDF] = 64
+
+	tests := []struct {
+		name     string
+		data     []byte
+		filename string
+		expected string
+		reason   string
+	}{
+		{name: "PDF", data: pdf, filename: "张三.pdf", expected: TypePDF},
+		{name: "PDF 文件头前有垃圾字节", data: append([]byte("\r\n"), pdf...), filename: "a.pdf", expected: TypePDF},
+		{name: "DOCX", data: validDOCX(t), filename: "a.docx", expected: TypeDOCX},
+		{name: "DOC", data: oleHeader, filename: "a.doc", expected: TypeDOC},
+		{name: "伪装成 PDF 的可执行文件", data: []byte("MZ\x90\x00"), filename: "a.pdf", reason: ReasonType},
+		{name: "扩展名与内容不符", data: pdf, filename: "a.docx", reason: ReasonType},
+		{name: "普通压缩包不是 DOCX", data: buildZip(t, map[string][]byte{"a.txt": []byte("x")}), filename: "a.docx", reason: ReasonType},
+		{name: "超过类型大小限制", data: append(pdf, make([]byte, 64)...), filename: "a.pdf", reason: ReasonSize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			typ, err := Validate(tt.data, tt.filename, policy)
+			if tt.reason != "" {
+				var rejected *RejectError
+				require.ErrorAs(t, err, &rejected)
+				assert.Equal(t, tt.reason, rejected.Reason)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, typ)
+		})
+	}
+}
+
+func TestValidateZipBomb(t *testing.T) {
+	policy := DefaultPolicy()
+
+	t.Run("压缩比异常", func(t *testing.T) {
+		data := buildZip(t, map[string][]byte{
+			"[Content_Types].xml": []byte(`<Types/>`),
+			"word/document.xml":   make([]byte, 8<<20),
+		})
+		_, err := Validate(data, "a.docx", policy)
+		var rejected *RejectError
+		require.ErrorAs(t, err, &rejected)
+		assert.Equal(t, ReasonArchive, rejected.Reason)
+	})
+
+	t.Run("文件数过多", func(t *testing.T) {
+		policy := DefaultPolicy()
+		policy.MaxDocxEntries = 3
+		files := map[string][]byte{"[Content_Types].xml": nil, "word/document.xml": nil}
+		for _, name := range []string{"a", "b", "c"} {
+			files["word/media/"+name] = nil
+		}
+		_, err := Validate(buildZip(t, files), "a.docx", policy)
+		var rejected *RejectError
+		require.ErrorAs(t, err, &rejected)
+		assert.Equal(t, ReasonArchive, rejected.Reason)
+	})
+}
+
+func TestSanitizeFilename(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "普通文件名", input: "张三 简历.pdf", expected: "张三 简历.pdf"},
+		{name: "扩展名转小写", input: "Resume.PDF", expected: "Resume.pdf"},
+		{name: "去掉目录", input: "../../etc/passwd.pdf", expected: "passwd.pdf"},
+		{name: "Windows 路径", input: `C:\Users\a\简历.docx`, expected: "简历.docx"},
+		{name: "保留字符", input: `a:b*c?.pdf`, expected: "a_b_c_.pdf"},
+		{name: "控制字符", input: "a\x00b\nc.pdf", expected: "abc.pdf"},
+		{name: "文字方向控制符", input: "resume\u202Efdp.exe", expected: "resumefdp.exe"},
+		{name: "只有扩展名", input: ".pdf", expected: "resume.pdf"},
+		{name: "空文件名", input: "", expected: "resume"},
+		{name: "过长文件名", input: strings.Repeat("长", 150) + ".pdf", expected: strings.Repeat("长", maxNameRunes) + ".pdf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, SanitizeFilename(tt.input))
+		})
+	}
+}
+
+// fakeClamd 按 INSTREAM 协议接收一个文件，内容包含 EICAR 时报告命中
+func fakeClamd(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				if cmd, err := r.ReadString(0); err != nil || cmd != "zINSTREAM\x00" {
+					io.WriteString(conn, "UNKNOWN COMMAND\x00")
+					return
+				}
+				var data bytes.Buffer
+				for {
+					var size uint32
+					if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+						return
+					}
+					if size == 0 {
+						break
+					}
+					io.CopyN(&data, r, int64(size))
+				}
+				if bytes.Contains(data.Bytes(), []byte("EICAR-STANDARD-ANTIVIRUS-TEST-FILE")) {
+					io.WriteString(conn, "stream: Eicar-Test-Signature FOUND\x00")
+					return
+				}
+				io.WriteString(conn, "stream: OK\x00")
+			}(conn)
+		}
+	}()
+	return "tcp://" + ln.Addr().String()
+}
+
+func TestClamdScanner(t *testing.T) {
+	s, err := NewClamdScanner(fakeClamd(t))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	t.Run("干净文件", func(t *testing.T) {
+		verdict, err := s.Scan(ctx, bytes.NewReader(bytes.Repeat([]byte("%PDF-1.4 "), 20000)))
+		assert.NoError(t, err)
+		assert.False(t, verdict.Infected)
+	})
+
+	t.Run("命中病毒", func(t *testing.T) {
+		verdict, err := s.Scan(ctx, strings.NewReader(`X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`))
+		assert.NoError(t, err)
+		assert.True(t, verdict.Infected)
+		assert.Equal(t, "Eicar-Test-Signature", verdict.Signature)
+	})
+
+	t.Run("服务不可用", func(t *testing.T) {
+		s, err := NewClamdScanner("tcp://127.0.0.1:1")
+		require.NoError(t, err)
+		_, err = s.Scan(ctx, strings.NewReader("x"))
+		assert.Error(t, err)
+	})
+}
+
+func TestNewClamdScanner(t *testing.T) {
+	tests := []struct {
+		addr    string
+		network string
+		address string
+	}{
+		{addr: "unix:///var/run/clamav/clamd.ctl", network: "unix", address: "/var/run/clamav/clamd.ctl"},
+		{addr: "/tmp/clamd.sock", network: "unix", address: "/tmp/clamd.sock"},
+		{addr: "tcp://clamav:3310", network: "tcp", address: "clamav:3310"},
+		{addr: "clamav:3310", network: "tcp", address: "clamav:3310"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			s, err := NewClamdScanner(tt.addr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.network, s.Network)
+			assert.Equal(t, tt.address, s.Address)
+		})
+	}
+}
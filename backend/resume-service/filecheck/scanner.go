@@ -0,0 +1,144 @@
+package filecheck
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// Verdict 病毒扫描结果
+type Verdict struct {
+	Infected  bool
+	Signature string // 命中的病毒特征名
+}
+
+// Scanner 病毒扫描器
+type Scanner interface {
+	Scan(ctx context.Context, r io.Reader) (Verdict, error)
+}
+
+// NopScanner 未配置扫描服务时使用，所有文件视为干净
+type NopScanner struct{}
+
+func (NopScanner) Scan(context.Context, io.Reader) (Verdict, error) { return Verdict{}, nil }
+
+// ScannerFromEnv 按 CLAMD_ADDRESS 创建扫描器，未配置时返回 NopScanner
+//
+//	CLAMD_ADDRESS  clamd 地址：unix:///var/run/clamav/clamd.ctl、/var/run/clamav/clamd.ctl 或 tcp://clamav:3310
+//	CLAMD_TIMEOUT  单个文件的扫描超时，默认 30s
+func ScannerFromEnv() (Scanner, error) {
+	addr := os.Getenv("CLAMD_ADDRESS")
+	if addr == "" {
+		return NopScanner{}, nil
+	}
+	s, err := NewClamdScanner(addr)
+	if err != nil {
+		return nil, err
+	}
+	if timeout, err := time.ParseDuration(os.Getenv("CLAMD_TIMEOUT")); err == nil && timeout > 0 {
+		s.Timeout = timeout
+	}
+	return s, nil
+}
+
+// clamdChunkSize INSTREAM 每次发送的数据块大小，需小于 clamd 的 StreamMaxLength
+const clamdChunkSize = 64 << 10
+
+// ClamdScanner 通过 clamd 的 INSTREAM 命令扫描文件
+type ClamdScanner struct {
+	Network string // unix 或 tcp
+	Address string
+	Timeout time.Duration
+}
+
+// NewClamdScanner 解析 clamd 地址
+func NewClamdScanner(addr string) (*ClamdScanner, error) {
+	s := &ClamdScanner{Timeout: 30 * time.Second}
+	switch {
+	case strings.HasPrefix(addr, "unix://"):
+		s.Network, s.Address = "unix", strings.TrimPrefix(addr, "unix://")
+	case strings.HasPrefix(addr, "tcp://"):
+		s.Network, s.Address = "tcp", strings.TrimPrefix(addr, "tcp://")
+	case strings.HasPrefix(addr, "/"):
+		s.Network, s.Address = "unix", addr
+	default:
+		s.Network, s.Address = "tcp", addr
+	}
+	if s.Address == "" {
+		return nil, errors.New("filecheck: empty clamd address")
+	}
+	return s, nil
+}
+
+// Scan 将内容以 INSTREAM 方式发送给 clamd，应答形如 "stream: OK" 或 "stream: Eicar-Signature FOUND"
+func (s *ClamdScanner) Scan(ctx context.Context, r io.Reader) (Verdict, error) {
+	if s.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.Timeout)
+		defer cancel()
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, s.Network, s.Address)
+	if err != nil {
+		return Verdict{}, fmt.Errorf("clamd: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if _, err := io.WriteString(conn, "zINSTREAM\x00"); err != nil {
+		return Verdict{}, fmt.Errorf("clamd: %w", err)
+	}
+	buf := make([]byte, clamdChunkSize)
+	var size [4]byte
+	for {
+		n, readErr := r.Read(buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size[:], uint32(n))
+			if _, err := conn.Write(size[:]); err != nil {
+				return Verdict{}, fmt.Errorf("clamd: %w", err)
+			}
+			if _, err := conn.Write(buf[:n]); err != nil {
+				return Verdict{}, fmt.Errorf("clamd: %w", err)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return Verdict{}, readErr
+		}
+	}
+	// 长度为 0 的数据块表示结束
+	binary.BigEndian.PutUint32(size[:], 0)
+	if _, err := conn.Write(size[:]); err != nil {
+		return Verdict{}, fmt.Errorf("clamd: %w", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && reply == "" {
+		return Verdict{}, fmt.Errorf("clamd: %w", err)
+	}
+	return parseClamdReply(strings.TrimRight(reply, "\x00\n"))
+}
+
+func parseClamdReply(reply string) (Verdict, error) {
+	result := strings.TrimSpace(strings.TrimPrefix(reply, "stream:"))
+	switch {
+	case result == "OK":
+		return Verdict{}, nil
+	case strings.HasSuffix(result, " FOUND"):
+		return Verdict{Infected: true, Signature: strings.TrimSuffix(result, " FOUND")}, nil
+	default:
+		return Verdict{}, fmt.Errorf("clamd: %s", reply)
+	}
+}
@@ -2,45 +2,69 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
 	"io"
 	"net/http"
 	"os"
-	"path/filepath"
+	"resume-service/evalcache"
+	"resume-service/evalqueue"
 	"resume-service/evaluator"
+	"resume-service/filecheck"
 	"resume-service/models"
 	"strconv"
 	"time"
 
+	"common/blind"
+	"common/storage"
+
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
 // AIEvaluateHandler AI 评估处理器
 type AIEvaluateHandler struct {
-	DB        *gorm.DB
-	Evaluator *evaluator.CozeEvaluator
+	DB         *gorm.DB
+	Evaluators *evaluator.Registry // 可用的评估服务，按职位、部门配置选择
+	Fallback   evaluator.Evaluator // 评估服务未配置或调用失败时改用的离线规则评分，为 nil 时不兜底
+	Storage    storage.Storage
+	Queue      *evalqueue.Queue // 批量评估队列
+	Cache      *evalcache.Cache // 评估结果缓存，相同简历和 JD 在有效期内不重复调用评估服务
+	Blind      blind.Policy     // 可查看盲筛中候选人身份信息的角色
 }
 
-// NewAIEvaluateHandler 创建 AI 评估处理器
-func NewAIEvaluateHandler(db *gorm.DB) *AIEvaluateHandler {
-	return &AIEvaluateHandler{
-		DB:        db,
-		Evaluator: evaluator.NewCozeEvaluator(),
+// NewAIEvaluateHandler 创建 AI 评估处理器，设置 EVAL_RULES_FALLBACK=false 关闭离线规则评分兜底
+func NewAIEvaluateHandler(db *gorm.DB, store storage.Storage) *AIEvaluateHandler {
+	h := &AIEvaluateHandler{
+		DB:         db,
+		Evaluators: evaluator.NewRegistryFromEnv(),
+		Storage:    store,
+		Cache:      evalcache.NewFromEnv(db),
+		Blind:      blind.PolicyFromEnv(),
+	}
+	if os.Getenv("EVAL_RULES_FALLBACK") != "false" {
+		h.Fallback, _ = h.Evaluators.Resolve("rules", "")
 	}
+	return h
 }
 
 // AIEvaluateRequest AI 评估请求
 type AIEvaluateRequest struct {
 	ResumeID      uint   `json:"resume_id"`      // 简历ID（从数据库获取）
 	JDText        string `json:"jd_text"`        // 职位描述
+	JobID         uint   `json:"job_id"`         // 关联职位，未提供 jd_text 时使用职位当前版本的描述
 	CandidateName string `json:"candidate_name"` // 候选人姓名
+	Provider      string `json:"provider"`       // 指定评估服务，如 rules；为空时按职位配置选择
+	Force         bool   `json:"force"`          // 强制重新评估，不复用缓存结果
 }
 
 // AIEvaluateResponse AI 评估响应
 type AIEvaluateResponse struct {
+	EvaluationID    uint     `json:"evaluation_id,omitempty"` // 评估记录，上传文件直接评估时为空
 	ResumeID        uint     `json:"resume_id"`
 	CandidateName   string   `json:"candidate_name"`
+	JobID           uint     `json:"job_id,omitempty"`
+	JobVersion      int      `json:"job_version,omitempty"` // 评估所依据的职位版本
+	Provider        string   `json:"provider"`              // 实际使用的评估服务，兜底时为 rules
+	Cached          bool     `json:"cached"`                // 结果复用自有效期内相同简历和 JD 的评估
 	TotalScore      float64  `json:"total_score"`
 	Grade           string   `json:"grade"`
 	JDMatchScore    int      `json:"jd_match_score"`
@@ -54,17 +78,30 @@ type AIEvaluateResponse struct {
 	MatchedSkills   []string `json:"matched_skills"`
 	MissingSkills   []string `json:"missing_skills"`
 	Summary         string   `json:"summary"`
+	Redacted        bool     `json:"redacted,omitempty"` // 盲筛中，候选人姓名及评语中的身份信息已隐藏
 }
 
-// CheckAIConfig 检查 AI 配置状态
+// CheckAIConfig 检查 AI 配置状态，指定 job_id 时返回该职位使用的评估服务
 func (h *AIEvaluateHandler) CheckAIConfig(c *gin.Context) {
-	configured := h.Evaluator.IsConfigured()
+	jobID, _ := strconv.Atoi(c.Query("job_id"))
+	ev, err := h.configuredEvaluator(uint(jobID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	fallback := ""
+	if h.Fallback != nil {
+		fallback = h.Fallback.Provider()
+	}
 	c.JSON(http.StatusOK, gin.H{
 		"code":    0,
 		"message": "success",
 		"data": gin.H{
-			"configured": configured,
-			"provider":   "coze",
+			"configured": ev.IsConfigured(),
+			"provider":   ev.Provider(),
+			"model":      ev.Model(),
+			"fallback":   fallback, // 未配置或调用失败时改用的评估服务，为空表示不兜底
+			"providers":  h.Evaluators.Providers(),
 		},
 	})
 }
@@ -77,15 +114,6 @@ func (h *AIEvaluateHandler) EvaluateByResumeID(c *gin.Context) {
 		return
 	}
 
-	// 检查 AI 是否配置
-	if !h.Evaluator.IsConfigured() {
-		c.JSON(http.StatusServiceUnavailable, gin.H{
-			"code":    503,
-			"message": "AI 服务未配置，请设置 COZE_TOKEN 和 COZE_WORKFLOW_ID 环境变量",
-		})
-		return
-	}
-
 	// 获取简历信息
 	var resume models.Resume
 	if err := h.DB.First(&resume, req.ResumeID).Error; err != nil {
@@ -93,71 +121,83 @@ func (h *AIEvaluateHandler) EvaluateByResumeID(c *gin.Context) {
 		return
 	}
 
+	jdText, job, err := h.resolveJD(req.JDText, req.JobID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "职位不存在"})
+		return
+	}
+	jdText = h.withScreening(jdText, resume.ID, job.ID)
+
+	// 检查 AI 是否配置
+	ev, ok := h.requireEvaluator(c, job.ID, req.Provider)
+	if !ok {
+		return
+	}
+
 	// 读取简历文件
-	pdfBytes, err := os.ReadFile(resume.FilePath)
+	pdfBytes, err := readResumeFile(c.Request.Context(), h.Storage, &resume)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "读取简历文件失败"})
 		return
 	}
 
-	// 调用 AI 评估
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Minute)
-	defer cancel()
-
 	candidateName := req.CandidateName
 	if candidateName == "" {
 		candidateName = resume.FileName
 	}
 
-	result, err := h.Evaluator.EvaluateResume(ctx, candidateName, req.JDText, pdfBytes)
+	// 相同简历内容和 JD 在缓存有效期内评估过时直接复用结果
+	h.ensureContentHash(&resume, pdfBytes)
+	if cached := h.cachedEvaluation(resume.ContentHash, jdText, ev, req.Force); cached != nil {
+		evaluation, err := h.reuseEvaluation(cached, &resume, job, jdText, candidateName)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "保存评估结果失败"})
+			return
+		}
+		if err := redactEvaluations(c, h.DB, h.Blind, evaluation); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "查询盲筛状态失败"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"code":    0,
+			"message": "评估成功",
+			"data":    evaluationResponse(evaluation),
+		})
+		return
+	}
+
+	// 调用 AI 评估
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Minute)
+	defer cancel()
+
+	started := time.Now()
+	result, ev, err := h.evaluate(ctx, ev, candidateName, jdText, pdfBytes, req.Provider == "")
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "AI 评估失败: " + err.Error()})
 		return
 	}
 
-	// 更新简历的匹配分数
-	resume.MatchScore = int(result.TotalScore)
-	resume.Status = "evaluated"
-	if resultJSON, err := json.Marshal(result); err == nil {
-		resume.ParsedData = string(resultJSON)
+	// 保存评估记录
+	evaluation, err := h.recordEvaluation(ev, &resume, job, jdText, candidateName, result, time.Since(started))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "保存评估结果失败"})
+		return
+	}
+	if err := redactEvaluations(c, h.DB, h.Blind, evaluation); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询盲筛状态失败"})
+		return
 	}
-	h.DB.Save(&resume)
 
 	// 返回评估结果
 	c.JSON(http.StatusOK, gin.H{
 		"code":    0,
 		"message": "评估成功",
-		"data": AIEvaluateResponse{
-			ResumeID:        resume.ID,
-			CandidateName:   candidateName,
-			TotalScore:      result.TotalScore,
-			Grade:           result.Grade,
-			JDMatchScore:    result.JDMatchScore,
-			AgeScore:        result.AgeScore,
-			ExperienceScore: result.ExperienceScore,
-			EducationScore:  result.EducationScore,
-			CompanyScore:    result.CompanyScore,
-			TechScore:       result.TechScore,
-			ProjectScore:    result.ProjectScore,
-			Recommendation:  result.Recommendation,
-			MatchedSkills:   result.MatchedSkills,
-			MissingSkills:   result.MissingSkills,
-			Summary:         result.Summary,
-		},
+		"data":    evaluationResponse(evaluation),
 	})
 }
 
 // EvaluateUploadedFile 上传文件并进行AI评估
 func (h *AIEvaluateHandler) EvaluateUploadedFile(c *gin.Context) {
-	// 检查 AI 是否配置
-	if !h.Evaluator.IsConfigured() {
-		c.JSON(http.StatusServiceUnavailable, gin.H{
-			"code":    503,
-			"message": "AI 服务未配置，请设置 COZE_TOKEN 和 COZE_WORKFLOW_ID 环境变量",
-		})
-		return
-	}
-
 	// 获取上传的文件
 	file, header, err := c.Request.FormFile("file")
 	if err != nil {
@@ -166,32 +206,63 @@ func (h *AIEvaluateHandler) EvaluateUploadedFile(c *gin.Context) {
 	}
 	defer file.Close()
 
-	// 检查文件类型
-	ext := filepath.Ext(header.Filename)
-	if ext != ".pdf" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "目前只支持 PDF 格式的简历"})
+	// 读取文件内容并按内容校验是否为 PDF
+	pdfBytes, err := io.ReadAll(io.LimitReader(file, filecheck.MaxFileSize+1))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "读取文件失败"})
 		return
 	}
-
-	// 读取文件内容
-	pdfBytes, err := io.ReadAll(file)
+	typ, err := filecheck.Validate(pdfBytes, filecheck.SanitizeFilename(header.Filename), filecheck.PolicyFromEnv())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "读取文件失败"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if typ != filecheck.TypePDF {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "目前只支持 PDF 格式的简历"})
 		return
 	}
 
 	// 获取其他参数
-	jdText := c.PostForm("jd_text")
+	jobID, _ := strconv.Atoi(c.PostForm("job_id"))
+	jdText, job, err := h.resolveJD(c.PostForm("jd_text"), uint(jobID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "职位不存在"})
+		return
+	}
 	candidateName := c.PostForm("candidate_name")
 	if candidateName == "" {
 		candidateName = header.Filename
 	}
 
+	// 检查 AI 是否配置
+	provider := c.PostForm("provider")
+	ev, ok := h.requireEvaluator(c, job.ID, provider)
+	if !ok {
+		return
+	}
+
+	// 上传的文件不保存评估记录，只能复用已保存的相同简历的评估，自身结果不进入缓存
+	if cached := h.cachedEvaluation(contentHash(pdfBytes), jdText, ev, c.PostForm("force") == "true"); cached != nil {
+		resp := evaluationResponse(cached)
+		resp.EvaluationID = 0
+		resp.ResumeID = 0
+		resp.CandidateName = candidateName
+		resp.JobID = job.ID
+		resp.JobVersion = job.Version
+		resp.Cached = true
+		c.JSON(http.StatusOK, gin.H{
+			"code":    0,
+			"message": "评估成功",
+			"data":    resp,
+		})
+		return
+	}
+
 	// 调用 AI 评估
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Minute)
 	defer cancel()
 
-	result, err := h.Evaluator.EvaluateResume(ctx, candidateName, jdText, pdfBytes)
+	result, ev, err := h.evaluate(ctx, ev, candidateName, jdText, pdfBytes, provider == "")
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "AI 评估失败: " + err.Error()})
 		return
@@ -203,6 +274,9 @@ func (h *AIEvaluateHandler) EvaluateUploadedFile(c *gin.Context) {
 		"message": "评估成功",
 		"data": AIEvaluateResponse{
 			CandidateName:   candidateName,
+			JobID:           job.ID,
+			JobVersion:      job.Version,
+			Provider:        ev.Provider(),
 			TotalScore:      result.TotalScore,
 			Grade:           result.Grade,
 			JDMatchScore:    result.JDMatchScore,
@@ -220,87 +294,16 @@ func (h *AIEvaluateHandler) EvaluateUploadedFile(c *gin.Context) {
 	})
 }
 
-// BatchEvaluate 批量评估简历
-func (h *AIEvaluateHandler) BatchEvaluate(c *gin.Context) {
-	var req struct {
-		ResumeIDs []uint `json:"resume_ids" binding:"required"`
-		JDText    string `json:"jd_text"`
-	}
-
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	// 检查 AI 是否配置
-	if !h.Evaluator.IsConfigured() {
-		c.JSON(http.StatusServiceUnavailable, gin.H{
-			"code":    503,
-			"message": "AI 服务未配置",
-		})
-		return
-	}
-
-	// 获取所有简历
-	var resumes []models.Resume
-	if err := h.DB.Where("id IN ?", req.ResumeIDs).Find(&resumes).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取简历失败"})
-		return
-	}
-
-	results := make([]AIEvaluateResponse, 0)
-	errors := make([]string, 0)
-
-	for _, resume := range resumes {
-		// 读取简历文件
-		pdfBytes, err := os.ReadFile(resume.FilePath)
-		if err != nil {
-			errors = append(errors, "简历 "+strconv.Itoa(int(resume.ID))+" 文件读取失败")
-			continue
-		}
-
-		// 调用 AI 评估
-		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Minute)
-		result, err := h.Evaluator.EvaluateResume(ctx, resume.FileName, req.JDText, pdfBytes)
-		cancel()
-
-		if err != nil {
-			errors = append(errors, "简历 "+strconv.Itoa(int(resume.ID))+" 评估失败: "+err.Error())
-			continue
-		}
-
-		// 更新简历
-		resume.MatchScore = int(result.TotalScore)
-		resume.Status = "evaluated"
-		if resultJSON, err := json.Marshal(result); err == nil {
-			resume.ParsedData = string(resultJSON)
-		}
-		h.DB.Save(&resume)
-
-		results = append(results, AIEvaluateResponse{
-			ResumeID:       resume.ID,
-			CandidateName:  resume.FileName,
-			TotalScore:     result.TotalScore,
-			Grade:          result.Grade,
-			JDMatchScore:   result.JDMatchScore,
-			Recommendation: result.Recommendation,
-		})
-	}
-
+// CacheStats 评估结果缓存的命中统计
+func (h *AIEvaluateHandler) CacheStats(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"code":    0,
-		"message": "批量评估完成",
-		"data": gin.H{
-			"results": results,
-			"errors":  errors,
-			"total":   len(req.ResumeIDs),
-			"success": len(results),
-			"failed":  len(errors),
-		},
+		"message": "success",
+		"data":    h.Cache.Stats(),
 	})
 }
 
-// GetEvaluationResult 获取评估结果
+// GetEvaluationResult 获取简历最近一次评估结果，历史记录见 ListResumeEvaluations
 func (h *AIEvaluateHandler) GetEvaluationResult(c *gin.Context) {
 	id := c.Param("id")
 	var resume models.Resume
@@ -310,20 +313,51 @@ func (h *AIEvaluateHandler) GetEvaluationResult(c *gin.Context) {
 		return
 	}
 
-	if resume.ParsedData == "" {
+	evaluation, err := latestEvaluation(h.DB, resume.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询评估结果失败"})
+		return
+	}
+	if evaluation == nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "该简历尚未进行 AI 评估"})
 		return
 	}
-
-	var result evaluator.EvaluationResult
-	if err := json.Unmarshal([]byte(resume.ParsedData), &result); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "解析评估结果失败"})
+	if err := redactEvaluations(c, h.DB, h.Blind, evaluation); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询盲筛状态失败"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"code":    0,
 		"message": "success",
-		"data":    result,
+		"data":    evaluation,
 	})
 }
+
+// resolveJD 确定评估使用的 JD：优先使用请求中的 jd_text，否则使用职位当前版本的描述。
+// 指定职位时返回其版本号，用于记录评估所依据的职位版本
+func (h *AIEvaluateHandler) resolveJD(jdText string, jobID uint) (string, jobContent, error) {
+	if jobID == 0 {
+		return jdText, jobContent{}, nil
+	}
+	job, err := loadJobContent(h.DB, jobID)
+	if err != nil {
+		return "", job, err
+	}
+	if jdText == "" {
+		jdText = job.JDText()
+	}
+	return jdText, job, nil
+}
+
+// setEvaluatedJob 记录简历最近一次评估所依据的职位版本
+func setEvaluatedJob(resume *models.Resume, job jobContent) {
+	if job.ID == 0 {
+		resume.EvaluatedJobID = nil
+		resume.EvaluatedJobVersion = 0
+		return
+	}
+	jobID := job.ID
+	resume.EvaluatedJobID = &jobID
+	resume.EvaluatedJobVersion = job.Version
+}
@@ -0,0 +1,482 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"resume-service/models"
+	"strconv"
+	"time"
+
+	"common/headcount"
+	"common/pipeline"
+	"common/screening"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxBulkApplications 批量操作单次最多处理的申请数
+const maxBulkApplications = 200
+
+// stageMove 一次阶段流转请求
+type stageMove struct {
+	To            string
+	Reason        string
+	RejectionCode string
+	ActorID       *uint
+	ActorName     string
+	Force         bool // 跳过流程的流转规则，用于候选人撤回申请和筛选问题自动淘汰
+}
+
+// moveError 流转不符合流程或名额限制，返回 400
+type moveError struct{ error }
+
+// currentActor 从可选 JWT 上下文读取操作人
+func currentActor(c *gin.Context) (*uint, string) {
+	v, ok := c.Get("user_id")
+	if !ok {
+		return nil, ""
+	}
+	id, ok := v.(uint)
+	if !ok {
+		return nil, ""
+	}
+	return &id, c.GetString("username")
+}
+
+// checkMove 校验流转是否符合流程配置；淘汰必须选择原因代码
+func checkMove(config pipeline.Config, from string, move stageMove) error {
+	if err := config.CanMove(from, move.To); err != nil {
+		return err
+	}
+	if move.To == pipeline.StageRejected && !pipeline.ValidRejectionCode(move.RejectionCode) {
+		return errors.New("淘汰候选人需选择有效的淘汰原因")
+	}
+	return nil
+}
+
+// moveApplication 在事务中将申请流转到新阶段并记录历史；录用时检查职位名额并同步录用人数
+func moveApplication(tx *gorm.DB, app *models.Application, move stageMove) error {
+	config, _, err := pipeline.Load(tx, app.JobID)
+	if err != nil {
+		return err
+	}
+	if !move.Force {
+		if err := checkMove(config, app.Status, move); err != nil {
+			return moveError{err}
+		}
+	}
+	if move.To != pipeline.StageRejected {
+		move.RejectionCode = ""
+	}
+
+	wasHired := headcount.IsHired(app.Status)
+	isHired := headcount.IsHired(move.To)
+	if !wasHired && isHired {
+		if err := headcount.CheckOpening(tx, app.JobID); err != nil {
+			if errors.Is(err, headcount.ErrNoOpenings) {
+				return moveError{err}
+			}
+			return err
+		}
+		now := time.Now()
+		app.HiredAt = &now
+	} else if wasHired && !isHired {
+		app.HiredAt = nil
+	}
+
+	from := app.Status
+	app.Status = move.To
+	if err := tx.Model(app).Select("status", "hired_at").Updates(app).Error; err != nil {
+		return err
+	}
+	transition := models.StageTransition{
+		ApplicationID: app.ID,
+		JobID:         app.JobID,
+		FromStage:     from,
+		ToStage:       move.To,
+		ActorID:       move.ActorID,
+		ActorName:     move.ActorName,
+		Reason:        move.Reason,
+		RejectionCode: move.RejectionCode,
+	}
+	if err := tx.Create(&transition).Error; err != nil {
+		return err
+	}
+
+	// 录用状态变化时同步职位已录用人数，招满自动关闭
+	if wasHired != isHired {
+		return headcount.SyncJob(tx, app.JobID)
+	}
+	return nil
+}
+
+// errDuplicateApplication 候选人对该职位已有未撤回的申请
+var errDuplicateApplication = errors.New("duplicate application")
+
+// insertApplication 在事务中创建申请：拒绝重复申请，记录申请时的职位版本，
+// 新申请一律进入职位流程的初始阶段并记录流转。回答了筛选问题时按淘汰条件评估，
+// 不满足淘汰条件的由系统直接淘汰；回答须已按职位的问题校验过（见 screening.Questions.Check）
+func insertApplication(tx *gorm.DB, app *models.Application, actorID *uint, actorName string) error {
+	if app.TalentID != 0 {
+		// 锁定人才，避免并发提交产生重复申请
+		var talent struct{ ID uint }
+		err := tx.Table("talents").Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", app.TalentID).Take(&talent).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&models.Application{}).
+			Where("job_id = ? AND talent_id = ? AND status <> ?", app.JobID, app.TalentID, pipeline.StageWithdrawn).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return errDuplicateApplication
+		}
+	}
+
+	app.JobVersion = 0
+	if job, err := loadJobContent(tx, app.JobID); err == nil {
+		app.JobVersion = job.Version
+	}
+	config, _, err := pipeline.Load(tx, app.JobID)
+	if err != nil {
+		return err
+	}
+	app.Status = config.Entry()
+	app.HiredAt = nil
+
+	app.Screening = nil
+	if len(app.Answers) > 0 {
+		questions, err := screening.Load(tx, app.JobID)
+		if err != nil {
+			return err
+		}
+		if len(questions) > 0 {
+			result := screening.Evaluate(questions, app.Answers, time.Now())
+			app.Screening = &result
+		}
+	}
+
+	if err := tx.Create(app).Error; err != nil {
+		return err
+	}
+	if err := tx.Create(&models.StageTransition{
+		ApplicationID: app.ID,
+		JobID:         app.JobID,
+		ToStage:       app.Status,
+		ActorID:       actorID,
+		ActorName:     actorName,
+	}).Error; err != nil {
+		return err
+	}
+
+	if app.Screening == nil || app.Screening.Outcome != screening.OutcomeRejected {
+		return nil
+	}
+	return moveApplication(tx, app, stageMove{
+		To:            pipeline.StageRejected,
+		Reason:        "筛选问题未通过：" + app.Screening.Failed(),
+		RejectionCode: pipeline.RejectionScreening,
+		ActorName:     "系统",
+		Force:         true,
+	})
+}
+
+// lockApplication 在事务中加锁读取申请，避免并发流转
+func lockApplication(tx *gorm.DB, id interface{}) (*models.Application, error) {
+	var app models.Application
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&app, id).Error; err != nil {
+		return nil, err
+	}
+	return &app, nil
+}
+
+// moveStatus 流转失败时的 HTTP 状态码
+func moveStatus(err error) int {
+	var me moveError
+	switch {
+	case errors.As(err, &me):
+		return http.StatusBadRequest
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// GetApplicationPipeline 获取职位的招聘流程及淘汰原因，未指定职位时返回默认流程
+func (h *ResumeHandler) GetApplicationPipeline(c *gin.Context) {
+	config := pipeline.Default()
+	custom := false
+	if jobID, err := strconv.ParseUint(c.Query("job_id"), 10, 64); err == nil {
+		if config, custom, err = pipeline.Load(h.DB, uint(jobID)); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "读取招聘流程失败"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"custom":            custom,
+			"config":            config,
+			"rejection_reasons": pipeline.RejectionReasons,
+		},
+	})
+}
+
+// GetApplicationHistory 获取申请的阶段流转历史
+func (h *ResumeHandler) GetApplicationHistory(c *gin.Context) {
+	var app models.Application
+	if err := h.DB.First(&app, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "Application not found"})
+		return
+	}
+
+	var history []models.StageTransition
+	if err := h.DB.Where("application_id = ?", app.ID).Order("created_at, id").Find(&history).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "查询流转历史失败"})
+		return
+	}
+	config, _, err := pipeline.Load(h.DB, app.JobID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "读取招聘流程失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"application_id": app.ID,
+			"status":         app.Status,
+			"history":        history,
+			"pipeline":       config,
+		},
+	})
+}
+
+// BulkMoveRequest 批量流转请求
+type BulkMoveRequest struct {
+	IDs           []uint `json:"ids" binding:"required"`
+	Stage         string `json:"stage"`
+	Reason        string `json:"reason"`
+	RejectionCode string `json:"rejection_code"`
+}
+
+// BulkMoveApplications 批量将申请流转到同一阶段，逐条校验，部分失败不影响其他申请
+func (h *ResumeHandler) BulkMoveApplications(c *gin.Context) {
+	var req BulkMoveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": err.Error()})
+		return
+	}
+	if req.Stage == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "请选择目标阶段"})
+		return
+	}
+	h.bulkMove(c, req)
+}
+
+// BulkRejectApplications 批量淘汰申请，必须选择淘汰原因
+func (h *ResumeHandler) BulkRejectApplications(c *gin.Context) {
+	var req BulkMoveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": err.Error()})
+		return
+	}
+	if !pipeline.ValidRejectionCode(req.RejectionCode) {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "请选择有效的淘汰原因"})
+		return
+	}
+	req.Stage = pipeline.StageRejected
+	h.bulkMove(c, req)
+}
+
+func (h *ResumeHandler) bulkMove(c *gin.Context, req BulkMoveRequest) {
+	if len(req.IDs) == 0 || len(req.IDs) > maxBulkApplications {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "每次需选择 1 到 " + strconv.Itoa(maxBulkApplications) + " 个申请"})
+		return
+	}
+
+	actorID, actorName := currentActor(c)
+	move := stageMove{To: req.Stage, Reason: req.Reason, RejectionCode: req.RejectionCode, ActorID: actorID, ActorName: actorName}
+
+	type failure struct {
+		ID      uint   `json:"id"`
+		Message string `json:"message"`
+	}
+	succeeded := make([]uint, 0, len(req.IDs))
+	failed := make([]failure, 0)
+	seen := make(map[uint]bool, len(req.IDs))
+	for _, id := range req.IDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		err := h.DB.Transaction(func(tx *gorm.DB) error {
+			app, err := lockApplication(tx, id)
+			if err != nil {
+				return err
+			}
+			return moveApplication(tx, app, move)
+		})
+		switch {
+		case err == nil:
+			succeeded = append(succeeded, id)
+		case moveStatus(err) == http.StatusBadRequest:
+			failed = append(failed, failure{ID: id, Message: err.Error()})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			failed = append(failed, failure{ID: id, Message: "申请不存在"})
+		default:
+			log.Printf("[流程] 批量流转申请 %d 失败: %v", id, err)
+			failed = append(failed, failure{ID: id, Message: "更新失败"})
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"succeeded": succeeded,
+			"failed":    failed,
+		},
+	})
+}
+
+// analyticsHistory 按职位和申请时间筛选申请的流转历史；指定职位时使用其流程，否则使用默认流程
+func (h *ResumeHandler) analyticsHistory(c *gin.Context) (pipeline.Config, *gorm.DB, []pipeline.Transition, bool) {
+	config := pipeline.Default()
+	apps := h.DB.Model(&models.Application{}).Select("id")
+	if v := c.Query("job_id"); v != "" {
+		jobID, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "job_id 无效"})
+			return config, nil, nil, false
+		}
+		if config, _, err = pipeline.Load(h.DB, uint(jobID)); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "读取招聘流程失败"})
+			return config, nil, nil, false
+		}
+		apps = apps.Where("job_id = ?", jobID)
+	}
+	for param, op := range map[string]string{"start": ">=", "end": "<"} {
+		if v := c.Query(param); v != "" {
+			t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": param + " 格式应为 2006-01-02"})
+				return config, nil, nil, false
+			}
+			apps = apps.Where("created_at "+op+" ?", t)
+		}
+	}
+
+	var history []pipeline.Transition
+	err := h.DB.Model(&models.StageTransition{}).
+		Select("application_id, from_stage, to_stage, created_at").
+		Where("application_id IN (?)", apps).
+		Order("application_id, created_at, id").
+		Scan(&history).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "查询流转历史失败"})
+		return config, nil, nil, false
+	}
+	return config, apps, history, true
+}
+
+// GetPipelineFunnel 按流转历史统计招聘漏斗及淘汰原因分布
+func (h *ResumeHandler) GetPipelineFunnel(c *gin.Context) {
+	config, apps, history, ok := h.analyticsHistory(c)
+	if !ok {
+		return
+	}
+
+	type reasonCount struct {
+		Code  string `json:"code"`
+		Label string `json:"label"`
+		Count int64  `json:"count"`
+	}
+	var counts []reasonCount
+	h.DB.Model(&models.StageTransition{}).
+		Select("rejection_code AS code, COUNT(DISTINCT application_id) AS count").
+		Where("to_stage = ? AND application_id IN (?)", pipeline.StageRejected, apps).
+		Group("rejection_code").Order("count DESC").Scan(&counts)
+	for i := range counts {
+		counts[i].Label = counts[i].Code
+		for _, r := range pipeline.RejectionReasons {
+			if r.Code == counts[i].Code {
+				counts[i].Label = r.Label
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"stages":     pipeline.Funnel(config, history),
+			"rejections": counts,
+		},
+	})
+}
+
+// GetTimeInStage 按流转历史统计各阶段停留时长
+func (h *ResumeHandler) GetTimeInStage(c *gin.Context) {
+	config, _, history, ok := h.analyticsHistory(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data":    pipeline.TimeInStage(config, history, time.Now()),
+	})
+}
+
+// BackfillStageHistory 为没有流转记录的历史申请补录：申请时间进入初始阶段，
+// 当前状态不是初始阶段时再补一条流转到当前状态的记录（录用时间或最后更新时间）
+func BackfillStageHistory(db *gorm.DB) {
+	var apps []models.Application
+	err := db.Where("NOT EXISTS (SELECT 1 FROM application_stage_transitions t WHERE t.application_id = applications.id)").
+		Find(&apps).Error
+	if err != nil {
+		log.Printf("Failed to load applications for stage history backfill: %v", err)
+		return
+	}
+	if len(apps) == 0 {
+		return
+	}
+
+	entry := pipeline.Default().Entry()
+	transitions := make([]models.StageTransition, 0, len(apps))
+	for _, app := range apps {
+		transitions = append(transitions, models.StageTransition{
+			CreatedAt: app.CreatedAt, ApplicationID: app.ID, JobID: app.JobID,
+			ToStage: entry, ActorName: "系统", Reason: "历史数据补录",
+		})
+		status := pipeline.Normalize(app.Status)
+		if status == "" || status == entry {
+			continue
+		}
+		at := app.UpdatedAt
+		if app.HiredAt != nil {
+			at = *app.HiredAt
+		}
+		transitions = append(transitions, models.StageTransition{
+			CreatedAt: at, ApplicationID: app.ID, JobID: app.JobID,
+			FromStage: entry, ToStage: status, ActorName: "系统", Reason: "历史数据补录",
+		})
+	}
+	if err := db.CreateInBatches(&transitions, 500).Error; err != nil {
+		log.Printf("Failed to backfill application stage history: %v", err)
+		return
+	}
+	log.Printf("Backfilled stage history for %d applications", len(apps))
+}
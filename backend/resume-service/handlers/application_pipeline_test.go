@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"testing"
+
+	"common/pipeline"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckMove(t *testing.T) {
+	config := pipeline.Default()
+	tests := []struct {
+		name    string
+		from    string
+		move    stageMove
+		wantErr string
+	}{
+		{name: "按流程推进", from: "pending", move: stageMove{To: "reviewed"}},
+		{name: "淘汰需选择原因", from: "interview", move: stageMove{To: pipeline.StageRejected}, wantErr: "淘汰原因"},
+		{name: "淘汰原因无效", from: "interview", move: stageMove{To: pipeline.StageRejected, RejectionCode: "bad"}, wantErr: "淘汰原因"},
+		{name: "淘汰并选择原因", from: "interview", move: stageMove{To: pipeline.StageRejected, RejectionCode: "interview_failed"}},
+		{name: "不允许的流转", from: "pending", move: stageMove{To: pipeline.StageHired}, wantErr: "不允许"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkMove(config, tt.from, tt.move)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"resume-service/models"
+	"strings"
+
+	"common/screening"
+)
+
+// screeningNote 通知中说明筛选问题的评估结果
+func screeningNote(r *screening.Result) string {
+	if r == nil {
+		return ""
+	}
+	switch r.Outcome {
+	case screening.OutcomeRejected:
+		return "，筛选问题未通过，已自动淘汰"
+	case screening.OutcomeFlagged:
+		return "，筛选问题待确认：" + strings.Join(r.Tags, "、")
+	}
+	return ""
+}
+
+// screeningCells 导出申请时的筛选结果（含标记的标签）和问题回答两列
+func screeningCells(app models.Application) []string {
+	r := app.Screening
+	if r == nil {
+		return []string{"", ""}
+	}
+	outcome := screening.OutcomeLabel(r.Outcome)
+	if len(r.Tags) > 0 {
+		outcome += "（" + strings.Join(r.Tags, "、") + "）"
+	}
+	return []string{outcome, r.AnswerText()}
+}
+
+// withScreening 按职位评估简历时，在 JD 后附上候选人申请该职位时对筛选问题的回答，供 AI 综合判断
+func (h *AIEvaluateHandler) withScreening(jdText string, resumeID, jobID uint) string {
+	if jobID == 0 {
+		return jdText
+	}
+	var app models.Application
+	err := h.DB.Where("resume_id = ? AND job_id = ? AND screening IS NOT NULL", resumeID, jobID).
+		Order("created_at DESC").Take(&app).Error
+	if err != nil || app.Screening == nil {
+		return jdText
+	}
+	prompt := app.Screening.Prompt()
+	if prompt == "" {
+		return jdText
+	}
+	return jdText + "\n\n" + prompt
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"resume-service/models"
+	"testing"
+
+	"common/screening"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScreeningCells(t *testing.T) {
+	items := []screening.Item{
+		{QuestionID: "work_permit", Question: "是否可在上海工作", Answer: screening.Yes, Passed: true},
+		{QuestionID: "salary", Question: "期望月薪", Answer: "50000", Action: screening.ActionFlag},
+	}
+	tests := []struct {
+		name   string
+		result *screening.Result
+		want   []string
+	}{
+		{name: "未评估", result: nil, want: []string{"", ""}},
+		{name: "通过", result: &screening.Result{Outcome: screening.OutcomePassed, Items: items[:1]}, want: []string{"通过", "是否可在上海工作：是"}},
+		{name: "待确认附带标签", result: &screening.Result{Outcome: screening.OutcomeFlagged, Tags: []string{"薪资偏高"}, Items: items},
+			want: []string{"待确认（薪资偏高）", "是否可在上海工作：是；期望月薪：50000"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, screeningCells(models.Application{Screening: tt.result}))
+		})
+	}
+}
+
+func TestScreeningNote(t *testing.T) {
+	tests := []struct {
+		name   string
+		result *screening.Result
+		want   string
+	}{
+		{name: "未评估", result: nil, want: ""},
+		{name: "通过", result: &screening.Result{Outcome: screening.OutcomePassed}, want: ""},
+		{name: "自动淘汰", result: &screening.Result{Outcome: screening.OutcomeRejected}, want: "，筛选问题未通过，已自动淘汰"},
+		{name: "待确认列出标签", result: &screening.Result{Outcome: screening.OutcomeFlagged, Tags: []string{"出差 50%", "薪资偏高"}}, want: "，筛选问题待确认：出差 50%、薪资偏高"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, screeningNote(tt.result))
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"resume-service/models"
+	"resume-service/parser"
+
+	"common/blind"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// errBlindFile 盲筛中的简历不提供原始文件（文件中的姓名、照片等无法隐藏）
+const errBlindFile = "该简历处于盲筛阶段，暂不提供原始文件"
+
+// blindResumes 当前用户权限不足时，返回仍处于盲筛中的简历及其需要隐藏的身份信息：
+// 关联的人才仍在盲筛中，或尚未关联人才、投递的职位开启了盲筛
+func blindResumes(c *gin.Context, db *gorm.DB, policy blind.Policy, resumes []*models.Resume) (map[uint]blind.Identity, error) {
+	result := map[uint]blind.Identity{}
+	if len(resumes) == 0 || policy.Exempt(c) {
+		return result, nil
+	}
+
+	var talentIDs, jobIDs []uint
+	for _, r := range resumes {
+		if r.TalentID != nil {
+			talentIDs = append(talentIDs, *r.TalentID)
+		} else if r.JobID != nil {
+			jobIDs = append(jobIDs, *r.JobID)
+		}
+	}
+	talents, err := blind.Talents(db, talentIDs)
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := blind.Jobs(db, jobIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var blindTalents []uint
+	for id := range talents {
+		blindTalents = append(blindTalents, id)
+	}
+	var profiles []talentProfile
+	if len(blindTalents) > 0 {
+		if err := db.Table("talents").Select("id, name, email, phone").Where("id IN ?", blindTalents).Scan(&profiles).Error; err != nil {
+			return nil, err
+		}
+	}
+	byID := make(map[uint]talentProfile, len(profiles))
+	for _, p := range profiles {
+		byID[p.ID] = p
+	}
+
+	for _, r := range resumes {
+		switch {
+		case r.TalentID != nil && talents[*r.TalentID]:
+			result[r.ID] = resumeIdentity(r, byID[*r.TalentID])
+		case r.TalentID == nil && r.JobID != nil && jobs[*r.JobID]:
+			result[r.ID] = resumeIdentity(r, talentProfile{})
+		}
+	}
+	return result, nil
+}
+
+// resumeIdentity 简历中需要隐藏的姓名、联系方式和学校，人才档案中的值优先
+func resumeIdentity(r *models.Resume, talent talentProfile) blind.Identity {
+	id := blind.Identity{Name: talent.Name, Email: talent.Email, Phone: talent.Phone}
+	parsed, err := decodeParsed(r.ParsedData)
+	if err != nil {
+		return id
+	}
+	if id.Name == "" {
+		id.Name = parsed.Name
+	}
+	if id.Email == "" {
+		id.Email = parsed.Email
+	}
+	if id.Phone == "" {
+		id.Phone = parsed.Phone
+	}
+	for _, e := range parsed.Educations {
+		if e.School != "" {
+			id.Schools = append(id.Schools, e.School)
+		}
+	}
+	return id
+}
+
+// redactResumes 隐藏盲筛中简历的身份信息，返回被隐藏的简历
+func redactResumes(c *gin.Context, db *gorm.DB, policy blind.Policy, resumes ...*models.Resume) (map[uint]bool, error) {
+	identities, err := blindResumes(c, db, policy, resumes)
+	if err != nil {
+		return nil, err
+	}
+	redacted := make(map[uint]bool, len(identities))
+	for _, r := range resumes {
+		if id, ok := identities[r.ID]; ok {
+			redactResume(r, id)
+			redacted[r.ID] = true
+		}
+	}
+	return redacted, nil
+}
+
+// redactResume 隐藏简历的文件名、文件地址、解析结果中的身份信息及待确认的字段冲突
+func redactResume(r *models.Resume, id blind.Identity) {
+	r.FileName = fmt.Sprintf("resume-%d%s", r.ID, r.FileType)
+	r.FilePath, r.FileURL = "", ""
+	r.TalentConflicts = nil
+	r.Redacted = true
+
+	if parsed, err := decodeParsed(r.ParsedData); err == nil {
+		redactParsed(parsed, id)
+		if b, err := json.Marshal(parsed); err == nil {
+			r.ParsedData = string(b)
+		}
+		return
+	}
+	var data any
+	if json.Unmarshal([]byte(r.ParsedData), &data) == nil {
+		if b, err := json.Marshal(id.RedactValue(data)); err == nil {
+			r.ParsedData = string(b)
+		}
+		return
+	}
+	r.ParsedData = id.RedactText(r.ParsedData)
+}
+
+// redactParsed 隐藏解析结果中的姓名、联系方式、学校，并抹去简介和经历描述中的身份信息
+func redactParsed(p *parser.ParsedResume, id blind.Identity) {
+	p.Name = blind.MaskName
+	p.Phone, p.Email = "", ""
+	p.Summary = id.RedactText(p.Summary)
+	for i := range p.WorkExperiences {
+		p.WorkExperiences[i].Description = id.RedactText(p.WorkExperiences[i].Description)
+	}
+	for i := range p.Educations {
+		p.Educations[i].School = blind.MaskSchool
+	}
+	for i := range p.Projects {
+		p.Projects[i].Description = id.RedactText(p.Projects[i].Description)
+	}
+}
+
+// redactEvaluations 隐藏盲筛中简历的评估结果里的候选人姓名及评语、原始结果中的身份信息
+func redactEvaluations(c *gin.Context, db *gorm.DB, policy blind.Policy, evaluations ...*models.Evaluation) error {
+	if len(evaluations) == 0 || policy.Exempt(c) {
+		return nil
+	}
+	resumeIDs := make([]uint, len(evaluations))
+	for i, e := range evaluations {
+		resumeIDs[i] = e.ResumeID
+	}
+	var resumes []models.Resume
+	if err := db.Unscoped().Where("id IN ?", resumeIDs).Find(&resumes).Error; err != nil {
+		return err
+	}
+	refs := make([]*models.Resume, len(resumes))
+	byID := make(map[uint]*models.Resume, len(resumes))
+	for i := range resumes {
+		refs[i] = &resumes[i]
+		byID[resumes[i].ID] = &resumes[i]
+	}
+	identities, err := blindResumes(c, db, policy, refs)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range evaluations {
+		id, ok := identities[e.ResumeID]
+		if !ok {
+			continue
+		}
+		e.CandidateName = blind.MaskName
+		if r := byID[e.ResumeID]; r.TalentID != nil {
+			e.CandidateName = blind.Alias(*r.TalentID)
+		}
+		e.Summary = id.RedactText(e.Summary)
+		e.Recommendation = id.RedactText(e.Recommendation)
+		if e.RawResult != nil {
+			e.RawResult, _ = id.RedactValue(map[string]any(e.RawResult)).(map[string]any)
+		}
+		e.Redacted = true
+	}
+	return nil
+}
+
+// blindFileForbidden 盲筛中的简历拒绝提供原始文件，已响应时返回 true
+func (h *ResumeHandler) blindFileForbidden(c *gin.Context, resume *models.Resume) bool {
+	identities, err := blindResumes(c, h.DB, h.Blind, []*models.Resume{resume})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "查询盲筛状态失败"})
+		return true
+	}
+	if _, ok := identities[resume.ID]; ok {
+		c.JSON(http.StatusForbidden, gin.H{"code": 1, "message": errBlindFile})
+		return true
+	}
+	return false
+}
+
+// blindTalents 当前用户权限不足时，返回申请中仍处于盲筛的人才
+func (h *ResumeHandler) blindTalents(c *gin.Context, applications []models.Application) (map[uint]bool, error) {
+	if len(applications) == 0 || h.Blind.Exempt(c) {
+		return map[uint]bool{}, nil
+	}
+	ids := make([]uint, len(applications))
+	for i, app := range applications {
+		ids[i] = app.TalentID
+	}
+	return blind.Talents(h.DB, ids)
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"encoding/json"
+	"resume-service/models"
+	"resume-service/parser"
+	"testing"
+
+	"common/blind"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactResume(t *testing.T) {
+	parsed := parser.ParsedResume{
+		Name:    "张三",
+		Phone:   "13812345678",
+		Email:   "zhangsan@example.com",
+		Skills:  []string{"Go"},
+		Summary: "张三，浙江大学毕业，5 年 Go 开发经验",
+		WorkExperiences: []parser.WorkExperience{
+			{Company: "某科技公司", Description: "负责支付系统，联系人张三 13812345678"},
+		},
+		Educations: []parser.EducationEntry{{School: "浙江大学", Degree: "本科"}},
+	}
+	data, err := json.Marshal(parsed)
+	require.NoError(t, err)
+	resume := &models.Resume{
+		ID: 7, FileName: "张三-简历.pdf", FileType: ".pdf", FileURL: "http://x/a.pdf", ParsedData: string(data),
+		TalentConflicts: models.FieldConflicts{{Field: "phone", Current: "13900000000", Parsed: "13812345678"}},
+	}
+
+	redactResume(resume, resumeIdentity(resume, talentProfile{}))
+
+	got, err := decodeParsed(resume.ParsedData)
+	require.NoError(t, err)
+	assert.True(t, resume.Redacted)
+	assert.Equal(t, "resume-7.pdf", resume.FileName)
+	assert.Empty(t, resume.FileURL)
+	assert.Empty(t, resume.TalentConflicts)
+	assert.Equal(t, blind.MaskName, got.Name)
+	assert.Empty(t, got.Phone)
+	assert.Empty(t, got.Email)
+	assert.Equal(t, "[姓名]，[学校]毕业，5 年 Go 开发经验", got.Summary)
+	assert.Equal(t, "负责支付系统，联系人[姓名] [电话]", got.WorkExperiences[0].Description)
+	assert.Equal(t, blind.MaskSchool, got.Educations[0].School)
+	assert.Equal(t, "本科", got.Educations[0].Degree, "学历不隐藏")
+	assert.Equal(t, []string{"Go"}, got.Skills)
+}
+
+func TestResumeIdentity(t *testing.T) {
+	resume := &models.Resume{ParsedData: `{"name":"张三","email":"a@b.com","educations":[{"school":"浙江大学"}]}`}
+
+	tests := []struct {
+		name   string
+		talent talentProfile
+		want   blind.Identity
+	}{
+		{name: "未关联人才", want: blind.Identity{Name: "张三", Email: "a@b.com", Schools: []string{"浙江大学"}}},
+		{name: "人才档案优先", talent: talentProfile{Name: "张叁", Phone: "13812345678"}, want: blind.Identity{Name: "张叁", Email: "a@b.com", Phone: "13812345678", Schools: []string{"浙江大学"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, resumeIdentity(resume, tt.talent))
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"net/http"
+	"resume-service/calibration"
+	"resume-service/models"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetCalibration 实时生成 AI 评分校准报告：按评估时间（start、end）、职位、部门筛选，
+// group_by 为 job 或 department 时附带分组报告，period 为 month（默认）或 week
+func (h *AIEvaluateHandler) GetCalibration(c *gin.Context) {
+	var filter calibration.Filter
+	for param, dst := range map[string]**time.Time{"start": &filter.From, "end": &filter.To} {
+		if v := c.Query(param); v != "" {
+			t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": param + " 格式应为 2006-01-02"})
+				return
+			}
+			*dst = &t
+		}
+	}
+	if v := c.Query("job_id"); v != "" {
+		jobID, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "job_id 无效"})
+			return
+		}
+		filter.JobID = uint(jobID)
+	}
+	filter.Department = c.Query("department")
+
+	opts := calibration.Options{Period: c.DefaultQuery("period", "month"), GroupBy: c.Query("group_by")}
+	if opts.Period != "month" && opts.Period != "week" {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "period 应为 month 或 week"})
+		return
+	}
+	if opts.GroupBy != "" && opts.GroupBy != "job" && opts.GroupBy != "department" {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "group_by 应为 job 或 department"})
+		return
+	}
+
+	samples, err := calibration.Load(h.DB, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "查询评估与招聘结果失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data":    calibration.Build(samples, opts),
+	})
+}
+
+// ListCalibrationReports 定期生成的校准报告快照列表（按时间倒序），不含报告内容
+func (h *AIEvaluateHandler) ListCalibrationReports(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	var total int64
+	h.DB.Model(&models.CalibrationReport{}).Count(&total)
+
+	var reports []models.CalibrationReport
+	if err := h.DB.Omit("report").Order("created_at DESC, id DESC").
+		Offset((page - 1) * pageSize).Limit(pageSize).Find(&reports).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "查询校准报告失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"reports":   reports,
+			"total":     total,
+			"page":      page,
+			"page_size": pageSize,
+		},
+	})
+}
+
+// GetCalibrationReport 校准报告快照详情
+func (h *AIEvaluateHandler) GetCalibrationReport(c *gin.Context) {
+	var report models.CalibrationReport
+	if err := h.DB.First(&report, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "校准报告不存在"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data":    report,
+	})
+}
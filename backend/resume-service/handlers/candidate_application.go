@@ -0,0 +1,508 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"resume-service/models"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"common/blind"
+	"common/notify"
+	"common/pipeline"
+	"common/screening"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// 候选人门户提交内容的长度限制
+const (
+	maxCoverLetterLen = 5000
+	maxAnswers        = 50
+	maxAnswerLen      = 2000
+)
+
+// jobStatusOpen 职位开放申请的状态（见 job-service models.JobStatusOpen）
+const jobStatusOpen = "open"
+
+// portalError 候选人提交的内容或申请状态不符合要求，返回 400
+type portalError struct{ error }
+
+// PortalApplyRequest 候选人申请职位
+type PortalApplyRequest struct {
+	JobID       uint              `json:"job_id" binding:"required"`
+	ResumeID    uint              `json:"resume_id"` // 不填时使用当前简历
+	CoverLetter string            `json:"cover_letter"`
+	Answers     screening.Answers `json:"answers"` // 职位筛选问题的回答，必答问题须作答
+}
+
+// PortalApplication 候选人可见的申请信息，不含内部阶段、备注、自定义字段和淘汰原因
+type PortalApplication struct {
+	ID              uint                 `json:"id"`
+	JobID           uint                 `json:"job_id"`
+	JobTitle        string               `json:"job_title"`
+	Department      string               `json:"department"`
+	Location        string               `json:"location"`
+	ResumeID        uint                 `json:"resume_id"`
+	Status          string               `json:"status"` // 见 pipeline.CandidateStatus
+	StatusLabel     string               `json:"status_label"`
+	StatusChangedAt time.Time            `json:"status_changed_at"`
+	CanWithdraw     bool                 `json:"can_withdraw"`
+	CoverLetter     string               `json:"cover_letter"`
+	Answers         screening.Answers    `json:"answers"`
+	AppliedAt       time.Time            `json:"applied_at"`
+	Timeline        []PortalStatusChange `json:"timeline,omitempty"` // 仅详情返回
+}
+
+// PortalStatusChange 候选人可见的状态变化
+type PortalStatusChange struct {
+	Status string    `json:"status"`
+	Label  string    `json:"label"`
+	At     time.Time `json:"at"`
+}
+
+// portalJob 候选人申请涉及的职位信息
+type portalJob struct {
+	ID             uint
+	DeletedAt      gorm.DeletedAt
+	Title          string
+	Department     string
+	Location       string
+	Status         string
+	ExpiresAt      *time.Time
+	CreatedBy      uint
+	BlindScreening bool
+}
+
+// open 职位是否接受申请
+func (j portalJob) open(now time.Time) bool {
+	return !j.DeletedAt.Valid && j.Status == jobStatusOpen && (j.ExpiresAt == nil || j.ExpiresAt.After(now))
+}
+
+// portalUser 当前登录用户（直接读取 user-service 的 users 表）
+type portalUser struct {
+	ID       uint
+	Username string
+	Email    string
+	Phone    string
+	RealName string
+}
+
+// cleanAnswers 去除空白，校验回答数量、长度及重复的问题
+func cleanAnswers(answers screening.Answers) (screening.Answers, error) {
+	if len(answers) > maxAnswers {
+		return nil, fmt.Errorf("最多回答 %d 个问题", maxAnswers)
+	}
+	out := make(screening.Answers, 0, len(answers))
+	seen := make(map[string]bool, len(answers))
+	for _, a := range answers {
+		a.QuestionID = strings.TrimSpace(a.QuestionID)
+		a.Answer = strings.TrimSpace(a.Answer)
+		if a.QuestionID == "" {
+			return nil, errors.New("回答缺少问题 ID")
+		}
+		if seen[a.QuestionID] {
+			return nil, fmt.Errorf("问题 %s 重复回答", a.QuestionID)
+		}
+		if utf8.RuneCountInString(a.Answer) > maxAnswerLen {
+			return nil, fmt.Errorf("问题 %s 的回答不能超过 %d 个字符", a.QuestionID, maxAnswerLen)
+		}
+		seen[a.QuestionID] = true
+		out = append(out, a)
+	}
+	return out, nil
+}
+
+// candidateTimeline 将流转历史转换为候选人可见的状态变化，合并连续相同的状态
+func candidateTimeline(config pipeline.Config, history []models.StageTransition) []PortalStatusChange {
+	timeline := make([]PortalStatusChange, 0, len(history))
+	for _, t := range history {
+		status := config.CandidateStatus(t.ToStage)
+		if n := len(timeline); n > 0 && timeline[n-1].Status == status {
+			continue
+		}
+		timeline = append(timeline, PortalStatusChange{Status: status, Label: pipeline.CandidateLabel(status), At: t.CreatedAt})
+	}
+	return timeline
+}
+
+// portalUserID 当前登录用户 ID
+func portalUserID(c *gin.Context) uint {
+	id, _ := c.Get("user_id")
+	userID, _ := id.(uint)
+	return userID
+}
+
+// resolvePortalTalent 当前用户的人才档案：已关联账号的档案优先，其次关联注册邮箱相同且未关联账号的档案，
+// 都没有时按账号信息创建
+func resolvePortalTalent(tx *gorm.DB, userID uint) (*talentProfile, error) {
+	var talent talentProfile
+	err := tx.Table("talents").Where("user_id = ? AND deleted_at IS NULL", userID).Order("id").Take(&talent).Error
+	if err == nil {
+		return &talent, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	var user portalUser
+	if err := tx.Table("users").Select("id, username, email, phone, real_name").
+		Where("id = ? AND deleted_at IS NULL", userID).Take(&user).Error; err != nil {
+		return nil, err
+	}
+	email := strings.ToLower(strings.TrimSpace(user.Email))
+	if email != "" {
+		err := tx.Table("talents").Where("deleted_at IS NULL AND user_id IS NULL AND LOWER(email) = ?", email).Order("id").Take(&talent).Error
+		if err == nil {
+			talent.UserID = &userID
+			return &talent, tx.Table("talents").Where("id = ?", talent.ID).Updates(map[string]interface{}{"user_id": userID, "updated_at": time.Now()}).Error
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+
+	name := strings.TrimSpace(user.RealName)
+	if name == "" {
+		name = user.Username
+	}
+	talent = talentProfile{Name: name, Email: email, Phone: strings.TrimSpace(user.Phone), Status: "active", Source: "portal", UserID: &userID}
+	if err := tx.Table("talents").Create(&talent).Error; err != nil {
+		return nil, err
+	}
+	log.Printf("[人才] 用户 %d 通过候选人门户创建人才 %d", userID, talent.ID)
+	return &talent, nil
+}
+
+// portalTalentIDs 当前用户关联的人才档案
+func portalTalentIDs(db *gorm.DB, userID uint) ([]uint, error) {
+	var ids []uint
+	err := db.Table("talents").Where("user_id = ? AND deleted_at IS NULL", userID).Pluck("id", &ids).Error
+	return ids, err
+}
+
+// portalResume 申请使用的简历：指定的简历须属于该人才，未指定时使用当前简历或最新上传的简历
+func portalResume(tx *gorm.DB, talentID, resumeID uint) (uint, error) {
+	q := tx.Model(&models.Resume{}).Where("talent_id = ?", talentID)
+	if resumeID != 0 {
+		q = q.Where("id = ?", resumeID)
+	}
+	var resume models.Resume
+	err := q.Order("is_primary DESC, created_at DESC").Take(&resume).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if resumeID != 0 {
+			return 0, portalError{errors.New("简历不存在")}
+		}
+		return 0, portalError{errors.New("请先上传简历")}
+	}
+	return resume.ID, err
+}
+
+// loadPortalJobs 读取申请涉及的职位
+func loadPortalJobs(db *gorm.DB, jobIDs []uint) (map[uint]portalJob, error) {
+	var jobs []portalJob
+	if len(jobIDs) > 0 {
+		if err := db.Table("jobs").
+			Select("id, deleted_at, title, department, location, status, expires_at, created_by, blind_screening").
+			Where("id IN ?", jobIDs).Scan(&jobs).Error; err != nil {
+			return nil, err
+		}
+	}
+	out := make(map[uint]portalJob, len(jobs))
+	for _, j := range jobs {
+		out[j.ID] = j
+	}
+	return out, nil
+}
+
+// portalApplications 转换为候选人可见的申请信息，withTimeline 时附带状态变化
+func portalApplications(db *gorm.DB, apps []models.Application, withTimeline bool) ([]PortalApplication, error) {
+	jobIDs := make([]uint, 0, len(apps))
+	appIDs := make([]uint, 0, len(apps))
+	for _, app := range apps {
+		jobIDs = append(jobIDs, app.JobID)
+		appIDs = append(appIDs, app.ID)
+	}
+	jobs, err := loadPortalJobs(db, jobIDs)
+	if err != nil {
+		return nil, err
+	}
+	var history []models.StageTransition
+	if len(appIDs) > 0 {
+		if err := db.Where("application_id IN ?", appIDs).Order("created_at, id").Find(&history).Error; err != nil {
+			return nil, err
+		}
+	}
+	byApp := make(map[uint][]models.StageTransition, len(apps))
+	for _, t := range history {
+		byApp[t.ApplicationID] = append(byApp[t.ApplicationID], t)
+	}
+
+	configs := map[uint]pipeline.Config{}
+	out := make([]PortalApplication, len(apps))
+	for i, app := range apps {
+		config, ok := configs[app.JobID]
+		if !ok {
+			if config, _, err = pipeline.Load(db, app.JobID); err != nil {
+				return nil, err
+			}
+			configs[app.JobID] = config
+		}
+		job := jobs[app.JobID]
+		status := config.CandidateStatus(app.Status)
+		timeline := candidateTimeline(config, byApp[app.ID])
+
+		out[i] = PortalApplication{
+			ID:              app.ID,
+			JobID:           app.JobID,
+			JobTitle:        job.Title,
+			Department:      job.Department,
+			Location:        job.Location,
+			ResumeID:        app.ResumeID,
+			Status:          status,
+			StatusLabel:     pipeline.CandidateLabel(status),
+			StatusChangedAt: app.CreatedAt,
+			CanWithdraw:     !pipeline.IsClosed(app.Status),
+			CoverLetter:     app.CoverLetter,
+			Answers:         app.Answers,
+			AppliedAt:       app.CreatedAt,
+		}
+		if n := len(timeline); n > 0 {
+			out[i].StatusChangedAt = timeline[n-1].At
+		}
+		if withTimeline {
+			out[i].Timeline = timeline
+		}
+	}
+	return out, nil
+}
+
+// notifyJobOwner 通知职位负责人候选人的申请变化，format 依次填入候选人、职位名称和 args；盲筛职位只显示候选人编号
+func (h *ResumeHandler) notifyJobOwner(job portalJob, talent *talentProfile, app *models.Application, title, format string, args ...interface{}) {
+	if h.Notifier == nil || job.CreatedBy == 0 {
+		return
+	}
+	name := talent.Name
+	if job.BlindScreening {
+		name = blind.Alias(talent.ID)
+	}
+	h.Notifier.SendAsync(notify.Message{
+		ReceiverID: job.CreatedBy,
+		Title:      title,
+		Content:    fmt.Sprintf(format, append([]interface{}{name, job.Title}, args...)...) + fmt.Sprintf("（申请 #%d）", app.ID),
+		Type:       notify.TypeSystem,
+	})
+}
+
+// ApplyToJob 候选人申请职位：按登录账号确定人才档案，只能申请开放中的职位，同一职位不能重复申请（已撤回的可重新申请）；
+// 职位设置了筛选问题时校验回答，不满足淘汰条件的申请直接淘汰
+func (h *ResumeHandler) ApplyToJob(c *gin.Context) {
+	var req PortalApplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": err.Error()})
+		return
+	}
+	req.CoverLetter = strings.TrimSpace(req.CoverLetter)
+	if utf8.RuneCountInString(req.CoverLetter) > maxCoverLetterLen {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": fmt.Sprintf("求职信不能超过 %d 个字符", maxCoverLetterLen)})
+		return
+	}
+	answers, err := cleanAnswers(req.Answers)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": err.Error()})
+		return
+	}
+
+	jobs, err := loadPortalJobs(h.DB, []uint{req.JobID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "查询职位失败"})
+		return
+	}
+	job, ok := jobs[req.JobID]
+	if !ok || job.DeletedAt.Valid {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "职位不存在"})
+		return
+	}
+	if !job.open(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "该职位已停止招聘"})
+		return
+	}
+	questions, err := screening.Load(h.DB, job.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "查询筛选问题失败"})
+		return
+	}
+	if answers, err = questions.Check(answers, true); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": err.Error()})
+		return
+	}
+
+	userID := portalUserID(c)
+	actorID, actorName := currentActor(c)
+	var talent *talentProfile
+	app := models.Application{JobID: job.ID, CoverLetter: req.CoverLetter, Answers: answers}
+	err = h.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if talent, err = resolvePortalTalent(tx, userID); err != nil {
+			return err
+		}
+		app.TalentID = talent.ID
+		if app.ResumeID, err = portalResume(tx, talent.ID, req.ResumeID); err != nil {
+			return err
+		}
+		return insertApplication(tx, &app, actorID, actorName)
+	})
+	var pe portalError
+	switch {
+	case errors.Is(err, errDuplicateApplication):
+		c.JSON(http.StatusConflict, gin.H{"code": 1, "message": "你已申请过该职位"})
+		return
+	case errors.As(err, &pe):
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "提交申请失败"})
+		return
+	}
+	h.notifyJobOwner(job, talent, &app, "新的职位申请", "候选人 %s 申请了职位「%s」%s", screeningNote(app.Screening))
+
+	views, err := portalApplications(h.DB, []models.Application{app}, true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "查询申请失败"})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"code":    0,
+		"message": "申请已提交",
+		"data":    views[0],
+	})
+}
+
+// ListMyApplications 当前候选人的申请列表
+func (h *ResumeHandler) ListMyApplications(c *gin.Context) {
+	talentIDs, err := portalTalentIDs(h.DB, portalUserID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "查询申请失败"})
+		return
+	}
+	var apps []models.Application
+	if len(talentIDs) > 0 {
+		if err := h.DB.Where("talent_id IN ?", talentIDs).Order("created_at DESC").Find(&apps).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "查询申请失败"})
+			return
+		}
+	}
+	views, err := portalApplications(h.DB, apps, false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "查询申请失败"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data":    views,
+	})
+}
+
+// myApplication 读取当前候选人的申请，不属于该候选人时视为不存在
+func (h *ResumeHandler) myApplication(tx *gorm.DB, c *gin.Context, lock bool) (*models.Application, error) {
+	talentIDs, err := portalTalentIDs(tx, portalUserID(c))
+	if err != nil {
+		return nil, err
+	}
+	var app *models.Application
+	if lock {
+		app, err = lockApplication(tx, c.Param("id"))
+	} else {
+		app = &models.Application{}
+		err = tx.First(app, c.Param("id")).Error
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range talentIDs {
+		if app.TalentID == id {
+			return app, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// GetMyApplication 当前候选人的申请详情及状态变化
+func (h *ResumeHandler) GetMyApplication(c *gin.Context) {
+	app, err := h.myApplication(h.DB, c, false)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "申请不存在"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "查询申请失败"})
+		return
+	}
+	views, err := portalApplications(h.DB, []models.Application{*app}, true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "查询申请失败"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data":    views[0],
+	})
+}
+
+// WithdrawMyApplication 候选人撤回申请：流程结束前均可撤回，不受职位流转规则限制，撤回后通知职位负责人
+func (h *ResumeHandler) WithdrawMyApplication(c *gin.Context) {
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	_ = c.ShouldBindJSON(&req)
+
+	actorID, actorName := currentActor(c)
+	var app *models.Application
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if app, err = h.myApplication(tx, c, true); err != nil {
+			return err
+		}
+		if pipeline.IsClosed(app.Status) {
+			return moveError{errors.New("申请已结束，无法撤回")}
+		}
+		move := stageMove{To: pipeline.StageWithdrawn, Reason: strings.TrimSpace(req.Reason), ActorID: actorID, ActorName: actorName, Force: true}
+		return moveApplication(tx, app, move)
+	})
+	if err != nil {
+		switch status := moveStatus(err); status {
+		case http.StatusNotFound:
+			c.JSON(status, gin.H{"code": 1, "message": "申请不存在"})
+		case http.StatusBadRequest:
+			c.JSON(status, gin.H{"code": 1, "message": err.Error()})
+		default:
+			c.JSON(status, gin.H{"code": 1, "message": "撤回申请失败"})
+		}
+		return
+	}
+
+	jobs, err := loadPortalJobs(h.DB, []uint{app.JobID})
+	if err == nil {
+		var talent talentProfile
+		if h.DB.Table("talents").Where("id = ?", app.TalentID).Take(&talent).Error == nil {
+			h.notifyJobOwner(jobs[app.JobID], &talent, app, "候选人撤回申请", "候选人 %s 撤回了职位「%s」的申请")
+		}
+	}
+
+	views, err := portalApplications(h.DB, []models.Application{*app}, true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "查询申请失败"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "申请已撤回",
+		"data":    views[0],
+	})
+}
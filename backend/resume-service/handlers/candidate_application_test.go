@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"resume-service/models"
+	"strings"
+	"testing"
+	"time"
+
+	"common/pipeline"
+	"common/screening"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCleanAnswers(t *testing.T) {
+	tests := []struct {
+		name    string
+		answers screening.Answers
+		want    screening.Answers
+		wantErr string
+	}{
+		{name: "未回答", answers: nil, want: screening.Answers{}},
+		{name: "去除空白", answers: screening.Answers{{QuestionID: " work_permit ", Answer: " 是 "}}, want: screening.Answers{{QuestionID: "work_permit", Answer: "是"}}},
+		{name: "缺少问题 ID", answers: screening.Answers{{Answer: "是"}}, wantErr: "缺少问题 ID"},
+		{name: "重复回答", answers: screening.Answers{{QuestionID: "q1", Answer: "是"}, {QuestionID: "q1", Answer: "否"}}, wantErr: "重复回答"},
+		{name: "回答过长", answers: screening.Answers{{QuestionID: "q1", Answer: strings.Repeat("长", maxAnswerLen+1)}}, wantErr: "不能超过"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cleanAnswers(tt.answers)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCandidateTimeline(t *testing.T) {
+	at := func(day int) time.Time { return time.Date(2026, 3, day, 9, 0, 0, 0, time.UTC) }
+	history := []models.StageTransition{
+		{ToStage: "pending", CreatedAt: at(1)},
+		{ToStage: "reviewed", CreatedAt: at(2)},
+		{ToStage: "interview", CreatedAt: at(3)},
+		{ToStage: "interview", CreatedAt: at(4)}, // 流程调整后的重复记录
+		{ToStage: pipeline.StageRejected, RejectionCode: "interview_failed", CreatedAt: at(5)},
+	}
+
+	assert.Equal(t, []PortalStatusChange{
+		{Status: pipeline.CandidateSubmitted, Label: "已投递", At: at(1)},
+		{Status: pipeline.CandidateInReview, Label: "处理中", At: at(2)},
+		{Status: pipeline.CandidateInterview, Label: "面试中", At: at(3)},
+		{Status: pipeline.CandidateClosed, Label: "流程已结束", At: at(5)},
+	}, candidateTimeline(pipeline.Default(), history))
+}
+
+func TestPortalJobOpen(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+
+	assert.True(t, portalJob{Status: jobStatusOpen}.open(now))
+	assert.True(t, portalJob{Status: jobStatusOpen, ExpiresAt: &future}.open(now))
+	assert.False(t, portalJob{Status: jobStatusOpen, ExpiresAt: &past}.open(now), "已过截止时间")
+	assert.False(t, portalJob{Status: "on_hold"}.open(now), "暂停招聘")
+}
@@ -0,0 +1,412 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"resume-service/evalcache"
+	"resume-service/evaluator"
+	"resume-service/models"
+	"strconv"
+	"time"
+
+	"common/textdiff"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// recordEvaluation 保存一次评估结果，并更新简历上最近一次评估的分数和职位版本。
+// 简历的解析结果不受影响
+func (h *AIEvaluateHandler) recordEvaluation(ev evaluator.Evaluator, resume *models.Resume, job jobContent, jdText, candidateName string, result *evaluator.EvaluationResult, duration time.Duration) (*models.Evaluation, error) {
+	evaluation := evaluationFromResult(result)
+	evaluation.Provider = ev.Provider()
+	evaluation.Model = ev.Model()
+	evaluation.DurationMs = duration.Milliseconds()
+	evaluation.CacheKey = evaluationCacheKey(resume.ContentHash, jdText, ev.Provider(), ev.Model())
+	if err := h.saveEvaluation(evaluation, resume, job, jdText, candidateName); err != nil {
+		return nil, err
+	}
+	return evaluation, nil
+}
+
+// reuseEvaluation 命中缓存时为简历新增一条评估记录，结果复制自缓存的评估。
+// 复制的记录不带缓存键，缓存有效期仍从实际调用评估服务时算起
+func (h *AIEvaluateHandler) reuseEvaluation(cached *models.Evaluation, resume *models.Resume, job jobContent, jdText, candidateName string) (*models.Evaluation, error) {
+	evaluation := *cached
+	cachedID := cached.ID
+	evaluation.ID = 0
+	evaluation.CreatedAt = time.Time{}
+	evaluation.JobID = nil
+	evaluation.JobVersion = 0
+	evaluation.DurationMs = 0
+	evaluation.CacheKey = ""
+	evaluation.CachedFromID = &cachedID
+	if err := h.saveEvaluation(&evaluation, resume, job, jdText, candidateName); err != nil {
+		return nil, err
+	}
+	return &evaluation, nil
+}
+
+// saveEvaluation 写入评估记录，并在同一事务中更新简历上最近一次评估的分数和职位版本
+func (h *AIEvaluateHandler) saveEvaluation(evaluation *models.Evaluation, resume *models.Resume, job jobContent, jdText, candidateName string) error {
+	evaluation.ResumeID = resume.ID
+	evaluation.CandidateName = candidateName
+	evaluation.JDText = jdText
+	evaluation.JDHash = jdHash(jdText)
+	if job.ID != 0 {
+		jobID := job.ID
+		evaluation.JobID = &jobID
+		evaluation.JobVersion = job.Version
+	}
+
+	resume.MatchScore = int(evaluation.TotalScore)
+	// 未解析的简历标记为已评估，避免被重复拉取评估；已解析的简历保留原状态
+	if resume.Status == "pending" {
+		resume.Status = "evaluated"
+	}
+	setEvaluatedJob(resume, job)
+
+	return h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(evaluation).Error; err != nil {
+			return err
+		}
+		return tx.Model(resume).Select("match_score", "status", "evaluated_job_id", "evaluated_job_version").Updates(resume).Error
+	})
+}
+
+// cachedEvaluation 查找可复用的评估记录，force 为 true 时跳过缓存。查询失败按未命中处理
+func (h *AIEvaluateHandler) cachedEvaluation(resumeHash, jdText string, ev evaluator.Evaluator, force bool) *models.Evaluation {
+	cached, err := h.Cache.Lookup(evaluationCacheKey(resumeHash, jdText, ev.Provider(), ev.Model()), force)
+	if err != nil {
+		log.Printf("Failed to look up evaluation cache: %v", err)
+		return nil
+	}
+	return cached
+}
+
+// evaluationCacheKey 评估缓存键；评估服务只依据 JD 评分，没有单独的评分标准
+func evaluationCacheKey(resumeHash, jdText, provider, model string) string {
+	return evalcache.Key(resumeHash, jdHash(jdText), "", provider, model)
+}
+
+// ensureContentHash 早期上传的简历没有记录内容哈希，按读取到的文件内容补上，用于评估缓存和重复上传识别
+func (h *AIEvaluateHandler) ensureContentHash(resume *models.Resume, data []byte) {
+	if resume.ContentHash != "" || len(data) == 0 {
+		return
+	}
+	resume.ContentHash = contentHash(data)
+	if err := h.DB.Model(resume).UpdateColumn("content_hash", resume.ContentHash).Error; err != nil {
+		log.Printf("Failed to backfill content hash for resume %d: %v", resume.ID, err)
+	}
+}
+
+// evaluationFromResult 将评估服务的结果转换为评估记录
+func evaluationFromResult(result *evaluator.EvaluationResult) *models.Evaluation {
+	return &models.Evaluation{
+		CandidateName:   result.Name,
+		TotalScore:      result.TotalScore,
+		Grade:           result.Grade,
+		JDMatchScore:    result.JDMatchScore,
+		AgeScore:        result.AgeScore,
+		ExperienceScore: result.ExperienceScore,
+		EducationScore:  result.EducationScore,
+		CompanyScore:    result.CompanyScore,
+		TechScore:       result.TechScore,
+		ProjectScore:    result.ProjectScore,
+		Recommendation:  result.Recommendation,
+		MatchedSkills:   result.MatchedSkills,
+		MissingSkills:   result.MissingSkills,
+		Summary:         result.Summary,
+		RawResult:       result.RawResult,
+	}
+}
+
+// evaluationResponse 评估接口的返回结构
+func evaluationResponse(e *models.Evaluation) AIEvaluateResponse {
+	resp := AIEvaluateResponse{
+		EvaluationID:    e.ID,
+		ResumeID:        e.ResumeID,
+		CandidateName:   e.CandidateName,
+		JobVersion:      e.JobVersion,
+		Provider:        e.Provider,
+		Cached:          e.CachedFromID != nil,
+		Redacted:        e.Redacted,
+		TotalScore:      e.TotalScore,
+		Grade:           e.Grade,
+		JDMatchScore:    e.JDMatchScore,
+		AgeScore:        e.AgeScore,
+		ExperienceScore: e.ExperienceScore,
+		EducationScore:  e.EducationScore,
+		CompanyScore:    e.CompanyScore,
+		TechScore:       e.TechScore,
+		ProjectScore:    e.ProjectScore,
+		Recommendation:  e.Recommendation,
+		MatchedSkills:   e.MatchedSkills,
+		MissingSkills:   e.MissingSkills,
+		Summary:         e.Summary,
+	}
+	if e.JobID != nil {
+		resp.JobID = *e.JobID
+	}
+	return resp
+}
+
+// jdHash JD 文本的 SHA-256，用于判断两次评估是否基于相同的 JD
+func jdHash(jdText string) string {
+	if jdText == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(jdText))
+	return hex.EncodeToString(sum[:])
+}
+
+// contentHash 文件内容的 SHA-256
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// ListResumeEvaluations 获取简历的评估历史（按时间倒序）
+func (h *AIEvaluateHandler) ListResumeEvaluations(c *gin.Context) {
+	var resume models.Resume
+	if err := h.DB.First(&resume, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "简历不存在"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	query := h.DB.Model(&models.Evaluation{}).Where("resume_id = ?", resume.ID)
+	if jobID := c.Query("job_id"); jobID != "" {
+		query = query.Where("job_id = ?", jobID)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	// 列表不返回原始结果和 JD 全文
+	var evaluations []models.Evaluation
+	if err := query.Omit("raw_result", "jd_text").Order("created_at DESC, id DESC").
+		Offset((page - 1) * pageSize).Limit(pageSize).Find(&evaluations).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "查询评估历史失败"})
+		return
+	}
+	refs := make([]*models.Evaluation, len(evaluations))
+	for i := range evaluations {
+		refs[i] = &evaluations[i]
+	}
+	if err := redactEvaluations(c, h.DB, h.Blind, refs...); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "查询盲筛状态失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"evaluations": evaluations,
+			"total":       total,
+			"page":        page,
+			"page_size":   pageSize,
+		},
+	})
+}
+
+// GetEvaluation 获取单次评估详情，包含原始结果
+func (h *AIEvaluateHandler) GetEvaluation(c *gin.Context) {
+	var evaluation models.Evaluation
+	if err := h.DB.First(&evaluation, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "评估记录不存在"})
+		return
+	}
+	if err := redactEvaluations(c, h.DB, h.Blind, &evaluation); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "查询盲筛状态失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data":    evaluation,
+	})
+}
+
+// CompareEvaluations 对比两次评估，默认 from 为同一简历的上一次评估
+func (h *AIEvaluateHandler) CompareEvaluations(c *gin.Context) {
+	var to models.Evaluation
+	if err := h.DB.First(&to, c.Query("to")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "评估记录不存在"})
+		return
+	}
+
+	var from models.Evaluation
+	var err error
+	if fromID := c.Query("from"); fromID != "" {
+		err = h.DB.First(&from, fromID).Error
+	} else {
+		err = h.DB.Where("resume_id = ? AND (created_at < ? OR (created_at = ? AND id < ?))", to.ResumeID, to.CreatedAt, to.CreatedAt, to.ID).
+			Order("created_at DESC, id DESC").Take(&from).Error
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "没有可对比的评估记录"})
+		return
+	}
+	if err := redactEvaluations(c, h.DB, h.Blind, &from, &to); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "查询盲筛状态失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"from":        from.ID,
+			"to":          to.ID,
+			"same_resume": from.ResumeID == to.ResumeID,
+			"same_jd":     from.JDHash == to.JDHash,
+			"score_delta": to.TotalScore - from.TotalScore,
+			"changes":     diffEvaluations(&from, &to),
+		},
+	})
+}
+
+// diffEvaluations 逐项对比两次评估的依据、分数和技能匹配
+func diffEvaluations(a, b *models.Evaluation) []FieldChange {
+	changes := make([]FieldChange, 0)
+
+	jobOf := func(e *models.Evaluation) interface{} {
+		if e.JobID == nil {
+			return nil
+		}
+		return *e.JobID
+	}
+	scalars := []struct {
+		field    string
+		from, to interface{}
+	}{
+		{"job_id", jobOf(a), jobOf(b)},
+		{"job_version", a.JobVersion, b.JobVersion},
+		{"provider", a.Provider, b.Provider},
+		{"model", a.Model, b.Model},
+		{"total_score", a.TotalScore, b.TotalScore},
+		{"grade", a.Grade, b.Grade},
+		{"jd_match_score", a.JDMatchScore, b.JDMatchScore},
+		{"age_score", a.AgeScore, b.AgeScore},
+		{"experience_score", a.ExperienceScore, b.ExperienceScore},
+		{"education_score", a.EducationScore, b.EducationScore},
+		{"company_score", a.CompanyScore, b.CompanyScore},
+		{"tech_score", a.TechScore, b.TechScore},
+		{"project_score", a.ProjectScore, b.ProjectScore},
+		{"recommendation", a.Recommendation, b.Recommendation},
+	}
+	for _, s := range scalars {
+		if s.from != s.to {
+			changes = append(changes, FieldChange{Field: s.field, From: s.from, To: s.to})
+		}
+	}
+
+	if a.JDHash != b.JDHash {
+		changes = append(changes, FieldChange{Field: "jd_text", Lines: textdiff.Lines(a.JDText, b.JDText)})
+	}
+	if lines := textdiff.Lines(a.Summary, b.Summary); textdiff.Changed(lines) {
+		changes = append(changes, FieldChange{Field: "summary", Lines: lines})
+	}
+
+	lists := []struct {
+		field    string
+		from, to []string
+	}{
+		{"matched_skills", a.MatchedSkills, b.MatchedSkills},
+		{"missing_skills", a.MissingSkills, b.MissingSkills},
+	}
+	for _, l := range lists {
+		if added, removed := textdiff.SetDiff(l.from, l.to); len(added) > 0 || len(removed) > 0 {
+			changes = append(changes, FieldChange{Field: l.field, Added: added, Removed: removed})
+		}
+	}
+
+	return changes
+}
+
+// legacyEvaluation 评估结果曾写入 resumes.parsed_data，按是否含评估字段识别
+func legacyEvaluation(data string) (*evaluator.EvaluationResult, bool) {
+	var fields map[string]json.RawMessage
+	if json.Unmarshal([]byte(data), &fields) != nil {
+		return nil, false
+	}
+	if _, ok := fields["total_score"]; !ok {
+		return nil, false
+	}
+	if _, ok := fields["grade"]; !ok {
+		return nil, false
+	}
+	var result evaluator.EvaluationResult
+	if json.Unmarshal([]byte(data), &result) != nil {
+		return nil, false
+	}
+	return &result, true
+}
+
+// BackfillEvaluations 将历史上写入 parsed_data 的评估结果迁移到 evaluations 表，并清空 parsed_data，
+// 简历可通过重新解析恢复解析结果
+func BackfillEvaluations(db *gorm.DB) {
+	var resumes []models.Resume
+	if err := db.Where("parsed_data LIKE ? AND parsed_data LIKE ?", "%\"total_score\"%", "%\"grade\"%").Find(&resumes).Error; err != nil {
+		log.Printf("Failed to load resumes for evaluation backfill: %v", err)
+		return
+	}
+
+	migrated := 0
+	for i := range resumes {
+		resume := &resumes[i]
+		result, ok := legacyEvaluation(resume.ParsedData)
+		if !ok {
+			continue
+		}
+		evaluation := evaluationFromResult(result)
+		evaluation.CreatedAt = resume.UpdatedAt
+		evaluation.ResumeID = resume.ID
+		evaluation.JobID = resume.EvaluatedJobID
+		evaluation.JobVersion = resume.EvaluatedJobVersion
+		evaluation.Provider = "coze"
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(evaluation).Error; err != nil {
+				return err
+			}
+			return tx.Model(resume).Update("parsed_data", "").Error
+		})
+		if err != nil {
+			log.Printf("Failed to backfill evaluation for resume %d: %v", resume.ID, err)
+			continue
+		}
+		migrated++
+	}
+	if migrated > 0 {
+		log.Printf("Migrated %d evaluation results out of resumes.parsed_data", migrated)
+	}
+}
+
+// latestEvaluation 简历最近一次评估
+func latestEvaluation(db *gorm.DB, resumeID uint) (*models.Evaluation, error) {
+	var evaluation models.Evaluation
+	err := db.Where("resume_id = ?", resumeID).Order("created_at DESC, id DESC").Take(&evaluation).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &evaluation, nil
+}
@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"resume-service/evalqueue"
+	"resume-service/evaluator"
+	"resume-service/models"
+	"strconv"
+	"time"
+
+	"common/storage"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxBatchResumes 单个批量评估任务的简历数上限
+const maxBatchResumes = 500
+
+// BatchEvaluate 提交批量评估任务，由后台队列处理，进度通过 WebSocket 推送或查询 GetBatch
+func (h *AIEvaluateHandler) BatchEvaluate(c *gin.Context) {
+	var req struct {
+		ResumeIDs []uint `json:"resume_ids" binding:"required"`
+		JDText    string `json:"jd_text"`
+		JobID     uint   `json:"job_id"`
+		Provider  string `json:"provider"` // 指定评估服务，为空时按职位配置选择
+		Force     bool   `json:"force"`    // 强制重新评估，不复用缓存结果
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ids := make([]uint, 0, len(req.ResumeIDs))
+	seen := map[uint]bool{}
+	for _, id := range req.ResumeIDs {
+		if id != 0 && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请选择要评估的简历"})
+		return
+	}
+	if len(ids) > maxBatchResumes {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("单次最多评估 %d 份简历", maxBatchResumes)})
+		return
+	}
+
+	// 提交时确定 JD，之后职位描述的修改不影响本次任务
+	jdText, job, err := h.resolveJD(req.JDText, req.JobID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "职位不存在"})
+		return
+	}
+	// 检查 AI 是否配置；评估时按职位配置重新选择服务
+	if _, ok := h.requireEvaluator(c, job.ID, req.Provider); !ok {
+		return
+	}
+
+	var existing []uint
+	if err := h.DB.Model(&models.Resume{}).Where("id IN ?", ids).Pluck("id", &existing).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取简历失败"})
+		return
+	}
+	found := map[uint]bool{}
+	for _, id := range existing {
+		found[id] = true
+	}
+	queued := make([]uint, 0, len(existing))
+	missing := make([]uint, 0)
+	for _, id := range ids {
+		if found[id] {
+			queued = append(queued, id)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	if len(queued) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "简历不存在"})
+		return
+	}
+
+	batch := &models.EvaluationBatch{JDText: jdText, Provider: req.Provider, Force: req.Force}
+	if job.ID != 0 {
+		jobID := job.ID
+		batch.JobID = &jobID
+		batch.JobVersion = job.Version
+	}
+	batch.CreatedBy, _ = currentActor(c)
+	if err := h.Queue.Submit(batch, queued); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "创建批量评估任务失败"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"code":    0,
+		"message": "批量评估任务已提交",
+		"data": gin.H{
+			"batch":   batch,
+			"missing": missing, // 不存在的简历，未加入任务
+		},
+	})
+}
+
+// EvaluateBatchItem 队列工作协程调用：评估批量任务中的一份简历并保存评估记录
+func (h *AIEvaluateHandler) EvaluateBatchItem(ctx context.Context, batch *models.EvaluationBatch, item *models.EvaluationBatchItem) (uint, error) {
+	var resume models.Resume
+	if err := h.DB.First(&resume, item.ResumeID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, evalqueue.Permanent(errors.New("简历不存在"))
+		}
+		return 0, err
+	}
+
+	pdfBytes, err := readResumeFile(ctx, h.Storage, &resume)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return 0, evalqueue.Permanent(errors.New("简历文件不存在"))
+		}
+		return 0, fmt.Errorf("简历文件读取失败: %w", err)
+	}
+
+	var job jobContent
+	if batch.JobID != nil {
+		job = jobContent{ID: *batch.JobID, Version: batch.JobVersion}
+	}
+	ev, err := h.evaluatorFor(job.ID, batch.Provider)
+	if errors.Is(err, evaluator.ErrUnknownProvider) {
+		return 0, evalqueue.Permanent(err)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	jdText := h.withScreening(batch.JDText, resume.ID, job.ID)
+
+	// 重复提交的任务在缓存有效期内直接复用已有结果
+	h.ensureContentHash(&resume, pdfBytes)
+	if cached := h.cachedEvaluation(resume.ContentHash, jdText, ev, batch.Force); cached != nil {
+		evaluation, err := h.reuseEvaluation(cached, &resume, job, jdText, resume.FileName)
+		if err != nil {
+			return 0, fmt.Errorf("评估结果保存失败: %w", err)
+		}
+		return evaluation.ID, nil
+	}
+
+	// 评估服务调用失败时先按队列重试，最后一次仍失败才改用离线规则评分
+	fallback := batch.Provider == "" && item.Attempts >= h.Queue.MaxAttempts
+	started := time.Now()
+	result, ev, err := h.evaluate(ctx, ev, resume.FileName, jdText, pdfBytes, fallback)
+	if err != nil {
+		return 0, fmt.Errorf("评估失败: %w", err)
+	}
+
+	evaluation, err := h.recordEvaluation(ev, &resume, job, jdText, resume.FileName, result, time.Since(started))
+	if err != nil {
+		return 0, fmt.Errorf("评估结果保存失败: %w", err)
+	}
+	return evaluation.ID, nil
+}
+
+// ListBatches 批量评估任务列表（按时间倒序）
+func (h *AIEvaluateHandler) ListBatches(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	query := h.DB.Model(&models.EvaluationBatch{})
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if id, _ := currentActor(c); id != nil && c.Query("mine") == "true" {
+		query = query.Where("created_by = ?", *id)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var batches []models.EvaluationBatch
+	if err := query.Order("id DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&batches).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "查询批量评估任务失败"})
+		return
+	}
+
+	summaries := make([]evalqueue.Summary, len(batches))
+	for i := range batches {
+		summaries[i] = h.Queue.Summary(&batches[i])
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"batches":   summaries,
+			"total":     total,
+			"page":      page,
+			"page_size": pageSize,
+		},
+	})
+}
+
+// GetBatch 批量评估任务进度及每份简历的处理情况
+func (h *AIEvaluateHandler) GetBatch(c *gin.Context) {
+	var batch models.EvaluationBatch
+	if err := h.DB.First(&batch, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "批量评估任务不存在"})
+		return
+	}
+
+	query := h.DB.Where("batch_id = ?", batch.ID)
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var items []models.EvaluationBatchItem
+	if err := query.Order("id").Find(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "查询任务条目失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"batch": h.Queue.Summary(&batch),
+			"items": items,
+		},
+	})
+}
+
+// CancelBatch 取消批量评估任务，已完成的评估保留
+func (h *AIEvaluateHandler) CancelBatch(c *gin.Context) {
+	var batch models.EvaluationBatch
+	if err := h.DB.First(&batch, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "批量评估任务不存在"})
+		return
+	}
+
+	cancelled, err := h.Queue.Cancel(batch.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "取消任务失败"})
+		return
+	}
+	if !cancelled {
+		c.JSON(http.StatusConflict, gin.H{"code": 1, "message": "任务已结束，无法取消"})
+		return
+	}
+
+	h.DB.First(&batch, batch.ID)
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "任务已取消",
+		"data":    h.Queue.Summary(&batch),
+	})
+}
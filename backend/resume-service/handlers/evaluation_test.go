@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"resume-service/models"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffEvaluations(t *testing.T) {
+	jobID := uint(3)
+	a := &models.Evaluation{
+		JobID: &jobID, JobVersion: 1, JDHash: jdHash("Go 工程师"), JDText: "Go 工程师",
+		Provider: "coze", Model: "wf1", TotalScore: 72, Grade: "B", TechScore: 20,
+		MatchedSkills: pq.StringArray{"Go"}, MissingSkills: pq.StringArray{"Kubernetes", "MySQL"},
+	}
+	b := &models.Evaluation{
+		JobID: &jobID, JobVersion: 2, JDHash: jdHash("Go 工程师\n熟悉 K8s"), JDText: "Go 工程师\n熟悉 K8s",
+		Provider: "coze", Model: "wf1", TotalScore: 80, Grade: "A", TechScore: 20,
+		MatchedSkills: pq.StringArray{"Go", "Kubernetes"}, MissingSkills: pq.StringArray{"MySQL"},
+	}
+
+	changes := diffEvaluations(a, b)
+	byField := map[string]FieldChange{}
+	for _, c := range changes {
+		byField[c.Field] = c
+	}
+
+	assert.Equal(t, FieldChange{Field: "job_version", From: 1, To: 2}, byField["job_version"])
+	assert.Equal(t, FieldChange{Field: "total_score", From: 72.0, To: 80.0}, byField["total_score"])
+	assert.Equal(t, FieldChange{Field: "grade", From: "B", To: "A"}, byField["grade"])
+	assert.Equal(t, []string{"Kubernetes"}, byField["matched_skills"].Added)
+	assert.Equal(t, []string{"Kubernetes"}, byField["missing_skills"].Removed)
+	assert.NotEmpty(t, byField["jd_text"].Lines, "JD 变化时给出逐行差异")
+	assert.NotContains(t, byField, "job_id")
+	assert.NotContains(t, byField, "tech_score", "未变化的分数不返回")
+
+	assert.Empty(t, diffEvaluations(a, a))
+}
+
+func TestLegacyEvaluation(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		wantOK bool
+	}{
+		{name: "评估结果", data: `{"name":"张三","total_score":85.5,"grade":"A","matched_skills":["Go"]}`, wantOK: true},
+		{name: "简历解析结果", data: `{"name":"张三","skills":["Go"],"total_years":5}`},
+		{name: "空", data: ""},
+		{name: "非 JSON", data: "grade total_score"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, ok := legacyEvaluation(tt.data)
+			assert.Equal(t, tt.wantOK, ok)
+			if ok {
+				assert.Equal(t, 85.5, result.TotalScore)
+				assert.Equal(t, []string{"Go"}, result.MatchedSkills)
+			}
+		})
+	}
+}
+
+func TestPickEvaluatorSetting(t *testing.T) {
+	settings := []models.EvaluatorSetting{
+		{ID: 1, Scope: models.EvaluatorScopeJob, ScopeKey: "7", Provider: "openai"},
+		{ID: 2, Scope: models.EvaluatorScopeDefault, Provider: "coze"},
+		{ID: 3, Scope: models.EvaluatorScopeDepartment, ScopeKey: "技术部", Provider: "fake"},
+		{ID: 4, Scope: models.EvaluatorScopeJob, ScopeKey: "8", Provider: "fake"},
+	}
+
+	tests := []struct {
+		name       string
+		settings   []models.EvaluatorSetting
+		jobID      uint
+		department string
+		want       uint
+	}{
+		{name: "职位配置优先", settings: settings, jobID: 7, department: "技术部", want: 1},
+		{name: "职位未配置时用部门配置", settings: settings, jobID: 9, department: "技术部", want: 3},
+		{name: "部门未配置时用全局配置", settings: settings, jobID: 9, department: "市场部", want: 2},
+		{name: "直接提交 JD 只看全局配置", settings: settings, want: 2},
+		{name: "没有配置", settings: settings[:1], jobID: 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pickEvaluatorSetting(tt.settings, tt.jobID, tt.department)
+			if tt.want == 0 {
+				assert.Nil(t, got)
+				return
+			}
+			if assert.NotNil(t, got) {
+				assert.Equal(t, tt.want, got.ID)
+			}
+		})
+	}
+}
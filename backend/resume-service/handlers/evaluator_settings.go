@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"resume-service/evaluator"
+	"resume-service/models"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm/clause"
+)
+
+// evaluatorFor 确定评估服务：请求指定的服务 > 职位配置 > 职位所属部门配置 > 全局配置 > 默认服务。
+// jobID 为 0（直接提交 JD）时只看全局配置。未指定服务且选中的服务未配置时改用离线规则评分
+func (h *AIEvaluateHandler) evaluatorFor(jobID uint, provider string) (evaluator.Evaluator, error) {
+	if provider != "" {
+		return h.Evaluators.Resolve(provider, "")
+	}
+
+	ev, err := h.configuredEvaluator(jobID)
+	if err != nil {
+		return nil, err
+	}
+	if !ev.IsConfigured() && h.Fallback != nil {
+		return h.Fallback, nil
+	}
+	return ev, nil
+}
+
+// configuredEvaluator 按职位、部门和全局配置选择的评估服务
+func (h *AIEvaluateHandler) configuredEvaluator(jobID uint) (evaluator.Evaluator, error) {
+	var department string
+	if jobID != 0 {
+		// 读取失败时按没有部门处理
+		_ = h.DB.Table("jobs").Select("COALESCE(department, '')").Where("id = ?", jobID).Row().Scan(&department)
+	}
+
+	var settings []models.EvaluatorSetting
+	err := h.DB.Where("(scope = ? AND scope_key = ?) OR (scope = ? AND scope_key = ?) OR scope = ?",
+		models.EvaluatorScopeJob, strconv.FormatUint(uint64(jobID), 10),
+		models.EvaluatorScopeDepartment, department,
+		models.EvaluatorScopeDefault,
+	).Find(&settings).Error
+	if err != nil {
+		return nil, err
+	}
+
+	setting := pickEvaluatorSetting(settings, jobID, department)
+	if setting == nil {
+		return h.Evaluators.Resolve("", "")
+	}
+	return h.Evaluators.Resolve(setting.Provider, setting.Model)
+}
+
+// pickEvaluatorSetting 按优先级选出生效的配置，没有配置时返回 nil
+func pickEvaluatorSetting(settings []models.EvaluatorSetting, jobID uint, department string) *models.EvaluatorSetting {
+	// 按优先级从低到高
+	type scopeKey struct{ scope, key string }
+	candidates := []scopeKey{{models.EvaluatorScopeDefault, ""}}
+	if department != "" {
+		candidates = append(candidates, scopeKey{models.EvaluatorScopeDepartment, department})
+	}
+	if jobID != 0 {
+		candidates = append(candidates, scopeKey{models.EvaluatorScopeJob, strconv.FormatUint(uint64(jobID), 10)})
+	}
+
+	var picked *models.EvaluatorSetting
+	rank := -1
+	for i := range settings {
+		for r, c := range candidates {
+			if settings[i].Scope == c.scope && settings[i].ScopeKey == c.key && r > rank {
+				picked, rank = &settings[i], r
+			}
+		}
+	}
+	return picked
+}
+
+// requireEvaluator 取本次评估使用的服务，服务不存在返回 400，未配置时返回 503
+func (h *AIEvaluateHandler) requireEvaluator(c *gin.Context, jobID uint, provider string) (evaluator.Evaluator, bool) {
+	ev, err := h.evaluatorFor(jobID, provider)
+	if errors.Is(err, evaluator.ErrUnknownProvider) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取评估服务配置失败: " + err.Error()})
+		return nil, false
+	}
+	if !ev.IsConfigured() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"code":    503,
+			"message": fmt.Sprintf("AI 服务未配置（%s），请检查评估服务的环境变量或为该职位选择其他评估服务", ev.Provider()),
+		})
+		return nil, false
+	}
+	return ev, true
+}
+
+// ListEvaluatorSettings 评估服务配置列表及可用的评估服务
+func (h *AIEvaluateHandler) ListEvaluatorSettings(c *gin.Context) {
+	query := h.DB.Order("scope, scope_key")
+	if scope := c.Query("scope"); scope != "" {
+		query = query.Where("scope = ?", scope)
+	}
+	var settings []models.EvaluatorSetting
+	if err := query.Find(&settings).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "查询评估服务配置失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"settings":  settings,
+			"providers": h.Evaluators.Providers(),
+			"default":   h.Evaluators.Default(),
+		},
+	})
+}
+
+// EvaluatorSettingRequest 设置某个范围使用的评估服务
+type EvaluatorSettingRequest struct {
+	Scope    string `json:"scope" binding:"required"`
+	ScopeKey string `json:"scope_key"`
+	Provider string `json:"provider" binding:"required"`
+	Model    string `json:"model"`
+}
+
+// SaveEvaluatorSetting 新增或更新评估服务配置，同一范围只保留一条
+func (h *AIEvaluateHandler) SaveEvaluatorSetting(c *gin.Context) {
+	var req EvaluatorSettingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": err.Error()})
+		return
+	}
+	req.ScopeKey = strings.TrimSpace(req.ScopeKey)
+	req.Model = strings.TrimSpace(req.Model)
+
+	switch req.Scope {
+	case models.EvaluatorScopeDefault:
+		req.ScopeKey = ""
+	case models.EvaluatorScopeDepartment:
+		if req.ScopeKey == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "请指定部门"})
+			return
+		}
+	case models.EvaluatorScopeJob:
+		jobID, err := strconv.ParseUint(req.ScopeKey, 10, 64)
+		if err != nil || jobID == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "scope_key 应为职位 ID"})
+			return
+		}
+		if _, err := loadJobContent(h.DB, uint(jobID)); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "职位不存在"})
+			return
+		}
+		req.ScopeKey = strconv.FormatUint(jobID, 10)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "scope 应为 default、department 或 job"})
+		return
+	}
+	if !h.Evaluators.Has(req.Provider) {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "未知的评估服务: " + req.Provider})
+		return
+	}
+
+	setting := models.EvaluatorSetting{
+		Scope:    req.Scope,
+		ScopeKey: req.ScopeKey,
+		Provider: req.Provider,
+		Model:    req.Model,
+	}
+	setting.UpdatedBy, _ = currentActor(c)
+	err := h.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "scope"}, {Name: "scope_key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"provider", "model", "updated_by", "updated_at"}),
+	}).Create(&setting).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "保存评估服务配置失败"})
+		return
+	}
+	h.DB.Where("scope = ? AND scope_key = ?", setting.Scope, setting.ScopeKey).Take(&setting)
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data":    setting,
+	})
+}
+
+// DeleteEvaluatorSetting 删除评估服务配置，该范围改用上一级配置
+func (h *AIEvaluateHandler) DeleteEvaluatorSetting(c *gin.Context) {
+	res := h.DB.Delete(&models.EvaluatorSetting{}, c.Param("id"))
+	if res.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "删除评估服务配置失败"})
+		return
+	}
+	if res.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "评估服务配置不存在"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "message": "success"})
+}
+
+// evaluate 调用评估服务，调用失败且允许兜底时改用离线规则评分；返回实际使用的评估服务。
+// 指定了评估服务的请求不兜底
+func (h *AIEvaluateHandler) evaluate(ctx context.Context, ev evaluator.Evaluator, name, jdText string, file []byte, fallback bool) (*evaluator.EvaluationResult, evaluator.Evaluator, error) {
+	result, err := ev.EvaluateResume(ctx, name, jdText, file)
+	if err == nil || !fallback || h.Fallback == nil || ev.Provider() == h.Fallback.Provider() || ctx.Err() != nil {
+		return result, ev, err
+	}
+
+	log.Printf("AI evaluation via %s failed, falling back to %s: %v", ev.Provider(), h.Fallback.Provider(), err)
+	result, fallbackErr := h.Fallback.EvaluateResume(ctx, name, jdText, file)
+	if fallbackErr != nil {
+		return nil, ev, err
+	}
+	result.RawResult["fallback_from"] = ev.Provider()
+	result.RawResult["fallback_reason"] = err.Error()
+	return result, h.Fallback, nil
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// jobContent 申请/评估时所依据的职位内容（直接读取 job-service 的 jobs 表）
+type jobContent struct {
+	ID           uint
+	Title        string
+	Description  string
+	Requirements pq.StringArray `gorm:"type:text[]"`
+	Version      int
+}
+
+// loadJobContent 读取职位当前内容及版本号
+func loadJobContent(db *gorm.DB, jobID uint) (jobContent, error) {
+	var job jobContent
+	err := db.Table("jobs").
+		Select("id, title, description, requirements, COALESCE(version, 1) AS version").
+		Where("id = ? AND deleted_at IS NULL", jobID).
+		Take(&job).Error
+	return job, err
+}
+
+// JDText 拼接职位描述与任职要求，作为 AI 评估的 JD 文本
+func (j jobContent) JDText() string {
+	var b strings.Builder
+	b.WriteString(j.Title)
+	if j.Description != "" {
+		b.WriteString("\n\n" + j.Description)
+	}
+	if len(j.Requirements) > 0 {
+		b.WriteString("\n\n任职要求：\n- " + strings.Join(j.Requirements, "\n- "))
+	}
+	return b.String()
+}
@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"resume-service/filecheck"
+	"resume-service/models"
+	"strings"
+	"time"
+
+	"common/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// resumeFileKey 新上传简历的存储 key：resumes/{年}/{月}/{纳秒}_{文件名}
+func resumeFileKey(filename string, now time.Time) string {
+	name := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r < 0x20 {
+			return '_'
+		}
+		return r
+	}, filepath.Base(filename))
+	return fmt.Sprintf("resumes/%s/%d_%s", now.Format("2006/01"), now.UnixNano(), name)
+}
+
+// readResumeFile 读取简历文件内容；迁移前的历史简历仍从本地路径读取
+func readResumeFile(ctx context.Context, store storage.Storage, resume *models.Resume) ([]byte, error) {
+	if resume.StorageKey == "" {
+		if resume.FilePath == "" {
+			return nil, storage.ErrNotFound
+		}
+		return os.ReadFile(resume.FilePath)
+	}
+
+	rc, err := store.Get(ctx, resume.StorageKey)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(io.LimitReader(rc, filecheck.MaxFileSize+1))
+}
+
+// deleteResumeFile 删除简历文件，失败只记录日志
+func (h *ResumeHandler) deleteResumeFile(ctx context.Context, resume *models.Resume) {
+	var err error
+	if resume.StorageKey != "" {
+		// 内容相同的简历共用存储对象，仍有引用时保留
+		if h.fileInUse(resume.StorageKey, resume.ID) {
+			return
+		}
+		err = h.Storage.Delete(ctx, resume.StorageKey)
+	} else if resume.FilePath != "" {
+		err = os.Remove(resume.FilePath)
+	}
+	if err != nil && !os.IsNotExist(err) {
+		log.Printf("Failed to delete resume file %d: %v", resume.ID, err)
+	}
+}
+
+// signFileURL 生成简历文件的限时访问地址，download 为 true 时以附件形式下载
+func (h *ResumeHandler) signFileURL(ctx context.Context, resume *models.Resume, download bool) (string, error) {
+	if resume.StorageKey == "" {
+		return "", storage.ErrNotFound
+	}
+	opts := storage.SignOptions{}
+	if download {
+		opts.Filename = resume.FileName
+	}
+	return h.Storage.SignedURL(ctx, resume.StorageKey, h.URLTTL, opts)
+}
+
+// FileViewerRoles 可以获取简历文件访问地址的招聘/HR 角色（hr 为自助注册的 HR 账号）
+var FileViewerRoles = []string{"admin", "hr_manager", "hr", "recruiter"}
+
+// canViewFiles 当前用户能否获取简历文件地址（角色由 JWT 中间件写入，匿名请求没有角色）
+func canViewFiles(c *gin.Context) bool {
+	role := c.GetString("role")
+	for _, r := range FileViewerRoles {
+		if role == r {
+			return true
+		}
+	}
+	return false
+}
+
+// withFileURLs 为返回的简历填充预览地址，file_url 不再是固定地址；
+// 只对招聘/HR 角色签发，否则按 ID 遍历即可拿到全部简历文件
+func (h *ResumeHandler) withFileURLs(c *gin.Context, resumes ...*models.Resume) {
+	allowed := canViewFiles(c)
+	for _, resume := range resumes {
+		resume.FileURL = ""
+		if !allowed || resume.StorageKey == "" {
+			continue
+		}
+		url, err := h.signFileURL(c.Request.Context(), resume, false)
+		if err != nil {
+			log.Printf("Failed to sign resume file url %d: %v", resume.ID, err)
+			continue
+		}
+		resume.FileURL = url
+	}
+}
+
+// GetResumeFileURL 获取简历文件的限时访问地址，download=true 时为下载地址
+func (h *ResumeHandler) GetResumeFileURL(c *gin.Context) {
+	var resume models.Resume
+	if err := h.DB.First(&resume, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "简历不存在"})
+		return
+	}
+	if h.blindFileForbidden(c, &resume) {
+		return
+	}
+
+	url, err := h.signFileURL(c.Request.Context(), &resume, c.Query("download") == "true")
+	if errors.Is(err, storage.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "文件不存在"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "生成文件地址失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"url":        url,
+			"expires_at": time.Now().Add(h.URLTTL),
+		},
+	})
+}
+
+// ServeResumeFile 校验签名后提供本地存储的简历文件（S3 存储的签名地址直接指向对象存储）
+func (h *ResumeHandler) ServeResumeFile(c *gin.Context) {
+	local, ok := h.Storage.(*storage.Local)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "文件不存在"})
+		return
+	}
+
+	// 只提供简历目录下的文件，隔离区等其他对象即使签名有效也不返回
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	if !strings.HasPrefix(key, "resumes/") {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "文件不存在"})
+		return
+	}
+	filename := c.Query("filename")
+	if err := local.Verify(key, c.Query("expires"), filename, c.Query("signature")); err != nil {
+		message := "文件地址无效"
+		if errors.Is(err, storage.ErrURLExpired) {
+			message = "文件地址已过期，请刷新页面后重试"
+		}
+		c.JSON(http.StatusForbidden, gin.H{"code": 1, "message": message})
+		return
+	}
+
+	rc, err := local.Get(c.Request.Context(), key)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "文件不存在"})
+		return
+	}
+	defer rc.Close()
+
+	f := rc.(*os.File)
+	info, err := f.Stat()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "读取文件失败"})
+		return
+	}
+
+	c.Header("Content-Type", storage.ContentType(key))
+	c.Header("X-Content-Type-Options", "nosniff")
+	if filename != "" {
+		c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	} else {
+		c.Header("Content-Disposition", "inline")
+	}
+	http.ServeContent(c.Writer, c.Request, "", info.ModTime(), f)
+}
+
+// DownloadResume 下载简历文件：重定向到限时下载地址，历史简历直接返回本地文件
+func (h *ResumeHandler) DownloadResume(c *gin.Context) {
+	var resume models.Resume
+	if err := h.DB.First(&resume, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "简历不存在"})
+		return
+	}
+	if h.blindFileForbidden(c, &resume) {
+		return
+	}
+
+	if resume.StorageKey == "" {
+		if _, err := os.Stat(resume.FilePath); resume.FilePath == "" || err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "文件不存在"})
+			return
+		}
+		c.FileAttachment(resume.FilePath, resume.FileName)
+		return
+	}
+
+	url, err := h.signFileURL(c.Request.Context(), &resume, true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "生成下载地址失败"})
+		return
+	}
+	c.Redirect(http.StatusFound, url)
+}
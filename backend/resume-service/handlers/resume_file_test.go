@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"resume-service/models"
+	"testing"
+	"time"
+
+	"common/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithFileURLs(t *testing.T) {
+	store, err := storage.NewLocal(t.TempDir(), "/api/v1/resumes/file", "secret")
+	require.NoError(t, err)
+	h := &ResumeHandler{Storage: store, URLTTL: time.Minute}
+
+	tests := []struct {
+		name   string
+		role   string
+		signed bool
+	}{
+		{"匿名请求不返回文件地址", "", false},
+		{"候选人不返回文件地址", "candidate", false},
+		{"面试官不返回文件地址", "interviewer", false},
+		{"招聘专员返回限时地址", "recruiter", true},
+		{"HR 返回限时地址", "hr", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("GET", "/api/v1/resumes", nil)
+			if tt.role != "" {
+				c.Set("role", tt.role)
+			}
+			resume := &models.Resume{ID: 1, StorageKey: "resumes/2024/06/a.pdf", FileURL: "/uploads/a.pdf"}
+
+			h.withFileURLs(c, resume)
+			if tt.signed {
+				assert.Contains(t, resume.FileURL, "/api/v1/resumes/file/resumes/2024/06/a.pdf?")
+			} else {
+				assert.Empty(t, resume.FileURL)
+			}
+		})
+	}
+}
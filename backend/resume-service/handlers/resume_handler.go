@@ -1,47 +1,73 @@
 package handlers
 
 import (
+	"bytes"
 	"encoding/base64"
-	"fmt"
+	"errors"
 	"log"
 	"net/http"
 	"os"
 	"path/filepath"
+	"resume-service/filecheck"
 	"resume-service/models"
 	"resume-service/parser"
 	"strconv"
-	"strings"
 	"time"
 
+	"common/blind"
+	"common/customfields"
+	"common/export"
+	"common/notify"
+	"common/pipeline"
+	"common/screening"
+	"common/storage"
+
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
-// 文件存储目录
-var UploadDir string
+// maxExportRows 单次导出的最大行数
+const maxExportRows = 5000
 
 type ResumeHandler struct {
-	DB     *gorm.DB
-	Parser *parser.ResumeParser
+	DB      *gorm.DB
+	Parser  *parser.ResumeParser
+	Storage storage.Storage
+	URLTTL  time.Duration // 简历文件签名地址的有效期
+
+	UploadPolicy filecheck.Policy
+	Scanner      filecheck.Scanner
+	ScanRequired bool         // 扫描服务不可用时拒绝上传
+	Blind        blind.Policy // 可查看盲筛中候选人身份信息的角色
+	Notifier     *notify.Client
 }
 
-func NewResumeHandler(db *gorm.DB) *ResumeHandler {
-	// 获取当前工作目录
-	wd, _ := os.Getwd()
-	UploadDir = filepath.Join(wd, "uploads")
-
-	// 确保上传目录存在
-	if err := os.MkdirAll(UploadDir, 0755); err != nil {
-		log.Printf("Warning: Failed to create upload dir: %v", err)
+func NewResumeHandler(db *gorm.DB, store storage.Storage) *ResumeHandler {
+	ttl, err := time.ParseDuration(os.Getenv("RESUME_URL_TTL"))
+	if err != nil || ttl <= 0 {
+		ttl = 15 * time.Minute
 	}
-	log.Printf("Upload directory: %s", UploadDir)
+
+	scanRequired, _ := strconv.ParseBool(os.Getenv("UPLOAD_SCAN_REQUIRED"))
 
 	return &ResumeHandler{
-		DB:     db,
-		Parser: parser.NewResumeParser(),
+		DB:           db,
+		Parser:       parser.NewResumeParser(),
+		Storage:      store,
+		URLTTL:       ttl,
+		UploadPolicy: filecheck.PolicyFromEnv(),
+		Scanner:      filecheck.NopScanner{},
+		ScanRequired: scanRequired,
+		Blind:        blind.PolicyFromEnv(),
 	}
 }
 
+// DefaultUploadDir 本地存储的默认目录（工作目录下的 uploads）
+func DefaultUploadDir() string {
+	wd, _ := os.Getwd()
+	return filepath.Join(wd, "uploads")
+}
+
 // UploadResumeFile 上传简历文件
 func (h *ResumeHandler) UploadResumeFile(c *gin.Context) {
 	log.Println("========== UploadResumeFile START ==========")
@@ -60,56 +86,27 @@ func (h *ResumeHandler) UploadResumeFile(c *gin.Context) {
 
 	log.Printf("[上传] ✓ 文件接收成功: 文件名=%s, 大小=%d bytes", file.Filename, file.Size)
 
-	// 检查文件类型（不区分大小写）
-	ext := strings.ToLower(filepath.Ext(file.Filename))
-	log.Printf("[上传] 文件扩展名: %s", ext)
-
-	allowedExts := map[string]bool{".pdf": true, ".doc": true, ".docx": true}
-	if !allowedExts[ext] {
-		log.Printf("[上传] ❌ 文件类型不支持: %s", ext)
-		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "只支持 PDF、DOC、DOCX 格式，当前格式: " + ext})
-		return
-	}
-	log.Printf("[上传] ✓ 文件类型检查通过")
-
-	// 检查文件大小（最大10MB）
-	if file.Size > 10*1024*1024 {
-		log.Printf("[上传] ❌ 文件太大: %d bytes", file.Size)
-		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "文件大小不能超过10MB"})
-		return
-	}
-	log.Printf("[上传] ✓ 文件大小检查通过")
-
-	// 生成唯一文件名
-	filename := fmt.Sprintf("%d_%s", time.Now().UnixNano(), file.Filename)
-	filePath := filepath.Join(UploadDir, filename)
-	log.Printf("[上传] 目标路径: %s", filePath)
-	log.Printf("[上传] UploadDir: %s", UploadDir)
-
-	// 检查目录是否存在
-	if _, err := os.Stat(UploadDir); os.IsNotExist(err) {
-		log.Printf("[上传] 目录不存在，创建目录: %s", UploadDir)
-		if err := os.MkdirAll(UploadDir, 0755); err != nil {
-			log.Printf("[上传] ❌ 创建目录失败: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "创建上传目录失败"})
+	// 按内容校验文件类型、大小并扫描病毒，未通过的文件不会写入简历存储
+	upload, err := h.checkUpload(c, file)
+	if err != nil {
+		var rejected *filecheck.RejectError
+		if !errors.As(err, &rejected) {
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "读取上传文件失败"})
 			return
 		}
-	}
-
-	// 保存文件
-	if err := c.SaveUploadedFile(file, filePath); err != nil {
-		log.Printf("[上传] ❌ 保存文件失败: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "文件保存失败: " + err.Error()})
+		status := http.StatusBadRequest
+		switch rejected.Reason {
+		case filecheck.ReasonSize:
+			status = http.StatusRequestEntityTooLarge
+		case filecheck.ReasonMalware:
+			status = http.StatusUnprocessableEntity
+		case filecheck.ReasonScanError:
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, gin.H{"code": 1, "message": rejected.Message})
 		return
 	}
-	log.Printf("[上传] ✓ 文件保存成功")
-
-	// 验证文件是否真的保存了
-	if info, err := os.Stat(filePath); err != nil {
-		log.Printf("[上传] ❌ 文件验证失败: %v", err)
-	} else {
-		log.Printf("[上传] ✓ 文件验证成功: 大小=%d bytes", info.Size())
-	}
+	log.Printf("[上传] ✓ 文件校验通过: 类型=%s", upload.Type)
 
 	// 获取其他表单数据
 	talentIDStr := c.PostForm("talent_id")
@@ -119,18 +116,13 @@ func (h *ResumeHandler) UploadResumeFile(c *gin.Context) {
 	talentID, _ := strconv.Atoi(talentIDStr)
 	jobID, _ := strconv.Atoi(jobIDStr)
 
-	// 生成访问URL
-	fileURL := "/api/v1/resumes/file/" + filename
-	log.Printf("[上传] 文件访问URL: %s", fileURL)
-
 	// 创建简历记录
 	resume := models.Resume{
-		FilePath: filePath,
-		FileName: file.Filename,
-		FileURL:  fileURL,
-		FileSize: file.Size,
-		FileType: ext,
-		Status:   "pending",
+		FileName:    upload.Name,
+		FileSize:    int64(len(upload.Data)),
+		FileType:    upload.Type,
+		ContentHash: upload.Hash,
+		Status:      ResumeStatusPending,
 	}
 	if talentID > 0 {
 		tid := uint(talentID)
@@ -141,72 +133,79 @@ func (h *ResumeHandler) UploadResumeFile(c *gin.Context) {
 		resume.JobID = &jid
 	}
 
+	// 同一人才重复上传相同内容时直接返回已有简历
+	duplicate, err := h.findDuplicateResume(upload.Hash, resume.TalentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "查询简历失败"})
+		return
+	}
+	if duplicate != nil {
+		log.Printf("[上传] ✓ 与简历 %d 内容相同，不重复保存", duplicate.ID)
+		h.withFileURLs(c, duplicate)
+		c.JSON(http.StatusOK, gin.H{
+			"code":      0,
+			"message":   "该简历已上传过，已返回现有记录",
+			"data":      duplicate,
+			"duplicate": true,
+		})
+		return
+	}
+
+	// 其他人才上传过相同文件时复用存储对象，否则写入对象存储
+	key, err := h.findStoredFile(upload.Hash)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "查询简历失败"})
+		return
+	}
+	stored := key == ""
+	if stored {
+		key = resumeFileKey(upload.Name, time.Now())
+		if err := h.Storage.Put(c.Request.Context(), key, bytes.NewReader(upload.Data), int64(len(upload.Data)), storage.ContentType(upload.Type)); err != nil {
+			log.Printf("[上传] ❌ 保存文件失败: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "文件保存失败: " + err.Error()})
+			return
+		}
+		log.Printf("[上传] ✓ 文件保存成功: key=%s", key)
+	} else {
+		log.Printf("[上传] ✓ 复用已存储的相同文件: key=%s", key)
+	}
+	resume.StorageKey = key
+
 	log.Printf("[上传] 准备写入数据库...")
-	if err := h.DB.Create(&resume).Error; err != nil {
+	err = h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&resume).Error; err != nil {
+			return err
+		}
+		return assignVersion(tx, &resume)
+	})
+	if err != nil {
 		log.Printf("[上传] ❌ 数据库写入失败: %v", err)
-		os.Remove(filePath)
+		if stored {
+			h.Storage.Delete(c.Request.Context(), key)
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "简历记录创建失败: " + err.Error()})
 		return
 	}
 
 	log.Printf("[上传] ✓ 数据库写入成功, ID=%d", resume.ID)
+
+	// 解析失败不影响上传结果，失败原因随简历返回，可修正后重新解析
+	message := "简历上传成功"
+	if err := h.parseFile(c.Request.Context(), &resume); err != nil {
+		log.Printf("[上传] ❌ 保存解析结果失败: %v", err)
+	} else if resume.Status == ResumeStatusFailed {
+		message = "简历上传成功，但解析失败: " + resume.ParseError
+	}
 	log.Println("========== UploadResumeFile SUCCESS ==========")
 
+	h.withFileURLs(c, &resume)
 	c.JSON(http.StatusCreated, gin.H{
 		"code":    0,
-		"message": "简历上传成功",
+		"message": message,
 		"data":    resume,
 	})
 }
 
-// ServeResumeFile 提供简历文件访问
-func (h *ResumeHandler) ServeResumeFile(c *gin.Context) {
-	filename := c.Param("filename")
-	filePath := filepath.Join(UploadDir, filename)
-
-	log.Printf("ServeResumeFile: filename=%s, UploadDir=%s, filePath=%s", filename, UploadDir, filePath)
-
-	// 检查文件是否存在
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		log.Printf("File not found: %s", filePath)
-		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "文件不存在"})
-		return
-	}
-
-	// 设置响应头
-	ext := strings.ToLower(filepath.Ext(filename))
-	switch ext {
-	case ".pdf":
-		c.Header("Content-Type", "application/pdf")
-	case ".doc":
-		c.Header("Content-Type", "application/msword")
-	case ".docx":
-		c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.wordprocessingml.document")
-	}
-
-	c.File(filePath)
-}
-
-// DownloadResume 下载简历文件
-func (h *ResumeHandler) DownloadResume(c *gin.Context) {
-	id := c.Param("id")
-	var resume models.Resume
-
-	if err := h.DB.First(&resume, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "简历不存在"})
-		return
-	}
-
-	// 检查文件是否存在
-	if _, err := os.Stat(resume.FilePath); os.IsNotExist(err) {
-		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "文件不存在"})
-		return
-	}
-
-	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", resume.FileName))
-	c.File(resume.FilePath)
-}
-
 // UploadResume 上传简历（JSON方式）
 func (h *ResumeHandler) UploadResume(c *gin.Context) {
 	var resume models.Resume
@@ -237,6 +236,11 @@ func (h *ResumeHandler) GetResume(c *gin.Context) {
 		return
 	}
 
+	h.withFileURLs(c, &resume)
+	if _, err := redactResumes(c, h.DB, h.Blind, &resume); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "查询盲筛状态失败"})
+		return
+	}
 	c.JSON(http.StatusOK, gin.H{
 		"code":    0,
 		"message": "success",
@@ -294,8 +298,11 @@ func (h *ResumeHandler) ListResumes(c *gin.Context) {
 	}
 
 	result := make([]ResumeWithTalent, len(resumes))
+	refs := make([]*models.Resume, len(resumes))
 	for i, resume := range resumes {
+		h.withFileURLs(c, &resume)
 		result[i].Resume = resume
+		refs[i] = &result[i].Resume
 		if resume.TalentID != nil {
 			var talent struct {
 				Name string `json:"name"`
@@ -304,6 +311,16 @@ func (h *ResumeHandler) ListResumes(c *gin.Context) {
 			result[i].TalentName = talent.Name
 		}
 	}
+	redacted, err := redactResumes(c, h.DB, h.Blind, refs...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "查询盲筛状态失败"})
+		return
+	}
+	for i := range result {
+		if redacted[result[i].ID] && result[i].TalentID != nil {
+			result[i].TalentName = blind.Alias(*result[i].TalentID)
+		}
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"code":    0,
@@ -328,11 +345,19 @@ func (h *ResumeHandler) DeleteResume(c *gin.Context) {
 	}
 
 	// 删除文件
-	if resume.FilePath != "" {
-		os.Remove(resume.FilePath)
-	}
+	h.deleteResumeFile(c.Request.Context(), &resume)
 
-	if err := h.DB.Delete(&resume).Error; err != nil {
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&resume).Error; err != nil {
+			return err
+		}
+		// 删除的是当前简历时，由最新的其他版本接替
+		if resume.IsPrimary && resume.TalentID != nil {
+			return promoteLatest(tx, *resume.TalentID)
+		}
+		return nil
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "Failed to delete resume"})
 		return
 	}
@@ -351,7 +376,35 @@ func (h *ResumeHandler) CreateApplication(c *gin.Context) {
 		return
 	}
 
-	if err := h.DB.Create(&app).Error; err != nil {
+	defs, err := customfields.LoadDefinitions(h.DB, customfields.EntityApplication)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "Failed to load custom fields"})
+		return
+	}
+	if app.CustomFields, err = customfields.Validate(defs, app.CustomFields, true); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": err.Error()})
+		return
+	}
+	// HR 代为录入时筛选问题可不作答，已作答的同样按淘汰条件评估
+	questions, err := screening.Load(h.DB, app.JobID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "Failed to load screening questions"})
+		return
+	}
+	if app.Answers, err = questions.Check(app.Answers, false); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": err.Error()})
+		return
+	}
+
+	actorID, actorName := currentActor(c)
+	err = h.DB.Transaction(func(tx *gorm.DB) error {
+		return insertApplication(tx, &app, actorID, actorName)
+	})
+	if errors.Is(err, errDuplicateApplication) {
+		c.JSON(http.StatusConflict, gin.H{"code": 1, "message": "该候选人已申请过此职位"})
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "Failed to create application"})
 		return
 	}
@@ -367,9 +420,6 @@ func (h *ResumeHandler) CreateApplication(c *gin.Context) {
 func (h *ResumeHandler) ListApplications(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
-	jobID := c.Query("job_id")
-	talentID := c.Query("talent_id")
-	status := c.Query("status")
 
 	offset := (page - 1) * pageSize
 
@@ -384,16 +434,10 @@ func (h *ResumeHandler) ListApplications(c *gin.Context) {
 		MatchScore int      `json:"match_score"`
 	}
 
-	query := h.DB.Model(&models.Application{})
-
-	if jobID != "" {
-		query = query.Where("applications.job_id = ?", jobID)
-	}
-	if talentID != "" {
-		query = query.Where("applications.talent_id = ?", talentID)
-	}
-	if status != "" {
-		query = query.Where("applications.status = ?", status)
+	query, _, err := h.applicationQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": err.Error()})
+		return
 	}
 
 	var total int64
@@ -428,6 +472,16 @@ func (h *ResumeHandler) ListApplications(c *gin.Context) {
 		h.DB.Table("jobs").Where("id = ?", app.JobID).First(&job)
 		result[i].JobTitle = job.Title
 	}
+	screening, err := h.blindTalents(c, applications)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "查询盲筛状态失败"})
+		return
+	}
+	for i := range result {
+		if screening[result[i].TalentID] {
+			result[i].TalentName = blind.Alias(result[i].TalentID)
+		}
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"code":    0,
@@ -441,19 +495,92 @@ func (h *ResumeHandler) ListApplications(c *gin.Context) {
 	})
 }
 
-// UpdateApplication 更新申请状态
-func (h *ResumeHandler) UpdateApplication(c *gin.Context) {
-	id := c.Param("id")
-	var app models.Application
+// ExportApplications 导出申请列表（CSV，包含自定义字段，筛选条件同列表；盲筛中的候选人按当前用户权限隐藏姓名）
+func (h *ResumeHandler) ExportApplications(c *gin.Context) {
+	query, defs, err := h.applicationQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": err.Error()})
+		return
+	}
+
+	var applications []models.Application
+	if err := query.Order("created_at DESC").Limit(maxExportRows).Find(&applications).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "Failed to export applications"})
+		return
+	}
 
-	if err := h.DB.First(&app, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "Application not found"})
+	blinded, err := h.blindTalents(c, applications)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "查询盲筛状态失败"})
 		return
 	}
 
+	header := []string{"ID", "候选人", "职位", "职位版本", "状态", "备注", "申请时间", "筛选结果", "筛选问题回答"}
+	header = append(header, customfields.Header(defs)...)
+
+	rows := make([][]string, 0, len(applications))
+	for _, app := range applications {
+		var talentName, jobTitle string
+		h.DB.Table("talents").Select("name").Where("id = ?", app.TalentID).Row().Scan(&talentName)
+		h.DB.Table("jobs").Select("title").Where("id = ?", app.JobID).Row().Scan(&jobTitle)
+		if blinded[app.TalentID] {
+			talentName = blind.Alias(app.TalentID)
+		}
+
+		row := []string{
+			strconv.Itoa(int(app.ID)), talentName, jobTitle, strconv.Itoa(app.JobVersion), app.Status, app.Notes,
+			app.CreatedAt.Format("2006-01-02 15:04"),
+		}
+		row = append(row, screeningCells(app)...)
+		rows = append(rows, append(row, customfields.Cells(defs, app.CustomFields)...))
+	}
+
+	export.WriteCSV(c, "applications", header, rows)
+}
+
+// applicationQuery 构建申请列表/导出的筛选条件
+func (h *ResumeHandler) applicationQuery(c *gin.Context) (*gorm.DB, []customfields.Definition, error) {
+	jobID := c.Query("job_id")
+	talentID := c.Query("talent_id")
+	status := c.Query("status")
+
+	query := h.DB.Model(&models.Application{})
+
+	if jobID != "" {
+		query = query.Where("applications.job_id = ?", jobID)
+	}
+	if talentID != "" {
+		query = query.Where("applications.talent_id = ?", talentID)
+	}
+	if status != "" {
+		query = query.Where("applications.status = ?", status)
+	}
+	// 筛选问题评估结果：passed, flagged, rejected
+	if outcome := c.Query("screening"); outcome != "" {
+		query = query.Where("applications.screening ->> 'outcome' = ?", outcome)
+	}
+
+	// 自定义字段筛选
+	defs, err := customfields.LoadDefinitions(h.DB, customfields.EntityApplication)
+	if err != nil {
+		return nil, nil, err
+	}
+	query, err = customfields.ApplyFilters(query, defs, c.Request.URL.Query(), "applications.custom_fields")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return query, defs, nil
+}
+
+// UpdateApplication 更新申请备注、自定义字段和阶段；阶段变更须符合职位的招聘流程并记录流转历史
+func (h *ResumeHandler) UpdateApplication(c *gin.Context) {
 	var req struct {
-		Status string `json:"status"`
-		Notes  string `json:"notes"`
+		Status        string              `json:"status"`
+		Notes         string              `json:"notes"`
+		CustomFields  customfields.Values `json:"custom_fields"`
+		Reason        string              `json:"reason"`         // 阶段变更原因
+		RejectionCode string              `json:"rejection_code"` // 淘汰时必填，见 pipeline.RejectionReasons
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -461,11 +588,48 @@ func (h *ResumeHandler) UpdateApplication(c *gin.Context) {
 		return
 	}
 
-	app.Status = req.Status
-	app.Notes = req.Notes
+	var defs []customfields.Definition
+	if req.CustomFields != nil {
+		var err error
+		if defs, err = customfields.LoadDefinitions(h.DB, customfields.EntityApplication); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "Failed to load custom fields"})
+			return
+		}
+	}
+
+	actorID, actorName := currentActor(c)
+	var app *models.Application
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if app, err = lockApplication(tx, c.Param("id")); err != nil {
+			return err
+		}
+
+		app.Notes = req.Notes
+		if req.CustomFields != nil {
+			if app.CustomFields, err = customfields.Patch(defs, app.CustomFields, req.CustomFields); err != nil {
+				return moveError{err}
+			}
+		}
 
-	if err := h.DB.Save(&app).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "Failed to update application"})
+		// 提交的状态与当前相同时只更新备注和自定义字段
+		if req.Status != "" && pipeline.Normalize(req.Status) != pipeline.Normalize(app.Status) {
+			move := stageMove{To: req.Status, Reason: req.Reason, RejectionCode: req.RejectionCode, ActorID: actorID, ActorName: actorName}
+			if err := moveApplication(tx, app, move); err != nil {
+				return err
+			}
+		}
+		return tx.Model(app).Select("notes", "custom_fields").Updates(app).Error
+	})
+	if err != nil {
+		switch status := moveStatus(err); status {
+		case http.StatusNotFound:
+			c.JSON(status, gin.H{"code": 1, "message": "Application not found"})
+		case http.StatusBadRequest:
+			c.JSON(status, gin.H{"code": 1, "message": err.Error()})
+		default:
+			c.JSON(status, gin.H{"code": 1, "message": "Failed to update application"})
+		}
 		return
 	}
 
@@ -541,7 +705,7 @@ func (h *ResumeHandler) ListResumesForEvaluation(c *gin.Context) {
 	offset := (page - 1) * pageSize
 
 	query := h.DB.Model(&models.Resume{})
-	query = query.Where("file_path != '' AND file_path IS NOT NULL")
+	query = query.Where("(storage_key != '' AND storage_key IS NOT NULL) OR (file_path != '' AND file_path IS NOT NULL)")
 
 	if status != "" && status != "all" {
 		query = query.Where("status = ?", status)
@@ -579,11 +743,9 @@ func (h *ResumeHandler) ListResumesForEvaluation(c *gin.Context) {
 			HasFile:  false,
 		}
 
-		if resume.FilePath != "" {
-			if fileBytes, err := os.ReadFile(resume.FilePath); err == nil {
-				item.HasFile = true
-				item.FileBase64 = base64.StdEncoding.EncodeToString(fileBytes)
-			}
+		if fileBytes, err := readResumeFile(c.Request.Context(), h.Storage, &resume); err == nil {
+			item.HasFile = true
+			item.FileBase64 = base64.StdEncoding.EncodeToString(fileBytes)
 		}
 
 		result = append(result, item)
@@ -625,6 +787,10 @@ func (h *ResumeHandler) UpdateResumeStatus(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "Failed to update resume status"})
 		return
 	}
+	if _, err := redactResumes(c, h.DB, h.Blind, &resume); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "查询盲筛状态失败"})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"code":    0,
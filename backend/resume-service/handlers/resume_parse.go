@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"resume-service/extractor"
+	"resume-service/models"
+	"resume-service/parser"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 简历文件解析状态
+const (
+	ResumeStatusPending = "pending"
+	ResumeStatusParsed  = "parsed"
+	ResumeStatusFailed  = "failed"
+)
+
+// parseFile 从简历文件中提取文本并结构化解析，结果写入 ParsedData；
+// 失败时状态置为 failed 并记录可展示给用户的原因。解析成功后同步人才档案
+func (h *ResumeHandler) parseFile(ctx context.Context, resume *models.Resume) error {
+	data, err := readResumeFile(ctx, h.Storage, resume)
+	var parsed *parser.ParsedResume
+	if err != nil {
+		err = fmt.Errorf("读取简历文件失败: %w", err)
+	} else {
+		var text string
+		if text, err = extractor.Extract(bytes.NewReader(data), int64(len(data)), resume.FileName); err == nil {
+			parsed, err = h.Parser.Parse(text)
+		}
+	}
+	if err == nil {
+		var parsedJSON []byte
+		if parsedJSON, err = json.Marshal(parsed); err == nil {
+			resume.ParsedData = string(parsedJSON)
+		}
+	}
+
+	if err != nil {
+		log.Printf("[解析] 简历 %d 解析失败: %v", resume.ID, err)
+		resume.Status = ResumeStatusFailed
+		resume.ParseError = err.Error()
+	} else {
+		resume.Status = ResumeStatusParsed
+		resume.ParseError = ""
+	}
+
+	if err := h.DB.Model(resume).Select("parsed_data", "status", "parse_error").Updates(resume).Error; err != nil {
+		return err
+	}
+
+	// 人才同步失败不影响解析结果，可重新解析重试
+	if parsed != nil {
+		if err := h.syncTalent(ctx, resume, parsed); err != nil {
+			log.Printf("[人才] 简历 %d 同步人才档案失败: %v", resume.ID, err)
+		}
+	}
+	return nil
+}
+
+// ReparseResume 重新解析已上传的简历文件
+func (h *ResumeHandler) ReparseResume(c *gin.Context) {
+	var resume models.Resume
+	if err := h.DB.First(&resume, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "简历不存在"})
+		return
+	}
+	if resume.StorageKey == "" && resume.FilePath == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "该简历没有上传文件，无法解析"})
+		return
+	}
+
+	if err := h.parseFile(c.Request.Context(), &resume); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "保存解析结果失败"})
+		return
+	}
+
+	if resume.Status == ResumeStatusFailed {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"code": 1, "message": resume.ParseError, "data": resume})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "解析成功",
+		"data":    resume,
+	})
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"resume-service/filecheck"
+	"resume-service/models"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// checkedUpload 通过校验的上传文件
+type checkedUpload struct {
+	Name string // 清理后的文件名
+	Type string // 按内容识别的类型
+	Data []byte
+	Hash string // 内容 SHA-256
+}
+
+// checkUpload 读取上传文件并校验类型、大小和病毒扫描结果，未通过时返回 *filecheck.RejectError 并记录拒收日志
+func (h *ResumeHandler) checkUpload(c *gin.Context, file *multipart.FileHeader) (*checkedUpload, error) {
+	ctx := c.Request.Context()
+	name := filecheck.SanitizeFilename(file.Filename)
+	limit := h.UploadPolicy.MaxUploadSize()
+
+	// 超过最大限制的文件不读取内容，直接拒绝
+	if file.Size > limit {
+		err := &filecheck.RejectError{Reason: filecheck.ReasonSize, Message: fmt.Sprintf("文件大小不能超过%dMB", limit>>20)}
+		h.rejectUpload(c, models.RejectedUpload{FileName: name, FileSize: file.Size}, err, nil)
+		return nil, err
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+	data, err := io.ReadAll(io.LimitReader(src, limit+1))
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	record := models.RejectedUpload{
+		FileName:     name,
+		FileSize:     int64(len(data)),
+		SHA256:       hex.EncodeToString(sum[:]),
+		DetectedType: filecheck.Detect(data),
+	}
+
+	typ, err := filecheck.Validate(data, name, h.UploadPolicy)
+	if err != nil {
+		h.rejectUpload(c, record, err, nil)
+		return nil, err
+	}
+
+	verdict, err := h.Scanner.Scan(ctx, bytes.NewReader(data))
+	if err != nil {
+		if h.ScanRequired {
+			rejected := &filecheck.RejectError{Reason: filecheck.ReasonScanError, Message: "文件安全扫描暂不可用，请稍后重试"}
+			record.Detail = err.Error()
+			h.rejectUpload(c, record, rejected, nil)
+			return nil, rejected
+		}
+		log.Printf("[上传] ⚠ 病毒扫描失败，跳过扫描: %s: %v", name, err)
+	}
+	if verdict.Infected {
+		rejected := &filecheck.RejectError{Reason: filecheck.ReasonMalware, Message: "文件未通过安全检查，已被拦截"}
+		record.Signature = verdict.Signature
+		h.rejectUpload(c, record, rejected, data)
+		return nil, rejected
+	}
+
+	return &checkedUpload{Name: name, Type: typ, Data: data, Hash: record.SHA256}, nil
+}
+
+// rejectUpload 记录拒收的上传；quarantine 非空时将文件存入隔离区，隔离区文件不会生成访问地址
+func (h *ResumeHandler) rejectUpload(c *gin.Context, record models.RejectedUpload, err error, quarantine []byte) {
+	var rejected *filecheck.RejectError
+	if errors.As(err, &rejected) {
+		record.Reason = rejected.Reason
+		if record.Detail == "" {
+			record.Detail = rejected.Message
+		}
+	}
+	record.ClientIP = c.ClientIP()
+
+	if quarantine != nil {
+		key := quarantineKey(record.FileName, time.Now())
+		ctx := context.WithoutCancel(c.Request.Context())
+		if err := h.Storage.Put(ctx, key, bytes.NewReader(quarantine), int64(len(quarantine)), "application/octet-stream"); err != nil {
+			log.Printf("[上传] ❌ 隔离文件失败: %v", err)
+		} else {
+			record.QuarantineKey = key
+		}
+	}
+
+	log.Printf("[上传] ❌ 拒收文件: 文件名=%s, 大小=%d, 原因=%s, 详情=%s, 特征=%s, sha256=%s, ip=%s",
+		record.FileName, record.FileSize, record.Reason, record.Detail, record.Signature, record.SHA256, record.ClientIP)
+	if err := h.DB.Create(&record).Error; err != nil {
+		log.Printf("[上传] ❌ 拒收记录写入失败: %v", err)
+	}
+}
+
+// quarantinePrefix 隔离区的存储 key 前缀
+const quarantinePrefix = "quarantine/"
+
+func quarantineKey(filename string, now time.Time) string {
+	return quarantinePrefix + resumeFileKey(filename, now)
+}
+
+// ListRejectedUploads 查询拒收的上传记录
+func (h *ResumeHandler) ListRejectedUploads(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	query := h.DB.Model(&models.RejectedUpload{})
+	if reason := c.Query("reason"); reason != "" {
+		query = query.Where("reason = ?", reason)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var records []models.RejectedUpload
+	if err := query.Order("id DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&records).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "查询拒收记录失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"records":   records,
+			"total":     total,
+			"page":      page,
+			"page_size": pageSize,
+		},
+	})
+}
@@ -0,0 +1,359 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"reflect"
+	"resume-service/models"
+	"resume-service/parser"
+	"strings"
+
+	"common/textdiff"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// FieldChange 两个简历版本间一个解析字段的变化
+type FieldChange struct {
+	Field   string          `json:"field"`
+	From    interface{}     `json:"from,omitempty"`
+	To      interface{}     `json:"to,omitempty"`
+	Added   []string        `json:"added,omitempty"`   // 列表字段新增项
+	Removed []string        `json:"removed,omitempty"` // 列表字段移除项
+	Lines   []textdiff.Line `json:"lines,omitempty"`   // 个人简介逐行差异
+}
+
+// findDuplicateResume 查找内容相同的已有简历：指定人才时只在该人才的简历中查找
+func (h *ResumeHandler) findDuplicateResume(hash string, talentID *uint) (*models.Resume, error) {
+	query := h.DB.Where("content_hash = ?", hash)
+	if talentID != nil {
+		query = query.Where("talent_id = ?", *talentID)
+	}
+	var resume models.Resume
+	err := query.Order("id").Take(&resume).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &resume, nil
+}
+
+// findStoredFile 查找内容相同的已存储文件，没有时返回空字符串
+func (h *ResumeHandler) findStoredFile(hash string) (string, error) {
+	var keys []string
+	err := h.DB.Model(&models.Resume{}).
+		Where("content_hash = ? AND storage_key <> ''", hash).
+		Order("id").Limit(1).Pluck("storage_key", &keys).Error
+	if err != nil || len(keys) == 0 {
+		return "", err
+	}
+	return keys[0], nil
+}
+
+// fileInUse 存储对象是否仍被其他简历引用
+func (h *ResumeHandler) fileInUse(key string, exceptID uint) bool {
+	var count int64
+	h.DB.Model(&models.Resume{}).Where("storage_key = ? AND id <> ?", key, exceptID).Count(&count)
+	return count > 0
+}
+
+// assignVersion 为已关联人才的新简历分配版本号，并设为该人才的当前简历
+func assignVersion(tx *gorm.DB, resume *models.Resume) error {
+	if resume.TalentID == nil || resume.Version > 0 {
+		return nil
+	}
+	talentID := *resume.TalentID
+
+	// 锁定人才记录，避免同一人才并发上传得到相同的版本号
+	if err := tx.Exec("SELECT id FROM talents WHERE id = ? FOR UPDATE", talentID).Error; err != nil {
+		return err
+	}
+	var latest int
+	if err := tx.Model(&models.Resume{}).Where("talent_id = ?", talentID).
+		Select("COALESCE(MAX(version), 0)").Scan(&latest).Error; err != nil {
+		return err
+	}
+
+	resume.Version = latest + 1
+	if err := tx.Model(resume).Select("version").Updates(resume).Error; err != nil {
+		return err
+	}
+	return setPrimary(tx, resume)
+}
+
+// setPrimary 将简历设为人才的当前简历，同步人才档案的 resume_id
+func setPrimary(tx *gorm.DB, resume *models.Resume) error {
+	talentID := *resume.TalentID
+	if err := tx.Model(&models.Resume{}).Where("talent_id = ? AND id <> ?", talentID, resume.ID).
+		Update("is_primary", false).Error; err != nil {
+		return err
+	}
+	resume.IsPrimary = true
+	if err := tx.Model(resume).Update("is_primary", true).Error; err != nil {
+		return err
+	}
+	return tx.Table("talents").Where("id = ?", talentID).Update("resume_id", resume.ID).Error
+}
+
+// promoteLatest 当前简历被删除后，将最新版本设为当前简历
+func promoteLatest(tx *gorm.DB, talentID uint) error {
+	var latest models.Resume
+	err := tx.Where("talent_id = ?", talentID).Order("version DESC, id DESC").Take(&latest).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return tx.Table("talents").Where("id = ? AND resume_id IS NOT NULL", talentID).Update("resume_id", nil).Error
+	}
+	if err != nil {
+		return err
+	}
+	return setPrimary(tx, &latest)
+}
+
+// SetPrimaryResume 将简历设为人才的当前简历
+func (h *ResumeHandler) SetPrimaryResume(c *gin.Context) {
+	var resume models.Resume
+	if err := h.DB.First(&resume, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "简历不存在"})
+		return
+	}
+	if resume.TalentID == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "简历未关联人才"})
+		return
+	}
+
+	if err := h.DB.Transaction(func(tx *gorm.DB) error { return setPrimary(tx, &resume) }); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "设置当前简历失败"})
+		return
+	}
+
+	h.withFileURLs(c, &resume)
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data":    resume,
+	})
+}
+
+// ListResumeVersions 获取简历所属人才的全部简历版本（按版本号倒序）
+func (h *ResumeHandler) ListResumeVersions(c *gin.Context) {
+	var resume models.Resume
+	if err := h.DB.First(&resume, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "简历不存在"})
+		return
+	}
+
+	versions := []models.Resume{resume}
+	if resume.TalentID != nil {
+		versions = nil
+		if err := h.DB.Where("talent_id = ?", *resume.TalentID).Order("version DESC, id DESC").Find(&versions).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "查询简历版本失败"})
+			return
+		}
+	}
+	refs := make([]*models.Resume, len(versions))
+	for i := range versions {
+		h.withFileURLs(c, &versions[i])
+		refs[i] = &versions[i]
+	}
+	if _, err := redactResumes(c, h.DB, h.Blind, refs...); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "查询盲筛状态失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"talent_id": resume.TalentID,
+			"versions":  versions,
+			"total":     len(versions),
+		},
+	})
+}
+
+// DiffResumeVersions 对比两个简历版本的解析结果，默认 from 为同一人才的上一个版本
+func (h *ResumeHandler) DiffResumeVersions(c *gin.Context) {
+	var to models.Resume
+	if err := h.DB.First(&to, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "简历不存在"})
+		return
+	}
+
+	var from models.Resume
+	var err error
+	if fromID := c.Query("from"); fromID != "" {
+		err = h.DB.First(&from, fromID).Error
+	} else if to.TalentID != nil && to.Version > 1 {
+		err = h.DB.Where("talent_id = ? AND version < ?", *to.TalentID, to.Version).
+			Order("version DESC").Take(&from).Error
+	} else {
+		err = gorm.ErrRecordNotFound
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "没有可对比的简历版本"})
+		return
+	}
+
+	a, errA := decodeParsed(from.ParsedData)
+	b, errB := decodeParsed(to.ParsedData)
+	if errA != nil || errB != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"code": 1, "message": "简历尚未解析，无法对比"})
+		return
+	}
+	identities, err := blindResumes(c, h.DB, h.Blind, []*models.Resume{&from, &to})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "查询盲筛状态失败"})
+		return
+	}
+	if id, ok := identities[from.ID]; ok {
+		redactParsed(a, id)
+	}
+	if id, ok := identities[to.ID]; ok {
+		redactParsed(b, id)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"from":         from.ID,
+			"to":           to.ID,
+			"from_version": from.Version,
+			"to_version":   to.Version,
+			"same_file":    from.ContentHash != "" && from.ContentHash == to.ContentHash,
+			"changes":      diffParsed(a, b),
+		},
+	})
+}
+
+// decodeParsed 读取简历的解析结果
+func decodeParsed(data string) (*parser.ParsedResume, error) {
+	if data == "" {
+		return nil, errors.New("not parsed")
+	}
+	var parsed parser.ParsedResume
+	if err := json.Unmarshal([]byte(data), &parsed); err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+// diffParsed 逐字段对比两份解析结果；工作、教育、项目经历按条目对比
+func diffParsed(a, b *parser.ParsedResume) []FieldChange {
+	changes := make([]FieldChange, 0)
+
+	scalars := []struct {
+		field    string
+		from, to interface{}
+	}{
+		{"name", a.Name, b.Name},
+		{"phone", a.Phone, b.Phone},
+		{"email", a.Email, b.Email},
+		{"location", a.Location, b.Location},
+		{"education", a.Education, b.Education},
+		{"experience", a.Experience, b.Experience},
+		{"total_years", a.TotalYears, b.TotalYears},
+	}
+	for _, s := range scalars {
+		if !reflect.DeepEqual(s.from, s.to) {
+			changes = append(changes, FieldChange{Field: s.field, From: s.from, To: s.to})
+		}
+	}
+
+	if lines := textdiff.Lines(a.Summary, b.Summary); textdiff.Changed(lines) {
+		changes = append(changes, FieldChange{Field: "summary", Lines: lines})
+	}
+
+	lists := []struct {
+		field    string
+		from, to []string
+	}{
+		{"skills", a.Skills, b.Skills},
+		{"certificates", a.Certificates, b.Certificates},
+		{"work_experiences", workLines(a.WorkExperiences), workLines(b.WorkExperiences)},
+		{"educations", educationLines(a.Educations), educationLines(b.Educations)},
+		{"projects", projectLines(a.Projects), projectLines(b.Projects)},
+	}
+	for _, l := range lists {
+		if added, removed := textdiff.SetDiff(l.from, l.to); len(added) > 0 || len(removed) > 0 {
+			changes = append(changes, FieldChange{Field: l.field, Added: added, Removed: removed})
+		}
+	}
+
+	return changes
+}
+
+// period 经历的起止时间
+func period(start, end string, current bool) string {
+	if current {
+		end = "至今"
+	}
+	if start == "" && end == "" {
+		return ""
+	}
+	return start + "~" + end
+}
+
+// joinNonEmpty 用“ | ”连接非空字段
+func joinNonEmpty(parts ...string) string {
+	var kept []string
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			kept = append(kept, p)
+		}
+	}
+	return strings.Join(kept, " | ")
+}
+
+func workLines(items []parser.WorkExperience) []string {
+	lines := make([]string, 0, len(items))
+	for _, w := range items {
+		lines = append(lines, joinNonEmpty(w.Company, w.Title, period(w.StartDate, w.EndDate, w.Current)))
+	}
+	return lines
+}
+
+func educationLines(items []parser.EducationEntry) []string {
+	lines := make([]string, 0, len(items))
+	for _, e := range items {
+		lines = append(lines, joinNonEmpty(e.School, e.Degree, e.Major, period(e.StartDate, e.EndDate, false)))
+	}
+	return lines
+}
+
+func projectLines(items []parser.Project) []string {
+	lines := make([]string, 0, len(items))
+	for _, p := range items {
+		lines = append(lines, joinNonEmpty(p.Name, p.Role, period(p.StartDate, p.EndDate, p.Current)))
+	}
+	return lines
+}
+
+// BackfillVersions 为已关联人才但尚无版本号的历史简历按上传顺序补建版本，最新一份设为当前简历
+func BackfillVersions(db *gorm.DB) {
+	var talentIDs []uint
+	db.Model(&models.Resume{}).Where("talent_id IS NOT NULL AND (version IS NULL OR version = 0)").
+		Distinct().Pluck("talent_id", &talentIDs)
+
+	for _, talentID := range talentIDs {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var resumes []models.Resume
+			if err := tx.Where("talent_id = ? AND (version IS NULL OR version = 0)", talentID).Order("id").Find(&resumes).Error; err != nil {
+				return err
+			}
+			for i := range resumes {
+				if err := assignVersion(tx, &resumes[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			log.Printf("Failed to backfill resume versions for talent %d: %v", talentID, err)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"resume-service/parser"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffParsed(t *testing.T) {
+	a := &parser.ParsedResume{
+		Name:   "张三",
+		Phone:  "13812345678",
+		Skills: []string{"Go", "MySQL"},
+		WorkExperiences: []parser.WorkExperience{
+			{Company: "某科技公司", Title: "Go开发工程师", StartDate: "2018-07", Current: true},
+		},
+	}
+	b := &parser.ParsedResume{
+		Name:   "张三",
+		Phone:  "13900000000",
+		Skills: []string{"Go", "Kubernetes"},
+		WorkExperiences: []parser.WorkExperience{
+			{Company: "某科技公司", Title: "Go开发工程师", StartDate: "2018-07", EndDate: "2023-06"},
+			{Company: "某云计算公司", Title: "高级Go开发工程师", StartDate: "2023-07", Current: true},
+		},
+	}
+
+	changes := diffParsed(a, b)
+	byField := make(map[string]FieldChange, len(changes))
+	for _, change := range changes {
+		byField[change.Field] = change
+	}
+
+	assert.NotContains(t, byField, "name", "未变化的字段不返回")
+	assert.Equal(t, FieldChange{Field: "phone", From: "13812345678", To: "13900000000"}, byField["phone"])
+	assert.Equal(t, FieldChange{Field: "skills", Added: []string{"Kubernetes"}, Removed: []string{"MySQL"}}, byField["skills"])
+	assert.Equal(t, FieldChange{
+		Field: "work_experiences",
+		Added: []string{
+			"某科技公司 | Go开发工程师 | 2018-07~2023-06",
+			"某云计算公司 | 高级Go开发工程师 | 2023-07~至今",
+		},
+		Removed: []string{"某科技公司 | Go开发工程师 | 2018-07~至今"},
+	}, byField["work_experiences"])
+
+	assert.Empty(t, diffParsed(a, a), "相同版本无差异")
+}
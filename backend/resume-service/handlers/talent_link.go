@@ -0,0 +1,314 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"resume-service/models"
+	"resume-service/parser"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// talentProfile 简历同步到的人才档案字段（直接读写 talent-service 的 talents 表）
+type talentProfile struct {
+	ID         uint
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	Name       string
+	Email      string
+	Phone      string
+	Skills     pq.StringArray `gorm:"type:text[]"`
+	Experience int
+	Education  string
+	Location   string
+	Status     string
+	Source     string
+	ResumeID   *uint
+	UserID     *uint // 候选人门户的登录账号
+}
+
+// talentColumns 参与冲突检查的档案字段
+var talentColumns = []string{"name", "email", "phone", "experience", "education", "location"}
+
+// profileFromResume 从解析结果提取档案字段
+func profileFromResume(parsed *parser.ParsedResume) talentProfile {
+	experience := int(math.Round(parsed.TotalYears))
+	if years, err := strconv.Atoi(strings.TrimSuffix(parsed.Experience, "年")); err == nil && years > 0 {
+		experience = years
+	}
+	return talentProfile{
+		Name:       strings.TrimSpace(parsed.Name),
+		Email:      strings.ToLower(strings.TrimSpace(parsed.Email)),
+		Phone:      strings.TrimSpace(parsed.Phone),
+		Skills:     parsed.Skills,
+		Experience: experience,
+		Education:  parsed.Education,
+		Location:   parsed.Location,
+	}
+}
+
+// fieldValue 档案字段的字符串形式，0 年经验视为空
+func (t talentProfile) fieldValue(field string) string {
+	switch field {
+	case "name":
+		return strings.TrimSpace(t.Name)
+	case "email":
+		return strings.TrimSpace(t.Email)
+	case "phone":
+		return strings.TrimSpace(t.Phone)
+	case "experience":
+		if t.Experience <= 0 {
+			return ""
+		}
+		return strconv.Itoa(t.Experience)
+	case "education":
+		return strings.TrimSpace(t.Education)
+	case "location":
+		return strings.TrimSpace(t.Location)
+	}
+	return ""
+}
+
+// sameFieldValue 比较档案值与解析值：邮箱忽略大小写，手机号忽略分隔符和国家码
+func sameFieldValue(field, a, b string) bool {
+	if field == "phone" {
+		return normalizePhone(a) == normalizePhone(b)
+	}
+	return strings.EqualFold(a, b)
+}
+
+// normalizePhone 只保留数字，去掉国内手机号的 86 前缀
+func normalizePhone(phone string) string {
+	digits := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, phone)
+	if len(digits) == 13 && strings.HasPrefix(digits, "86") {
+		return digits[2:]
+	}
+	return digits
+}
+
+// mergeTalentProfile 将解析结果合并到已有档案：空字段直接补全，技能取并集，
+// 已有值不同的字段记为冲突而不覆盖
+func mergeTalentProfile(current, parsed talentProfile) (map[string]interface{}, models.FieldConflicts) {
+	updates := map[string]interface{}{}
+	var conflicts models.FieldConflicts
+
+	for _, field := range talentColumns {
+		newValue := parsed.fieldValue(field)
+		if newValue == "" {
+			continue
+		}
+		oldValue := current.fieldValue(field)
+		switch {
+		case oldValue == "":
+			if field == "experience" {
+				updates[field] = parsed.Experience
+			} else {
+				updates[field] = newValue
+			}
+		case !sameFieldValue(field, oldValue, newValue):
+			conflicts = append(conflicts, models.FieldConflict{Field: field, Current: oldValue, Parsed: newValue})
+		}
+	}
+
+	if skills, changed := mergeSkills(current.Skills, parsed.Skills); changed {
+		updates["skills"] = skills
+	}
+	return updates, conflicts
+}
+
+// mergeSkills 按忽略大小写去重追加新技能
+func mergeSkills(current, parsed []string) (pq.StringArray, bool) {
+	seen := make(map[string]bool, len(current))
+	merged := append(pq.StringArray{}, current...)
+	for _, skill := range current {
+		seen[strings.ToLower(skill)] = true
+	}
+	changed := false
+	for _, skill := range parsed {
+		if key := strings.ToLower(skill); !seen[key] {
+			seen[key] = true
+			merged = append(merged, skill)
+			changed = true
+		}
+	}
+	return merged, changed
+}
+
+// findTalent 按邮箱、手机号依次查找已有人才
+func findTalent(tx *gorm.DB, email, phone string) (*talentProfile, error) {
+	var talent talentProfile
+	if email != "" {
+		err := tx.Table("talents").Where("deleted_at IS NULL AND LOWER(email) = ?", strings.ToLower(email)).Order("id").Take(&talent).Error
+		if err == nil {
+			return &talent, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+	if digits := normalizePhone(phone); digits != "" {
+		err := tx.Table("talents").
+			Where(`deleted_at IS NULL AND regexp_replace(phone, '\D', '', 'g') IN ?`, []string{digits, "86" + digits}).
+			Order("id").Take(&talent).Error
+		if err == nil {
+			return &talent, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// syncTalent 解析成功后创建或更新简历对应的人才档案，并互相关联；
+// 已指定 talent_id 时更新该人才，否则按邮箱、手机号去重。新关联的简历成为该人才的最新版本
+func (h *ResumeHandler) syncTalent(ctx context.Context, resume *models.Resume, parsed *parser.ParsedResume) error {
+	profile := profileFromResume(parsed)
+
+	return h.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var talent *talentProfile
+		if resume.TalentID != nil {
+			var existing talentProfile
+			err := tx.Table("talents").Where("id = ? AND deleted_at IS NULL", *resume.TalentID).Take(&existing).Error
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			if err == nil {
+				talent = &existing
+			}
+		}
+		if talent == nil {
+			var err error
+			if talent, err = findTalent(tx, profile.Email, profile.Phone); err != nil {
+				return err
+			}
+		}
+
+		created := false
+		if talent == nil {
+			// 无法识别身份的简历不建档，避免产生无法去重的人才
+			if profile.Name == "" || (profile.Email == "" && profile.Phone == "") {
+				return nil
+			}
+			talent = &talentProfile{}
+			*talent = profile
+			talent.Status = "active"
+			talent.Source = "resume"
+			if err := tx.Table("talents").Create(talent).Error; err != nil {
+				return err
+			}
+			log.Printf("[人才] 简历 %d 创建人才 %d", resume.ID, talent.ID)
+			created = true
+		}
+
+		talentID := talent.ID
+		resume.TalentID = &talentID
+		if err := assignVersion(tx, resume); err != nil {
+			return err
+		}
+
+		// 新建的人才已由简历填充，无需再比较
+		if created {
+			resume.TalentConflicts = nil
+		} else {
+			updates, conflicts := mergeTalentProfile(*talent, profile)
+			if len(updates) > 0 {
+				updates["updated_at"] = time.Now()
+				if err := tx.Table("talents").Where("id = ?", talent.ID).Updates(updates).Error; err != nil {
+					return err
+				}
+			}
+			resume.TalentConflicts = conflicts
+			if len(conflicts) > 0 {
+				log.Printf("[人才] 简历 %d 与人才 %d 有 %d 个字段不一致", resume.ID, talent.ID, len(conflicts))
+			}
+		}
+
+		return tx.Model(resume).Select("talent_id", "talent_conflicts").Updates(resume).Error
+	})
+}
+
+// ResolveTalentConflictsRequest 处理字段冲突：accept 中的字段以简历解析值覆盖档案，ignore 中的字段保留档案值
+type ResolveTalentConflictsRequest struct {
+	Accept []string `json:"accept"`
+	Ignore []string `json:"ignore"`
+}
+
+// ResolveTalentConflicts 处理简历与人才档案的字段冲突
+func (h *ResumeHandler) ResolveTalentConflicts(c *gin.Context) {
+	var resume models.Resume
+	if err := h.DB.First(&resume, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": "简历不存在"})
+		return
+	}
+	var req ResolveTalentConflictsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": err.Error()})
+		return
+	}
+	if resume.TalentID == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "简历未关联人才"})
+		return
+	}
+
+	accept := make(map[string]bool, len(req.Accept))
+	for _, field := range req.Accept {
+		accept[field] = true
+	}
+	ignore := make(map[string]bool, len(req.Ignore))
+	for _, field := range req.Ignore {
+		ignore[field] = true
+	}
+
+	updates := map[string]interface{}{}
+	var remaining models.FieldConflicts
+	for _, conflict := range resume.TalentConflicts {
+		switch {
+		case accept[conflict.Field]:
+			if conflict.Field == "experience" {
+				years, _ := strconv.Atoi(conflict.Parsed)
+				updates[conflict.Field] = years
+			} else {
+				updates[conflict.Field] = conflict.Parsed
+			}
+		case ignore[conflict.Field]:
+		default:
+			remaining = append(remaining, conflict)
+		}
+	}
+
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if len(updates) > 0 {
+			updates["updated_at"] = time.Now()
+			if err := tx.Table("talents").Where("id = ?", *resume.TalentID).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		resume.TalentConflicts = remaining
+		return tx.Model(&resume).Select("talent_conflicts").Updates(&resume).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "更新人才档案失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data":    resume,
+	})
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"resume-service/models"
+	"resume-service/parser"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		name     string
+		phone    string
+		expected string
+	}{
+		{name: "国内手机号", phone: "13812345678", expected: "13812345678"},
+		{name: "带国家码和分隔符", phone: "+86 138-1234-5678", expected: "13812345678"},
+		{name: "国际号码", phone: "+1 (415) 555-0100", expected: "14155550100"},
+		{name: "空", phone: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, normalizePhone(tt.phone))
+		})
+	}
+}
+
+func TestProfileFromResume(t *testing.T) {
+	profile := profileFromResume(&parser.ParsedResume{Name: " 张三 ", Email: "ZhangSan@Example.com", Experience: "5年", TotalYears: 4.2})
+	assert.Equal(t, "张三", profile.Name)
+	assert.Equal(t, "zhangsan@example.com", profile.Email)
+	assert.Equal(t, 5, profile.Experience, "优先使用简历写明的年限")
+
+	profile = profileFromResume(&parser.ParsedResume{TotalYears: 3.6})
+	assert.Equal(t, 4, profile.Experience, "未写明时按工作经历计算")
+}
+
+func TestMergeTalentProfile(t *testing.T) {
+	current := talentProfile{
+		Name:      "张三",
+		Email:     "zhangsan@example.com",
+		Phone:     "+86 138-1234-5678",
+		Skills:    pq.StringArray{"Go", "MySQL"},
+		Education: "本科",
+	}
+	parsed := talentProfile{
+		Name:       "张三",
+		Email:      "ZhangSan@example.com",
+		Phone:      "13812345678",
+		Skills:     pq.StringArray{"go", "Kubernetes"},
+		Experience: 6,
+		Education:  "硕士",
+		Location:   "北京",
+	}
+
+	updates, conflicts := mergeTalentProfile(current, parsed)
+	assert.Equal(t, map[string]interface{}{
+		"experience": 6,
+		"location":   "北京",
+		"skills":     pq.StringArray{"Go", "MySQL", "Kubernetes"},
+	}, updates, "空字段补全、技能取并集，大小写和号码格式差异不算冲突")
+	assert.Equal(t, models.FieldConflicts{
+		{Field: "education", Current: "本科", Parsed: "硕士"},
+	}, conflicts, "已有值不同的字段不覆盖")
+
+	t.Run("解析结果为空的字段不处理", func(t *testing.T) {
+		updates, conflicts := mergeTalentProfile(current, talentProfile{})
+		assert.Empty(t, updates)
+		assert.Empty(t, conflicts)
+	})
+}
@@ -3,10 +3,17 @@ package main
 import (
 	"log"
 	"os"
+	"resume-service/calibration"
+	"resume-service/evalqueue"
+	"resume-service/filecheck"
 	"resume-service/handlers"
 	"resume-service/models"
+	"time"
 
 	"common/middleware"
+	"common/notify"
+	"common/skills"
+	"common/storage"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/driver/postgres"
@@ -38,17 +45,44 @@ func main() {
 		log.Fatal("Failed to connect database:", err)
 	}
 
-	if err := db.AutoMigrate(&models.Resume{}, &models.Application{}); err != nil {
+	if err := db.AutoMigrate(&models.Resume{}, &models.Application{}, &models.StageTransition{}, &models.RejectedUpload{}, &models.Evaluation{}, &models.EvaluationBatch{}, &models.EvaluationBatchItem{}, &models.EvaluatorSetting{}, &models.CalibrationReport{}); err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
+	handlers.BackfillVersions(db)
+	handlers.BackfillStageHistory(db)
+	handlers.BackfillEvaluations(db)
+
+	// 加载共享技能字典（由 job-service 维护），并定期刷新
+	if err := skills.Default().Load(db); err != nil {
+		log.Printf("Warning: Failed to load skill dictionary: %v", err)
+	}
+	skills.Default().Watch(db, 5*time.Minute)
 
 	r := gin.Default()
 
 	r.Use(middleware.CORS())
 	r.Use(middleware.SimpleOperationLog("resume-service"))
 
-	resumeHandler := handlers.NewResumeHandler(db)
-	aiHandler := handlers.NewAIEvaluateHandler(db)
+	// 简历文件存储：默认本地 uploads 目录，多副本部署时配置 STORAGE_DRIVER=s3
+	store, err := storage.New(storage.ConfigFromEnv(handlers.DefaultUploadDir(), "/api/v1/resumes/file"))
+	if err != nil {
+		log.Fatal("Failed to init storage:", err)
+	}
+
+	resumeHandler := handlers.NewResumeHandler(db, store)
+	// 上传病毒扫描：配置 CLAMD_ADDRESS 后启用
+	if resumeHandler.Scanner, err = filecheck.ScannerFromEnv(); err != nil {
+		log.Fatal("Failed to init virus scanner:", err)
+	}
+	aiHandler := handlers.NewAIEvaluateHandler(db, store)
+	// 批量评估队列：任务保存在数据库，重启后继续处理，进度经 message-service 推送给提交人
+	notifier := notify.NewClientFromEnv()
+	// 候选人申请、撤回时通知职位负责人
+	resumeHandler.Notifier = notifier
+	aiHandler.Queue = evalqueue.NewFromEnv(db, aiHandler.EvaluateBatchItem, notifier)
+	aiHandler.Queue.Start()
+	// AI 评分校准报告：定期对照评估分数与招聘结果，生成快照并通知管理员
+	calibration.NewFromEnv(db, notifier).Start()
 
 	// 健康检查
 	r.GET("/health", func(c *gin.Context) {
@@ -58,37 +92,78 @@ func main() {
 	api := r.Group("/api/v1")
 	{
 		// Resume routes
-		resumes := api.Group("/resumes")
+		// 携带登录 token 时按角色判断能否查看盲筛中简历的身份信息
+		resumes := api.Group("/resumes", middleware.OptionalJWTAuth())
 		{
 			resumes.POST("", resumeHandler.UploadResume)
 			resumes.POST("/upload", resumeHandler.UploadResumeFile)
 			resumes.GET("", resumeHandler.ListResumes)
-			resumes.GET("/evaluation", resumeHandler.ListResumesForEvaluation) // 用于自动评估系统
-			resumes.GET("/file/:filename", resumeHandler.ServeResumeFile)      // 提供文件访问
+			resumes.GET("/evaluation", resumeHandler.ListResumesForEvaluation)  // 用于自动评估系统
+			resumes.GET("/rejected-uploads", resumeHandler.ListRejectedUploads) // 拒收的上传文件
+			resumes.GET("/file/*key", resumeHandler.ServeResumeFile)            // 签名地址访问文件（本地存储）
 			resumes.GET("/:id", resumeHandler.GetResume)
-			resumes.GET("/:id/download", resumeHandler.DownloadResume)
+			// 简历文件地址只签发给登录的招聘/HR 角色
+			resumes.GET("/:id/download", middleware.JWTAuth(), middleware.RoleAuth(handlers.FileViewerRoles...), resumeHandler.DownloadResume)
+			resumes.GET("/:id/file-url", middleware.JWTAuth(), middleware.RoleAuth(handlers.FileViewerRoles...), resumeHandler.GetResumeFileURL) // 限时访问地址
 			resumes.DELETE("/:id", resumeHandler.DeleteResume)
-			resumes.PUT("/:id/status", resumeHandler.UpdateResumeStatus) // 更新简历状态
+			resumes.PUT("/:id/status", resumeHandler.UpdateResumeStatus)                // 更新简历状态
+			resumes.POST("/:id/reparse", resumeHandler.ReparseResume)                   // 重新解析简历文件
+			resumes.POST("/:id/talent-conflicts", resumeHandler.ResolveTalentConflicts) // 处理与人才档案的字段冲突
+			resumes.GET("/:id/versions", resumeHandler.ListResumeVersions)              // 同一人才的简历版本
+			resumes.GET("/:id/diff", resumeHandler.DiffResumeVersions)                  // 对比两个版本的解析结果
+			resumes.PUT("/:id/primary", resumeHandler.SetPrimaryResume)                 // 设为当前简历
+			resumes.GET("/:id/evaluations", aiHandler.ListResumeEvaluations)            // AI 评估历史
 			resumes.POST("/parse", resumeHandler.ParseResume)
 			resumes.POST("/match", resumeHandler.MatchResumeToJob)
 		}
 
 		// AI Evaluation routes
-		ai := api.Group("/ai")
+		// 携带登录 token 时记录批量任务的提交人，并按角色判断能否查看盲筛中候选人的姓名
+		ai := api.Group("/ai", middleware.OptionalJWTAuth())
 		{
 			ai.GET("/config", aiHandler.CheckAIConfig)
+			ai.GET("/cache/stats", aiHandler.CacheStats) // 评估结果缓存命中统计
 			ai.POST("/evaluate", aiHandler.EvaluateByResumeID)
 			ai.POST("/evaluate/upload", aiHandler.EvaluateUploadedFile)
-			ai.POST("/evaluate/batch", aiHandler.BatchEvaluate)
-			ai.GET("/evaluate/:id/result", aiHandler.GetEvaluationResult)
+			ai.POST("/evaluate/batch", aiHandler.BatchEvaluate)            // 提交批量评估任务
+			ai.GET("/evaluate/batches", aiHandler.ListBatches)             // 批量评估任务列表
+			ai.GET("/evaluate/batches/:id", aiHandler.GetBatch)            // 任务进度
+			ai.POST("/evaluate/batches/:id/cancel", aiHandler.CancelBatch) // 取消任务
+			ai.GET("/evaluate/:id/result", aiHandler.GetEvaluationResult)  // 简历最近一次评估
+			ai.GET("/evaluations/compare", aiHandler.CompareEvaluations)   // 对比两次评估
+			ai.GET("/evaluations/:id", aiHandler.GetEvaluation)
+			ai.GET("/calibration", aiHandler.GetCalibration)                 // AI 分数与招聘结果的校准报告
+			ai.GET("/calibration/reports", aiHandler.ListCalibrationReports) // 定期生成的报告快照
+			ai.GET("/calibration/reports/:id", aiHandler.GetCalibrationReport)
+			ai.GET("/provider-settings", aiHandler.ListEvaluatorSettings) // 评估服务配置
+			// 修改评估服务配置需管理员权限
+			ai.PUT("/provider-settings", middleware.JWTAuth(), middleware.RoleAuth("admin"), aiHandler.SaveEvaluatorSetting) // 按全局、部门或职位选择评估服务
+			ai.DELETE("/provider-settings/:id", middleware.JWTAuth(), middleware.RoleAuth("admin"), aiHandler.DeleteEvaluatorSetting)
 		}
 
 		// Application routes
-		applications := api.Group("/applications")
+		// 携带登录 token 时记录阶段流转的操作人，并按角色判断能否查看盲筛中候选人的姓名
+		applications := api.Group("/applications", middleware.OptionalJWTAuth())
 		{
 			applications.POST("", resumeHandler.CreateApplication)
 			applications.GET("", resumeHandler.ListApplications)
+			applications.GET("/export", resumeHandler.ExportApplications)
+			applications.GET("/pipeline", resumeHandler.GetApplicationPipeline)     // 职位招聘流程及淘汰原因
+			applications.GET("/funnel", resumeHandler.GetPipelineFunnel)            // 按流转历史统计的漏斗
+			applications.GET("/time-in-stage", resumeHandler.GetTimeInStage)        // 各阶段停留时长
+			applications.POST("/bulk-move", resumeHandler.BulkMoveApplications)     // 批量流转
+			applications.POST("/bulk-reject", resumeHandler.BulkRejectApplications) // 批量淘汰
 			applications.PUT("/:id", resumeHandler.UpdateApplication)
+			applications.GET("/:id/history", resumeHandler.GetApplicationHistory) // 阶段流转历史
+
+			// 候选人门户：按登录账号关联的人才档案申请职位、查看进度和撤回
+			mine := applications.Group("/mine", middleware.JWTAuth())
+			{
+				mine.POST("", resumeHandler.ApplyToJob)
+				mine.GET("", resumeHandler.ListMyApplications)
+				mine.GET("/:id", resumeHandler.GetMyApplication)
+				mine.POST("/:id/withdraw", resumeHandler.WithdrawMyApplication)
+			}
 		}
 	}
 
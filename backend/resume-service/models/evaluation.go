@@ -0,0 +1,160 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Evaluation 一次 AI 评估的结果，每次评估新增一条，简历上的 MatchScore 等字段只反映最近一次
+type Evaluation struct {
+	ID            uint      `gorm:"primarykey" json:"id"`
+	CreatedAt     time.Time `gorm:"index" json:"created_at"`
+	ResumeID      uint      `gorm:"index;not null" json:"resume_id"`
+	CandidateName string    `gorm:"size:255" json:"candidate_name"`
+	// 评估所依据的职位及版本；直接提交 JD 文本时 JobID 为空，以 JDHash 区分不同 JD
+	JobID      *uint  `gorm:"index" json:"job_id,omitempty"`
+	JobVersion int    `json:"job_version,omitempty"`
+	JDHash     string `gorm:"size:64;index" json:"jd_hash"`
+	JDText     string `gorm:"type:text" json:"jd_text"`
+	Provider   string `gorm:"size:50" json:"provider"` // coze、openai、fake
+	Model      string `gorm:"size:100" json:"model"`   // 模型名称或工作流 ID
+	DurationMs int64  `json:"duration_ms"`
+	// 缓存键，由简历内容、JD 和评估服务版本决定，有效期内相同键的评估直接复用结果
+	CacheKey     string `gorm:"size:64;index" json:"-"`
+	CachedFromID *uint  `json:"cached_from_id,omitempty"` // 结果复用自该评估记录，未调用评估服务
+
+	TotalScore      float64        `json:"total_score"`
+	Grade           string         `gorm:"size:10" json:"grade"`
+	JDMatchScore    int            `json:"jd_match_score"`
+	AgeScore        int            `json:"age_score"`
+	ExperienceScore int            `json:"experience_score"`
+	EducationScore  int            `json:"education_score"`
+	CompanyScore    int            `json:"company_score"`
+	TechScore       int            `json:"tech_score"`
+	ProjectScore    int            `json:"project_score"`
+	Recommendation  string         `gorm:"type:text" json:"recommendation"`
+	MatchedSkills   pq.StringArray `gorm:"type:text[]" json:"matched_skills"`
+	MissingSkills   pq.StringArray `gorm:"type:text[]" json:"missing_skills"`
+	Summary         string         `gorm:"type:text" json:"summary"`
+	RawResult       JSONObject     `gorm:"type:jsonb" json:"raw_result"` // 评估服务返回的原始结果
+	Redacted        bool           `gorm:"-" json:"redacted,omitempty"`  // 盲筛中，候选人姓名及评语中的身份信息已隐藏
+}
+
+// 批量评估状态
+const (
+	BatchQueued    = "queued"    // 等待处理
+	BatchRunning   = "running"   // 处理中
+	BatchCompleted = "completed" // 全部条目已结束（成功或失败）
+	BatchCancelled = "cancelled" // 已取消，未开始的条目不再处理
+)
+
+// 批量评估条目状态
+const (
+	ItemQueued    = "queued"
+	ItemRunning   = "running"
+	ItemSucceeded = "succeeded"
+	ItemFailed    = "failed" // 重试次数用尽或不可重试的错误
+	ItemCancelled = "cancelled"
+)
+
+// EvaluationBatch 批量评估任务，提交时确定 JD，由后台工作协程逐条处理，服务重启后继续
+type EvaluationBatch struct {
+	ID         uint       `gorm:"primarykey" json:"id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	Status     string     `gorm:"size:20;index;default:'queued'" json:"status"`
+	JobID      *uint      `json:"job_id,omitempty"`
+	JobVersion int        `json:"job_version,omitempty"`
+	JDText     string     `gorm:"type:text" json:"-"`
+	Provider   string     `gorm:"size:50" json:"provider,omitempty"` // 指定的评估服务，为空时按职位配置选择
+	Force      bool       `json:"force,omitempty"`                   // 强制重新评估，不复用缓存结果
+	Total      int        `json:"total"`
+	CreatedBy  *uint      `json:"created_by,omitempty"` // 提交人，进度推送给该用户
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// EvaluationBatchItem 批量评估中的一份简历
+type EvaluationBatchItem struct {
+	ID            uint       `gorm:"primarykey" json:"id"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	BatchID       uint       `gorm:"index;not null" json:"batch_id"`
+	ResumeID      uint       `gorm:"not null" json:"resume_id"`
+	Status        string     `gorm:"size:20;default:'queued';index:idx_evaluation_item_pending,priority:1" json:"status"`
+	NextAttemptAt time.Time  `gorm:"index:idx_evaluation_item_pending,priority:2" json:"next_attempt_at"` // 排队或重试等待到的时间
+	Attempts      int        `json:"attempts"`
+	LastError     string     `gorm:"size:1000" json:"last_error,omitempty"`
+	EvaluationID  *uint      `json:"evaluation_id,omitempty"`
+	LockedBy      string     `gorm:"size:100" json:"-"` // 处理中的工作实例
+	LockedAt      *time.Time `json:"-"`
+}
+
+// 评估服务配置范围
+const (
+	EvaluatorScopeDefault    = "default"
+	EvaluatorScopeDepartment = "department" // 按部门区分，部门是平台内的租户边界
+	EvaluatorScopeJob        = "job"
+)
+
+// EvaluatorSetting 指定某个范围使用的评估服务，优先级：职位 > 部门 > 全局 > 环境变量 EVAL_PROVIDER
+type EvaluatorSetting struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Scope     string    `gorm:"size:20;not null;uniqueIndex:idx_evaluator_setting_scope,priority:1" json:"scope"`
+	ScopeKey  string    `gorm:"size:100;not null;default:'';uniqueIndex:idx_evaluator_setting_scope,priority:2" json:"scope_key"` // 职位 ID 或部门名称，全局配置为空
+	Provider  string    `gorm:"size:50;not null" json:"provider"`
+	Model     string    `gorm:"size:100" json:"model"` // 为空时使用服务的默认模型
+	UpdatedBy *uint     `json:"updated_by,omitempty"`
+}
+
+// CalibrationReport 定期生成的 AI 评分校准报告快照，内容见 calibration.Report
+type CalibrationReport struct {
+	ID          uint       `gorm:"primarykey" json:"id"`
+	CreatedAt   time.Time  `gorm:"index" json:"created_at"`
+	PeriodStart time.Time  `json:"period_start"` // 报告覆盖的评估时间范围
+	PeriodEnd   time.Time  `json:"period_end"`
+	Samples     int        `json:"samples"`
+	Report      JSONObject `gorm:"type:jsonb" json:"report,omitempty"`
+}
+
+// JSONObject 以 JSONB 存储的任意对象
+type JSONObject map[string]interface{}
+
+// Value 实现 driver.Valuer
+func (o JSONObject) Value() (driver.Value, error) {
+	if o == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(o)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan 实现 sql.Scanner
+func (o *JSONObject) Scan(src interface{}) error {
+	var data []byte
+	switch s := src.(type) {
+	case nil:
+		*o = nil
+		return nil
+	case []byte:
+		data = s
+	case string:
+		data = []byte(s)
+	default:
+		return fmt.Errorf("models: unsupported scan type %T for JSONObject", src)
+	}
+	if len(data) == 0 {
+		*o = nil
+		return nil
+	}
+	return json.Unmarshal(data, o)
+}
@@ -1,8 +1,14 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 
+	"common/customfields"
+	"common/screening"
+
 	"gorm.io/gorm"
 )
 
@@ -14,24 +20,119 @@ type Resume struct {
 	TalentID   *uint          `json:"talent_id"` // 可为空
 	JobID      *uint          `json:"job_id"`    // 可为空
 	FileName   string         `gorm:"size:255" json:"file_name"`
-	FilePath   string         `gorm:"size:500" json:"file_path"`
-	FileURL    string         `gorm:"size:500" json:"file_url"`
+	FilePath   string         `gorm:"size:500" json:"file_path"` // 迁移到对象存储前的本地路径
+	StorageKey string         `gorm:"size:500" json:"-"`         // 对象存储 key
+	FileURL    string         `gorm:"size:500" json:"file_url"`  // 返回时填充的限时访问地址
 	FileSize   int64          `json:"file_size"`
 	FileType   string         `gorm:"size:20" json:"file_type"`                // .pdf, .doc, .docx
 	ParsedData string         `gorm:"type:text" json:"parsed_data"`            // JSON格式存储解析后的数据
 	MatchScore int            `json:"match_score"`                             // 匹配度分数
-	Status     string         `gorm:"size:20;default:'pending'" json:"status"` // pending, parsed, active, archived
+	Status     string         `gorm:"size:20;default:'pending'" json:"status"` // pending, parsed, failed, active, archived
+	ParseError string         `gorm:"size:500" json:"parse_error,omitempty"`   // 文件解析失败原因
+	// 文件内容的 SHA-256，用于识别重复上传；内容相同的简历共用同一个存储对象
+	ContentHash string `gorm:"size:64;index" json:"content_hash,omitempty"`
+	Version     int    `json:"version,omitempty"`               // 同一人才的简历版本号，从 1 开始
+	IsPrimary   bool   `gorm:"default:false" json:"is_primary"` // 是否为人才的当前简历
+	// 最近一次 AI 评估所依据的职位及其版本
+	EvaluatedJobID      *uint `json:"evaluated_job_id,omitempty"`
+	EvaluatedJobVersion int   `json:"evaluated_job_version,omitempty"`
+	// 解析结果与已有人才档案不一致的字段，待人工确认
+	TalentConflicts FieldConflicts `gorm:"type:jsonb;default:'[]'" json:"talent_conflicts,omitempty"`
+	// 盲筛中，文件名、解析结果中的身份信息已隐藏，原始文件不可访问
+	Redacted bool `gorm:"-" json:"redacted,omitempty"`
+}
+
+// FieldConflict 简历解析值与人才档案现有值不一致的字段
+type FieldConflict struct {
+	Field   string `json:"field"`   // 人才档案字段，如 phone、education
+	Current string `json:"current"` // 档案中的现有值
+	Parsed  string `json:"parsed"`  // 简历解析出的值
+}
+
+// FieldConflicts 以 JSONB 存储的字段冲突列表
+type FieldConflicts []FieldConflict
+
+// Value 实现 driver.Valuer
+func (c FieldConflicts) Value() (driver.Value, error) {
+	if c == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan 实现 sql.Scanner
+func (c *FieldConflicts) Scan(src interface{}) error {
+	var data []byte
+	switch s := src.(type) {
+	case nil:
+		*c = nil
+		return nil
+	case []byte:
+		data = s
+	case string:
+		data = []byte(s)
+	default:
+		return fmt.Errorf("models: unsupported scan type %T for FieldConflicts", src)
+	}
+	if len(data) == 0 {
+		*c = nil
+		return nil
+	}
+	return json.Unmarshal(data, c)
 }
 
 type Application struct {
-	ID          uint           `gorm:"primarykey" json:"id"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
-	JobID       uint           `json:"job_id"`
-	TalentID    uint           `json:"talent_id"`
-	ResumeID    uint           `json:"resume_id"`
-	Status      string         `gorm:"size:20;default:'pending'" json:"status"` // pending, reviewed, interview, rejected, accepted
-	CoverLetter string         `gorm:"type:text" json:"cover_letter"`
-	Notes       string         `gorm:"type:text" json:"notes"`
+	ID           uint                `gorm:"primarykey" json:"id"`
+	CreatedAt    time.Time           `json:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt      `gorm:"index" json:"-"`
+	JobID        uint                `json:"job_id"`
+	TalentID     uint                `json:"talent_id"`
+	ResumeID     uint                `json:"resume_id"`
+	Status       string              `gorm:"size:20;default:'pending'" json:"status"` // 招聘流程阶段，见 common/pipeline；默认流程为 pending, reviewed, interview, offer, hired, rejected, withdrawn
+	CoverLetter  string              `gorm:"type:text" json:"cover_letter"`
+	Notes        string              `gorm:"type:text" json:"notes"`
+	CustomFields customfields.Values `gorm:"type:jsonb;default:'{}'" json:"custom_fields"` // 管理员定义的自定义字段
+	HiredAt      *time.Time          `json:"hired_at,omitempty"`                           // 录用时间，用于按周期统计编制完成情况
+	JobVersion   int                 `json:"job_version"`                                  // 申请时的职位版本
+	Answers      screening.Answers   `gorm:"type:jsonb;default:'[]'" json:"answers"`       // 候选人对职位筛选问题的回答
+	Screening    *screening.Result   `gorm:"type:jsonb" json:"screening,omitempty"`        // 提交时按淘汰条件评估的结果，未回答筛选问题时为空
+}
+
+// StageTransition 申请的阶段流转记录，新建申请时记录进入初始阶段（FromStage 为空）
+type StageTransition struct {
+	ID            uint      `gorm:"primarykey" json:"id"`
+	CreatedAt     time.Time `gorm:"index" json:"created_at"`
+	ApplicationID uint      `gorm:"index;not null" json:"application_id"`
+	JobID         uint      `gorm:"index" json:"job_id"`
+	FromStage     string    `gorm:"size:20" json:"from_stage"`
+	ToStage       string    `gorm:"size:20;index" json:"to_stage"`
+	ActorID       *uint     `json:"actor_id,omitempty"` // 操作人，匿名请求和系统补录时为空
+	ActorName     string    `gorm:"size:100" json:"actor_name"`
+	Reason        string    `gorm:"type:text" json:"reason"`
+	RejectionCode string    `gorm:"size:50;index" json:"rejection_code,omitempty"` // 淘汰原因代码，见 pipeline.RejectionReasons
+}
+
+// TableName 设置表名
+func (StageTransition) TableName() string {
+	return "application_stage_transitions"
+}
+
+// RejectedUpload 未通过校验的上传文件记录；命中病毒的文件隔离保存，不会对外提供访问
+type RejectedUpload struct {
+	ID            uint      `gorm:"primarykey" json:"id"`
+	CreatedAt     time.Time `json:"created_at"`
+	FileName      string    `gorm:"size:255" json:"file_name"`
+	FileSize      int64     `json:"file_size"`
+	SHA256        string    `gorm:"size:64;index" json:"sha256"`
+	DetectedType  string    `gorm:"size:20" json:"detected_type"`
+	Reason        string    `gorm:"size:20;index" json:"reason"` // type, size, archive, malware, scan_error
+	Detail        string    `gorm:"size:500" json:"detail"`
+	Signature     string    `gorm:"size:200" json:"signature,omitempty"` // 命中的病毒特征
+	QuarantineKey string    `gorm:"size:500" json:"-"`                   // 隔离区存储 key
+	ClientIP      string    `gorm:"size:64" json:"client_ip"`
 }
@@ -2,8 +2,12 @@ package parser
 
 import (
 	"encoding/json"
+	"fmt"
 	"regexp"
 	"strings"
+	"time"
+
+	"common/skills"
 )
 
 // ParsedResume 解析后的简历结构
@@ -16,19 +20,48 @@ type ParsedResume struct {
 	Experience string   `json:"experience"`
 	Skills     []string `json:"skills"`
 	Summary    string   `json:"summary"`
+
+	// 分段解析结果
+	Language        string           `json:"language"` // zh, en
+	WorkExperiences []WorkExperience `json:"work_experiences"`
+	Educations      []EducationEntry `json:"educations"`
+	Projects        []Project        `json:"projects"`
+	Certificates    []string         `json:"certificates"`
+	TotalYears      float64          `json:"total_years"` // 按工作经历时间区间计算的总工作年限
 }
 
 // ResumeParser 简历解析器
-type ResumeParser struct{}
+type ResumeParser struct {
+	skills *skills.Dictionary
+	now    func() time.Time // 计算“至今”的工作年限，测试时可替换
+}
 
 // NewResumeParser 创建解析器实例
 func NewResumeParser() *ResumeParser {
-	return &ResumeParser{}
+	return NewResumeParserWithDictionary(skills.Default())
+}
+
+// NewResumeParserWithDictionary 使用指定技能字典创建解析器
+func NewResumeParserWithDictionary(dict *skills.Dictionary) *ResumeParser {
+	return &ResumeParser{skills: dict, now: time.Now}
 }
 
 // Parse 解析简历文本
 func (p *ResumeParser) Parse(text string) (*ParsedResume, error) {
 	result := &ParsedResume{}
+	sections := splitSections(text)
+	result.Language = detectLanguage(text)
+
+	// 分段提取工作、教育、项目经历和证书
+	result.WorkExperiences = parseWorkExperiences(sections[sectionWork])
+	result.Educations = parseEducations(sections[sectionEducation])
+	if len(result.Educations) == 0 {
+		result.Educations = parseLabeledEducation(text)
+	}
+	result.Projects = parseProjects(sections[sectionProject])
+	result.Certificates = parseCertificates(sections[sectionCertificate])
+	result.TotalYears = totalYears(result.WorkExperiences, p.now())
+	result.Summary = summarize(sections[sectionSummary])
 
 	// 提取姓名（通常在简历开头）
 	result.Name = p.extractName(text)
@@ -42,11 +75,17 @@ func (p *ResumeParser) Parse(text string) (*ParsedResume, error) {
 	// 提取技能
 	result.Skills = p.extractSkills(text)
 
-	// 提取教育背景
-	result.Education = p.extractEducation(text)
+	// 提取教育背景，优先取教育经历中的最高学历
+	result.Education = highestDegree(result.Educations)
+	if result.Education == "" {
+		result.Education = p.extractEducation(text)
+	}
 
-	// 提取工作经验年限
+	// 提取工作经验年限，简历未写明时按工作经历计算
 	result.Experience = p.extractExperience(text)
+	if result.Experience == "" && result.TotalYears >= 1 {
+		result.Experience = fmt.Sprintf("%d年", int(result.TotalYears))
+	}
 
 	// 提取地点
 	result.Location = p.extractLocation(text)
@@ -69,36 +108,73 @@ func (p *ResumeParser) ParseToJSON(text string) (string, error) {
 	return string(jsonBytes), nil
 }
 
-// extractName 提取姓名
+var (
+	nameLabelRegex  = regexp.MustCompile(`(?i)(?:姓\s*名|name)\s*[：:]\s*([\x{4e00}-\x{9fa5}]{2,4}(?:·[\x{4e00}-\x{9fa5}]{2,10})?|[A-Z][a-zA-Z'-]+(?:[ \t]+[A-Z][a-zA-Z'-]+){1,3})`)
+	chineseName     = regexp.MustCompile(`^[\x{4e00}-\x{9fa5}]{2,4}(?:·[\x{4e00}-\x{9fa5}]{2,10})?$`)
+	englishName     = regexp.MustCompile(`^(?:[A-Z][a-z]+(?:[ '-][A-Z][a-z]+){1,3}|[A-Z]+(?: [A-Z]+){1,3})$`)
+	nameFieldRegex  = regexp.MustCompile(`\s*[|｜,，/]\s*|\s{2,}|\t`)
+	nameSuffixRegex = regexp.MustCompile(`(?:的)?(?:个人)?简历$`)
+)
+
+// notNames 简历开头常见的非姓名短语
+var notNames = map[string]bool{
+	"个人简历": true, "求职简历": true, "简历": true, "基本信息": true, "个人信息": true, "联系方式": true,
+	"resume": true, "curriculum vitae": true, "cv": true,
+}
+
+// extractName 提取姓名：优先“姓名：”标签，其次取开头几行中的中文或英文姓名
 func (p *ResumeParser) extractName(text string) string {
-	// 中文姓名正则：2-4个汉字
-	nameRegex := regexp.MustCompile(`姓\s*名[：:]\s*([\x{4e00}-\x{9fa5}]{2,4})`)
-	if matches := nameRegex.FindStringSubmatch(text); len(matches) > 1 {
+	if matches := nameLabelRegex.FindStringSubmatch(text); len(matches) > 1 {
 		return matches[1]
 	}
 
-	// 尝试从开头提取
 	lines := strings.Split(text, "\n")
 	for _, line := range lines[:min(5, len(lines))] {
 		line = strings.TrimSpace(line)
-		if len(line) >= 2 && len(line) <= 12 {
-			// 检查是否全是中文
-			if matched, _ := regexp.MatchString(`^[\x{4e00}-\x{9fa5}]{2,4}$`, line); matched {
-				return line
+		if line == "" || notNames[strings.ToLower(line)] {
+			continue
+		}
+		if _, ok := sectionHeader(line); ok {
+			continue
+		}
+
+		// 姓名常与性别、年龄等写在同一行，如“张三 | 男 | 28岁”
+		field := nameFieldRegex.Split(line, 2)[0]
+		candidate := nameSuffixRegex.ReplaceAllString(strings.Fields(field)[0], "")
+		if chineseName.MatchString(candidate) && !notNames[candidate] {
+			return candidate
+		}
+		if englishName.MatchString(field) && !containsAny(field, titleKeywords) && !containsAny(field, companyKeywords) && !notNames[strings.ToLower(field)] {
+			if strings.ToUpper(field) == field {
+				return titleCase(field)
 			}
+			return field
 		}
 	}
 
 	return ""
 }
 
+// titleCase 将全大写的英文姓名转为首字母大写
+func titleCase(s string) string {
+	words := strings.Fields(strings.ToLower(s))
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
+
 // extractPhone 提取手机号
 func (p *ResumeParser) extractPhone(text string) string {
-	phoneRegex := regexp.MustCompile(`1[3-9]\d{9}`)
-	if matches := phoneRegex.FindString(text); matches != "" {
-		return matches
+	// 国内手机号，允许“+86”前缀和“138-1234-5678”“138 1234 5678”等分隔写法
+	phoneRegex := regexp.MustCompile(`(?:\+?86[\s-]?)?(1[3-9]\d(?:[\s-]?\d{4}){2})`)
+	if matches := phoneRegex.FindStringSubmatch(text); len(matches) > 1 {
+		return strings.NewReplacer(" ", "", "-", "").Replace(matches[1])
 	}
-	return ""
+
+	// 英文简历中的国际号码，如“+1 (415) 555-0100”
+	intlRegex := regexp.MustCompile(`\+\d{1,3}[\s.-]?\(?\d{1,4}\)?(?:[\s.-]?\d{2,4}){2,4}`)
+	return strings.TrimSpace(intlRegex.FindString(text))
 }
 
 // extractEmail 提取邮箱
@@ -110,39 +186,9 @@ func (p *ResumeParser) extractEmail(text string) string {
 	return ""
 }
 
-// extractSkills 提取技能
+// extractSkills 提取技能（基于共享技能字典，同义词归一为规范名称）
 func (p *ResumeParser) extractSkills(text string) []string {
-	// 常见技术技能关键词
-	skillKeywords := []string{
-		// 编程语言
-		"Java", "Python", "Go", "Golang", "JavaScript", "TypeScript", "C++", "C#", "PHP", "Ruby", "Swift", "Kotlin", "Rust",
-		// 前端
-		"Vue", "Vue.js", "Vue3", "React", "Angular", "HTML", "CSS", "SCSS", "Less", "Webpack", "Vite", "Node.js", "Next.js",
-		// 后端
-		"Spring", "Spring Boot", "Django", "Flask", "Express", "Gin", "FastAPI", "Laravel",
-		// 数据库
-		"MySQL", "PostgreSQL", "MongoDB", "Redis", "Elasticsearch", "Oracle", "SQL Server",
-		// 云和DevOps
-		"Docker", "Kubernetes", "K8s", "AWS", "Azure", "GCP", "Linux", "Nginx", "Jenkins", "Git", "CI/CD",
-		// 大数据和AI
-		"Hadoop", "Spark", "Flink", "TensorFlow", "PyTorch", "机器学习", "深度学习",
-		// 其他
-		"微服务", "分布式", "高并发", "消息队列", "RabbitMQ", "Kafka", "gRPC", "RESTful",
-	}
-
-	textLower := strings.ToLower(text)
-	var skills []string
-	seen := make(map[string]bool)
-
-	for _, skill := range skillKeywords {
-		skillLower := strings.ToLower(skill)
-		if strings.Contains(textLower, skillLower) && !seen[skillLower] {
-			skills = append(skills, skill)
-			seen[skillLower] = true
-		}
-	}
-
-	return skills
+	return p.skills.Extract(text)
 }
 
 // extractEducation 提取教育背景
@@ -172,6 +218,12 @@ func (p *ResumeParser) extractExperience(text string) string {
 		return matches[1] + "年"
 	}
 
+	// 英文简历，如“8+ years of experience”“5 years of backend development experience”
+	expRegexEn := regexp.MustCompile(`(?i)(\d+)\+?\s*years?\s+(?:of\s+)?(?:[a-z-]+\s+){0,3}?experience`)
+	if matches := expRegexEn.FindStringSubmatch(text); len(matches) > 1 {
+		return matches[1] + "年"
+	}
+
 	return ""
 }
 
@@ -188,6 +240,13 @@ func (p *ResumeParser) extractLocation(text string) string {
 		}
 	}
 
+	// 英文简历中的城市拼写，统一为中文名
+	for _, city := range englishCities {
+		if containsAny(text, []string{city.en}) {
+			return city.zh
+		}
+	}
+
 	return ""
 }
 
@@ -199,9 +258,9 @@ func (p *ResumeParser) CalculateMatchScore(resume *ParsedResume, jobSkills []str
 	// 技能匹配 (最高50分)
 	if len(jobSkills) > 0 {
 		matchedSkills := 0
-		for _, skill := range resume.Skills {
-			for _, jobSkill := range jobSkills {
-				if strings.EqualFold(skill, jobSkill) {
+		for _, jobSkill := range jobSkills {
+			for _, skill := range resume.Skills {
+				if p.skills.Matches(skill, jobSkill) {
 					matchedSkills++
 					break
 				}
@@ -215,9 +274,6 @@ func (p *ResumeParser) CalculateMatchScore(resume *ParsedResume, jobSkills []str
 	}
 
 	// 学历匹配 (最高25分)
-	educationRank := map[string]int{
-		"博士": 5, "硕士": 4, "研究生": 4, "本科": 3, "学士": 3, "大专": 2, "专科": 2,
-	}
 	resumeEduRank := educationRank[resume.Education]
 	jobEduRank := educationRank[jobEducation]
 	if resumeEduRank >= jobEduRank {
@@ -249,6 +305,38 @@ func (p *ResumeParser) CalculateMatchScore(resume *ParsedResume, jobSkills []str
 	return score
 }
 
+// educationRank 学历等级
+var educationRank = map[string]int{
+	"博士": 5, "硕士": 4, "研究生": 4, "本科": 3, "学士": 3, "大专": 2, "专科": 2,
+}
+
+var englishCities = []struct{ en, zh string }{
+	{"beijing", "北京"}, {"shanghai", "上海"}, {"shenzhen", "深圳"}, {"guangzhou", "广州"}, {"hangzhou", "杭州"},
+	{"chengdu", "成都"}, {"nanjing", "南京"}, {"wuhan", "武汉"}, {"xi'an", "西安"}, {"suzhou", "苏州"},
+	{"tianjin", "天津"}, {"chongqing", "重庆"}, {"changsha", "长沙"}, {"zhengzhou", "郑州"}, {"qingdao", "青岛"},
+	{"dalian", "大连"}, {"ningbo", "宁波"}, {"xiamen", "厦门"}, {"fuzhou", "福州"}, {"hefei", "合肥"},
+}
+
+// highestDegree 教育经历中的最高学历（中文）
+func highestDegree(educations []EducationEntry) string {
+	best := ""
+	for _, e := range educations {
+		if level := degreeLevel(e.Degree); educationRank[level] > educationRank[best] {
+			best = level
+		}
+	}
+	return best
+}
+
+// summarize 自我评价段落，过长时截断
+func summarize(lines []string) string {
+	summary := []rune(strings.Join(lines, "\n"))
+	if len(summary) > 500 {
+		summary = summary[:500]
+	}
+	return string(summary)
+}
+
 func parseInt(s string) (int, error) {
 	var result int
 	for _, c := range s {
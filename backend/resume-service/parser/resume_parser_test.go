@@ -2,6 +2,7 @@ package parser
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -24,6 +25,16 @@ func TestExtractPhone(t *testing.T) {
 			text:     "张三，手机13912345678，北京",
 			expected: "13912345678",
 		},
+		{
+			name:     "带区号和分隔符",
+			text:     "Tel: +86 138-1234-5678",
+			expected: "13812345678",
+		},
+		{
+			name:     "英文简历国际号码",
+			text:     "Phone: +1 (415) 555-0100",
+			expected: "+1 (415) 555-0100",
+		},
 		{
 			name:     "无手机号",
 			text:     "这是一段没有手机号的文本",
@@ -307,3 +318,313 @@ func TestParse(t *testing.T) {
 	assert.Equal(t, "5年", result.Experience)
 	assert.GreaterOrEqual(t, len(result.Skills), 4)
 }
+
+func TestExtractName(t *testing.T) {
+	parser := NewResumeParser()
+
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{
+			name:     "姓名标签",
+			text:     "个人简历\n姓名：欧阳娜娜\n性别：女",
+			expected: "欧阳娜娜",
+		},
+		{
+			name:     "少数民族姓名",
+			text:     "姓名：迪丽热巴·迪力木拉提",
+			expected: "迪丽热巴·迪力木拉提",
+		},
+		{
+			name:     "姓名与性别年龄同行",
+			text:     "张三 | 男 | 28岁\n手机：13812345678",
+			expected: "张三",
+		},
+		{
+			name:     "标题带简历字样",
+			text:     "李四的个人简历\n手机：13812345678",
+			expected: "李四",
+		},
+		{
+			name:     "跳过简历标题和分段标题",
+			text:     "个人简历\n基本信息\n王五\n",
+			expected: "王五",
+		},
+		{
+			name:     "英文姓名",
+			text:     "John Smith\nSenior Backend Engineer",
+			expected: "John Smith",
+		},
+		{
+			name:     "全大写英文姓名",
+			text:     "JANE DOE\njane@example.com",
+			expected: "Jane Doe",
+		},
+		{
+			name:     "英文职位不是姓名",
+			text:     "Software Engineer\nsmith@example.com",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, parser.extractName(tt.text))
+		})
+	}
+}
+
+func TestFindDateRange(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		start   string
+		end     string
+		current bool
+		found   bool
+	}{
+		{name: "斜杠与至今", line: "兰亭集势 (2017/06 - 至今 )", start: "2017-06", current: true, found: true},
+		{name: "点分隔", line: "2015.9-2019.6 华南理工大学", start: "2015-09", end: "2019-06", found: true},
+		{name: "年月", line: "2018年3月~2020年12月 某公司", start: "2018-03", end: "2020-12", found: true},
+		{name: "横线分隔年月", line: "2017-06 - 2019-05", start: "2017-06", end: "2019-05", found: true},
+		{name: "仅年份", line: "2009 - 2013", start: "2009", end: "2013", found: true},
+		{name: "英文月份", line: "Acme Corp. Jan 2019 – Present", start: "2019-01", current: true, found: true},
+		{name: "英文数字月份", line: "06/2017 to 05/2019", start: "2017-06", end: "2019-05", found: true},
+		{name: "手机号不是日期", line: "手机：13812345678", found: false},
+		{name: "无日期", line: "负责订单系统开发", found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, ok := findDateRange(tt.line)
+			assert.Equal(t, tt.found, ok)
+			assert.Equal(t, tt.start, r.Start)
+			assert.Equal(t, tt.end, r.End)
+			assert.Equal(t, tt.current, r.Current)
+		})
+	}
+}
+
+func TestSectionHeader(t *testing.T) {
+	tests := []struct {
+		line    string
+		section string
+		ok      bool
+	}{
+		{"工作经历", sectionWork, true},
+		{"【项目经验】", sectionProject, true},
+		{"教育背景：", sectionEducation, true},
+		{"工作经历 / WORK EXPERIENCE", sectionWork, true},
+		{"PROFESSIONAL EXPERIENCE", sectionWork, true},
+		{"Certifications", sectionCertificate, true},
+		{"工作经验：5年", "", false},
+		{"负责工作经历相关模块的开发", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			section, ok := sectionHeader(tt.line)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.section, section)
+		})
+	}
+}
+
+func TestTotalYears(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		experiences []WorkExperience
+		expected    float64
+	}{
+		{
+			name: "连续经历",
+			experiences: []WorkExperience{
+				{StartDate: "2016-07", EndDate: "2019-07"},
+				{StartDate: "2019-07", EndDate: "2022-07"},
+			},
+			expected: 6,
+		},
+		{
+			name: "重叠经历只计算一次",
+			experiences: []WorkExperience{
+				{StartDate: "2018-01", EndDate: "2020-01"},
+				{StartDate: "2019-01", EndDate: "2021-01"},
+			},
+			expected: 3,
+		},
+		{
+			name: "至今按当前时间计算",
+			experiences: []WorkExperience{
+				{StartDate: "2021-06", Current: true},
+			},
+			expected: 3,
+		},
+		{
+			name: "缺少结束时间的经历不计入",
+			experiences: []WorkExperience{
+				{StartDate: "2020-01"},
+			},
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, totalYears(tt.experiences, now))
+		})
+	}
+}
+
+func TestParseSections(t *testing.T) {
+	parser := NewResumeParser()
+	parser.now = func() time.Time { return time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC) }
+
+	resumeText := `
+张三 | 男 | 28岁
+手机：138-1234-5678  邮箱：zhangsan@example.com
+
+工作经历
+2019.07 - 至今  深圳某某科技有限公司  高级Go开发工程师
+1. 负责订单系统的设计与开发
+2. 主导服务拆分
+某某网络公司 | Java开发工程师 | 2016.07-2019.06
+负责支付接口开发
+
+教育背景
+2012.09-2016.06  华南理工大学  计算机科学与技术  本科
+
+项目经验
+项目名称：订单中心重构 2020.03-2021.01
+项目角色：技术负责人
+项目描述：将单体订单服务拆分为微服务
+消息推送平台
+- 基于 Kafka 的消息推送
+
+证书
+CET-6、软件设计师、PMP
+
+自我评价
+热爱技术，责任心强。
+`
+
+	result, err := parser.Parse(resumeText)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "张三", result.Name)
+	assert.Equal(t, "13812345678", result.Phone)
+	assert.Equal(t, "zh", result.Language)
+
+	assert.Equal(t, []WorkExperience{
+		{
+			Company:     "深圳某某科技有限公司",
+			Title:       "高级Go开发工程师",
+			StartDate:   "2019-07",
+			Current:     true,
+			Description: "1. 负责订单系统的设计与开发\n2. 主导服务拆分",
+		},
+		{
+			Company:     "某某网络公司",
+			Title:       "Java开发工程师",
+			StartDate:   "2016-07",
+			EndDate:     "2019-06",
+			Description: "负责支付接口开发",
+		},
+	}, result.WorkExperiences)
+	assert.Equal(t, 7.8, result.TotalYears)
+	assert.Equal(t, "7年", result.Experience)
+
+	assert.Equal(t, []EducationEntry{
+		{School: "华南理工大学", Degree: "本科", Major: "计算机科学与技术", StartDate: "2012-09", EndDate: "2016-06"},
+	}, result.Educations)
+	assert.Equal(t, "本科", result.Education)
+
+	assert.Len(t, result.Projects, 2)
+	assert.Equal(t, "订单中心重构", result.Projects[0].Name)
+	assert.Equal(t, "技术负责人", result.Projects[0].Role)
+	assert.Equal(t, "2020-03", result.Projects[0].StartDate)
+	assert.Equal(t, "将单体订单服务拆分为微服务", result.Projects[0].Description)
+	assert.Equal(t, "消息推送平台", result.Projects[1].Name)
+
+	assert.Equal(t, []string{"CET-6", "软件设计师", "PMP"}, result.Certificates)
+	assert.Equal(t, "热爱技术，责任心强。", result.Summary)
+}
+
+func TestParseEnglishResume(t *testing.T) {
+	parser := NewResumeParser()
+	parser.now = func() time.Time { return time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC) }
+
+	resumeText := `JOHN SMITH
+Senior Backend Engineer | Shanghai
+Email: john.smith@example.com | Phone: +1 (415) 555-0100
+
+SUMMARY
+Backend engineer with 8+ years of experience building distributed systems.
+
+EXPERIENCE
+Senior Software Engineer, Acme Corp.   Jan 2019 – Present
+- Led migration to Kubernetes and Go microservices
+Software Engineer — Globex Inc
+Jun 2015 - Dec 2018
+- Built payment APIs in Java
+
+EDUCATION
+Stanford University, M.S. in Computer Science, 2013 - 2015
+University of Washington — B.S. Computer Science — 2009 - 2013
+
+PROJECTS
+OpenTracer | Maintainer | 2020 - 2022
+Open-source tracing library.
+
+CERTIFICATIONS
+AWS Certified Solutions Architect, CKA
+`
+
+	result, err := parser.Parse(resumeText)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "John Smith", result.Name)
+	assert.Equal(t, "john.smith@example.com", result.Email)
+	assert.Equal(t, "+1 (415) 555-0100", result.Phone)
+	assert.Equal(t, "上海", result.Location)
+	assert.Equal(t, "en", result.Language)
+	assert.Equal(t, "8年", result.Experience)
+	assert.Contains(t, result.Skills, "Go")
+	assert.Contains(t, result.Skills, "Kubernetes")
+
+	assert.Len(t, result.WorkExperiences, 2)
+	assert.Equal(t, "Acme Corp.", result.WorkExperiences[0].Company)
+	assert.Equal(t, "Senior Software Engineer", result.WorkExperiences[0].Title)
+	assert.True(t, result.WorkExperiences[0].Current)
+	assert.Equal(t, "Globex Inc", result.WorkExperiences[1].Company)
+	assert.Equal(t, "Software Engineer", result.WorkExperiences[1].Title)
+	assert.Equal(t, "2015-06", result.WorkExperiences[1].StartDate)
+	assert.Equal(t, "2018-12", result.WorkExperiences[1].EndDate)
+	assert.Equal(t, 8.9, result.TotalYears)
+
+	assert.Equal(t, []EducationEntry{
+		{School: "Stanford University", Degree: "M.S.", Major: "Computer Science", StartDate: "2013", EndDate: "2015"},
+		{School: "University of Washington", Degree: "B.S.", Major: "Computer Science", StartDate: "2009", EndDate: "2013"},
+	}, result.Educations)
+	assert.Equal(t, "硕士", result.Education)
+
+	assert.Len(t, result.Projects, 1)
+	assert.Equal(t, "OpenTracer", result.Projects[0].Name)
+	assert.Equal(t, "Open-source tracing library.", result.Projects[0].Description)
+
+	assert.Equal(t, []string{"AWS Certified Solutions Architect", "CKA"}, result.Certificates)
+}
+
+func TestParseLabeledEducation(t *testing.T) {
+	parser := NewResumeParser()
+
+	result, err := parser.Parse("朱某\n毕业学校：北京理工大学珠海学院；毕业专业：计算机科学与技术；")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []EducationEntry{
+		{School: "北京理工大学珠海学院", Major: "计算机科学与技术"},
+	}, result.Educations)
+}
@@ -0,0 +1,612 @@
+package parser
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// 简历分段
+const (
+	sectionBasic       = "basic"
+	sectionSummary     = "summary"
+	sectionWork        = "work"
+	sectionEducation   = "education"
+	sectionProject     = "project"
+	sectionSkills      = "skills"
+	sectionCertificate = "certificate"
+	sectionOther       = "other"
+)
+
+// sectionHeaders 各分段的标题关键词（小写），中英文简历通用
+var sectionHeaders = []struct {
+	section  string
+	keywords []string
+}{
+	{sectionWork, []string{"工作经历", "工作经验", "职业经历", "工作履历", "实习经历", "实习经验",
+		"work experience", "professional experience", "employment history", "work history", "experience", "internships", "internship"}},
+	{sectionEducation, []string{"教育经历", "教育背景", "学习经历", "教育信息",
+		"education", "educational background", "academic background"}},
+	{sectionProject, []string{"项目经历", "项目经验", "主要项目", "项目作品",
+		"projects", "project experience", "personal projects"}},
+	{sectionSkills, []string{"专业技能", "技能特长", "个人技能", "技术技能", "技能", "技术栈",
+		"skills", "technical skills", "core competencies"}},
+	{sectionCertificate, []string{"资格证书", "证书", "获得证书", "职业证书", "证书资质",
+		"certifications", "certificates", "licenses & certifications", "licenses and certifications"}},
+	{sectionSummary, []string{"自我评价", "个人简介", "个人总结", "个人优势", "自我介绍",
+		"summary", "profile", "about me", "professional summary", "objective"}},
+	{sectionOther, []string{"求职意向", "基本信息", "个人信息", "荣誉奖项", "获奖经历", "获奖情况", "兴趣爱好", "语言能力",
+		"awards", "honors", "honors & awards", "interests", "languages", "personal information", "contact"}},
+}
+
+var (
+	headerTrimLeft  = regexp.MustCompile(`^[\s#*【\[(（■●◆▶>·•=\-—]+`)
+	headerTrimRight = regexp.MustCompile(`[\s】\])）:：=\-—]+$`)
+	// 中文标题后常跟英文翻译，如“工作经历 / WORK EXPERIENCE”
+	headerTranslation = regexp.MustCompile(`^[\s/|a-z&]*$`)
+	// 序号最多两位，避免把“2012.09”这类年份当成序号
+	bulletLine = regexp.MustCompile(`^\s*(?:[-*•·●◆▪■]|\d{1,2}\s*[.、)）]|[（(]\d{1,2}[)）])\s*`)
+)
+
+// sectionHeader 判断一行是否为分段标题
+func sectionHeader(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || len([]rune(line)) > 30 {
+		return "", false
+	}
+	normalized := strings.ToLower(headerTrimRight.ReplaceAllString(headerTrimLeft.ReplaceAllString(line, ""), ""))
+	for _, h := range sectionHeaders {
+		for _, kw := range h.keywords {
+			if normalized == kw {
+				return h.section, true
+			}
+			if strings.HasPrefix(normalized, kw) && !isASCII(kw) && headerTranslation.MatchString(normalized[len(kw):]) {
+				return h.section, true
+			}
+		}
+	}
+	return "", false
+}
+
+func isASCII(s string) bool {
+	for _, r := range s {
+		if r > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
+
+// splitSections 按分段标题切分简历，标题之前的内容归入基本信息
+func splitSections(text string) map[string][]string {
+	sections := make(map[string][]string)
+	current := sectionBasic
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if section, ok := sectionHeader(line); ok {
+			current = section
+			continue
+		}
+		sections[current] = append(sections[current], line)
+	}
+	return sections
+}
+
+// 日期区间，如“2017/06 - 至今”“2015.9-2019.6”“2018年3月~2020年5月”“Jan 2019 – Present”“06/2017 - 05/2019”
+const (
+	datePattern = `(?:(?:jan|feb|mar|apr|may|jun|jul|aug|sep|sept|oct|nov|dec)[a-z]*\.?\s*,?\s*(?:19|20)\d{2}` +
+		`|\d{1,2}\s*/\s*(?:19|20)\d{2}` +
+		`|(?:19|20)\d{2}(?:\s*[./\-年]\s*\d{1,2}\s*月?)?)`
+	presentPattern = `至今|现在|今|present|now|current|today`
+)
+
+var (
+	dateRangeRegex = regexp.MustCompile(`(?i)(?:^|[^\d])(` + datePattern + `)\s*(?:-|–|—|~|～|至|到|\bto\b)+\s*(` + presentPattern + `|` + datePattern + `)`)
+	monthYearRegex = regexp.MustCompile(`(?i)^([a-z]+)\.?\s*,?\s*(\d{4})$`)
+	numericMonth   = regexp.MustCompile(`^(\d{1,2})\s*/\s*(\d{4})$`)
+	yearMonthRegex = regexp.MustCompile(`^(\d{4})(?:\s*[./\-年]\s*(\d{1,2})\s*月?)?$`)
+	presentRegex   = regexp.MustCompile(`(?i)^(?:` + presentPattern + `)$`)
+	emptyBrackets  = regexp.MustCompile(`[(（\[【]\s*[)）\]】]`)
+	edgeSeparators = regexp.MustCompile(`^[\s,，|｜\-—–:：]+|[\s,，|｜\-—–:：]+$`)
+)
+
+var monthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+// parseDate 将日期统一为“YYYY-MM”（无月份时为“YYYY”）
+func parseDate(s string) (string, bool) {
+	s = strings.TrimSpace(s)
+	if m := yearMonthRegex.FindStringSubmatch(s); m != nil {
+		if m[2] == "" {
+			return m[1], true
+		}
+		month, _ := strconv.Atoi(m[2])
+		if month < 1 || month > 12 {
+			return "", false
+		}
+		return fmt.Sprintf("%s-%02d", m[1], month), true
+	}
+	if m := numericMonth.FindStringSubmatch(s); m != nil {
+		month, _ := strconv.Atoi(m[1])
+		if month < 1 || month > 12 {
+			return "", false
+		}
+		return fmt.Sprintf("%s-%02d", m[2], month), true
+	}
+	if m := monthYearRegex.FindStringSubmatch(s); m != nil && len(m[1]) >= 3 {
+		if month, ok := monthNames[strings.ToLower(m[1][:3])]; ok {
+			return fmt.Sprintf("%s-%02d", m[2], month), true
+		}
+	}
+	return "", false
+}
+
+// dateRange 日期区间及其在行中的位置
+type dateRange struct {
+	Start, End string
+	Current    bool
+	from, to   int
+}
+
+// findDateRange 查找行中的第一个日期区间
+func findDateRange(line string) (dateRange, bool) {
+	for _, idx := range dateRangeRegex.FindAllStringSubmatchIndex(line, -1) {
+		start, ok := parseDate(line[idx[2]:idx[3]])
+		if !ok {
+			continue
+		}
+		r := dateRange{Start: start, from: idx[2], to: idx[5]}
+		end := line[idx[4]:idx[5]]
+		if presentRegex.MatchString(end) {
+			r.Current = true
+		} else if r.End, ok = parseDate(end); !ok {
+			continue
+		}
+		return r, true
+	}
+	return dateRange{}, false
+}
+
+// stripDateRange 去掉行中的日期区间及其外层括号
+func stripDateRange(line string, r dateRange) string {
+	rest := line[:r.from] + " " + line[r.to:]
+	rest = emptyBrackets.ReplaceAllString(rest, "")
+	return edgeSeparators.ReplaceAllString(rest, "")
+}
+
+// 标题行中的字段分隔符
+var (
+	workFieldSeparator      = regexp.MustCompile(`\s*[|｜·•，,;；]\s*|\s{2,}|\t|\s+[—–]\s+|\s+at\s+`)
+	educationFieldSeparator = regexp.MustCompile(`\s*[|｜·•，,;；/]\s*|\s{2,}|\t|\s+[-—–]\s+`)
+)
+
+func splitFields(line string, sep *regexp.Regexp) []string {
+	var fields []string
+	for _, f := range sep.Split(line, -1) {
+		f = strings.Trim(f, " ()（）[]【】:：")
+		if f != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// containsAny 是否包含任一关键词；英文关键词按整词匹配，避免“inc”命中“principal”
+func containsAny(s string, keywords []string) bool {
+	lower := strings.ToLower(s)
+	for _, kw := range keywords {
+		if !isASCII(kw) {
+			if strings.Contains(lower, kw) {
+				return true
+			}
+			continue
+		}
+		for idx := 0; ; {
+			pos := strings.Index(lower[idx:], kw)
+			if pos < 0 {
+				break
+			}
+			start, end := idx+pos, idx+pos+len(kw)
+			if (start == 0 || !isWordByte(lower[start-1])) && (end == len(lower) || !isWordByte(lower[end])) {
+				return true
+			}
+			idx = start + 1
+		}
+	}
+	return false
+}
+
+func isWordByte(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= '0' && b <= '9'
+}
+
+var (
+	titleKeywords = []string{"工程师", "经理", "开发", "总监", "主管", "专员", "设计师", "架构师", "负责人", "实习生", "顾问",
+		"分析师", "助理", "组长", "技术专家", "运营", "测试", "产品", "cto", "ceo",
+		"engineer", "developer", "manager", "director", "lead", "intern", "analyst", "designer", "architect",
+		"consultant", "scientist", "specialist", "programmer", "administrator", "officer"}
+	companyKeywords = []string{"公司", "集团", "科技", "有限", "银行", "研究院", "研究所", "工作室", "网络", "信息技术",
+		"inc", "inc.", "ltd", "ltd.", "llc", "corp", "corp.", "co", "company", "technologies", "group", "gmbh", "limited"}
+	schoolKeywords = []string{"大学", "学院", "学校", "中学", "university", "college", "institute", "school", "academy"}
+	roleKeywords   = []string{"负责人", "开发", "工程师", "经理", "架构师", "组长", "成员", "主导", "核心",
+		"lead", "developer", "engineer", "owner", "member", "manager", "architect"}
+)
+
+// WorkExperience 工作经历
+type WorkExperience struct {
+	Company     string `json:"company"`
+	Title       string `json:"title"`
+	StartDate   string `json:"start_date"`
+	EndDate     string `json:"end_date"`
+	Current     bool   `json:"current"` // 至今
+	Description string `json:"description"`
+}
+
+// EducationEntry 教育经历
+type EducationEntry struct {
+	School    string `json:"school"`
+	Degree    string `json:"degree"`
+	Major     string `json:"major"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+}
+
+// Project 项目经历
+type Project struct {
+	Name        string `json:"name"`
+	Role        string `json:"role"`
+	StartDate   string `json:"start_date"`
+	EndDate     string `json:"end_date"`
+	Current     bool   `json:"current"`
+	Description string `json:"description"`
+}
+
+// parseWorkExperiences 解析工作经历：含日期区间的行开始一段新经历，公司和职位在同一行或相邻行
+func parseWorkExperiences(lines []string) []WorkExperience {
+	var result []WorkExperience
+	var desc []string
+	flush := func() {
+		if len(result) > 0 {
+			result[len(result)-1].Description = strings.Join(desc, "\n")
+		}
+		desc = nil
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		r, ok := findDateRange(line)
+		if !ok {
+			desc = append(desc, line)
+			continue
+		}
+
+		header := stripDateRange(line, r)
+		if header == "" {
+			// 日期单独成行：公司/职位在上一行（尚未归入描述或是明显的标题行）或下一行
+			if n := len(desc); n > 0 && (len(result) == 0 || isWorkHeader(desc[n-1])) {
+				header = desc[n-1]
+				desc = desc[:n-1]
+			} else if i+1 < len(lines) && !hasDateRange(lines[i+1]) && isWorkHeader(lines[i+1]) {
+				header = lines[i+1]
+				i++
+			}
+		}
+
+		flush()
+		exp := WorkExperience{StartDate: r.Start, EndDate: r.End, Current: r.Current}
+		exp.Company, exp.Title = classifyWorkFields(splitFields(header, workFieldSeparator))
+		result = append(result, exp)
+	}
+	flush()
+	return result
+}
+
+func hasDateRange(line string) bool {
+	_, ok := findDateRange(line)
+	return ok
+}
+
+func isWorkHeader(line string) bool {
+	return len([]rune(line)) <= 40 && !bulletLine.MatchString(line) &&
+		(containsAny(line, companyKeywords) || containsAny(line, titleKeywords))
+}
+
+// classifyWorkFields 按关键词区分公司和职位，无法判断时先公司后职位
+func classifyWorkFields(fields []string) (company, title string) {
+	var rest []string
+	for _, f := range fields {
+		switch {
+		case company == "" && containsAny(f, companyKeywords):
+			company = f
+		case title == "" && containsAny(f, titleKeywords):
+			title = f
+		default:
+			rest = append(rest, f)
+		}
+	}
+	for _, f := range rest {
+		if company == "" {
+			company = f
+		} else if title == "" {
+			title = f
+		}
+	}
+	return company, title
+}
+
+// degreeLevels 学历关键词及对应的中文学历，用于中英文简历统一学历
+var degreeLevels = []struct {
+	keyword string
+	level   string
+}{
+	{"博士", "博士"}, {"phd", "博士"}, {"ph.d", "博士"}, {"doctor", "博士"},
+	{"硕士", "硕士"}, {"研究生", "硕士"}, {"mba", "硕士"}, {"master", "硕士"}, {"m.s.", "硕士"}, {"msc", "硕士"},
+	{"本科", "本科"}, {"学士", "本科"}, {"bachelor", "本科"}, {"b.s.", "本科"}, {"bsc", "本科"}, {"b.e.", "本科"}, {"b.a.", "本科"},
+	{"大专", "大专"}, {"专科", "大专"}, {"associate", "大专"},
+}
+
+// degreeLevel 学历字段对应的中文学历
+func degreeLevel(degree string) string {
+	for _, d := range degreeLevels {
+		if containsAny(degree, []string{d.keyword}) {
+			return d.level
+		}
+	}
+	return ""
+}
+
+// splitDegree 拆分英文简历中学位和专业写在一起的情况，如“M.S. in Computer Science”“B.S. Computer Science”
+func splitDegree(field string) (degree, major string) {
+	lower := strings.ToLower(field)
+	for _, d := range degreeLevels {
+		if !isASCII(d.keyword) || !strings.HasPrefix(lower, d.keyword) {
+			continue
+		}
+		end := len(d.keyword)
+		// 学位全称，如“Bachelor of Science in Computer Science”
+		if m := degreeFullName.FindStringIndex(lower); m != nil {
+			end = m[1]
+		}
+		degree = strings.TrimSpace(field[:end])
+		major = strings.TrimSpace(degreeMajorPrefix.ReplaceAllString(field[end:], ""))
+		return degree, major
+	}
+	return field, ""
+}
+
+var (
+	degreeFullName    = regexp.MustCompile(`^(?:bachelor|master|doctor|associate)(?:'s)?(?:\s+of\s+(?:science|arts|engineering|business administration|philosophy))?`)
+	degreeMajorPrefix = regexp.MustCompile(`(?i)^[\s,]*(?:degree\s+)?(?:in|of)?\s+`)
+)
+
+// parseEducations 解析教育经历：含日期区间、学校名或学历的行开始一段新经历
+func parseEducations(lines []string) []EducationEntry {
+	var result []EducationEntry
+	for _, line := range lines {
+		line = bulletLine.ReplaceAllString(line, "")
+		r, hasDate := findDateRange(line)
+		fields := line
+		if hasDate {
+			fields = stripDateRange(line, r)
+		}
+
+		var entry EducationEntry
+		for _, f := range splitFields(fields, educationFieldSeparator) {
+			switch {
+			case entry.School == "" && containsAny(f, schoolKeywords):
+				entry.School = f
+			case entry.Degree == "" && degreeLevel(f) != "":
+				var major string
+				entry.Degree, major = splitDegree(f)
+				if entry.Major == "" {
+					entry.Major = major
+				}
+			case entry.Major == "":
+				entry.Major = strings.TrimSuffix(strings.TrimLeft(strings.TrimPrefix(f, "专业"), "：: "), "专业")
+			}
+		}
+
+		if !hasDate && entry.School == "" && entry.Degree == "" {
+			// 补充说明（如主修课程）不单独成条
+			continue
+		}
+		if len(result) > 0 && !hasDate && entry.School == "" {
+			// 学历/专业单独成行时补充到上一条
+			last := &result[len(result)-1]
+			if last.Degree == "" {
+				last.Degree = entry.Degree
+				if last.Major == "" {
+					last.Major = entry.Major
+				}
+				continue
+			}
+		}
+		if hasDate {
+			entry.StartDate, entry.EndDate = r.Start, r.End
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
+var (
+	projectNameLabel = regexp.MustCompile(`(?i)^(?:项目名称|项目名|project name|project)\s*[：:]\s*`)
+	projectRoleLabel = regexp.MustCompile(`(?i)^(?:项目角色|担任角色|担任职务|角色|职责角色|role)\s*[：:]\s*(.+)$`)
+	projectDescLabel = regexp.MustCompile(`(?i)^(?:项目描述|项目简介|项目介绍|description)\s*[：:]\s*`)
+	projectNameHint  = regexp.MustCompile(`(?:项目|系统|平台|工具|框架|引擎|app|App|APP)$`)
+)
+
+// parseProjects 解析项目经历：含日期区间、“项目名称：”或以“系统/平台”等结尾的短行开始一个新项目
+func parseProjects(lines []string) []Project {
+	var result []Project
+	var desc []string
+	flush := func() {
+		if len(result) > 0 {
+			result[len(result)-1].Description = strings.Join(desc, "\n")
+		}
+		desc = nil
+	}
+
+	for _, line := range lines {
+		if m := projectRoleLabel.FindStringSubmatch(line); m != nil && len(result) > 0 {
+			result[len(result)-1].Role = strings.TrimSpace(m[1])
+			continue
+		}
+		if projectDescLabel.MatchString(line) {
+			desc = append(desc, projectDescLabel.ReplaceAllString(line, ""))
+			continue
+		}
+
+		r, hasDate := findDateRange(line)
+		header := line
+		if hasDate {
+			header = stripDateRange(line, r)
+		}
+		labeled := projectNameLabel.MatchString(header)
+		bullet := bulletLine.MatchString(line)
+		if !hasDate && !labeled && (bullet || len([]rune(line)) > 30 || !projectNameHint.MatchString(line)) {
+			desc = append(desc, line)
+			continue
+		}
+
+		flush()
+		p := Project{StartDate: r.Start, EndDate: r.End, Current: r.Current}
+		for _, f := range splitFields(projectNameLabel.ReplaceAllString(header, ""), workFieldSeparator) {
+			if p.Name == "" {
+				p.Name = f
+			} else if p.Role == "" && containsAny(f, roleKeywords) {
+				p.Role = f
+			}
+		}
+		result = append(result, p)
+	}
+	flush()
+	return result
+}
+
+var (
+	schoolLabel = regexp.MustCompile(`(?:毕业院校|毕业学校|就读院校|学校)\s*[：:]\s*([^；;，,|｜\s]+)`)
+	majorLabel  = regexp.MustCompile(`(?:毕业专业|所学专业|专业)\s*[：:]\s*([^；;，,|｜\s]+)`)
+	degreeLabel = regexp.MustCompile(`(?:最高学历|学历|学位)\s*[：:]\s*([^；;，,|｜\s]+)`)
+)
+
+// parseLabeledEducation 没有教育经历分段时，从“毕业学校：XX；专业：XX”这类基本信息中提取
+func parseLabeledEducation(text string) []EducationEntry {
+	var entry EducationEntry
+	if m := schoolLabel.FindStringSubmatch(text); m != nil {
+		entry.School = m[1]
+	}
+	if entry.School == "" {
+		return nil
+	}
+	if m := majorLabel.FindStringSubmatch(text); m != nil {
+		entry.Major = m[1]
+	}
+	if m := degreeLabel.FindStringSubmatch(text); m != nil {
+		entry.Degree = m[1]
+	}
+	return []EducationEntry{entry}
+}
+
+var certificateSeparator = regexp.MustCompile(`\s*[，,、;；|｜]\s*`)
+
+// parseCertificates 解析证书，每行或以顿号/逗号分隔各为一项
+func parseCertificates(lines []string) []string {
+	var result []string
+	seen := make(map[string]bool)
+	for _, line := range lines {
+		line = bulletLine.ReplaceAllString(line, "")
+		for _, item := range certificateSeparator.Split(line, -1) {
+			item = strings.TrimSpace(strings.TrimRight(item, "。."))
+			if item != "" && !seen[item] {
+				seen[item] = true
+				result = append(result, item)
+			}
+		}
+	}
+	return result
+}
+
+// monthIndex 将“YYYY-MM”/“YYYY”转为月份序号
+func monthIndex(date string) (int, bool) {
+	if date == "" {
+		return 0, false
+	}
+	year, err := strconv.Atoi(date[:4])
+	if err != nil {
+		return 0, false
+	}
+	month := 1
+	if len(date) == 7 {
+		month, _ = strconv.Atoi(date[5:])
+	}
+	return year*12 + month - 1, true
+}
+
+// totalYears 按工作经历的时间区间计算总工作年限，重叠的时间段只计算一次
+func totalYears(experiences []WorkExperience, now time.Time) float64 {
+	type span struct{ from, to int }
+	var spans []span
+	current := now.Year()*12 + int(now.Month()) - 1
+	for _, e := range experiences {
+		from, ok := monthIndex(e.StartDate)
+		if !ok {
+			continue
+		}
+		to := current
+		if !e.Current {
+			if to, ok = monthIndex(e.EndDate); !ok {
+				continue
+			}
+		}
+		if to > from {
+			spans = append(spans, span{from, to})
+		}
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i].from < spans[j].from })
+	months, end := 0, math.MinInt
+	for _, s := range spans {
+		if s.from < end {
+			if s.to > end {
+				months += s.to - end
+				end = s.to
+			}
+			continue
+		}
+		months += s.to - s.from
+		end = s.to
+	}
+	return math.Round(float64(months)/12*10) / 10
+}
+
+// detectLanguage 按汉字占比判断简历语言
+func detectLanguage(text string) string {
+	cjk, latin := 0, 0
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			cjk++
+		case r <= unicode.MaxASCII && unicode.IsLetter(r):
+			latin++
+		}
+	}
+	if cjk == 0 && latin == 0 {
+		return ""
+	}
+	// 英文单词平均约 5 个字母，汉字不足单词数的一成时视为英文简历
+	if cjk*10 < latin/5 {
+		return "en"
+	}
+	return "zh"
+}
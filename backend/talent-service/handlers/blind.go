@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"talent-service/models"
+
+	"common/blind"
+
+	"github.com/gin-gonic/gin"
+)
+
+// redactBlind 当前用户权限不足时，隐藏仍处于盲筛中的人才的身份信息
+func (h *TalentHandler) redactBlind(c *gin.Context, talents []models.Talent) error {
+	if len(talents) == 0 || h.Blind.Exempt(c) {
+		return nil
+	}
+	ids := make([]uint, len(talents))
+	for i, t := range talents {
+		ids[i] = t.ID
+	}
+	screening, err := blind.Talents(h.DB, ids)
+	if err != nil {
+		return err
+	}
+	for i := range talents {
+		if screening[talents[i].ID] {
+			redactTalent(&talents[i])
+		}
+	}
+	return nil
+}
+
+// redactTalent 隐藏姓名、联系方式、性别、年龄，并抹去简介中的身份信息
+func redactTalent(t *models.Talent) {
+	id := blind.Identity{Name: t.Name, Email: t.Email, Phone: t.Phone}
+	t.Name = blind.Alias(t.ID)
+	t.Email, t.Phone, t.Gender = "", "", ""
+	t.Age = 0
+	t.UserID = nil
+	t.Summary = id.RedactText(t.Summary)
+	t.Redacted = true
+}
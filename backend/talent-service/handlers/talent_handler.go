@@ -1,20 +1,35 @@
 package handlers
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"talent-service/models"
 
+	"common/blind"
+	"common/customfields"
+	"common/export"
+	"common/salary"
+	"common/skills"
+
 	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
 	"gorm.io/gorm"
 )
 
+// maxExportRows 单次导出的最大行数
+const maxExportRows = 5000
+
 type TalentHandler struct {
-	DB *gorm.DB
+	DB     *gorm.DB
+	Skills *skills.Dictionary
+	Blind  blind.Policy
 }
 
 func NewTalentHandler(db *gorm.DB) *TalentHandler {
-	return &TalentHandler{DB: db}
+	return &TalentHandler{DB: db, Skills: skills.Default(), Blind: blind.PolicyFromEnv()}
 }
 
 // CreateTalent 创建人才
@@ -25,6 +40,24 @@ func (h *TalentHandler) CreateTalent(c *gin.Context) {
 		return
 	}
 
+	talent.Skills = h.Skills.Normalize(talent.Skills)
+
+	var err error
+	if talent.SalaryRange, talent.Salary, err = salary.Resolve(talent.Salary, talent.SalaryRange); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	defs, err := customfields.LoadDefinitions(h.DB, customfields.EntityTalent)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load custom fields"})
+		return
+	}
+	if talent.CustomFields, err = customfields.Validate(defs, talent.CustomFields, true); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	if err := h.DB.Create(&talent).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create talent: " + err.Error()})
 		return
@@ -43,12 +76,79 @@ func (h *TalentHandler) ListTalents(c *gin.Context) {
 
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	offset := (page - 1) * pageSize
+
+	query, _, err := h.listQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var total int64
+	query.Count(&total)
+
+	if err := query.Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&talents).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch talents"})
+		return
+	}
+	if err := h.redactBlind(c, talents); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check blind screening"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"talents":   talents,
+			"total":     total,
+			"page":      page,
+			"page_size": pageSize,
+		},
+	})
+}
+
+// ExportTalents 导出人才列表（CSV，包含自定义字段，筛选条件同列表；盲筛中的人才按当前用户权限隐藏身份信息）
+func (h *TalentHandler) ExportTalents(c *gin.Context) {
+	query, defs, err := h.listQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var talents []models.Talent
+	if err := query.Order("created_at DESC").Limit(maxExportRows).Find(&talents).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export talents"})
+		return
+	}
+	if err := h.redactBlind(c, talents); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check blind screening"})
+		return
+	}
+
+	header := []string{"ID", "姓名", "邮箱", "电话", "技能", "工作年限", "学历", "所在地", "期望薪资", "当前公司", "当前职位", "状态", "来源", "创建时间"}
+	header = append(header, customfields.Header(defs)...)
+
+	rows := make([][]string, 0, len(talents))
+	for _, t := range talents {
+		row := []string{
+			strconv.Itoa(int(t.ID)), t.Name, t.Email, t.Phone, strings.Join(t.Skills, ", "),
+			strconv.Itoa(t.Experience), t.Education, t.Location, t.Salary, t.CurrentCompany,
+			t.CurrentPosition, t.Status, t.Source, t.CreatedAt.Format("2006-01-02 15:04"),
+		}
+		rows = append(rows, append(row, customfields.Cells(defs, t.CustomFields)...))
+	}
+
+	export.WriteCSV(c, "talents", header, rows)
+}
+
+// listQuery 构建人才列表/导出的筛选条件
+func (h *TalentHandler) listQuery(c *gin.Context) (*gorm.DB, []customfields.Definition, error) {
 	status := c.Query("status")
 	search := c.Query("search")
 	experience := c.Query("experience")
 
-	offset := (page - 1) * pageSize
-
 	query := h.DB.Model(&models.Talent{})
 
 	if status != "" {
@@ -56,7 +156,8 @@ func (h *TalentHandler) ListTalents(c *gin.Context) {
 	}
 
 	if search != "" {
-		query = query.Where("name ILIKE ? OR email ILIKE ? OR ? = ANY(skills)", "%"+search+"%", "%"+search+"%", search)
+		skill, _ := h.Skills.Canonical(search)
+		query = query.Where("name ILIKE ? OR email ILIKE ? OR ? = ANY(skills)", "%"+search+"%", "%"+search+"%", skill)
 	}
 
 	// 经验筛选
@@ -75,24 +176,23 @@ func (h *TalentHandler) ListTalents(c *gin.Context) {
 		}
 	}
 
-	var total int64
-	query.Count(&total)
+	// 期望薪资区间筛选
+	query, err := salary.ApplyFilter(query, "salary_", c.Request.URL.Query())
+	if err != nil {
+		return nil, nil, err
+	}
 
-	if err := query.Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&talents).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch talents"})
-		return
+	// 自定义字段筛选
+	defs, err := customfields.LoadDefinitions(h.DB, customfields.EntityTalent)
+	if err != nil {
+		return nil, nil, err
+	}
+	query, err = customfields.ApplyFilters(query, defs, c.Request.URL.Query(), "custom_fields")
+	if err != nil {
+		return nil, nil, err
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"code":    0,
-		"message": "success",
-		"data": gin.H{
-			"talents":   talents,
-			"total":     total,
-			"page":      page,
-			"page_size": pageSize,
-		},
-	})
+	return query, defs, nil
 }
 
 // GetTalent 获取单个人才详情
@@ -104,11 +204,16 @@ func (h *TalentHandler) GetTalent(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Talent not found"})
 		return
 	}
+	talents := []models.Talent{talent}
+	if err := h.redactBlind(c, talents); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check blind screening"})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"code":    0,
 		"message": "success",
-		"data":    talent,
+		"data":    talents[0],
 	})
 }
 
@@ -128,6 +233,34 @@ func (h *TalentHandler) UpdateTalent(c *gin.Context) {
 		return
 	}
 
+	if raw, ok := updateData["skills"]; ok {
+		updateData["skills"] = pq.StringArray(h.Skills.Normalize(toStringSlice(raw)))
+	}
+
+	if err := resolveSalaryUpdate(updateData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if raw, ok := updateData["custom_fields"]; ok {
+		patch, err := customfields.FromRaw(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		defs, err := customfields.LoadDefinitions(h.DB, customfields.EntityTalent)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load custom fields"})
+			return
+		}
+		merged, err := customfields.Patch(defs, talent.CustomFields, patch)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		updateData["custom_fields"] = merged
+	}
+
 	if err := h.DB.Model(&talent).Updates(updateData).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update talent: " + err.Error()})
 		return
@@ -160,7 +293,7 @@ func (h *TalentHandler) SearchTalents(c *gin.Context) {
 	var talents []models.Talent
 
 	keyword := c.Query("keyword")
-	skills := c.QueryArray("skills")
+	skillFilter := c.QueryArray("skills")
 	minExp, _ := strconv.Atoi(c.DefaultQuery("min_experience", "0"))
 	maxExp, _ := strconv.Atoi(c.DefaultQuery("max_experience", "100"))
 	education := c.Query("education")
@@ -174,12 +307,13 @@ func (h *TalentHandler) SearchTalents(c *gin.Context) {
 
 	// 关键词搜索（搜索姓名、技能、职位等）
 	if keyword != "" {
+		skill, _ := h.Skills.Canonical(keyword)
 		query = query.Where("name ILIKE ? OR current_position ILIKE ? OR summary ILIKE ? OR ? = ANY(skills)",
-			"%"+keyword+"%", "%"+keyword+"%", "%"+keyword+"%", keyword)
+			"%"+keyword+"%", "%"+keyword+"%", "%"+keyword+"%", skill)
 	}
 
-	if len(skills) > 0 {
-		query = query.Where("skills && ?", skills)
+	if len(skillFilter) > 0 {
+		query = query.Where("skills && ?", pq.StringArray(h.Skills.Normalize(skillFilter)))
 	}
 
 	query = query.Where("experience >= ? AND experience <= ?", minExp, maxExp)
@@ -192,6 +326,24 @@ func (h *TalentHandler) SearchTalents(c *gin.Context) {
 		query = query.Where("location ILIKE ?", "%"+location+"%")
 	}
 
+	// 期望薪资区间筛选
+	query, err := salary.ApplyFilter(query, "salary_", c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 自定义字段筛选
+	defs, err := customfields.LoadDefinitions(h.DB, customfields.EntityTalent)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load custom fields"})
+		return
+	}
+	if query, err = customfields.ApplyFilters(query, defs, c.Request.URL.Query(), "custom_fields"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	var total int64
 	query.Count(&total)
 
@@ -199,6 +351,10 @@ func (h *TalentHandler) SearchTalents(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search talents"})
 		return
 	}
+	if err := h.redactBlind(c, talents); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check blind screening"})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"code":    0,
@@ -211,3 +367,49 @@ func (h *TalentHandler) SearchTalents(c *gin.Context) {
 		},
 	})
 }
+
+// toStringSlice 将 JSON 解码后的数组转换为字符串切片
+func toStringSlice(v interface{}) []string {
+	items, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// resolveSalaryUpdate 将局部更新中的薪资文本/结构化薪资统一转换为数据库列
+func resolveSalaryUpdate(updateData map[string]interface{}) error {
+	raw, hasRange := updateData["salary_range"]
+	text, hasText := updateData["salary"].(string)
+	if !hasRange && !hasText {
+		return nil
+	}
+	delete(updateData, "salary_range")
+
+	var r salary.Range
+	if hasRange && raw != nil {
+		data, err := json.Marshal(raw)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, &r); err != nil {
+			return fmt.Errorf("salary_range 格式错误")
+		}
+	}
+
+	r, text, err := salary.Resolve(text, r)
+	if err != nil {
+		return err
+	}
+	updateData["salary"] = text
+	for k, v := range r.Columns("salary_") {
+		updateData[k] = v
+	}
+	return nil
+}
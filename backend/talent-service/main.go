@@ -4,8 +4,12 @@ import (
 	"log"
 	"talent-service/handlers"
 	"talent-service/models"
+	"time"
 
+	"common/customfields"
 	"common/middleware"
+	"common/salary"
+	"common/skills"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/driver/postgres"
@@ -19,10 +23,23 @@ func main() {
 		log.Fatal("Failed to connect database:", err)
 	}
 
-	if err := db.AutoMigrate(&models.Talent{}); err != nil {
+	if err := db.AutoMigrate(&models.Talent{}, &customfields.Definition{}); err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
 
+	// 将历史自由文本期望薪资迁移为结构化薪资
+	if n, err := salary.Backfill(db, "talents", "salary", "salary_"); err != nil {
+		log.Printf("Warning: Failed to migrate talent salaries: %v", err)
+	} else if n > 0 {
+		log.Printf("Migrated %d talent salaries to structured ranges", n)
+	}
+
+	// 加载共享技能字典（由 job-service 维护），并定期刷新
+	if err := skills.Default().Load(db); err != nil {
+		log.Printf("Warning: Failed to load skill dictionary: %v", err)
+	}
+	skills.Default().Watch(db, 5*time.Minute)
+
 	r := gin.Default()
 
 	r.Use(middleware.CORS())
@@ -35,16 +52,21 @@ func main() {
 		c.JSON(200, gin.H{"status": "healthy", "service": "talent-service", "port": 8086})
 	})
 
-	api := r.Group("/api/v1/talents")
+	// 携带登录 token 时按角色判断能否查看盲筛中人才的身份信息
+	api := r.Group("/api/v1/talents", middleware.OptionalJWTAuth())
 	{
 		api.POST("", talentHandler.CreateTalent)
 		api.GET("", talentHandler.ListTalents)
 		api.GET("/search", talentHandler.SearchTalents)
+		api.GET("/export", talentHandler.ExportTalents)
 		api.GET("/:id", talentHandler.GetTalent)
 		api.PUT("/:id", talentHandler.UpdateTalent)
 		api.DELETE("/:id", talentHandler.DeleteTalent)
 	}
 
+	// 自定义字段定义管理（人才、职位、申请共用，修改需管理员权限）
+	customfields.NewHandler(db).RegisterRoutes(r.Group("/api/v1/custom-fields"), middleware.JWTAuth(), middleware.RoleAuth("admin"))
+
 	log.Println("Talent service is running on :8086")
 	if err := r.Run(":8086"); err != nil {
 		log.Fatal("Failed to start server:", err)
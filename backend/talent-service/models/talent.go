@@ -3,31 +3,37 @@ package models
 import (
 	"time"
 
+	"common/customfields"
+	"common/salary"
+
 	"github.com/lib/pq"
 	"gorm.io/gorm"
 )
 
 type Talent struct {
-	ID              uint           `gorm:"primarykey" json:"id"`
-	CreatedAt       time.Time      `json:"created_at"`
-	UpdatedAt       time.Time      `json:"updated_at"`
-	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
-	Name            string         `gorm:"size:100;not null" json:"name"`
-	Email           string         `gorm:"size:100;not null" json:"email"`
-	Phone           string         `gorm:"size:20" json:"phone"`
-	Skills          pq.StringArray `gorm:"type:text[]" json:"skills"`
-	Experience      int            `json:"experience"`
-	Education       string         `gorm:"size:50" json:"education"`
-	Status          string         `gorm:"size:20;default:'active'" json:"status"`
-	Tags            pq.StringArray `gorm:"type:text[]" json:"tags"`
-	UserID          *uint          `json:"user_id,omitempty"`
-	Location        string         `gorm:"size:100" json:"location"`
-	Salary          string         `gorm:"size:50" json:"salary"`
-	Summary         string         `gorm:"type:text" json:"summary"`
-	Gender          string         `gorm:"size:10" json:"gender"`
-	Age             int            `json:"age"`
-	CurrentCompany  string         `gorm:"size:100" json:"current_company"`
-	CurrentPosition string         `gorm:"size:100" json:"current_position"`
-	Source          string         `gorm:"size:50" json:"source"`
-	ResumeID        *uint          `json:"resume_id,omitempty"`
+	ID              uint                `gorm:"primarykey" json:"id"`
+	CreatedAt       time.Time           `json:"created_at"`
+	UpdatedAt       time.Time           `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt      `gorm:"index" json:"-"`
+	Name            string              `gorm:"size:100;not null" json:"name"`
+	Email           string              `gorm:"size:100;not null" json:"email"`
+	Phone           string              `gorm:"size:20" json:"phone"`
+	Skills          pq.StringArray      `gorm:"type:text[]" json:"skills"`
+	Experience      int                 `json:"experience"`
+	Education       string              `gorm:"size:50" json:"education"`
+	Status          string              `gorm:"size:20;default:'active'" json:"status"`
+	Tags            pq.StringArray      `gorm:"type:text[]" json:"tags"`
+	UserID          *uint               `json:"user_id,omitempty"`
+	Location        string              `gorm:"size:100" json:"location"`
+	Salary          string              `gorm:"size:50" json:"salary"`
+	Summary         string              `gorm:"type:text" json:"summary"`
+	Gender          string              `gorm:"size:10" json:"gender"`
+	Age             int                 `json:"age"`
+	CurrentCompany  string              `gorm:"size:100" json:"current_company"`
+	CurrentPosition string              `gorm:"size:100" json:"current_position"`
+	Source          string              `gorm:"size:50" json:"source"`
+	ResumeID        *uint               `json:"resume_id,omitempty"`
+	CustomFields    customfields.Values `gorm:"type:jsonb;default:'{}'" json:"custom_fields"`        // 管理员定义的自定义字段
+	SalaryRange     salary.Range        `gorm:"embedded;embeddedPrefix:salary_" json:"salary_range"` // 结构化期望薪资，由 salary 文本解析或直接提交
+	Redacted        bool                `gorm:"-" json:"redacted,omitempty"`                         // 盲筛中，身份信息已隐藏
 }
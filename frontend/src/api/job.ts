@@ -30,5 +30,75 @@ export const jobApi = {
     // 获取职位统计
     getStats() {
         return request.get<ApiResponse>('/jobs/stats')
+    },
+
+    // 候选人端职位列表（仅审批通过且开放中的职位）
+    listPublic(params?: any) {
+        return request.get<ApiResponse>('/jobs/public', { params })
+    },
+
+    // 提交审批
+    submit(id: number) {
+        return request.post<ApiResponse>(`/jobs/${id}/submit`)
+    },
+
+    // 撤回审批
+    withdraw(id: number) {
+        return request.post<ApiResponse>(`/jobs/${id}/withdraw`)
+    },
+
+    // 审批通过
+    approve(id: number, comment?: string) {
+        return request.post<ApiResponse>(`/jobs/${id}/approve`, { comment })
+    },
+
+    // 审批驳回
+    reject(id: number, comment: string) {
+        return request.post<ApiResponse>(`/jobs/${id}/reject`, { comment })
+    },
+
+    // 审批评论
+    comment(id: number, content: string) {
+        return request.post<ApiResponse>(`/jobs/${id}/comments`, { content })
+    },
+
+    // 审批记录
+    getApprovals(id: number) {
+        return request.get<ApiResponse>(`/jobs/${id}/approvals`)
+    },
+
+    // 我的待审批
+    myPendingApprovals() {
+        return request.get<ApiResponse>('/jobs/approvals/pending')
+    },
+
+    // 变更职位状态（暂停、恢复、关闭等）
+    changeStatus(id: number, status: Job['status']) {
+        return request.post<ApiResponse>(`/jobs/${id}/status`, { status })
+    },
+
+    // 预览从职位描述中提取的技能、要求、职级、年限和学历
+    extract(data: { title?: string; description: string }) {
+        return request.post<ApiResponse>('/jobs/extract', data)
+    },
+
+    // 版本历史
+    getVersions(id: number) {
+        return request.get<ApiResponse>(`/jobs/${id}/versions`)
+    },
+
+    // 获取某个版本
+    getVersion(id: number, version: number) {
+        return request.get<ApiResponse>(`/jobs/${id}/versions/${version}`)
+    },
+
+    // 对比两个版本，默认对比当前版本与上一版本
+    diffVersions(id: number, from?: number, to?: number) {
+        return request.get<ApiResponse>(`/jobs/${id}/versions/diff`, { params: { from, to } })
+    },
+
+    // 复制职位（或指定版本）为新草稿
+    clone(id: number, data?: { version?: number; title?: string }) {
+        return request.post<ApiResponse>(`/jobs/${id}/clone`, data)
     }
 }
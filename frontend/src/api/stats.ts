@@ -27,8 +27,12 @@ export interface ChannelStats {
 
 export interface DepartmentProgress {
   department: string
+  period: string
+  planned: number
   target: number
   hired: number
+  in_pipeline: number
+  open_positions: number
   progress: number
 }
 
@@ -66,8 +70,10 @@ export const statsApi = {
   },
 
   // 获取部门招聘进度
-  getDepartmentProgress() {
-    return request.get<{ code: number; message: string; data: DepartmentProgress[] }>('/stats/department-progress')
+  getDepartmentProgress(period?: string) {
+    return request.get<{ code: number; message: string; data: DepartmentProgress[] }>('/stats/department-progress', {
+      params: period ? { period } : undefined
+    })
   },
 
   // 获取面试官排行
@@ -26,12 +26,24 @@ export interface Talent {
     resume_id?: number
     location: string
     salary: string
+    salary_range?: SalaryRange
     summary: string
     user_id: number
     created_at: string
     updated_at: string
 }
 
+export interface SalaryRange {
+    min: number
+    max: number
+    currency: string
+    period: 'hour' | 'day' | 'month' | 'year'
+    months: number
+    negotiable: boolean
+    annual_min: number
+    annual_max: number
+}
+
 export interface Job {
     id: number
     title: string
@@ -40,16 +52,63 @@ export interface Job {
     salary: string
     location: string
     type: 'full-time' | 'part-time' | 'contract' | 'internship'
-    status: 'open' | 'closed' | 'filled'
+    status: 'draft' | 'pending_approval' | 'scheduled' | 'open' | 'on_hold' | 'closed' | 'filled'
     created_by: number
     department: string
     level: string
     skills: string[]
     benefits: string[]
+    headcount: number
+    hired: number
+    salary_range?: SalaryRange
+    version?: number
+    min_experience?: number
+    education?: string
+    publish_at?: string | null
+    expires_at?: string | null
     created_at: string
     updated_at: string
 }
 
+// 从职位描述中提取的建议值
+export interface JobSuggestions {
+    skills: string[]
+    requirements: string[]
+    level: string
+    min_experience: number
+    education: string
+}
+
+export interface JobVersion {
+    id: number
+    job_id: number
+    version: number
+    title: string
+    description: string
+    requirements: string[]
+    salary: string
+    location: string
+    type: Job['type']
+    department: string
+    level: string
+    skills: string[]
+    benefits: string[]
+    headcount: number
+    salary_range?: SalaryRange
+    changed_by: number
+    change_note: string
+    created_at: string
+}
+
+export interface JobFieldChange {
+    field: string
+    from?: unknown
+    to?: unknown
+    added?: string[]
+    removed?: string[]
+    lines?: { op: 'equal' | 'insert' | 'delete'; text: string }[]
+}
+
 export interface Resume {
     id: number
     talent_id: number
@@ -58,10 +117,22 @@ export interface Resume {
     file_size: number
     parsed_data: string
     status: string
+    parse_error?: string
+    talent_conflicts?: FieldConflict[]
+    content_hash?: string
+    version?: number
+    is_primary?: boolean
     created_at: string
     updated_at: string
 }
 
+// 简历解析值与人才档案现有值不一致的字段
+export interface FieldConflict {
+    field: string
+    current: string
+    parsed: string
+}
+
 export interface Application {
     id: number
     job_id: number
@@ -70,6 +141,7 @@ export interface Application {
     status: 'pending' | 'reviewed' | 'interview' | 'rejected' | 'accepted'
     cover_letter: string
     notes: string
+    job_version?: number
     created_at: string
     updated_at: string
 }
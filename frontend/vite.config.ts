@@ -63,6 +63,11 @@ export default defineConfig({
                 target: 'http://localhost:8085',
                 changeOrigin: true
             },
+            '/api/v1/ws': {
+                target: 'ws://localhost:8085',
+                ws: true,
+                changeOrigin: true
+            },
             // 人才服务
             '/api/v1/talents': {
                 target: 'http://localhost:8086',